@@ -22,13 +22,18 @@ import (
 	"os"
 	"path/filepath"
 	"strconv"
+	"strings"
+	"time"
 	_ "time/tzdata"
 
 	"github.com/observiq/bindplane-agent/collector"
+	"github.com/observiq/bindplane-agent/internal/control"
 	"github.com/observiq/bindplane-agent/internal/logging"
 	"github.com/observiq/bindplane-agent/internal/service"
+	"github.com/observiq/bindplane-agent/internal/throughputwrapper"
 	"github.com/observiq/bindplane-agent/internal/version"
 	"github.com/observiq/bindplane-agent/opamp"
+	"github.com/observiq/bindplane-agent/receiver/agentlogsreceiver"
 	"github.com/oklog/ulid/v2"
 	"github.com/spf13/pflag"
 	"go.uber.org/zap"
@@ -49,12 +54,27 @@ const (
 	configPathENV    = "CONFIG_YAML_PATH"
 	managerPathENV   = "MANAGER_YAML_PATH"
 	loggingPathENV   = "LOGGING_YAML_PATH"
+	controlENV       = "CONTROL_ENDPOINT"
+	controlTokenENV  = "CONTROL_TOKEN"
+	drainTimeoutENV  = "DRAIN_TIMEOUT"
+
+	throughputSamplingRatioENV  = "THROUGHPUT_SAMPLING_RATIO"
+	throughputAttributesENV     = "THROUGHPUT_ATTRIBUTES"
+	throughputMaxCardinalityENV = "THROUGHPUT_MAX_CARDINALITY"
+	controlSnapshotEndpointsENV = "CONTROL_SNAPSHOT_ENDPOINTS"
 )
 
 func main() {
 	collectorConfigPaths := pflag.StringSlice("config", getDefaultCollectorConfigPaths(), "the collector config path")
 	managerConfigPath := pflag.String("manager", getDefaultManagerConfigPath(), "The configuration for remote management")
 	loggingConfigPath := pflag.String("logging", getDefaultLoggingConfigPath(), "the collector logging config path")
+	controlEndpoint := pflag.String("control-endpoint", os.Getenv(controlENV), "localhost address or unix:// socket path to serve the control API on in standalone mode")
+	controlSnapshotEndpoints := pflag.StringSlice("control-snapshot-endpoints", getDefaultControlSnapshotEndpoints(), "URLs the control API may send snapshots to")
+	drainTimeout := pflag.Duration("drain-timeout", getDefaultDrainTimeout(), "how long to wait on shutdown for processors and exporters to flush in-flight telemetry")
+	throughputSettings := getDefaultThroughputSettings()
+	pflag.Float64Var(&throughputSettings.SamplingRatio, "throughput-sampling-ratio", throughputSettings.SamplingRatio, "ratio of receiver payloads to measure the size of")
+	pflag.StringSliceVar(&throughputSettings.Attributes, "throughput-attributes", throughputSettings.Attributes, "resource attributes to break down receiver throughput by")
+	pflag.IntVar(&throughputSettings.MaxCardinality, "throughput-max-cardinality", throughputSettings.MaxCardinality, "maximum number of throughput attribute combinations per receiver")
 
 	_ = pflag.String("log-level", "", "not implemented") // TEMP(jsirianni): Required for OTEL k8s operator
 	var showVersion = pflag.BoolP("version", "v", false, "prints the version of the collector")
@@ -78,6 +98,15 @@ func main() {
 		log.Fatalf("Failed to set up logger: %v", err)
 	}
 
+	if *drainTimeout <= 0 {
+		logger.Fatal("Drain timeout must be positive", zap.Duration("drain_timeout", *drainTimeout))
+	}
+	service.SetStopTimeout(*drainTimeout)
+
+	if err := throughputwrapper.SetSettings(throughputSettings); err != nil {
+		logger.Fatal("Failed to set throughput settings", zap.Error(err))
+	}
+
 	var runnableService service.RunnableService
 
 	// Set feature flags
@@ -85,7 +114,21 @@ func main() {
 		logger.Fatal("Failed to set feature flags.", zap.Error(err))
 	}
 
-	col, err := collector.New(*collectorConfigPaths, version.Version(), logOpts)
+	if *controlEndpoint != "" {
+		// The control API reads and replaces a single config file, which is only the effective config if no others are merged with it
+		if len(*collectorConfigPaths) != 1 {
+			logger.Fatal("The control API requires exactly one collector config", zap.Strings("config", *collectorConfigPaths))
+		}
+
+		// Component health is reported by the control API, so it must be enabled before the collector is created
+		collector.EnableComponentHealth()
+	}
+
+	// Pass the collector's logs to agent logs receivers, excluding the components their logs pass through
+	col, err := collector.New(*collectorConfigPaths, version.Version(), logOpts,
+		collector.WithCoreWrapper(agentlogsreceiver.WrapCore),
+		collector.WithConverter(agentlogsreceiver.NewConverter()),
+	)
 	if err != nil {
 		logger.Fatal("Failed to create collector.", zap.Error(err))
 	}
@@ -110,7 +153,21 @@ func main() {
 		}
 	} else if errors.Is(err, os.ErrNotExist) {
 		logger.Info("Starting Standalone Mode")
-		runnableService = service.NewStandaloneCollectorService(col)
+		if *controlEndpoint == "" {
+			runnableService = service.NewStandaloneCollectorService(col)
+		} else {
+			runnableService, err = service.NewStandaloneCollectorServiceWithControl(col, logger, control.Settings{
+				Endpoint:            *controlEndpoint,
+				CollectorConfigPath: (*collectorConfigPaths)[0],
+				LoggingConfigPath:   *loggingConfigPath,
+				// The token is only read from the environment, so it isn't visible in the process list
+				Token:             os.Getenv(controlTokenENV),
+				SnapshotEndpoints: *controlSnapshotEndpoints,
+			})
+			if err != nil {
+				logger.Fatal("Failed to initiate standalone mode", zap.Error(err))
+			}
+		}
 	} else {
 		logger.Fatal("Error while searching for management config", zap.Error(err))
 	}
@@ -147,6 +204,52 @@ func getDefaultLoggingConfigPath() string {
 	return logging.DefaultConfigPath
 }
 
+func getDefaultControlSnapshotEndpoints() []string {
+	if endpoints, ok := os.LookupEnv(controlSnapshotEndpointsENV); ok && endpoints != "" {
+		return strings.Split(endpoints, ",")
+	}
+	return nil
+}
+
+func getDefaultDrainTimeout() time.Duration {
+	dt, ok := os.LookupEnv(drainTimeoutENV)
+	if !ok {
+		return service.DefaultStopTimeout
+	}
+
+	timeout, err := time.ParseDuration(dt)
+	if err != nil {
+		log.Fatalf("Invalid value '%s' for environment option '%s': %v", dt, drainTimeoutENV, err)
+	}
+	return timeout
+}
+
+func getDefaultThroughputSettings() throughputwrapper.Settings {
+	settings := throughputwrapper.DefaultSettings()
+
+	if sr, ok := os.LookupEnv(throughputSamplingRatioENV); ok {
+		ratio, err := strconv.ParseFloat(sr, 64)
+		if err != nil {
+			log.Fatalf("Invalid value '%s' for environment option '%s': %v", sr, throughputSamplingRatioENV, err)
+		}
+		settings.SamplingRatio = ratio
+	}
+
+	if attrs, ok := os.LookupEnv(throughputAttributesENV); ok && attrs != "" {
+		settings.Attributes = strings.Split(attrs, ",")
+	}
+
+	if mc, ok := os.LookupEnv(throughputMaxCardinalityENV); ok {
+		maxCardinality, err := strconv.Atoi(mc)
+		if err != nil {
+			log.Fatalf("Invalid value '%s' for environment option '%s': %v", mc, throughputMaxCardinalityENV, err)
+		}
+		settings.MaxCardinality = maxCardinality
+	}
+
+	return settings
+}
+
 func logOptions(loggingConfigPath *string) ([]zap.Option, error) {
 	if loggingConfigPath == nil {
 		return nil, nil
@@ -157,7 +260,14 @@ func logOptions(loggingConfigPath *string) ([]zap.Option, error) {
 		return nil, fmt.Errorf("failed to create logger config: %w", err)
 	}
 
-	return l.Options()
+	opts, cleanup, err := l.Options()
+	if err != nil {
+		return nil, err
+	}
+
+	// No loggers exist yet, so there are no previous options to replace
+	cleanup()
+	return opts, nil
 }
 
 func checkManagerConfig(configPath *string) error {
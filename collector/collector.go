@@ -24,8 +24,11 @@ import (
 	"time"
 
 	"github.com/observiq/bindplane-agent/factories"
+	"github.com/observiq/bindplane-agent/internal/drain"
+	"go.opentelemetry.io/collector/confmap"
 	"go.opentelemetry.io/collector/otelcol"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 )
 
 // The timeout for how long to wait on shutting down the collector before bailing out during a Restart.
@@ -48,6 +51,9 @@ type collector struct {
 	configPaths []string
 	version     string
 	loggingOpts []zap.Option
+	// coreWrappers and converters are applied on every run, see WithCoreWrapper and WithConverter
+	coreWrappers []func(zapcore.Core) zapcore.Core
+	converters   []confmap.Converter
 	// factories for modifying in test
 	factories  otelcol.Factories
 	mux        sync.Mutex
@@ -61,21 +67,45 @@ type collector struct {
 	collectorCtxCancel func()
 }
 
+// Option is an option for creating a collector
+type Option func(c *collector)
+
+// WithCoreWrapper wraps the core of the collector's logger with wrap.
+// Unlike the logging options, it's kept when the logging options are replaced, and wraps the core created from them.
+func WithCoreWrapper(wrap func(zapcore.Core) zapcore.Core) Option {
+	return func(c *collector) {
+		c.coreWrappers = append(c.coreWrappers, wrap)
+	}
+}
+
+// WithConverter adds a converter that is applied to the collector's config
+func WithConverter(converter confmap.Converter) Option {
+	return func(c *collector) {
+		c.converters = append(c.converters, converter)
+	}
+}
+
 // New returns a new collector.
-func New(configPaths []string, version string, loggingOpts []zap.Option) (Collector, error) {
+func New(configPaths []string, version string, loggingOpts []zap.Option, opts ...Option) (Collector, error) {
 	factories, err := factories.DefaultFactories()
 	if err != nil {
 		return nil, fmt.Errorf("error while setting up default factories: %w", err)
 	}
 
-	return &collector{
+	c := &collector{
 		configPaths: configPaths,
 		version:     version,
 		loggingOpts: loggingOpts,
 		statusChan:  make(chan *Status, 10),
 		wg:          &sync.WaitGroup{},
 		factories:   factories,
-	}, nil
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c, nil
 }
 
 // GetLoggingOpts returns the current logging options
@@ -88,6 +118,16 @@ func (c *collector) SetLoggingOpts(opts []zap.Option) {
 	c.loggingOpts = opts
 }
 
+// runLoggingOpts returns the logging options of a run.
+// The core wrappers are the last options, so they wrap the core created from the logging options.
+func (c *collector) runLoggingOpts() []zap.Option {
+	opts := c.loggingOpts[:len(c.loggingOpts):len(c.loggingOpts)]
+	for _, wrap := range c.coreWrappers {
+		opts = append(opts, zap.WrapCore(wrap))
+	}
+	return opts
+}
+
 // Run will run the collector. This function will return an error
 // if the collector was unable to startup.
 func (c *collector) Run(ctx context.Context) error {
@@ -103,9 +143,12 @@ func (c *collector) Run(ctx context.Context) error {
 		return fmt.Errorf("register component telemetry: %w", err)
 	}
 
+	// Components are recreated on each run, so their previous status no longer applies
+	componentHealth.reset()
+
 	// The OT collector only supports using settings once during the lifetime
 	// of a single collector instance. We must remake the settings on each startup.
-	settings, err := NewSettings(c.configPaths, c.version, c.loggingOpts, c.factories)
+	settings, err := NewSettings(c.configPaths, c.version, c.runLoggingOpts(), c.factories, c.converters...)
 	if err != nil {
 		return err
 	}
@@ -176,8 +219,14 @@ func (c *collector) Run(ctx context.Context) error {
 	return c.waitForStartup(ctx, startupErr)
 }
 
-// Stop will stop the collector.
+// Stop will stop the collector, draining in-flight telemetry and logging a summary of the drain.
 func (c *collector) Stop(ctx context.Context) {
+	c.stop(ctx, true)
+}
+
+// stop stops the collector. If drainTelemetry is true, processors and exporters are bounded by the
+// stop deadline and a drain summary is logged. Restarts don't drain, since the collector keeps running.
+func (c *collector) stop(ctx context.Context, drainTelemetry bool) {
 	c.mux.Lock()
 	defer c.mux.Unlock()
 
@@ -185,6 +234,12 @@ func (c *collector) Stop(ctx context.Context) {
 		return
 	}
 
+	// Receivers stop first, then processors flush their state and exporters drain until the stop deadline
+	if drainTelemetry {
+		deadline, _ := ctx.Deadline()
+		drain.Begin(deadline)
+	}
+
 	c.svc.Shutdown()
 
 	shutdownCompleteChan := make(chan struct{})
@@ -203,16 +258,47 @@ func (c *collector) Stop(ctx context.Context) {
 	c.wg.Wait()
 	close(shutdownCompleteChan)
 
+	if drainTelemetry {
+		c.logDrainSummary(drain.End())
+	}
+
 	c.svc = nil
 }
 
+// logDrainSummary logs how much telemetry was flushed and dropped while the collector stopped.
+func (c *collector) logDrainSummary(summary drain.Summary) {
+	logger, err := zap.NewProduction(c.loggingOpts...)
+	if err != nil {
+		return
+	}
+
+	for _, component := range summary.Components {
+		if component.Dropped == 0 && !component.TimedOut {
+			continue
+		}
+
+		logger.Warn("Component did not fully drain",
+			zap.String("component", component.ID),
+			zap.Int64("flushed", component.Flushed),
+			zap.Int64("dropped", component.Dropped),
+			zap.Bool("timed_out", component.TimedOut),
+		)
+	}
+
+	logger.Info("Collector drain complete",
+		zap.Int64("flushed", summary.Flushed),
+		zap.Int64("dropped", summary.Dropped),
+		zap.Duration("duration", summary.Duration),
+	)
+}
+
 // Restart will restart the collector. It will also reset the status channel.
 // After calling restart call Status() to get a handle to the new channel.
 func (c *collector) Restart(ctx context.Context) error {
 	// We stop with a timeout, because we don't want the collector to hang when restarting.
 	timeoutCtx, cancel := context.WithTimeout(ctx, collectorRestartTimeout)
 	defer cancel()
-	c.Stop(timeoutCtx)
+	c.stop(timeoutCtx, false)
 
 	// Reset status channel so it's not polluted by the collector shutting down and restarting
 	c.statusChan = make(chan *Status, 10)
@@ -17,13 +17,18 @@ package collector
 import (
 	"context"
 	"fmt"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/confmap"
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/receiver"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
 )
 
 const slowShutdownTypestr = "slowshutdown"
@@ -46,6 +51,47 @@ func TestCollectorRunValid(t *testing.T) {
 	require.False(t, status.Running)
 }
 
+func TestCollectorRunWithOptions(t *testing.T) {
+	ctx := context.Background()
+
+	var wrapped, converted atomic.Bool
+	collector, err := New([]string{"./test/valid.yaml"}, "0.0.0", nil,
+		WithCoreWrapper(func(core zapcore.Core) zapcore.Core {
+			wrapped.Store(true)
+			return core
+		}),
+		WithConverter(converterFunc(func(_ context.Context, _ *confmap.Conf) error {
+			converted.Store(true)
+			return nil
+		})),
+	)
+	require.NoError(t, err)
+
+	// Core wrappers are kept when the logging options are replaced
+	collector.SetLoggingOpts([]zap.Option{zap.Development()})
+
+	err = collector.Run(ctx)
+	require.NoError(t, err)
+
+	status := <-collector.Status()
+	require.True(t, status.Running)
+	require.True(t, wrapped.Load())
+	require.True(t, converted.Load())
+	require.Len(t, collector.GetLoggingOpts(), 1)
+
+	collector.Stop(ctx)
+	status = <-collector.Status()
+	require.False(t, status.Running)
+}
+
+// converterFunc is a confmap.Converter implemented by a function
+type converterFunc func(context.Context, *confmap.Conf) error
+
+// Convert calls the function
+func (f converterFunc) Convert(ctx context.Context, conf *confmap.Conf) error {
+	return f(ctx, conf)
+}
+
 func TestCollectorRunMultiple(t *testing.T) {
 	collector, err := New([]string{"./test/valid.yaml"}, "0.0.0", nil)
 	require.NoError(t, err)
@@ -144,6 +190,26 @@ func TestCollectorRestart(t *testing.T) {
 	require.False(t, status.Running)
 }
 
+func TestCollectorDrainSummaryOnlyOnStop(t *testing.T) {
+	ctx := context.Background()
+
+	core, logs := observer.New(zapcore.InfoLevel)
+	opts := []zap.Option{zap.WrapCore(func(zapcore.Core) zapcore.Core { return core })}
+	collector, err := New([]string{"./test/valid.yaml"}, "0.0.0", opts)
+	require.NoError(t, err)
+
+	require.NoError(t, collector.Run(ctx))
+	<-collector.Status()
+
+	require.NoError(t, collector.Restart(ctx))
+	<-collector.Status()
+	require.Zero(t, logs.FilterMessage("Collector drain complete").Len())
+
+	collector.Stop(ctx)
+	<-collector.Status()
+	require.Equal(t, 1, logs.FilterMessage("Collector drain complete").Len())
+}
+
 func TestCollectorPrematureStop(t *testing.T) {
 	collector, err := New([]string{"./test/valid.yaml"}, "0.0.0", nil)
 	require.NoError(t, err)
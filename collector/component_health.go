@@ -0,0 +1,197 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collector
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/confmap"
+	"go.opentelemetry.io/collector/extension"
+	"go.opentelemetry.io/collector/otelcol"
+)
+
+// componentHealthType is the type of the extension that watches component status.
+// It is added to the collector config when component health is enabled, and never needs to be configured by users.
+const componentHealthType component.Type = "bindplane_agent_health"
+
+// componentHealthEnabled is whether the component health extension is added to the collector
+var componentHealthEnabled atomic.Bool
+
+// componentHealth holds the latest status of each component
+var componentHealth = &healthStore{
+	components: make(map[string]ComponentHealth),
+}
+
+// EnableComponentHealth enables tracking the status of each component, which is then returned by GetComponentHealth.
+// Takes effect the next time the collector is started.
+func EnableComponentHealth() {
+	componentHealthEnabled.Store(true)
+}
+
+// ComponentHealth is the most recently reported status of a component
+type ComponentHealth struct {
+	ID        string    `json:"id"`
+	Kind      string    `json:"kind"`
+	Pipelines []string  `json:"pipelines,omitempty"`
+	Status    string    `json:"status"`
+	Healthy   bool      `json:"healthy"`
+	Error     string    `json:"error,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// GetComponentHealth returns the status of each component of the running collector, sorted by kind and ID.
+// Nothing is returned unless component health is enabled.
+func GetComponentHealth() []ComponentHealth {
+	return componentHealth.list()
+}
+
+// healthStore holds the latest status of each component
+type healthStore struct {
+	mutex      sync.Mutex
+	components map[string]ComponentHealth
+}
+
+// set records the status event for the component
+func (h *healthStore) set(source *component.InstanceID, event *component.StatusEvent) {
+	pipelines := make([]string, 0, len(source.PipelineIDs))
+	for id := range source.PipelineIDs {
+		pipelines = append(pipelines, id.String())
+	}
+	sort.Strings(pipelines)
+
+	health := ComponentHealth{
+		ID:        source.ID.String(),
+		Kind:      source.Kind.String(),
+		Pipelines: pipelines,
+		Status:    event.Status().String(),
+		Healthy:   !component.StatusIsError(event.Status()),
+		Timestamp: event.Timestamp(),
+	}
+	if event.Err() != nil {
+		health.Error = event.Err().Error()
+	}
+
+	// Processors are instanced per pipeline, so the pipelines are part of the key
+	key := fmt.Sprintf("%s/%s/%s", health.Kind, health.ID, strings.Join(pipelines, ","))
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.components[key] = health
+}
+
+// list returns the status of each component, sorted by kind and ID
+func (h *healthStore) list() []ComponentHealth {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	keys := make([]string, 0, len(h.components))
+	for key := range h.components {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	components := make([]ComponentHealth, 0, len(keys))
+	for _, key := range keys {
+		components = append(components, h.components[key])
+	}
+
+	return components
+}
+
+// reset clears the status of all components
+func (h *healthStore) reset() {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.components = make(map[string]ComponentHealth)
+}
+
+// healthExtension is an extension that records the status of each component in the health store
+type healthExtension struct {
+	store *healthStore
+}
+
+var _ extension.StatusWatcher = (*healthExtension)(nil)
+
+// Start does nothing
+func (e *healthExtension) Start(_ context.Context, _ component.Host) error {
+	return nil
+}
+
+// Shutdown does nothing
+func (e *healthExtension) Shutdown(_ context.Context) error {
+	return nil
+}
+
+// ComponentStatusChanged records the new status of the component
+func (e *healthExtension) ComponentStatusChanged(source *component.InstanceID, event *component.StatusEvent) {
+	e.store.set(source, event)
+}
+
+// healthExtensionConfig is the empty config of the health extension
+type healthExtensionConfig struct{}
+
+// newHealthExtensionFactory creates the factory for the health extension
+func newHealthExtensionFactory() extension.Factory {
+	return extension.NewFactory(
+		componentHealthType,
+		func() component.Config { return &healthExtensionConfig{} },
+		func(_ context.Context, _ extension.CreateSettings, _ component.Config) (extension.Extension, error) {
+			return &healthExtension{store: componentHealth}, nil
+		},
+		component.StabilityLevelStable,
+	)
+}
+
+// withHealthExtension returns a copy of the factories that includes the health extension
+func withHealthExtension(factories otelcol.Factories) otelcol.Factories {
+	extensions := make(map[component.Type]extension.Factory, len(factories.Extensions)+1)
+	for t, f := range factories.Extensions {
+		extensions[t] = f
+	}
+	extensions[componentHealthType] = newHealthExtensionFactory()
+	factories.Extensions = extensions
+
+	return factories
+}
+
+// healthConverter adds the health extension to the collector config
+type healthConverter struct{}
+
+// Convert adds the health extension to the extensions and service sections of the config
+func (healthConverter) Convert(_ context.Context, conf *confmap.Conf) error {
+	id := string(componentHealthType)
+
+	serviceExtensions := []any{}
+	if existing, ok := conf.Get("service::extensions").([]any); ok {
+		serviceExtensions = append(serviceExtensions, existing...)
+	}
+	serviceExtensions = append(serviceExtensions, id)
+
+	return conf.Merge(confmap.NewFromStringMap(map[string]any{
+		"extensions": map[string]any{
+			id: nil,
+		},
+		"service": map[string]any{
+			"extensions": serviceExtensions,
+		},
+	}))
+}
@@ -0,0 +1,108 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collector
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/confmap"
+)
+
+func TestHealthConverter(t *testing.T) {
+	conf := confmap.NewFromStringMap(map[string]any{
+		"extensions": map[string]any{
+			"pprof": nil,
+		},
+		"service": map[string]any{
+			"extensions": []any{"pprof"},
+		},
+	})
+
+	require.NoError(t, healthConverter{}.Convert(context.Background(), conf))
+	require.Equal(t, []any{"pprof", string(componentHealthType)}, conf.Get("service::extensions"))
+	require.True(t, conf.IsSet("extensions::pprof"))
+	require.True(t, conf.IsSet("extensions::"+string(componentHealthType)))
+}
+
+func TestHealthStore(t *testing.T) {
+	store := &healthStore{components: make(map[string]ComponentHealth)}
+
+	receiver := &component.InstanceID{
+		ID:   component.NewID("filelog"),
+		Kind: component.KindReceiver,
+		PipelineIDs: map[component.ID]struct{}{
+			component.NewID("logs"):                  {},
+			component.NewIDWithName("logs", "other"): {},
+		},
+	}
+	exporter := &component.InstanceID{
+		ID:   component.NewID("nop"),
+		Kind: component.KindExporter,
+		PipelineIDs: map[component.ID]struct{}{
+			component.NewID("logs"): {},
+		},
+	}
+
+	store.set(receiver, component.NewStatusEvent(component.StatusStarting))
+	store.set(exporter, component.NewRecoverableErrorEvent(errors.New("oops")))
+	store.set(receiver, component.NewStatusEvent(component.StatusOK))
+
+	health := store.list()
+	require.Len(t, health, 2)
+
+	require.Equal(t, "nop", health[0].ID)
+	require.Equal(t, "Exporter", health[0].Kind)
+	require.Equal(t, component.StatusRecoverableError.String(), health[0].Status)
+	require.False(t, health[0].Healthy)
+	require.Equal(t, "oops", health[0].Error)
+
+	require.Equal(t, "filelog", health[1].ID)
+	require.Equal(t, "Receiver", health[1].Kind)
+	require.Equal(t, []string{"logs", "logs/other"}, health[1].Pipelines)
+	require.Equal(t, component.StatusOK.String(), health[1].Status)
+	require.True(t, health[1].Healthy)
+	require.Empty(t, health[1].Error)
+
+	store.reset()
+	require.Empty(t, store.list())
+}
+
+func TestCollectorRunWithComponentHealth(t *testing.T) {
+	EnableComponentHealth()
+	t.Cleanup(func() {
+		componentHealthEnabled.Store(false)
+	})
+
+	ctx := context.Background()
+	collector, err := New([]string{"./test/valid.yaml"}, "0.0.0", nil)
+	require.NoError(t, err)
+
+	require.NoError(t, collector.Run(ctx))
+	defer collector.Stop(ctx)
+
+	require.Eventually(t, func() bool {
+		for _, health := range GetComponentHealth() {
+			if health.ID == "filelog" && health.Status == component.StatusOK.String() {
+				return true
+			}
+		}
+		return false
+	}, 5*time.Second, 100*time.Millisecond)
+}
@@ -31,6 +31,10 @@ func SetFeatureFlags() error {
 	if err := featuregate.GlobalRegistry().Set("filelog.mtimeSortType", true); err != nil {
 		return fmt.Errorf("failed to enable filelog.mtimeSortType: %w", err)
 	}
+	// Component telemetry, such as receiver throughput, is recorded with the OTel metrics API
+	if err := featuregate.GlobalRegistry().Set("telemetry.useOtelForInternalMetrics", true); err != nil {
+		return fmt.Errorf("failed to enable telemetry.useOtelForInternalMetrics: %w", err)
+	}
 
 	return nil
 }
@@ -28,19 +28,27 @@ import (
 const buildDescription = "observIQ's opentelemetry-collector distribution"
 
 // NewSettings returns new settings for the collector with default values.
-func NewSettings(configPaths []string, version string, loggingOpts []zap.Option, factories otelcol.Factories) (*otelcol.CollectorSettings, error) {
+// The extra converters are applied to the config after the default converters.
+func NewSettings(configPaths []string, version string, loggingOpts []zap.Option, factories otelcol.Factories, extraConverters ...confmap.Converter) (*otelcol.CollectorSettings, error) {
 	buildInfo := component.BuildInfo{
 		Command:     os.Args[0],
 		Description: buildDescription,
 		Version:     version,
 	}
 
+	converters := []confmap.Converter{expandconverter.New()}
+	if componentHealthEnabled.Load() {
+		factories = withHealthExtension(factories)
+		converters = append(converters, healthConverter{})
+	}
+	converters = append(converters, extraConverters...)
+
 	fmp := fileprovider.New()
 	configProviderSettings := otelcol.ConfigProviderSettings{
 		ResolverSettings: confmap.ResolverSettings{
 			URIs:       configPaths,
 			Providers:  map[string]confmap.Provider{fmp.Scheme(): fmp},
-			Converters: []confmap.Converter{expandconverter.New()},
+			Converters: converters,
 		},
 	}
 	provider, err := otelcol.NewConfigProvider(configProviderSettings)
@@ -0,0 +1,44 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collector
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/observiq/bindplane-agent/factories"
+	"go.opentelemetry.io/collector/otelcol"
+)
+
+// ValidateConfig validates the collector config files without starting the collector.
+// An error is returned if the config can't be parsed, or refers to components that don't exist or are misconfigured.
+func ValidateConfig(ctx context.Context, configPaths []string) error {
+	factories, err := factories.DefaultFactories()
+	if err != nil {
+		return fmt.Errorf("error while setting up default factories: %w", err)
+	}
+
+	settings, err := NewSettings(configPaths, "", nil, factories)
+	if err != nil {
+		return err
+	}
+
+	svc, err := otelcol.NewCollector(*settings)
+	if err != nil {
+		return fmt.Errorf("failed to create service: %w", err)
+	}
+
+	return svc.DryRun(ctx)
+}
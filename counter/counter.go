@@ -15,28 +15,107 @@
 // Package counter contains structs used to count telemetry grouped by resource and attributes.
 package counter
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"sort"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+)
+
+// OverflowAttribute is the attribute of the attribute set that combines counts that weren't tracked individually.
+const OverflowAttribute = "otel.metric.overflow"
 
 // TelemetryCounter tracks the number of times a set of resource and attribute dimensions have been seen.
 type TelemetryCounter struct {
 	resources map[string]*ResourceCounter
+	// limit is the maximum number of attribute sets tracked, or 0 if unlimited
+	limit int
+	// size is the number of attribute sets tracked, excluding overflow attribute sets
+	size int
+	// exemplarLimit is the maximum number of exemplars sampled per attribute set
+	exemplarLimit int
 }
 
 // NewTelemetryCounter creates a new TelemetryCounter.
 func NewTelemetryCounter() *TelemetryCounter {
+	return NewBoundedTelemetryCounter(0)
+}
+
+// NewBoundedTelemetryCounter creates a new TelemetryCounter that tracks at most limit attribute sets.
+// Once the limit is reached, counts of new attribute sets are added to the overflow attribute set of their resource.
+// Counts of new resources are added to an overflow resource. A limit of 0 is unlimited.
+func NewBoundedTelemetryCounter(limit int) *TelemetryCounter {
 	return &TelemetryCounter{
 		resources: make(map[string]*ResourceCounter),
+		limit:     limit,
 	}
 }
 
 // Add increments the counter with the supplied dimensions.
 func (t *TelemetryCounter) Add(resource, attributes map[string]any) {
+	t.AddAdjusted(resource, attributes, 1)
+}
+
+// AddAdjusted increments the counter with the supplied dimensions, adding the adjusted count to the adjusted total.
+func (t *TelemetryCounter) AddAdjusted(resource, attributes map[string]any, adjustedCount float64) {
+	t.add(resource, attributes, 1, adjustedCount)
+}
+
+// AddExemplar increments the counter with the supplied dimensions like AddAdjusted, and offers the exemplar to the sampled exemplars of the attribute set.
+func (t *TelemetryCounter) AddExemplar(resource, attributes map[string]any, adjustedCount float64, exemplar Exemplar) {
+	t.add(resource, attributes, 1, adjustedCount).exemplars.Offer(exemplar)
+}
+
+// SetExemplarLimit sets the maximum number of exemplars sampled per attribute set. A limit of 0 disables exemplars.
+func (t *TelemetryCounter) SetExemplarLimit(limit int) {
+	t.exemplarLimit = limit
+}
+
+// Merge adds all counts of the other counter to this counter.
+func (t *TelemetryCounter) Merge(other *TelemetryCounter) {
+	for _, resource := range other.resources {
+		for _, attributes := range resource.attributes {
+			t.add(resource.values, attributes.values, attributes.count, attributes.adjustedCount)
+		}
+	}
+}
+
+func (t *TelemetryCounter) add(resource, attributes map[string]any, count int, adjustedCount float64) *AttributeCounter {
 	key := getDimensionKey(resource)
+	if _, ok := t.resources[key]; !ok && t.full() {
+		resource = overflowValues()
+		key = getDimensionKey(resource)
+	}
+
 	if _, ok := t.resources[key]; !ok {
 		t.resources[key] = NewResourceCounter(resource)
 	}
 
-	t.resources[key].Add(attributes)
+	rc := t.resources[key]
+	attrKey := getDimensionKey(attributes)
+	if _, ok := rc.attributes[attrKey]; !ok {
+		if t.full() {
+			attributes = overflowValues()
+			attrKey = getDimensionKey(attributes)
+		} else {
+			t.size++
+		}
+	}
+
+	if _, ok := rc.attributes[attrKey]; !ok {
+		rc.attributes[attrKey] = NewAttributeCounter(attributes)
+		rc.attributes[attrKey].exemplars = NewExemplarReservoir(t.exemplarLimit)
+	}
+
+	ac := rc.attributes[attrKey]
+	ac.count += count
+	ac.adjustedCount += adjustedCount
+	return ac
+}
+
+// full returns true if the counter can't track any more attribute sets.
+func (t *TelemetryCounter) full() bool {
+	return t.limit > 0 && t.size >= t.limit
 }
 
 // Resources returns a map of resource ID to a counter for that resource.
@@ -44,9 +123,19 @@ func (t TelemetryCounter) Resources() map[string]*ResourceCounter {
 	return t.resources
 }
 
+// Len returns the number of attribute sets counted across all resources, including overflow attribute sets.
+func (t TelemetryCounter) Len() int {
+	n := 0
+	for _, resource := range t.resources {
+		n += len(resource.attributes)
+	}
+	return n
+}
+
 // Reset resets the counter.
 func (t *TelemetryCounter) Reset() {
 	t.resources = make(map[string]*ResourceCounter)
+	t.size = 0
 }
 
 // ResourceCounter dimensions the counter by resource.
@@ -65,12 +154,17 @@ func NewResourceCounter(values map[string]any) *ResourceCounter {
 
 // Add increments the counter with the supplied dimensions.
 func (r *ResourceCounter) Add(attributes map[string]any) {
+	r.AddAdjusted(attributes, 1)
+}
+
+// AddAdjusted increments the counter with the supplied dimensions, adding the adjusted count to the adjusted total.
+func (r *ResourceCounter) AddAdjusted(attributes map[string]any, adjustedCount float64) {
 	key := getDimensionKey(attributes)
 	if _, ok := r.attributes[key]; !ok {
 		r.attributes[key] = NewAttributeCounter(attributes)
 	}
 
-	r.attributes[key].Add()
+	r.attributes[key].AddAdjusted(adjustedCount)
 }
 
 // Attributes returns a map of attribute set ID to a counter for that attribute set.
@@ -83,22 +177,90 @@ func (r ResourceCounter) Values() map[string]any {
 	return r.values
 }
 
+// TopAttributes returns the n attribute counters with the highest counts, in descending order,
+// and a counter with the overflow attribute set combining the counts of all other attribute sets.
+// If adjusted is true, attribute counters are ranked by their adjusted count.
+// The combined counter is nil if there are no other attribute sets.
+func (r ResourceCounter) TopAttributes(n int, adjusted bool) ([]*AttributeCounter, *AttributeCounter) {
+	overflowKey := getDimensionKey(overflowValues())
+	keys := make([]string, 0, len(r.attributes))
+	for key := range r.attributes {
+		if key != overflowKey {
+			keys = append(keys, key)
+		}
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		a, b := r.attributes[keys[i]], r.attributes[keys[j]]
+		if adjusted && a.adjustedCount != b.adjustedCount {
+			return a.adjustedCount > b.adjustedCount
+		}
+		if !adjusted && a.count != b.count {
+			return a.count > b.count
+		}
+		// Break ties consistently
+		return keys[i] < keys[j]
+	})
+
+	top := make([]*AttributeCounter, 0, n)
+	var other *AttributeCounter
+	if overflow, ok := r.attributes[overflowKey]; ok {
+		other = NewAttributeCounter(overflowValues())
+		other.merge(overflow)
+	}
+
+	for i, key := range keys {
+		if i < n {
+			top = append(top, r.attributes[key])
+			continue
+		}
+
+		if other == nil {
+			other = NewAttributeCounter(overflowValues())
+		}
+		other.merge(r.attributes[key])
+	}
+
+	return top, other
+}
+
 // AttributeCounter dimensions the counter by attributes.
 type AttributeCounter struct {
-	values map[string]any
-	count  int
+	values        map[string]any
+	count         int
+	adjustedCount float64
+	exemplars     *ExemplarReservoir
 }
 
 // NewAttributeCounter creates a new AttributeCounter.
 func NewAttributeCounter(values map[string]any) *AttributeCounter {
 	return &AttributeCounter{
-		values: values,
+		values:    values,
+		exemplars: NewExemplarReservoir(0),
 	}
 }
 
 // Add increments the counter.
 func (a *AttributeCounter) Add() {
+	a.AddAdjusted(1)
+}
+
+// AddAdjusted increments the counter, adding the adjusted count to the adjusted total.
+func (a *AttributeCounter) AddAdjusted(adjustedCount float64) {
 	a.count++
+	a.adjustedCount += adjustedCount
+}
+
+// merge adds the counts of the other attribute counter, and offers its exemplars.
+func (a *AttributeCounter) merge(other *AttributeCounter) {
+	a.count += other.count
+	a.adjustedCount += other.adjustedCount
+	if a.exemplars.limit < other.exemplars.limit {
+		a.exemplars.limit = other.exemplars.limit
+	}
+	for _, exemplar := range other.exemplars.exemplars {
+		a.exemplars.Offer(exemplar)
+	}
 }
 
 // Count returns the number of counts for this attribute counter.
@@ -106,11 +268,63 @@ func (a AttributeCounter) Count() int {
 	return a.count
 }
 
+// AdjustedCount returns the sum of adjusted counts for this attribute counter.
+// This estimates the count before sampling when counted telemetry was annotated with its adjusted count.
+func (a AttributeCounter) AdjustedCount() float64 {
+	return a.adjustedCount
+}
+
 // Values returns the attribute map that this counter tracks.
 func (a AttributeCounter) Values() map[string]any {
 	return a.values
 }
 
+// Exemplars returns the exemplars sampled from the telemetry counted by this attribute counter.
+func (a AttributeCounter) Exemplars() []Exemplar {
+	return a.exemplars.Exemplars()
+}
+
+// AdjustedCount returns the number of items represented by an item with the raw adjusted count value.
+// Missing or invalid values represent a single item.
+func AdjustedCount(value any) float64 {
+	switch v := value.(type) {
+	case float64:
+		if v > 0 {
+			return v
+		}
+	case int64:
+		if v > 0 {
+			return float64(v)
+		}
+	}
+
+	return 1
+}
+
+// AttributeAdjustedCount returns the adjusted count held by the key attribute of attrs, which is either a pcommon.Map or a raw attribute map.
+// Telemetry represents a single item if key is empty or the attribute is missing.
+func AttributeAdjustedCount(attrs any, key string) float64 {
+	if key == "" {
+		return 1
+	}
+
+	switch a := attrs.(type) {
+	case pcommon.Map:
+		if value, ok := a.Get(key); ok {
+			return AdjustedCount(value.AsRaw())
+		}
+	case map[string]any:
+		return AdjustedCount(a[key])
+	}
+
+	return 1
+}
+
+// overflowValues returns the values of an overflow resource or attribute set.
+func overflowValues() map[string]any {
+	return map[string]any{OverflowAttribute: true}
+}
+
 // getDimensionKey returns a unique key for the dimension.
 func getDimensionKey(dimension map[string]any) string {
 	dimensionJSON, _ := json.Marshal(dimension)
@@ -18,6 +18,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
 )
 
 func TestLogCounter(t *testing.T) {
@@ -49,3 +50,176 @@ func TestLogCounter(t *testing.T) {
 	counter.Reset()
 	require.Len(t, counter.resources, 0)
 }
+
+func TestLogCounterAdjusted(t *testing.T) {
+	counter := NewTelemetryCounter()
+	resourceMap := map[string]any{"resource1": "value1"}
+	attrMap := map[string]any{"attr1": "value1"}
+
+	counter.Add(resourceMap, attrMap)
+	counter.AddAdjusted(resourceMap, attrMap, 10)
+	counter.AddAdjusted(resourceMap, attrMap, 2.5)
+
+	attrCounter := counter.resources[getDimensionKey(resourceMap)].attributes[getDimensionKey(attrMap)]
+	require.Equal(t, 3, attrCounter.Count())
+	require.Equal(t, 13.5, attrCounter.AdjustedCount())
+}
+
+func TestAdjustedCount(t *testing.T) {
+	require.Equal(t, 10.0, AdjustedCount(10.0))
+	require.Equal(t, 4.0, AdjustedCount(int64(4)))
+	require.Equal(t, 1.0, AdjustedCount(nil))
+	require.Equal(t, 1.0, AdjustedCount("10"))
+	require.Equal(t, 1.0, AdjustedCount(-2.0))
+}
+
+func TestAttributeAdjustedCount(t *testing.T) {
+	attrs := pcommon.NewMap()
+	attrs.PutDouble("adjusted", 4.0)
+	require.Equal(t, 4.0, AttributeAdjustedCount(attrs, "adjusted"))
+	require.Equal(t, 1.0, AttributeAdjustedCount(attrs, "missing"))
+	require.Equal(t, 1.0, AttributeAdjustedCount(attrs, ""))
+
+	raw := map[string]any{"adjusted": int64(3)}
+	require.Equal(t, 3.0, AttributeAdjustedCount(raw, "adjusted"))
+	require.Equal(t, 1.0, AttributeAdjustedCount(raw, "missing"))
+	require.Equal(t, 1.0, AttributeAdjustedCount(nil, "adjusted"))
+}
+
+func TestBoundedTelemetryCounter(t *testing.T) {
+	counter := NewBoundedTelemetryCounter(2)
+	resourceMap1 := map[string]any{"resource1": "value1"}
+	resourceMap2 := map[string]any{"resource2": "value2"}
+	attrMap1 := map[string]any{"attr1": "value1"}
+	attrMap2 := map[string]any{"attr2": "value2"}
+	attrMap3 := map[string]any{"attr3": "value3"}
+	overflowKey := getDimensionKey(overflowValues())
+
+	counter.Add(resourceMap1, attrMap1)
+	counter.Add(resourceMap1, attrMap2)
+	counter.Add(resourceMap1, attrMap3)
+	counter.AddAdjusted(resourceMap1, attrMap3, 4)
+	// Existing attribute sets are still counted after the limit is reached
+	counter.Add(resourceMap1, attrMap1)
+	counter.Add(resourceMap2, attrMap1)
+
+	resource1 := counter.resources[getDimensionKey(resourceMap1)]
+	require.Len(t, resource1.attributes, 3)
+	require.Equal(t, 2, resource1.attributes[getDimensionKey(attrMap1)].Count())
+	require.Equal(t, 2, resource1.attributes[overflowKey].Count())
+	require.Equal(t, 5.0, resource1.attributes[overflowKey].AdjustedCount())
+	require.Equal(t, map[string]any{OverflowAttribute: true}, resource1.attributes[overflowKey].Values())
+
+	// New resources are counted in the overflow resource
+	require.NotContains(t, counter.resources, getDimensionKey(resourceMap2))
+	overflowResource := counter.resources[overflowKey]
+	require.Equal(t, 1, overflowResource.attributes[overflowKey].Count())
+	require.Equal(t, 4, counter.Len())
+
+	counter.Reset()
+	require.Zero(t, counter.Len())
+	counter.Add(resourceMap2, attrMap1)
+	require.Contains(t, counter.resources, getDimensionKey(resourceMap2))
+}
+
+func TestTelemetryCounterMerge(t *testing.T) {
+	resourceMap := map[string]any{"resource1": "value1"}
+	attrMap1 := map[string]any{"attr1": "value1"}
+	attrMap2 := map[string]any{"attr2": "value2"}
+
+	counter1 := NewTelemetryCounter()
+	counter1.Add(resourceMap, attrMap1)
+	counter1.AddAdjusted(resourceMap, attrMap2, 3)
+	counter2 := NewTelemetryCounter()
+	counter2.Add(resourceMap, attrMap1)
+
+	merged := NewBoundedTelemetryCounter(1)
+	merged.Merge(counter1)
+	merged.Merge(counter2)
+
+	resource := merged.resources[getDimensionKey(resourceMap)]
+	require.Equal(t, 2, resource.attributes[getDimensionKey(attrMap1)].Count())
+	require.Equal(t, 1, resource.attributes[getDimensionKey(overflowValues())].Count())
+	require.Equal(t, 3.0, resource.attributes[getDimensionKey(overflowValues())].AdjustedCount())
+}
+
+func TestResourceCounterTopAttributes(t *testing.T) {
+	counter := NewBoundedTelemetryCounter(4)
+	resourceMap := map[string]any{"resource1": "value1"}
+	for i, n := range []int{5, 1, 3, 3} {
+		for j := 0; j < n; j++ {
+			counter.AddAdjusted(resourceMap, map[string]any{"attr": i}, float64(10-i))
+		}
+	}
+	// Overflow counts are always part of the other attribute set
+	for j := 0; j < 10; j++ {
+		counter.Add(resourceMap, map[string]any{"attr": "overflow"})
+	}
+	resource := counter.resources[getDimensionKey(resourceMap)]
+
+	top, other := resource.TopAttributes(2, false)
+	require.Len(t, top, 2)
+	require.Equal(t, map[string]any{"attr": 0}, top[0].Values())
+	require.Equal(t, 5, top[0].Count())
+	require.Equal(t, map[string]any{"attr": 2}, top[1].Values())
+	require.Equal(t, map[string]any{OverflowAttribute: true}, other.Values())
+	require.Equal(t, 14, other.Count())
+
+	top, other = resource.TopAttributes(1, true)
+	require.Len(t, top, 1)
+	require.Equal(t, 50.0, top[0].AdjustedCount())
+	require.Equal(t, 9+24+21+10.0, other.AdjustedCount())
+
+	top, other = resource.TopAttributes(5, false)
+	require.Len(t, top, 4)
+	require.Equal(t, 10, other.Count())
+
+	counter.Reset()
+	counter.Add(resourceMap, map[string]any{"attr": 0})
+	_, other = counter.resources[getDimensionKey(resourceMap)].TopAttributes(1, false)
+	require.Nil(t, other)
+}
+
+func TestCounterExemplars(t *testing.T) {
+	counter := NewTelemetryCounter()
+	counter.SetExemplarLimit(2)
+	resourceMap := map[string]any{"resource1": "value1"}
+	attrMap := map[string]any{"attr1": "value1"}
+
+	exemplar := func(i byte) Exemplar {
+		return Exemplar{SpanID: [8]byte{i}, Value: 1}
+	}
+
+	counter.AddExemplar(resourceMap, attrMap, 1, exemplar(1))
+	counter.AddExemplar(resourceMap, attrMap, 1, exemplar(2))
+
+	attrs := counter.resources[getDimensionKey(resourceMap)].attributes[getDimensionKey(attrMap)]
+	require.Equal(t, 2, attrs.Count())
+	require.Equal(t, []Exemplar{exemplar(1), exemplar(2)}, attrs.Exemplars())
+
+	// The reservoir never holds more than the limit
+	for i := 3; i < 100; i++ {
+		counter.AddExemplar(resourceMap, attrMap, 1, exemplar(byte(i)))
+	}
+	require.Equal(t, 99, attrs.Count())
+	require.Len(t, attrs.Exemplars(), 2)
+
+	// Counts added without exemplars don't sample any
+	counter.Add(resourceMap, map[string]any{"attr2": "value2"})
+	require.Empty(t, counter.resources[getDimensionKey(resourceMap)].attributes[getDimensionKey(map[string]any{"attr2": "value2"})].Exemplars())
+}
+
+func TestTopAttributesExemplars(t *testing.T) {
+	counter := NewTelemetryCounter()
+	counter.SetExemplarLimit(1)
+	resourceMap := map[string]any{"resource1": "value1"}
+
+	counter.AddExemplar(resourceMap, map[string]any{"attr": "a"}, 1, Exemplar{SpanID: [8]byte{1}})
+	counter.AddExemplar(resourceMap, map[string]any{"attr": "a"}, 1, Exemplar{SpanID: [8]byte{1}})
+	counter.AddExemplar(resourceMap, map[string]any{"attr": "b"}, 1, Exemplar{SpanID: [8]byte{2}})
+
+	top, other := counter.resources[getDimensionKey(resourceMap)].TopAttributes(1, false)
+	require.Len(t, top, 1)
+	require.Equal(t, [8]byte{1}, top[0].Exemplars()[0].SpanID)
+	require.Equal(t, []Exemplar{{SpanID: [8]byte{2}}}, other.Exemplars())
+}
@@ -0,0 +1,135 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package counter
+
+import (
+	"errors"
+	"math/rand"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+)
+
+// DefaultMaxExemplars is the default maximum number of exemplars per datapoint.
+const DefaultMaxExemplars = 1
+
+// ExemplarConfig is the config of exemplars sampled from telemetry and attached to the datapoints created from it.
+type ExemplarConfig struct {
+	// Enabled enables sampling exemplars.
+	Enabled bool `mapstructure:"enabled"`
+
+	// MaxPerDatapoint is the maximum number of exemplars attached to each datapoint.
+	MaxPerDatapoint int `mapstructure:"max_per_datapoint"`
+
+	// Attributes are the telemetry attributes added to each exemplar.
+	Attributes []string `mapstructure:"attributes"`
+}
+
+// Validate validates the exemplar config.
+func (c ExemplarConfig) Validate() error {
+	if c.Enabled && c.MaxPerDatapoint < 1 {
+		return errors.New("exemplars.max_per_datapoint must be positive")
+	}
+	return nil
+}
+
+// Limit returns the maximum number of exemplars per datapoint, or 0 if exemplars are disabled.
+func (c ExemplarConfig) Limit() int {
+	if !c.Enabled {
+		return 0
+	}
+	return c.MaxPerDatapoint
+}
+
+// NewExemplar creates an exemplar of telemetry with the value, keeping the configured attributes of attrs.
+func (c ExemplarConfig) NewExemplar(timestamp pcommon.Timestamp, traceID pcommon.TraceID, spanID pcommon.SpanID, attrs pcommon.Map, value float64) Exemplar {
+	values := make(map[string]any, len(c.Attributes))
+	for _, key := range c.Attributes {
+		if v, ok := attrs.Get(key); ok {
+			values[key] = v.AsRaw()
+		}
+	}
+
+	return Exemplar{
+		Timestamp:  timestamp.AsTime(),
+		TraceID:    traceID,
+		SpanID:     spanID,
+		Value:      value,
+		Attributes: values,
+	}
+}
+
+// Exemplar is a sample of the telemetry counted by an attribute counter.
+type Exemplar struct {
+	// Timestamp is the time of the sampled telemetry.
+	Timestamp time.Time
+	// TraceID is the trace ID of the sampled telemetry, or all zeros if not present.
+	TraceID [16]byte
+	// SpanID is the span ID of the sampled telemetry, or all zeros if not present.
+	SpanID [8]byte
+	// Value is the count the sampled telemetry contributed.
+	Value float64
+	// Attributes are attributes of the sampled telemetry that are not attributes of the counter.
+	Attributes map[string]any
+}
+
+// CopyTo copies the exemplar to dest. The value is set as an int if intValue is true, otherwise as a double.
+func (e Exemplar) CopyTo(dest pmetric.Exemplar, intValue bool) error {
+	dest.SetTimestamp(pcommon.NewTimestampFromTime(e.Timestamp))
+	dest.SetTraceID(e.TraceID)
+	dest.SetSpanID(e.SpanID)
+	if intValue {
+		dest.SetIntValue(int64(e.Value))
+	} else {
+		dest.SetDoubleValue(e.Value)
+	}
+	return dest.FilteredAttributes().FromRaw(e.Attributes)
+}
+
+// ExemplarReservoir keeps a uniform random sample of the exemplars offered to it.
+type ExemplarReservoir struct {
+	limit     int
+	offered   int
+	exemplars []Exemplar
+}
+
+// NewExemplarReservoir creates a reservoir keeping up to limit exemplars.
+func NewExemplarReservoir(limit int) *ExemplarReservoir {
+	return &ExemplarReservoir{limit: limit}
+}
+
+// Exemplars returns the exemplars kept by the reservoir.
+func (r *ExemplarReservoir) Exemplars() []Exemplar {
+	return r.exemplars
+}
+
+// Offer samples the exemplar, replacing a kept exemplar at random once the reservoir is full.
+func (r *ExemplarReservoir) Offer(exemplar Exemplar) {
+	if r.limit <= 0 {
+		return
+	}
+
+	r.offered++
+	if len(r.exemplars) < r.limit {
+		r.exemplars = append(r.exemplars, exemplar)
+		return
+	}
+
+	//#nosec G404 -- randomly generated number is not used for security purposes. It's ok if it's weak
+	if i := rand.Intn(r.offered); i < r.limit {
+		r.exemplars[i] = exemplar
+	}
+}
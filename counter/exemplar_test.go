@@ -0,0 +1,86 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package counter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+)
+
+func TestExemplarConfigValidate(t *testing.T) {
+	require.NoError(t, ExemplarConfig{}.Validate())
+	require.NoError(t, ExemplarConfig{Enabled: true, MaxPerDatapoint: 1}.Validate())
+	require.EqualError(t, ExemplarConfig{Enabled: true}.Validate(), "exemplars.max_per_datapoint must be positive")
+}
+
+func TestExemplarConfigLimit(t *testing.T) {
+	require.Equal(t, 0, ExemplarConfig{MaxPerDatapoint: 3}.Limit())
+	require.Equal(t, 3, ExemplarConfig{Enabled: true, MaxPerDatapoint: 3}.Limit())
+}
+
+func TestNewExemplar(t *testing.T) {
+	attrs := pcommon.NewMap()
+	attrs.PutStr("user", "jdoe")
+	attrs.PutStr("other", "value")
+
+	cfg := ExemplarConfig{Enabled: true, MaxPerDatapoint: 1, Attributes: []string{"user", "missing"}}
+	timestamp := pcommon.NewTimestampFromTime(time.Unix(100, 0))
+	exemplar := cfg.NewExemplar(timestamp, pcommon.TraceID{1}, pcommon.SpanID{2}, attrs, 2.5)
+
+	require.Equal(t, Exemplar{
+		Timestamp:  time.Unix(100, 0).UTC(),
+		TraceID:    [16]byte{1},
+		SpanID:     [8]byte{2},
+		Value:      2.5,
+		Attributes: map[string]any{"user": "jdoe"},
+	}, exemplar)
+}
+
+func TestExemplarCopyTo(t *testing.T) {
+	exemplar := Exemplar{
+		Timestamp:  time.Unix(100, 0),
+		TraceID:    [16]byte{1},
+		SpanID:     [8]byte{2},
+		Value:      3,
+		Attributes: map[string]any{"user": "jdoe"},
+	}
+
+	dest := pmetric.NewExemplar()
+	require.NoError(t, exemplar.CopyTo(dest, true))
+	require.Equal(t, pcommon.NewTimestampFromTime(time.Unix(100, 0)), dest.Timestamp())
+	require.Equal(t, pcommon.TraceID{1}, dest.TraceID())
+	require.Equal(t, pcommon.SpanID{2}, dest.SpanID())
+	require.Equal(t, int64(3), dest.IntValue())
+	require.Equal(t, map[string]any{"user": "jdoe"}, dest.FilteredAttributes().AsRaw())
+
+	require.NoError(t, exemplar.CopyTo(dest, false))
+	require.Equal(t, 3.0, dest.DoubleValue())
+}
+
+func TestExemplarReservoir(t *testing.T) {
+	reservoir := NewExemplarReservoir(2)
+	for i := 0; i < 100; i++ {
+		reservoir.Offer(Exemplar{SpanID: [8]byte{byte(i)}})
+	}
+	require.Len(t, reservoir.Exemplars(), 2)
+
+	disabled := NewExemplarReservoir(0)
+	disabled.Offer(Exemplar{})
+	require.Empty(t, disabled.Exemplars())
+}
@@ -18,6 +18,7 @@ package factories
 import (
 	"fmt"
 
+	"github.com/observiq/bindplane-agent/internal/drain"
 	"github.com/observiq/bindplane-agent/internal/throughputwrapper"
 	"github.com/observiq/bindplane-agent/processor/throughputmeasurementprocessor"
 	"go.opentelemetry.io/collector/connector"
@@ -40,10 +41,6 @@ func RegisterComponentTelemetry() error {
 		return fmt.Errorf("failed to register throughput measurement processor telemetry: %w", err)
 	}
 
-	if err := throughputwrapper.RegisterMetricViews(); err != nil {
-		return fmt.Errorf("failed to register throughput wrapper telemetry: %w", err)
-	}
-
 	return nil
 }
 
@@ -64,12 +61,12 @@ func combineFactories(receivers []receiver.Factory, processors []processor.Facto
 		errs = append(errs, err)
 	}
 
-	processorMap, err := processor.MakeFactoryMap(processors...)
+	processorMap, err := processor.MakeFactoryMap(wrapProcessors(processors)...)
 	if err != nil {
 		errs = append(errs, err)
 	}
 
-	exporterMap, err := exporter.MakeFactoryMap(exporters...)
+	exporterMap, err := exporter.MakeFactoryMap(wrapExporters(exporters)...)
 	if err != nil {
 		errs = append(errs, err)
 	}
@@ -102,3 +99,23 @@ func wrapReceivers(receivers []receiver.Factory) []receiver.Factory {
 
 	return wrappedReceivers
 }
+
+func wrapProcessors(processors []processor.Factory) []processor.Factory {
+	wrappedProcessors := make([]processor.Factory, len(processors))
+
+	for i, proc := range processors {
+		wrappedProcessors[i] = drain.WrapProcessorFactory(proc)
+	}
+
+	return wrappedProcessors
+}
+
+func wrapExporters(exporters []exporter.Factory) []exporter.Factory {
+	wrappedExporters := make([]exporter.Factory, len(exporters))
+
+	for i, exp := range exporters {
+		wrappedExporters[i] = drain.WrapExporterFactory(exp)
+	}
+
+	return wrappedExporters
+}
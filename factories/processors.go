@@ -22,6 +22,7 @@ import (
 	"github.com/observiq/bindplane-agent/processor/maskprocessor"
 	"github.com/observiq/bindplane-agent/processor/metricextractprocessor"
 	"github.com/observiq/bindplane-agent/processor/metricstatsprocessor"
+	"github.com/observiq/bindplane-agent/processor/quotaprocessor"
 	"github.com/observiq/bindplane-agent/processor/removeemptyvaluesprocessor"
 	"github.com/observiq/bindplane-agent/processor/resourceattributetransposerprocessor"
 	"github.com/observiq/bindplane-agent/processor/samplingprocessor"
@@ -72,6 +73,7 @@ var defaultProcessors = []processor.Factory{
 	metricstatsprocessor.NewFactory(),
 	metricstransformprocessor.NewFactory(),
 	probabilisticsamplerprocessor.NewFactory(),
+	quotaprocessor.NewFactory(),
 	removeemptyvaluesprocessor.NewFactory(),
 	resourceattributetransposerprocessor.NewFactory(),
 	resourcedetectionprocessor.NewFactory(),
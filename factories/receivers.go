@@ -15,6 +15,7 @@
 package factories
 
 import (
+	"github.com/observiq/bindplane-agent/receiver/agentlogsreceiver"
 	"github.com/observiq/bindplane-agent/receiver/azureblobrehydrationreceiver"
 	"github.com/observiq/bindplane-agent/receiver/httpreceiver"
 	"github.com/observiq/bindplane-agent/receiver/m365receiver"
@@ -93,6 +94,7 @@ import (
 var defaultReceivers = []receiver.Factory{
 	activedirectorydsreceiver.NewFactory(),
 	aerospikereceiver.NewFactory(),
+	agentlogsreceiver.NewFactory(),
 	apachereceiver.NewFactory(),
 	apachesparkreceiver.NewFactory(),
 	awscloudwatchreceiver.NewFactory(),
@@ -16,11 +16,13 @@ require (
 	github.com/observiq/bindplane-agent/processor/maskprocessor v1.41.0
 	github.com/observiq/bindplane-agent/processor/metricextractprocessor v1.41.0
 	github.com/observiq/bindplane-agent/processor/metricstatsprocessor v1.41.0
+	github.com/observiq/bindplane-agent/processor/quotaprocessor v1.41.0
 	github.com/observiq/bindplane-agent/processor/removeemptyvaluesprocessor v1.41.0
 	github.com/observiq/bindplane-agent/processor/resourceattributetransposerprocessor v1.41.0
 	github.com/observiq/bindplane-agent/processor/samplingprocessor v1.41.0
 	github.com/observiq/bindplane-agent/processor/spancountprocessor v1.41.0
 	github.com/observiq/bindplane-agent/processor/throughputmeasurementprocessor v1.41.0
+	github.com/observiq/bindplane-agent/receiver/agentlogsreceiver v1.41.0
 	github.com/observiq/bindplane-agent/receiver/azureblobrehydrationreceiver v1.41.0
 	github.com/observiq/bindplane-agent/receiver/httpreceiver v1.41.0
 	github.com/observiq/bindplane-agent/receiver/m365receiver v1.41.0
@@ -155,7 +157,6 @@ require (
 	github.com/shirou/gopsutil/v3 v3.23.11
 	github.com/spf13/pflag v1.0.5
 	github.com/stretchr/testify v1.8.4
-	go.opencensus.io v0.24.0
 	go.opentelemetry.io/collector/component v0.91.0
 	go.opentelemetry.io/collector/confmap v0.91.0
 	go.opentelemetry.io/collector/connector v0.91.0
@@ -176,6 +177,9 @@ require (
 	go.opentelemetry.io/collector/processor/memorylimiterprocessor v0.91.0
 	go.opentelemetry.io/collector/receiver v0.91.0
 	go.opentelemetry.io/collector/receiver/otlpreceiver v0.91.0
+	go.opentelemetry.io/otel v1.21.0
+	go.opentelemetry.io/otel/metric v1.21.0
+	go.opentelemetry.io/otel/sdk/metric v1.21.0
 	go.uber.org/multierr v1.11.0
 	go.uber.org/zap v1.26.0
 	golang.org/x/sys v0.15.0
@@ -607,16 +611,14 @@ require (
 	go.etcd.io/bbolt v1.3.8 // indirect
 	go.mongodb.org/atlas v0.35.0 // indirect
 	go.mongodb.org/mongo-driver v1.13.0 // indirect
+	go.opencensus.io v0.24.0 // indirect
 	go.opentelemetry.io/collector/semconv v0.91.0 // indirect; indir7.0
 	go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc v0.46.1 // indirect
 	go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp v0.46.1 // indirect
 	go.opentelemetry.io/contrib/propagators/b3 v1.21.1 // indirect
 	go.opentelemetry.io/contrib/zpages v0.46.1 // indirect
-	go.opentelemetry.io/otel v1.21.0 // indirect
 	go.opentelemetry.io/otel/exporters/prometheus v0.44.1-0.20231201153405-6027c1ae76f2 // indirect
-	go.opentelemetry.io/otel/metric v1.21.0 // indirect
 	go.opentelemetry.io/otel/sdk v1.21.0 // indirect
-	go.opentelemetry.io/otel/sdk/metric v1.21.0 // indirect
 	go.opentelemetry.io/otel/trace v1.21.0 // indirect
 	go.uber.org/atomic v1.11.0 // indirect
 	golang.org/x/crypto v0.16.0 // indirect
@@ -673,6 +675,8 @@ replace github.com/observiq/bindplane-agent/packagestate => ./packagestate
 
 replace github.com/observiq/bindplane-agent/processor/metricstatsprocessor => ./processor/metricstatsprocessor
 
+replace github.com/observiq/bindplane-agent/processor/quotaprocessor => ./processor/quotaprocessor
+
 replace github.com/observiq/bindplane-agent/processor/removeemptyvaluesprocessor => ./processor/removeemptyvaluesprocessor
 
 replace github.com/observiq/bindplane-agent/processor/throughputmeasurementprocessor => ./processor/throughputmeasurementprocessor
@@ -701,6 +705,8 @@ replace github.com/observiq/bindplane-agent/receiver/azureblobrehydrationreceive
 
 replace github.com/observiq/bindplane-agent/receiver/httpreceiver => ./receiver/httpreceiver
 
+replace github.com/observiq/bindplane-agent/receiver/agentlogsreceiver => ./receiver/agentlogsreceiver
+
 // Does not build with windows and only used in configschema executable
 // Relevant issue https://github.com/mattn/go-ieproxy/issues/45
 replace github.com/mattn/go-ieproxy => github.com/mattn/go-ieproxy v0.0.1
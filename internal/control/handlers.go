@@ -0,0 +1,512 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package control
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/observiq/bindplane-agent/collector"
+	"github.com/observiq/bindplane-agent/internal/logging"
+	"github.com/observiq/bindplane-agent/internal/reload"
+	"github.com/observiq/bindplane-agent/internal/report"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"gopkg.in/yaml.v3"
+)
+
+// maxBodySize is the largest request body accepted by the API
+const maxBodySize = 10 << 20
+
+// routes returns the handler for all API routes
+func (s *Server) routes() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/config", s.handleConfig)
+	mux.HandleFunc("/v1/config/validate", s.handleValidateConfig)
+	mux.HandleFunc("/v1/logging/level", s.handleLogLevel)
+	mux.HandleFunc("/v1/snapshots", s.handleSnapshots)
+	mux.HandleFunc("/v1/health", s.handleHealth)
+	return s.authorize(mux)
+}
+
+// authorize rejects requests that aren't addressed to localhost or don't have the configured bearer token.
+// Checking the host prevents web pages from reaching the API through DNS rebinding.
+func (s *Server) authorize(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !isLocalHost(r.Host) {
+			writeError(w, http.StatusForbidden, fmt.Errorf("host %q is not allowed", r.Host))
+			return
+		}
+
+		if s.settings.Token != "" {
+			token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(s.settings.Token)) != 1 {
+				w.Header().Set("WWW-Authenticate", "Bearer")
+				writeError(w, http.StatusUnauthorized, errors.New("invalid or missing bearer token"))
+				return
+			}
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// isLocalHost returns true if the request host, with or without a port, is localhost or a loopback address
+func isLocalHost(host string) bool {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+
+	if host == "localhost" {
+		return true
+	}
+
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// handleConfig returns the collector config on GET, and validates and applies a new collector config on PUT
+func (s *Server) handleConfig(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		contents, err := os.ReadFile(filepath.Clean(s.settings.CollectorConfigPath))
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, fmt.Errorf("failed to read collector config: %w", err))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/yaml")
+		_, _ = w.Write(contents)
+	case http.MethodPut:
+		contents, ok := readBody(w, r)
+		if !ok {
+			return
+		}
+
+		s.configMutex.Lock()
+		defer s.configMutex.Unlock()
+
+		if err := s.validateConfig(r, contents); err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid collector config: %w", err))
+			return
+		}
+
+		configName := filepath.Base(s.settings.CollectorConfigPath)
+		if err := reload.CollectorConfig(s.logger, s.col, configName, s.settings.CollectorConfigPath, contents); err != nil {
+			s.logger.Error("Failed to apply collector config", zap.Error(err))
+			writeError(w, http.StatusInternalServerError, fmt.Errorf("failed to apply collector config, previous config restored: %w", err))
+			return
+		}
+
+		s.logger.Info("Applied new collector config")
+		writeJSON(w, http.StatusOK, map[string]any{"applied": true})
+	default:
+		writeMethodNotAllowed(w, http.MethodGet, http.MethodPut)
+	}
+}
+
+// handleValidateConfig validates a collector config without applying it
+func (s *Server) handleValidateConfig(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeMethodNotAllowed(w, http.MethodPost)
+		return
+	}
+
+	contents, ok := readBody(w, r)
+	if !ok {
+		return
+	}
+
+	if err := s.validateConfig(r, contents); err != nil {
+		writeJSON(w, http.StatusOK, map[string]any{"valid": false, "error": err.Error()})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{"valid": true})
+}
+
+// validateConfig validates the collector config contents by writing them to a temporary file
+func (s *Server) validateConfig(r *http.Request, contents []byte) error {
+	f, err := os.CreateTemp("", "collector-config-*.yaml")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary config: %w", err)
+	}
+	defer func() {
+		_ = os.Remove(f.Name())
+	}()
+
+	_, err = f.Write(contents)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write temporary config: %w", err)
+	}
+
+	return s.validate(r.Context(), []string{f.Name()})
+}
+
+// logLevelBody is the request and response body of the log level endpoint
+type logLevelBody struct {
+	Level string `json:"level"`
+
+	// Components are the level overrides of individual components.
+	// In a request, omitting them leaves the existing overrides as is, and an empty map removes them.
+	Components map[string]string `json:"components,omitempty"`
+}
+
+// newLogLevelBody returns the body describing the levels of the logging config
+func newLogLevelBody(l *logging.LoggerConfig) logLevelBody {
+	body := logLevelBody{Level: l.Level.String()}
+	if len(l.Components) > 0 {
+		body.Components = make(map[string]string, len(l.Components))
+		for component, level := range l.Components {
+			body.Components[component] = level.String()
+		}
+	}
+	return body
+}
+
+// handleLogLevel returns the configured log levels on GET, and changes them on PUT
+func (s *Server) handleLogLevel(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		l, err := logging.NewLoggerConfig(s.settings.LoggingConfigPath)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+
+		writeJSON(w, http.StatusOK, newLogLevelBody(l))
+	case http.MethodPut:
+		var body logLevelBody
+		if !decodeJSON(w, r, &body) {
+			return
+		}
+
+		level, err := zapcore.ParseLevel(body.Level)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		var components map[string]zapcore.Level
+		if body.Components != nil {
+			components = make(map[string]zapcore.Level, len(body.Components))
+			for component, componentLevel := range body.Components {
+				components[component], err = zapcore.ParseLevel(componentLevel)
+				if err != nil {
+					writeError(w, http.StatusBadRequest, fmt.Errorf("invalid level for component %s: %w", component, err))
+					return
+				}
+			}
+		}
+
+		if err := logging.ValidateComponentLevels(components); err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		s.configMutex.Lock()
+		defer s.configMutex.Unlock()
+
+		contents, err := os.ReadFile(filepath.Clean(s.settings.LoggingConfigPath))
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, fmt.Errorf("failed to read logging config: %w", err))
+			return
+		}
+
+		newContents, err := setLogLevels(contents, level, components)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+
+		configName := filepath.Base(s.settings.LoggingConfigPath)
+		if _, err := reload.LoggingConfig(s.logger, s.col, configName, s.settings.LoggingConfigPath, newContents); err != nil {
+			s.logger.Error("Failed to apply log level", zap.Error(err))
+			writeError(w, http.StatusInternalServerError, fmt.Errorf("failed to apply log level, previous config restored: %w", err))
+			return
+		}
+
+		l, err := logging.NewLoggerConfig(s.settings.LoggingConfigPath)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+
+		s.logger.Info("Changed log level", zap.Stringer("level", level))
+		writeJSON(w, http.StatusOK, newLogLevelBody(l))
+	default:
+		writeMethodNotAllowed(w, http.MethodGet, http.MethodPut)
+	}
+}
+
+// setLogLevels sets the level and component overrides in the logging config, keeping the rest of the file, including comments, as is.
+// If components is nil, the existing overrides are kept.
+func setLogLevels(contents []byte, level zapcore.Level, components map[string]zapcore.Level) ([]byte, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(contents, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse logging config: %w", err)
+	}
+
+	// An empty file has no document, so start a new one
+	if len(doc.Content) == 0 {
+		doc = yaml.Node{
+			Kind:    yaml.DocumentNode,
+			Content: []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}},
+		}
+	}
+
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return nil, errors.New("logging config is not a map")
+	}
+
+	setMapValue(root, "level", &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: level.String()})
+
+	if components != nil {
+		var componentsNode yaml.Node
+		if err := componentsNode.Encode(components); err != nil {
+			return nil, fmt.Errorf("failed to encode component levels: %w", err)
+		}
+		setMapValue(root, "components", &componentsNode)
+	}
+
+	return encodeYAML(&doc)
+}
+
+// setMapValue sets the value of the key in the mapping node, adding the key if it doesn't exist
+func setMapValue(mapping *yaml.Node, key string, value *yaml.Node) {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			mapping.Content[i+1] = value
+			return
+		}
+	}
+
+	mapping.Content = append(mapping.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, value)
+}
+
+// encodeYAML encodes the document with the two space indentation used by the config files
+func encodeYAML(doc *yaml.Node) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(doc); err != nil {
+		return nil, fmt.Errorf("failed to encode logging config: %w", err)
+	}
+
+	if err := enc.Close(); err != nil {
+		return nil, fmt.Errorf("failed to encode logging config: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// snapshotRequest is the request body to send a snapshot to an endpoint
+type snapshotRequest struct {
+	Processor    string           `json:"processor" yaml:"processor"`
+	PipelineType string           `json:"pipeline_type" yaml:"pipeline_type"`
+	Endpoint     snapshotEndpoint `json:"endpoint" yaml:"endpoint"`
+}
+
+// snapshotEndpoint is where a snapshot is sent
+type snapshotEndpoint struct {
+	URL     string              `json:"url" yaml:"url"`
+	Headers map[string][]string `json:"headers,omitempty" yaml:"headers,omitempty"`
+}
+
+// handleSnapshots returns the snapshot of a processor on GET, and sends the snapshot to an endpoint on POST
+func (s *Server) handleSnapshots(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		processor := r.URL.Query().Get("processor")
+		pipelineType := r.URL.Query().Get("pipeline_type")
+		if processor == "" {
+			writeError(w, http.StatusBadRequest, errors.New("processor must be specified"))
+			return
+		}
+
+		payload, err := report.GetSnapshotReporter().Snapshot(processor, pipelineType)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		snapshotJSON, err := snapshotToJSON(pipelineType, payload)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(snapshotJSON)
+	case http.MethodPost:
+		var req snapshotRequest
+		if !decodeJSON(w, r, &req) {
+			return
+		}
+
+		if req.Processor == "" || req.Endpoint.URL == "" {
+			writeError(w, http.StatusBadRequest, errors.New("processor and endpoint url must be specified"))
+			return
+		}
+
+		if !s.isSnapshotEndpoint(req.Endpoint.URL) {
+			writeError(w, http.StatusForbidden, fmt.Errorf("endpoint %q is not a configured snapshot endpoint", req.Endpoint.URL))
+			return
+		}
+
+		// Snapshots are sent through the report manager, the same as when requested by an OpAMP server
+		reportConfig, err := yaml.Marshal(map[string]any{"snapshot": req})
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+
+		if err := report.GetManager().ResetConfig(reportConfig); err != nil {
+			writeError(w, http.StatusBadGateway, err)
+			return
+		}
+
+		writeJSON(w, http.StatusOK, map[string]any{"sent": true})
+	default:
+		writeMethodNotAllowed(w, http.MethodGet, http.MethodPost)
+	}
+}
+
+// isSnapshotEndpoint returns true if snapshots may be sent to the URL
+func (s *Server) isSnapshotEndpoint(url string) bool {
+	for _, endpoint := range s.settings.SnapshotEndpoints {
+		if endpoint == url {
+			return true
+		}
+	}
+	return false
+}
+
+// snapshotToJSON converts a snapshot payload of OTLP protobuf to OTLP JSON
+func snapshotToJSON(pipelineType string, payload []byte) ([]byte, error) {
+	switch pipelineType {
+	case "logs":
+		logs, err := (&plog.ProtoUnmarshaler{}).UnmarshalLogs(payload)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read snapshot: %w", err)
+		}
+		return (&plog.JSONMarshaler{}).MarshalLogs(logs)
+	case "metrics":
+		metrics, err := (&pmetric.ProtoUnmarshaler{}).UnmarshalMetrics(payload)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read snapshot: %w", err)
+		}
+		return (&pmetric.JSONMarshaler{}).MarshalMetrics(metrics)
+	default:
+		traces, err := (&ptrace.ProtoUnmarshaler{}).UnmarshalTraces(payload)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read snapshot: %w", err)
+		}
+		return (&ptrace.JSONMarshaler{}).MarshalTraces(traces)
+	}
+}
+
+// healthResponse is the response body of the health endpoint
+type healthResponse struct {
+	Healthy    bool                        `json:"healthy"`
+	Components []collector.ComponentHealth `json:"components"`
+}
+
+// handleHealth returns the health of each component. The status code is 503 if any component is unhealthy.
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeMethodNotAllowed(w, http.MethodGet)
+		return
+	}
+
+	resp := healthResponse{
+		Healthy:    true,
+		Components: collector.GetComponentHealth(),
+	}
+	for _, component := range resp.Components {
+		if !component.Healthy {
+			resp.Healthy = false
+		}
+	}
+
+	status := http.StatusOK
+	if !resp.Healthy {
+		status = http.StatusServiceUnavailable
+	}
+
+	writeJSON(w, status, resp)
+}
+
+// readBody reads the request body, writing an error response if it can't be read
+func readBody(w http.ResponseWriter, r *http.Request) ([]byte, bool) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBodySize))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("failed to read request body: %w", err))
+		return nil, false
+	}
+
+	return body, true
+}
+
+// decodeJSON decodes the JSON request body into v, writing an error response if it can't be decoded
+func decodeJSON(w http.ResponseWriter, r *http.Request, v any) bool {
+	body, ok := readBody(w, r)
+	if !ok {
+		return false
+	}
+
+	if err := json.Unmarshal(body, v); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid request body: %w", err))
+		return false
+	}
+
+	return true
+}
+
+// writeJSON writes v as the JSON response body
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes the error as a JSON response body
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+// writeMethodNotAllowed writes a response for a request with a method the route doesn't support
+func writeMethodNotAllowed(w http.ResponseWriter, allowed ...string) {
+	for _, method := range allowed {
+		w.Header().Add("Allow", method)
+	}
+	writeError(w, http.StatusMethodNotAllowed, errors.New("method not allowed"))
+}
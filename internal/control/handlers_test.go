@@ -0,0 +1,362 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package control
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/observiq/bindplane-agent/collector/mocks"
+	"github.com/observiq/bindplane-agent/internal/report"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const testCollectorConfig = "receivers:\n  nop:\n"
+
+// testToken is the bearer token of test servers
+const testToken = "test-token"
+
+// newTestServer creates a server with config files in a temporary directory
+func newTestServer(t *testing.T, col *mocks.MockCollector) *Server {
+	dir := t.TempDir()
+	collectorConfigPath := filepath.Join(dir, "config.yaml")
+	loggingConfigPath := filepath.Join(dir, "logging.yaml")
+	require.NoError(t, os.WriteFile(collectorConfigPath, []byte(testCollectorConfig), 0600))
+	require.NoError(t, os.WriteFile(loggingConfigPath, []byte("# Log at info by default\noutput: stdout\nlevel: info\n"), 0600))
+
+	s, err := NewServer(zap.NewNop(), col, Settings{
+		Endpoint:            "localhost:0",
+		CollectorConfigPath: collectorConfigPath,
+		LoggingConfigPath:   loggingConfigPath,
+		Token:               testToken,
+	})
+	require.NoError(t, err)
+
+	s.validate = func(_ context.Context, configPaths []string) error {
+		contents, err := os.ReadFile(configPaths[0])
+		require.NoError(t, err)
+		if strings.Contains(string(contents), "invalid") {
+			return errors.New("config is invalid")
+		}
+		return nil
+	}
+
+	return s
+}
+
+func doRequest(s *Server, method, target, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, "http://localhost"+target, strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer "+testToken)
+
+	rec := httptest.NewRecorder()
+	s.routes().ServeHTTP(rec, req)
+	return rec
+}
+
+func TestAuthorize(t *testing.T) {
+	testCases := []struct {
+		name           string
+		host           string
+		authorization  string
+		expectedStatus int
+	}{
+		{
+			name:           "localhost with token",
+			host:           "localhost:8888",
+			authorization:  "Bearer " + testToken,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "loopback with token",
+			host:           "127.0.0.1:8888",
+			authorization:  "Bearer " + testToken,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "missing token",
+			host:           "localhost:8888",
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "wrong token",
+			host:           "localhost:8888",
+			authorization:  "Bearer wrong",
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "other host",
+			host:           "attacker.example.com:8888",
+			authorization:  "Bearer " + testToken,
+			expectedStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := newTestServer(t, mocks.NewMockCollector(t))
+
+			req := httptest.NewRequest(http.MethodGet, "/v1/health", nil)
+			req.Host = tc.host
+			if tc.authorization != "" {
+				req.Header.Set("Authorization", tc.authorization)
+			}
+
+			rec := httptest.NewRecorder()
+			s.routes().ServeHTTP(rec, req)
+			require.Equal(t, tc.expectedStatus, rec.Code)
+		})
+	}
+}
+
+func TestHandleConfig(t *testing.T) {
+	t.Run("Get config", func(t *testing.T) {
+		s := newTestServer(t, mocks.NewMockCollector(t))
+
+		rec := doRequest(s, http.MethodGet, "/v1/config", "")
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.Equal(t, "application/yaml", rec.Header().Get("Content-Type"))
+		require.Equal(t, testCollectorConfig, rec.Body.String())
+	})
+
+	t.Run("Apply config", func(t *testing.T) {
+		col := mocks.NewMockCollector(t)
+		col.On("Restart", mock.Anything).Return(nil)
+		s := newTestServer(t, col)
+
+		rec := doRequest(s, http.MethodPut, "/v1/config", "receivers:\n  otlp:\n")
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		contents, err := os.ReadFile(s.settings.CollectorConfigPath)
+		require.NoError(t, err)
+		require.Equal(t, "receivers:\n  otlp:\n", string(contents))
+	})
+
+	t.Run("Invalid config is not applied", func(t *testing.T) {
+		s := newTestServer(t, mocks.NewMockCollector(t))
+
+		rec := doRequest(s, http.MethodPut, "/v1/config", "invalid")
+		require.Equal(t, http.StatusBadRequest, rec.Code)
+		require.Contains(t, rec.Body.String(), "config is invalid")
+
+		contents, err := os.ReadFile(s.settings.CollectorConfigPath)
+		require.NoError(t, err)
+		require.Equal(t, testCollectorConfig, string(contents))
+	})
+
+	t.Run("Failed restart rolls back", func(t *testing.T) {
+		col := mocks.NewMockCollector(t)
+		col.On("Restart", mock.Anything).Return(errors.New("bad restart")).Once()
+		col.On("Restart", mock.Anything).Return(nil).Once()
+		s := newTestServer(t, col)
+
+		rec := doRequest(s, http.MethodPut, "/v1/config", "receivers:\n  otlp:\n")
+		require.Equal(t, http.StatusInternalServerError, rec.Code)
+		require.Contains(t, rec.Body.String(), "bad restart")
+
+		contents, err := os.ReadFile(s.settings.CollectorConfigPath)
+		require.NoError(t, err)
+		require.Equal(t, testCollectorConfig, string(contents))
+	})
+
+	t.Run("Method not allowed", func(t *testing.T) {
+		s := newTestServer(t, mocks.NewMockCollector(t))
+
+		rec := doRequest(s, http.MethodDelete, "/v1/config", "")
+		require.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+		require.Equal(t, []string{http.MethodGet, http.MethodPut}, rec.Header().Values("Allow"))
+	})
+}
+
+func TestHandleValidateConfig(t *testing.T) {
+	s := newTestServer(t, mocks.NewMockCollector(t))
+
+	rec := doRequest(s, http.MethodPost, "/v1/config/validate", "receivers:\n  otlp:\n")
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.JSONEq(t, `{"valid":true}`, rec.Body.String())
+
+	rec = doRequest(s, http.MethodPost, "/v1/config/validate", "invalid")
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.JSONEq(t, `{"valid":false,"error":"config is invalid"}`, rec.Body.String())
+}
+
+func TestHandleLogLevel(t *testing.T) {
+	t.Run("Get level", func(t *testing.T) {
+		s := newTestServer(t, mocks.NewMockCollector(t))
+
+		rec := doRequest(s, http.MethodGet, "/v1/logging/level", "")
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.JSONEq(t, `{"level":"info"}`, rec.Body.String())
+	})
+
+	t.Run("Set level", func(t *testing.T) {
+		col := mocks.NewMockCollector(t)
+		// Only the level changes, so the collector isn't restarted
+		col.On("GetLoggingOpts").Return(nil)
+		s := newTestServer(t, col)
+
+		rec := doRequest(s, http.MethodPut, "/v1/logging/level", `{"level":"debug"}`)
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.JSONEq(t, `{"level":"debug"}`, rec.Body.String())
+
+		contents, err := os.ReadFile(s.settings.LoggingConfigPath)
+		require.NoError(t, err)
+		require.Equal(t, "# Log at info by default\noutput: stdout\nlevel: debug\n", string(contents))
+	})
+
+	t.Run("Set component levels", func(t *testing.T) {
+		col := mocks.NewMockCollector(t)
+		col.On("GetLoggingOpts").Return(nil)
+		s := newTestServer(t, col)
+
+		rec := doRequest(s, http.MethodPut, "/v1/logging/level", `{"level":"info","components":{"exporter/chronicle":"debug"}}`)
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.JSONEq(t, `{"level":"info","components":{"exporter/chronicle":"debug"}}`, rec.Body.String())
+
+		rec = doRequest(s, http.MethodGet, "/v1/logging/level", "")
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.JSONEq(t, `{"level":"info","components":{"exporter/chronicle":"debug"}}`, rec.Body.String())
+	})
+
+	t.Run("Invalid component", func(t *testing.T) {
+		s := newTestServer(t, mocks.NewMockCollector(t))
+
+		rec := doRequest(s, http.MethodPut, "/v1/logging/level", `{"level":"info","components":{"chronicle":"debug"}}`)
+		require.Equal(t, http.StatusBadRequest, rec.Code)
+		require.Contains(t, rec.Body.String(), "invalid component")
+	})
+
+	t.Run("Invalid level", func(t *testing.T) {
+		s := newTestServer(t, mocks.NewMockCollector(t))
+
+		rec := doRequest(s, http.MethodPut, "/v1/logging/level", `{"level":"loud"}`)
+		require.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
+
+func TestSetLogLevels(t *testing.T) {
+	contents, err := setLogLevels([]byte(""), zapcore.WarnLevel, nil)
+	require.NoError(t, err)
+	require.Equal(t, "level: warn\n", string(contents))
+
+	contents, err = setLogLevels([]byte("output: stdout\n"), zapcore.ErrorLevel, nil)
+	require.NoError(t, err)
+	require.Equal(t, "output: stdout\nlevel: error\n", string(contents))
+
+	contents, err = setLogLevels([]byte("level: info\noutput: file\nfile:\n  filename: agent.log\n"), zapcore.DebugLevel, nil)
+	require.NoError(t, err)
+	require.Equal(t, "level: debug\noutput: file\nfile:\n  filename: agent.log\n", string(contents))
+
+	contents, err = setLogLevels([]byte("level: info\ncomponents:\n  exporter/otlp: debug\n"), zapcore.InfoLevel, map[string]zapcore.Level{"receiver/filelog": zapcore.WarnLevel})
+	require.NoError(t, err)
+	require.Equal(t, "level: info\ncomponents:\n  receiver/filelog: warn\n", string(contents))
+
+	contents, err = setLogLevels([]byte("level: info\ncomponents:\n  exporter/otlp: debug\n"), zapcore.WarnLevel, nil)
+	require.NoError(t, err)
+	require.Equal(t, "level: warn\ncomponents:\n  exporter/otlp: debug\n", string(contents))
+
+	_, err = setLogLevels([]byte("- level"), zapcore.ErrorLevel, nil)
+	require.ErrorContains(t, err, "logging config is not a map")
+}
+
+func TestHandleSnapshots(t *testing.T) {
+	t.Run("Get snapshot", func(t *testing.T) {
+		s := newTestServer(t, mocks.NewMockCollector(t))
+
+		logs := plog.NewLogs()
+		logs.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords().AppendEmpty().Body().SetStr("hello")
+		report.GetSnapshotReporter().SaveLogs("snapshotprocessor/control", logs)
+		defer report.GetSnapshotReporter().Reset()
+
+		rec := doRequest(s, http.MethodGet, "/v1/snapshots?processor=snapshotprocessor/control&pipeline_type=logs", "")
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		snapshot, err := (&plog.JSONUnmarshaler{}).UnmarshalLogs(rec.Body.Bytes())
+		require.NoError(t, err)
+		require.Equal(t, 1, snapshot.LogRecordCount())
+	})
+
+	t.Run("Get snapshot invalid pipeline type", func(t *testing.T) {
+		s := newTestServer(t, mocks.NewMockCollector(t))
+
+		rec := doRequest(s, http.MethodGet, "/v1/snapshots?processor=snapshotprocessor/control&pipeline_type=profiles", "")
+		require.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("Send snapshot", func(t *testing.T) {
+		s := newTestServer(t, mocks.NewMockCollector(t))
+
+		received := make(chan http.Header, 1)
+		endpoint := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			received <- r.Header
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer endpoint.Close()
+		s.settings.SnapshotEndpoints = []string{endpoint.URL}
+
+		body, err := json.Marshal(snapshotRequest{
+			Processor:    "snapshotprocessor/control",
+			PipelineType: "logs",
+			Endpoint: snapshotEndpoint{
+				URL:     endpoint.URL,
+				Headers: map[string][]string{"Session-Id": {"1234"}},
+			},
+		})
+		require.NoError(t, err)
+
+		rec := doRequest(s, http.MethodPost, "/v1/snapshots", string(body))
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		headers := <-received
+		require.Equal(t, "1234", headers.Get("Session-Id"))
+		require.Equal(t, "snapshotprocessor/control", headers.Get("Component-ID"))
+	})
+
+	t.Run("Send snapshot unconfigured endpoint", func(t *testing.T) {
+		s := newTestServer(t, mocks.NewMockCollector(t))
+		s.settings.SnapshotEndpoints = []string{"https://example.com/snapshots"}
+
+		rec := doRequest(s, http.MethodPost, "/v1/snapshots", `{"processor":"snapshotprocessor/control","pipeline_type":"logs","endpoint":{"url":"https://attacker.example.com"}}`)
+		require.Equal(t, http.StatusForbidden, rec.Code)
+	})
+
+	t.Run("Send snapshot missing endpoint", func(t *testing.T) {
+		s := newTestServer(t, mocks.NewMockCollector(t))
+
+		rec := doRequest(s, http.MethodPost, "/v1/snapshots", `{"processor":"snapshotprocessor/control"}`)
+		require.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
+
+func TestHandleHealth(t *testing.T) {
+	s := newTestServer(t, mocks.NewMockCollector(t))
+
+	rec := doRequest(s, http.MethodGet, "/v1/health", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var resp healthResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	require.True(t, resp.Healthy)
+}
@@ -0,0 +1,176 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package control provides a local HTTP API for managing a standalone collector
+package control
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/observiq/bindplane-agent/collector"
+	"go.uber.org/zap"
+)
+
+// unixScheme is the prefix of endpoints that listen on a unix socket
+const unixScheme = "unix://"
+
+// Settings configures the control server
+type Settings struct {
+	// Endpoint is the localhost address or unix:// socket path the server listens on
+	Endpoint string
+
+	// CollectorConfigPath is the path of the collector config managed through the server
+	CollectorConfigPath string
+
+	// LoggingConfigPath is the path of the logging config managed through the server
+	LoggingConfigPath string
+
+	// Token is the bearer token required on every request. It must be set for TCP endpoints,
+	// since any local user can connect to a localhost port.
+	Token string
+
+	// SnapshotEndpoints are the URLs that snapshots may be sent to. Sending snapshots is disabled if empty.
+	SnapshotEndpoints []string
+}
+
+// Server serves the control API
+type Server struct {
+	logger   *zap.Logger
+	col      collector.Collector
+	settings Settings
+	network  string
+	address  string
+
+	// validate validates collector config files, and is replaced in tests
+	validate func(ctx context.Context, configPaths []string) error
+
+	// configMutex ensures only one change is applied to the collector at a time
+	configMutex sync.Mutex
+
+	server *http.Server
+	wg     sync.WaitGroup
+}
+
+// NewServer creates a new control server for the collector.
+// An error is returned if the endpoint is not a unix socket or a localhost address,
+// or if the endpoint is a localhost address and no token is set.
+func NewServer(logger *zap.Logger, col collector.Collector, settings Settings) (*Server, error) {
+	network, address, err := parseEndpoint(settings.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	if network == "tcp" && settings.Token == "" {
+		return nil, fmt.Errorf("a token is required to serve the control API on %q", settings.Endpoint)
+	}
+
+	s := &Server{
+		logger:   logger.Named("control"),
+		col:      col,
+		settings: settings,
+		network:  network,
+		address:  address,
+		validate: collector.ValidateConfig,
+	}
+
+	s.server = &http.Server{
+		Handler:           s.routes(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	return s, nil
+}
+
+// Start starts listening on the endpoint and serving requests
+func (s *Server) Start() error {
+	if s.network == "unix" {
+		// Remove a socket left behind by a previous run
+		if info, err := os.Stat(s.address); err == nil && info.Mode().Type() == fs.ModeSocket {
+			if err := os.Remove(s.address); err != nil {
+				return fmt.Errorf("failed to remove existing socket: %w", err)
+			}
+		}
+	}
+
+	listener, err := net.Listen(s.network, s.address)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", s.settings.Endpoint, err)
+	}
+
+	if s.network == "unix" {
+		// Only the user running the collector may use the socket
+		if err := os.Chmod(s.address, 0600); err != nil {
+			_ = listener.Close()
+			return fmt.Errorf("failed to set socket permissions: %w", err)
+		}
+	}
+
+	s.logger.Info("Control API listening", zap.String("endpoint", s.settings.Endpoint))
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		if err := s.server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			s.logger.Error("Control API stopped unexpectedly", zap.Error(err))
+		}
+	}()
+
+	return nil
+}
+
+// Stop stops the server, waiting for in progress requests to finish
+func (s *Server) Stop(ctx context.Context) error {
+	err := s.server.Shutdown(ctx)
+	s.wg.Wait()
+	if err != nil {
+		return fmt.Errorf("failed to shutdown control API: %w", err)
+	}
+
+	return nil
+}
+
+// parseEndpoint returns the network and address to listen on for the endpoint.
+// TCP endpoints must be bound to localhost, so the API is never exposed to the network.
+func parseEndpoint(endpoint string) (network, address string, err error) {
+	if strings.HasPrefix(endpoint, unixScheme) {
+		address = strings.TrimPrefix(endpoint, unixScheme)
+		if address == "" {
+			return "", "", errors.New("unix socket path must be specified")
+		}
+		return "unix", address, nil
+	}
+
+	host, _, err := net.SplitHostPort(endpoint)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid endpoint %q: %w", endpoint, err)
+	}
+
+	if host != "localhost" {
+		ip := net.ParseIP(host)
+		if ip == nil || !ip.IsLoopback() {
+			return "", "", fmt.Errorf("endpoint %q must be bound to localhost", endpoint)
+		}
+	}
+
+	return "tcp", endpoint, nil
+}
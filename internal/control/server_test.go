@@ -0,0 +1,137 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package control
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"path/filepath"
+	"testing"
+
+	"github.com/observiq/bindplane-agent/collector/mocks"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestParseEndpoint(t *testing.T) {
+	testCases := []struct {
+		name            string
+		endpoint        string
+		expectedNetwork string
+		expectedAddress string
+		expectedErr     string
+	}{
+		{
+			name:            "unix socket",
+			endpoint:        "unix:///var/run/agent.sock",
+			expectedNetwork: "unix",
+			expectedAddress: "/var/run/agent.sock",
+		},
+		{
+			name:        "unix socket missing path",
+			endpoint:    "unix://",
+			expectedErr: "unix socket path must be specified",
+		},
+		{
+			name:            "localhost",
+			endpoint:        "localhost:9999",
+			expectedNetwork: "tcp",
+			expectedAddress: "localhost:9999",
+		},
+		{
+			name:            "loopback IPv4",
+			endpoint:        "127.0.0.1:9999",
+			expectedNetwork: "tcp",
+			expectedAddress: "127.0.0.1:9999",
+		},
+		{
+			name:            "loopback IPv6",
+			endpoint:        "[::1]:9999",
+			expectedNetwork: "tcp",
+			expectedAddress: "[::1]:9999",
+		},
+		{
+			name:        "all interfaces",
+			endpoint:    "0.0.0.0:9999",
+			expectedErr: "must be bound to localhost",
+		},
+		{
+			name:        "missing host",
+			endpoint:    ":9999",
+			expectedErr: "must be bound to localhost",
+		},
+		{
+			name:        "hostname",
+			endpoint:    "example.com:9999",
+			expectedErr: "must be bound to localhost",
+		},
+		{
+			name:        "missing port",
+			endpoint:    "localhost",
+			expectedErr: "invalid endpoint",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			network, address, err := parseEndpoint(tc.endpoint)
+			if tc.expectedErr != "" {
+				require.ErrorContains(t, err, tc.expectedErr)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedNetwork, network)
+			require.Equal(t, tc.expectedAddress, address)
+		})
+	}
+}
+
+func TestNewServerInvalidEndpoint(t *testing.T) {
+	_, err := NewServer(zap.NewNop(), mocks.NewMockCollector(t), Settings{Endpoint: "0.0.0.0:9999"})
+	require.Error(t, err)
+}
+
+func TestNewServerTCPRequiresToken(t *testing.T) {
+	_, err := NewServer(zap.NewNop(), mocks.NewMockCollector(t), Settings{Endpoint: "localhost:9999"})
+	require.ErrorContains(t, err, "a token is required")
+
+	_, err = NewServer(zap.NewNop(), mocks.NewMockCollector(t), Settings{Endpoint: "localhost:9999", Token: testToken})
+	require.NoError(t, err)
+}
+
+func TestServerStartStopUnixSocket(t *testing.T) {
+	socketPath := filepath.Join(t.TempDir(), "control.sock")
+
+	s, err := NewServer(zap.NewNop(), mocks.NewMockCollector(t), Settings{Endpoint: unixScheme + socketPath})
+	require.NoError(t, err)
+	require.NoError(t, s.Start())
+
+	client := http.Client{
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				return (&net.Dialer{}).DialContext(ctx, "unix", socketPath)
+			},
+		},
+	}
+
+	resp, err := client.Get("http://localhost/v1/health")
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	require.NoError(t, s.Stop(context.Background()))
+}
@@ -0,0 +1,256 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package delta
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ManifestFileName is the name of the manifest file at the root of a delta archive
+const ManifestFileName = "manifest.json"
+
+// Actions that can be taken for a file in the manifest
+const (
+	// ActionPatch applies a patch against the installed file
+	ActionPatch = "patch"
+	// ActionAdd uses a full copy of the file included in the delta archive
+	ActionAdd = "add"
+	// ActionCopy copies the installed file unchanged
+	ActionCopy = "copy"
+)
+
+// Manifest describes how to build the files of TargetVersion from an installation of BaseVersion
+type Manifest struct {
+	// BaseVersion is the version the patches were created against
+	BaseVersion string `json:"base_version"`
+	// TargetVersion is the version produced by applying the patches
+	TargetVersion string `json:"target_version"`
+	// Files is the list of files in the target version
+	Files []ManifestFile `json:"files"`
+}
+
+// ManifestFile describes how to produce a single file of the target version
+type ManifestFile struct {
+	// Path is the path of the file, relative to the install directory
+	Path string `json:"path"`
+	// Action is one of "patch", "add" or "copy"
+	Action string `json:"action"`
+	// Source is the path, relative to the delta archive, of the patch for "patch" or the full file for "add"
+	Source string `json:"source,omitempty"`
+	// BaseSHA256 is the hex encoded sha256 of the installed file, for "patch" and "copy"
+	BaseSHA256 string `json:"base_sha256,omitempty"`
+	// SHA256 is the hex encoded sha256 of the resulting file
+	SHA256 string `json:"sha256"`
+	// Mode is the file mode of the resulting file
+	Mode os.FileMode `json:"mode"`
+}
+
+// LoadManifest reads the manifest from the root of the extracted delta archive at deltaDir
+func LoadManifest(deltaDir string) (*Manifest, error) {
+	data, err := os.ReadFile(filepath.Join(deltaDir, ManifestFileName))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest: %w", err)
+	}
+
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest: %w", err)
+	}
+
+	if err := m.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid manifest: %w", err)
+	}
+
+	return &m, nil
+}
+
+// Validate checks that the manifest is well formed
+func (m Manifest) Validate() error {
+	if m.BaseVersion == "" {
+		return errors.New("base_version must be specified")
+	}
+
+	if m.TargetVersion == "" {
+		return errors.New("target_version must be specified")
+	}
+
+	for _, f := range m.Files {
+		if err := validateRelPath(f.Path); err != nil {
+			return fmt.Errorf("file %q: %w", f.Path, err)
+		}
+
+		switch f.Action {
+		case ActionPatch, ActionAdd:
+			if err := validateRelPath(f.Source); err != nil {
+				return fmt.Errorf("file %q source: %w", f.Path, err)
+			}
+		case ActionCopy:
+		default:
+			return fmt.Errorf("file %q: unknown action %q", f.Path, f.Action)
+		}
+
+		if f.Action != ActionAdd && f.BaseSHA256 == "" {
+			return fmt.Errorf("file %q: base_sha256 must be specified", f.Path)
+		}
+
+		if f.SHA256 == "" {
+			return fmt.Errorf("file %q: sha256 must be specified", f.Path)
+		}
+	}
+
+	return nil
+}
+
+// Apply builds the files of the target version into outDir, using the installation at baseDir
+// and the extracted delta archive at deltaDir. The hash of every base and resulting file is verified.
+func (m Manifest) Apply(baseDir, deltaDir, outDir string) error {
+	for _, f := range m.Files {
+		if err := m.applyFile(f, baseDir, deltaDir, outDir); err != nil {
+			return fmt.Errorf("failed to build %s: %w", f.Path, err)
+		}
+	}
+
+	return nil
+}
+
+func (m Manifest) applyFile(f ManifestFile, baseDir, deltaDir, outDir string) error {
+	basePath := filepath.Join(baseDir, filepath.FromSlash(f.Path))
+	outPath := filepath.Join(outDir, filepath.FromSlash(f.Path))
+
+	if f.Action != ActionAdd {
+		if err := verifyFileHash(basePath, f.BaseSHA256); err != nil {
+			return fmt.Errorf("installed file does not match base: %w", err)
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(outPath), 0750); err != nil {
+		return fmt.Errorf("failed to create dir: %w", err)
+	}
+
+	mode := f.Mode.Perm()
+	if mode == 0 {
+		mode = 0600
+	}
+
+	out, err := os.OpenFile(filepath.Clean(outPath), os.O_CREATE|os.O_TRUNC|os.O_WRONLY, mode)
+	if err != nil {
+		return fmt.Errorf("failed to open output file: %w", err)
+	}
+
+	err = writeFile(out, f, basePath, deltaDir)
+	if closeErr := out.Close(); closeErr != nil && err == nil {
+		err = fmt.Errorf("failed to close output file: %w", closeErr)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if err := verifyFileHash(outPath, f.SHA256); err != nil {
+		return fmt.Errorf("resulting file is invalid: %w", err)
+	}
+
+	return nil
+}
+
+// writeFile writes the contents of f to out
+func writeFile(out io.Writer, f ManifestFile, basePath, deltaDir string) error {
+	switch f.Action {
+	case ActionPatch:
+		base, err := os.Open(filepath.Clean(basePath))
+		if err != nil {
+			return fmt.Errorf("failed to open installed file: %w", err)
+		}
+		defer base.Close()
+
+		patch, err := os.Open(filepath.Join(deltaDir, filepath.Clean(filepath.FromSlash(f.Source))))
+		if err != nil {
+			return fmt.Errorf("failed to open patch: %w", err)
+		}
+		defer patch.Close()
+
+		if err := Apply(base, patch, out); err != nil {
+			return fmt.Errorf("failed to apply patch: %w", err)
+		}
+	case ActionAdd:
+		return copyFrom(out, filepath.Join(deltaDir, filepath.FromSlash(f.Source)))
+	case ActionCopy:
+		return copyFrom(out, basePath)
+	}
+
+	return nil
+}
+
+func copyFrom(out io.Writer, inPath string) error {
+	in, err := os.Open(filepath.Clean(inPath))
+	if err != nil {
+		return fmt.Errorf("failed to open file: %w", err)
+	}
+	defer in.Close()
+
+	if _, err := io.Copy(out, in); err != nil {
+		return fmt.Errorf("failed to copy file: %w", err)
+	}
+
+	return nil
+}
+
+// verifyFileHash checks that the sha256 of the file at path matches the hex encoded expectedHash
+func verifyFileHash(path, expectedHash string) error {
+	expected, err := hex.DecodeString(expectedHash)
+	if err != nil {
+		return fmt.Errorf("failed to decode expected hash: %w", err)
+	}
+
+	f, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return fmt.Errorf("failed to open file: %w", err)
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return fmt.Errorf("failed to calculate file hash: %w", err)
+	}
+
+	if subtle.ConstantTimeCompare(expected, h.Sum(nil)) == 0 {
+		return errors.New("file hash did not match expected")
+	}
+
+	return nil
+}
+
+// validateRelPath ensures p is a relative path that stays within the directory it is joined to
+func validateRelPath(p string) error {
+	if p == "" {
+		return errors.New("path must be specified")
+	}
+
+	clean := filepath.Clean(filepath.FromSlash(p))
+	if filepath.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, ".."+string(filepath.Separator)) {
+		return errors.New("path must be relative to the archive")
+	}
+
+	return nil
+}
@@ -0,0 +1,194 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package delta
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadManifest(t *testing.T) {
+	testCases := []struct {
+		name        string
+		manifest    Manifest
+		expectedErr string
+	}{
+		{
+			name: "valid",
+			manifest: Manifest{
+				BaseVersion:   "v1.0.0",
+				TargetVersion: "v1.1.0",
+				Files: []ManifestFile{
+					{Path: "collector", Action: ActionPatch, Source: "patches/collector", BaseSHA256: "00", SHA256: "00"},
+					{Path: "plugins/new.yaml", Action: ActionAdd, Source: "files/plugins/new.yaml", SHA256: "00"},
+					{Path: "LICENSE", Action: ActionCopy, BaseSHA256: "00", SHA256: "00"},
+				},
+			},
+		},
+		{
+			name:        "missing base version",
+			manifest:    Manifest{TargetVersion: "v1.1.0"},
+			expectedErr: "base_version must be specified",
+		},
+		{
+			name:        "missing target version",
+			manifest:    Manifest{BaseVersion: "v1.0.0"},
+			expectedErr: "target_version must be specified",
+		},
+		{
+			name: "path outside of install dir",
+			manifest: Manifest{
+				BaseVersion:   "v1.0.0",
+				TargetVersion: "v1.1.0",
+				Files: []ManifestFile{
+					{Path: "../collector", Action: ActionCopy, BaseSHA256: "00", SHA256: "00"},
+				},
+			},
+			expectedErr: "path must be relative",
+		},
+		{
+			name: "unknown action",
+			manifest: Manifest{
+				BaseVersion:   "v1.0.0",
+				TargetVersion: "v1.1.0",
+				Files: []ManifestFile{
+					{Path: "collector", Action: "delete", SHA256: "00"},
+				},
+			},
+			expectedErr: "unknown action",
+		},
+		{
+			name: "missing base hash",
+			manifest: Manifest{
+				BaseVersion:   "v1.0.0",
+				TargetVersion: "v1.1.0",
+				Files: []ManifestFile{
+					{Path: "collector", Action: ActionPatch, Source: "patches/collector", SHA256: "00"},
+				},
+			},
+			expectedErr: "base_sha256 must be specified",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeManifest(t, dir, tc.manifest)
+
+			m, err := LoadManifest(dir)
+			if tc.expectedErr != "" {
+				require.ErrorContains(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.manifest, *m)
+		})
+	}
+
+	t.Run("missing manifest", func(t *testing.T) {
+		_, err := LoadManifest(t.TempDir())
+		require.ErrorContains(t, err, "failed to read manifest")
+	})
+}
+
+func TestManifestApply(t *testing.T) {
+	baseCollector := []byte("the old collector binary, version 1.0.0, with plenty of shared bytes to match")
+	newCollector := []byte("the new collector binary, version 1.1.0, with plenty of shared bytes to match")
+	license := []byte("license text")
+	plugin := []byte("new plugin")
+
+	setup := func(t *testing.T) (baseDir, deltaDir, outDir string, m Manifest) {
+		baseDir, deltaDir, outDir = t.TempDir(), t.TempDir(), t.TempDir()
+		writeTestFile(t, filepath.Join(baseDir, "collector"), baseCollector)
+		writeTestFile(t, filepath.Join(baseDir, "LICENSE"), license)
+		writeTestFile(t, filepath.Join(deltaDir, "patches", "collector"), Diff(baseCollector, newCollector))
+		writeTestFile(t, filepath.Join(deltaDir, "files", "plugins", "new.yaml"), plugin)
+
+		m = Manifest{
+			BaseVersion:   "v1.0.0",
+			TargetVersion: "v1.1.0",
+			Files: []ManifestFile{
+				{Path: "collector", Action: ActionPatch, Source: "patches/collector", BaseSHA256: sha(baseCollector), SHA256: sha(newCollector), Mode: 0700},
+				{Path: "plugins/new.yaml", Action: ActionAdd, Source: "files/plugins/new.yaml", SHA256: sha(plugin)},
+				{Path: "LICENSE", Action: ActionCopy, BaseSHA256: sha(license), SHA256: sha(license)},
+			},
+		}
+		return
+	}
+
+	t.Run("builds target files", func(t *testing.T) {
+		baseDir, deltaDir, outDir, m := setup(t)
+
+		require.NoError(t, m.Apply(baseDir, deltaDir, outDir))
+		requireFileContents(t, filepath.Join(outDir, "collector"), newCollector)
+		requireFileContents(t, filepath.Join(outDir, "plugins", "new.yaml"), plugin)
+		requireFileContents(t, filepath.Join(outDir, "LICENSE"), license)
+	})
+
+	t.Run("installed file does not match base", func(t *testing.T) {
+		baseDir, deltaDir, outDir, m := setup(t)
+		writeTestFile(t, filepath.Join(baseDir, "collector"), []byte("locally modified"))
+
+		err := m.Apply(baseDir, deltaDir, outDir)
+		require.ErrorContains(t, err, "installed file does not match base")
+	})
+
+	t.Run("resulting file does not match hash", func(t *testing.T) {
+		baseDir, deltaDir, outDir, m := setup(t)
+		m.Files[1].SHA256 = sha([]byte("something else"))
+
+		err := m.Apply(baseDir, deltaDir, outDir)
+		require.ErrorContains(t, err, "resulting file is invalid")
+	})
+
+	t.Run("corrupt patch", func(t *testing.T) {
+		baseDir, deltaDir, outDir, m := setup(t)
+		writeTestFile(t, filepath.Join(deltaDir, "patches", "collector"), []byte("garbage"))
+
+		err := m.Apply(baseDir, deltaDir, outDir)
+		require.ErrorContains(t, err, "failed to apply patch")
+	})
+}
+
+func writeManifest(t *testing.T, dir string, m Manifest) {
+	t.Helper()
+	data, err := json.Marshal(m)
+	require.NoError(t, err)
+	writeTestFile(t, filepath.Join(dir, ManifestFileName), data)
+}
+
+func writeTestFile(t *testing.T, p string, data []byte) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(p), 0750))
+	require.NoError(t, os.WriteFile(p, data, 0600))
+}
+
+func requireFileContents(t *testing.T, p string, expected []byte) {
+	t.Helper()
+	actual, err := os.ReadFile(p)
+	require.NoError(t, err)
+	require.Equal(t, expected, actual)
+}
+
+func sha(data []byte) string {
+	h := sha256.Sum256(data)
+	return hex.EncodeToString(h[:])
+}
@@ -0,0 +1,233 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package delta creates and applies binary patches, and applies manifests of patches
+// to upgrade an installation from one version to another.
+package delta
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// A patch is made up of a header followed by a sequence of operations:
+//
+//	magic          "BPDELTA1"
+//	target size    uvarint
+//	operations     copy (0x01, uvarint offset, uvarint length) - copy length bytes from offset of the base
+//	               insert (0x02, uvarint length, data)        - write length bytes of data
+//	               end (0x00)
+const (
+	patchMagic = "BPDELTA1"
+
+	opEnd    byte = 0x00
+	opCopy   byte = 0x01
+	opInsert byte = 0x02
+
+	// blockSize is the minimum length of a match between the base and target
+	blockSize = 32
+	// hashBase is the multiplier for the rolling hash
+	hashBase uint32 = 257
+	// maxCandidates is the maximum number of base offsets tracked for a single block hash
+	maxCandidates = 8
+)
+
+// ErrInvalidPatch is returned when a patch is malformed or doesn't apply to the given base.
+var ErrInvalidPatch = errors.New("invalid patch")
+
+// Apply applies the patch read from patch to base, writing the result to out.
+func Apply(base io.ReaderAt, patch io.Reader, out io.Writer) error {
+	r := bufio.NewReader(patch)
+
+	magic := make([]byte, len(patchMagic))
+	if _, err := io.ReadFull(r, magic); err != nil {
+		return fmt.Errorf("failed to read patch header: %w", err)
+	}
+
+	if string(magic) != patchMagic {
+		return fmt.Errorf("%w: bad magic", ErrInvalidPatch)
+	}
+
+	targetSize, err := binary.ReadUvarint(r)
+	if err != nil {
+		return fmt.Errorf("failed to read target size: %w", err)
+	}
+
+	var written uint64
+	for {
+		op, err := r.ReadByte()
+		if err != nil {
+			return fmt.Errorf("failed to read operation: %w", err)
+		}
+
+		switch op {
+		case opEnd:
+			if written != targetSize {
+				return fmt.Errorf("%w: wrote %d bytes, expected %d", ErrInvalidPatch, written, targetSize)
+			}
+			return nil
+		case opCopy:
+			offset, err := binary.ReadUvarint(r)
+			if err != nil {
+				return fmt.Errorf("failed to read copy offset: %w", err)
+			}
+
+			length, err := binary.ReadUvarint(r)
+			if err != nil {
+				return fmt.Errorf("failed to read copy length: %w", err)
+			}
+
+			n, err := io.Copy(out, io.NewSectionReader(base, int64(offset), int64(length)))
+			if err != nil {
+				return fmt.Errorf("failed to copy from base: %w", err)
+			}
+
+			if uint64(n) != length {
+				return fmt.Errorf("%w: copy of %d bytes at offset %d is outside of base", ErrInvalidPatch, length, offset)
+			}
+			written += length
+		case opInsert:
+			length, err := binary.ReadUvarint(r)
+			if err != nil {
+				return fmt.Errorf("failed to read insert length: %w", err)
+			}
+
+			n, err := io.CopyN(out, r, int64(length))
+			if err != nil {
+				return fmt.Errorf("failed to insert %d bytes (inserted %d): %w", length, n, err)
+			}
+			written += length
+		default:
+			return fmt.Errorf("%w: unknown operation %#x", ErrInvalidPatch, op)
+		}
+
+		if written > targetSize {
+			return fmt.Errorf("%w: output exceeds target size %d", ErrInvalidPatch, targetSize)
+		}
+	}
+}
+
+// Diff creates a patch that transforms base into target.
+func Diff(base, target []byte) []byte {
+	var buf bytes.Buffer
+	buf.WriteString(patchMagic)
+	writeUvarint(&buf, uint64(len(target)))
+
+	index := indexBlocks(base)
+	pow := hashPower()
+
+	literalStart := 0
+	i := 0
+	var h uint32
+	hashValid := false
+
+	for i+blockSize <= len(target) {
+		if !hashValid {
+			h = blockHash(target[i : i+blockSize])
+			hashValid = true
+		}
+
+		if offset, length := longestMatch(index[h], base, target, i); length > 0 {
+			writeInsert(&buf, target[literalStart:i])
+			writeCopy(&buf, offset, length)
+
+			i += length
+			literalStart = i
+			hashValid = false
+			continue
+		}
+
+		// Roll the hash forward by one byte
+		if i+blockSize < len(target) {
+			h = (h-uint32(target[i])*pow)*hashBase + uint32(target[i+blockSize])
+		}
+		i++
+	}
+
+	writeInsert(&buf, target[literalStart:])
+	buf.WriteByte(opEnd)
+
+	return buf.Bytes()
+}
+
+// indexBlocks records the offsets of each non-overlapping block in base by its hash
+func indexBlocks(base []byte) map[uint32][]int {
+	index := make(map[uint32][]int, len(base)/blockSize)
+	for offset := 0; offset+blockSize <= len(base); offset += blockSize {
+		h := blockHash(base[offset : offset+blockSize])
+		if len(index[h]) < maxCandidates {
+			index[h] = append(index[h], offset)
+		}
+	}
+	return index
+}
+
+// longestMatch returns the candidate offset in base that matches target at position i for the most bytes.
+// A length of 0 is returned if no candidate matches at least blockSize bytes.
+func longestMatch(candidates []int, base, target []byte, i int) (offset, length int) {
+	for _, candidate := range candidates {
+		n := 0
+		for candidate+n < len(base) && i+n < len(target) && base[candidate+n] == target[i+n] {
+			n++
+		}
+
+		if n >= blockSize && n > length {
+			offset, length = candidate, n
+		}
+	}
+	return offset, length
+}
+
+// blockHash computes the polynomial hash of block
+func blockHash(block []byte) uint32 {
+	var h uint32
+	for _, b := range block {
+		h = h*hashBase + uint32(b)
+	}
+	return h
+}
+
+// hashPower returns hashBase^(blockSize-1), used to remove the leading byte from the rolling hash
+func hashPower() uint32 {
+	pow := uint32(1)
+	for i := 0; i < blockSize-1; i++ {
+		pow *= hashBase
+	}
+	return pow
+}
+
+func writeCopy(buf *bytes.Buffer, offset, length int) {
+	buf.WriteByte(opCopy)
+	writeUvarint(buf, uint64(offset))
+	writeUvarint(buf, uint64(length))
+}
+
+func writeInsert(buf *bytes.Buffer, data []byte) {
+	if len(data) == 0 {
+		return
+	}
+	buf.WriteByte(opInsert)
+	writeUvarint(buf, uint64(len(data)))
+	buf.Write(data)
+}
+
+func writeUvarint(buf *bytes.Buffer, v uint64) {
+	var tmp [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(tmp[:], v)
+	buf.Write(tmp[:n])
+}
@@ -0,0 +1,140 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package delta
+
+import (
+	"bytes"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffApply(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	randomBytes := func(n int) []byte {
+		b := make([]byte, n)
+		_, _ = rng.Read(b)
+		return b
+	}
+
+	base := randomBytes(64 * 1024)
+
+	modified := append([]byte{}, base[:10000]...)
+	modified = append(modified, []byte("some inserted bytes")...)
+	modified = append(modified, base[10000:30000]...)
+	modified = append(modified, base[40000:]...)
+	modified = append(modified, base[:5000]...)
+
+	testCases := []struct {
+		name   string
+		base   []byte
+		target []byte
+	}{
+		{
+			name:   "identical",
+			base:   base,
+			target: base,
+		},
+		{
+			name:   "modified",
+			base:   base,
+			target: modified,
+		},
+		{
+			name:   "unrelated",
+			base:   base,
+			target: randomBytes(1000),
+		},
+		{
+			name:   "empty base",
+			base:   []byte{},
+			target: randomBytes(100),
+		},
+		{
+			name:   "empty target",
+			base:   base,
+			target: []byte{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			patch := Diff(tc.base, tc.target)
+
+			var out bytes.Buffer
+			require.NoError(t, Apply(bytes.NewReader(tc.base), bytes.NewReader(patch), &out))
+			require.Equal(t, len(tc.target), out.Len())
+			require.True(t, bytes.Equal(tc.target, out.Bytes()))
+		})
+	}
+
+	t.Run("patch is smaller than target", func(t *testing.T) {
+		patch := Diff(base, modified)
+		require.Less(t, len(patch), len(modified)/10)
+	})
+}
+
+func TestApplyInvalid(t *testing.T) {
+	base := bytes.Repeat([]byte("abcdefgh"), 100)
+	target := append(bytes.Repeat([]byte("abcdefgh"), 50), []byte("tail")...)
+	patch := Diff(base, target)
+
+	testCases := []struct {
+		name        string
+		base        []byte
+		patch       []byte
+		expectedErr string
+	}{
+		{
+			name:        "bad magic",
+			base:        base,
+			patch:       append([]byte("NOTDELTA"), patch[len(patchMagic):]...),
+			expectedErr: "bad magic",
+		},
+		{
+			name:        "truncated",
+			base:        base,
+			patch:       patch[:len(patch)-3],
+			expectedErr: "failed to",
+		},
+		{
+			name:        "base too short",
+			base:        base[:100],
+			patch:       patch,
+			expectedErr: "outside of base",
+		},
+		{
+			name:        "unknown operation",
+			base:        base,
+			patch:       []byte(patchMagic + "\x04\x07"),
+			expectedErr: "unknown operation",
+		},
+		{
+			name:        "size mismatch",
+			base:        base,
+			patch:       []byte(patchMagic + "\x04\x02\x02ab\x00"),
+			expectedErr: "expected 4",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var out bytes.Buffer
+			err := Apply(bytes.NewReader(tc.base), bytes.NewReader(tc.patch), &out)
+			require.ErrorContains(t, err, tc.expectedErr)
+		})
+	}
+}
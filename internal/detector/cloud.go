@@ -0,0 +1,250 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package detector
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	awsEndpoint   = "http://169.254.169.254"
+	gcpEndpoint   = "http://metadata.google.internal"
+	azureEndpoint = "http://169.254.169.254"
+
+	// cloudRequestTimeout bounds each metadata request, so hosts outside of a cloud are not held up
+	cloudRequestTimeout = time.Second
+)
+
+// cloudDetector detects the cloud provider, region, and instance using the provider's metadata service.
+// AWS, GCP, and Azure are supported. The result is cached once the provider is detected, as it can't change while running.
+type cloudDetector struct {
+	client        *http.Client
+	awsEndpoint   string
+	gcpEndpoint   string
+	azureEndpoint string
+
+	mutex  sync.Mutex
+	cached map[string]string
+}
+
+func newCloudDetector() *cloudDetector {
+	return &cloudDetector{
+		client:        &http.Client{Timeout: cloudRequestTimeout},
+		awsEndpoint:   awsEndpoint,
+		gcpEndpoint:   gcpEndpoint,
+		azureEndpoint: azureEndpoint,
+	}
+}
+
+// Type returns the type of the detector
+func (d *cloudDetector) Type() string {
+	return TypeCloud
+}
+
+// Detect queries the metadata service of each supported provider, and returns the attributes from the first that responds
+func (d *cloudDetector) Detect(ctx context.Context) (map[string]string, error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if d.cached != nil {
+		return d.cached, nil
+	}
+
+	probes := []func(context.Context) (map[string]string, error){
+		d.detectAWS,
+		d.detectGCP,
+		d.detectAzure,
+	}
+
+	// Query all providers at once, so hosts outside of a cloud only wait for a single timeout
+	results := make([]map[string]string, len(probes))
+	var wg sync.WaitGroup
+	for i, probe := range probes {
+		wg.Add(1)
+		go func(i int, probe func(context.Context) (map[string]string, error)) {
+			defer wg.Done()
+			// Failed probes just mean the host isn't on that provider
+			results[i], _ = probe(ctx)
+		}(i, probe)
+	}
+	wg.Wait()
+
+	for _, result := range results {
+		if result != nil {
+			d.cached = result
+			return result, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// awsIdentityDocument is the subset of the EC2 instance identity document used for attributes
+type awsIdentityDocument struct {
+	AccountID        string `json:"accountId"`
+	AvailabilityZone string `json:"availabilityZone"`
+	InstanceID       string `json:"instanceId"`
+	Region           string `json:"region"`
+}
+
+// detectAWS reads the EC2 instance identity document using IMDSv2
+func (d *cloudDetector) detectAWS(ctx context.Context) (map[string]string, error) {
+	tokenReq, err := http.NewRequestWithContext(ctx, http.MethodPut, d.awsEndpoint+"/latest/api/token", nil)
+	if err != nil {
+		return nil, err
+	}
+	tokenReq.Header.Set("X-aws-ec2-metadata-token-ttl-seconds", "60")
+
+	token, err := d.do(tokenReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get metadata token: %w", err)
+	}
+
+	docReq, err := http.NewRequestWithContext(ctx, http.MethodGet, d.awsEndpoint+"/latest/dynamic/instance-identity/document", nil)
+	if err != nil {
+		return nil, err
+	}
+	docReq.Header.Set("X-aws-ec2-metadata-token", string(token))
+
+	body, err := d.do(docReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get identity document: %w", err)
+	}
+
+	var doc awsIdentityDocument
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse identity document: %w", err)
+	}
+
+	return cloudAttributes("aws", doc.Region, doc.AvailabilityZone, doc.InstanceID, doc.AccountID), nil
+}
+
+// gcpInstance is the subset of the GCE instance metadata used for attributes
+type gcpInstance struct {
+	ID   json.Number `json:"id"`
+	Zone string      `json:"zone"`
+}
+
+// detectGCP reads the GCE instance metadata
+func (d *cloudDetector) detectGCP(ctx context.Context) (map[string]string, error) {
+	instanceReq, err := http.NewRequestWithContext(ctx, http.MethodGet, d.gcpEndpoint+"/computeMetadata/v1/instance/?recursive=true", nil)
+	if err != nil {
+		return nil, err
+	}
+	instanceReq.Header.Set("Metadata-Flavor", "Google")
+
+	body, err := d.do(instanceReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get instance metadata: %w", err)
+	}
+
+	var instance gcpInstance
+	if err := json.Unmarshal(body, &instance); err != nil {
+		return nil, fmt.Errorf("failed to parse instance metadata: %w", err)
+	}
+
+	projectReq, err := http.NewRequestWithContext(ctx, http.MethodGet, d.gcpEndpoint+"/computeMetadata/v1/project/project-id", nil)
+	if err != nil {
+		return nil, err
+	}
+	projectReq.Header.Set("Metadata-Flavor", "Google")
+
+	project, err := d.do(projectReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project ID: %w", err)
+	}
+
+	// The zone is in the form projects/<project number>/zones/<zone>
+	zone := instance.Zone[strings.LastIndex(instance.Zone, "/")+1:]
+	region := zone
+	if i := strings.LastIndex(zone, "-"); i > 0 {
+		region = zone[:i]
+	}
+
+	return cloudAttributes("gcp", region, zone, instance.ID.String(), string(project)), nil
+}
+
+// azureCompute is the subset of the Azure instance metadata used for attributes
+type azureCompute struct {
+	Location       string `json:"location"`
+	SubscriptionID string `json:"subscriptionId"`
+	VMID           string `json:"vmId"`
+	Zone           string `json:"zone"`
+}
+
+// detectAzure reads the Azure instance metadata
+func (d *cloudDetector) detectAzure(ctx context.Context) (map[string]string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, d.azureEndpoint+"/metadata/instance/compute?api-version=2021-02-01", nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Metadata", "true")
+
+	body, err := d.do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get instance metadata: %w", err)
+	}
+
+	var compute azureCompute
+	if err := json.Unmarshal(body, &compute); err != nil {
+		return nil, fmt.Errorf("failed to parse instance metadata: %w", err)
+	}
+
+	return cloudAttributes("azure", compute.Location, compute.Zone, compute.VMID, compute.SubscriptionID), nil
+}
+
+// do sends the request and returns the response body, if the response is successful
+func (d *cloudDetector) do(req *http.Request) ([]byte, error) {
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+
+	return io.ReadAll(resp.Body)
+}
+
+// cloudAttributes creates the attributes for a cloud host, omitting empty values
+func cloudAttributes(provider, region, zone, instanceID, accountID string) map[string]string {
+	attributes := map[string]string{
+		"cloud.provider": provider,
+	}
+
+	for key, value := range map[string]string{
+		"cloud.region":            region,
+		"cloud.availability_zone": zone,
+		"cloud.account.id":        accountID,
+		"host.id":                 instanceID,
+	} {
+		if value != "" {
+			attributes[key] = value
+		}
+	}
+
+	return attributes
+}
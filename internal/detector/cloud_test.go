@@ -0,0 +1,121 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package detector
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCloudDetector(t *testing.T) {
+	testCases := []struct {
+		name     string
+		handler  http.HandlerFunc
+		expected map[string]string
+	}{
+		{
+			name: "AWS",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				switch {
+				case r.Method == http.MethodPut && r.URL.Path == "/latest/api/token":
+					w.Write([]byte("token"))
+				case r.URL.Path == "/latest/dynamic/instance-identity/document" && r.Header.Get("X-aws-ec2-metadata-token") == "token":
+					w.Write([]byte(`{"accountId":"123456789012","availabilityZone":"us-east-1a","instanceId":"i-0123456789abcdef0","region":"us-east-1"}`))
+				default:
+					w.WriteHeader(http.StatusNotFound)
+				}
+			},
+			expected: map[string]string{
+				"cloud.provider":          "aws",
+				"cloud.region":            "us-east-1",
+				"cloud.availability_zone": "us-east-1a",
+				"cloud.account.id":        "123456789012",
+				"host.id":                 "i-0123456789abcdef0",
+			},
+		},
+		{
+			name: "GCP",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				if r.Header.Get("Metadata-Flavor") != "Google" {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				switch r.URL.Path {
+				case "/computeMetadata/v1/instance/":
+					w.Write([]byte(`{"id":1234567890123456789,"zone":"projects/123/zones/us-central1-a"}`))
+				case "/computeMetadata/v1/project/project-id":
+					w.Write([]byte("my-project"))
+				default:
+					w.WriteHeader(http.StatusNotFound)
+				}
+			},
+			expected: map[string]string{
+				"cloud.provider":          "gcp",
+				"cloud.region":            "us-central1",
+				"cloud.availability_zone": "us-central1-a",
+				"cloud.account.id":        "my-project",
+				"host.id":                 "1234567890123456789",
+			},
+		},
+		{
+			name: "Azure",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/metadata/instance/compute" || r.Header.Get("Metadata") != "true" {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				w.Write([]byte(`{"location":"eastus","subscriptionId":"sub","vmId":"vm","zone":""}`))
+			},
+			expected: map[string]string{
+				"cloud.provider":   "azure",
+				"cloud.region":     "eastus",
+				"cloud.account.id": "sub",
+				"host.id":          "vm",
+			},
+		},
+		{
+			name: "Not in a cloud",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusNotFound)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := httptest.NewServer(tc.handler)
+			defer s.Close()
+
+			d := newCloudDetector()
+			d.awsEndpoint = s.URL
+			d.gcpEndpoint = s.URL
+			d.azureEndpoint = s.URL
+
+			attributes, err := d.Detect(context.Background())
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, attributes)
+
+			// Detected attributes are cached
+			s.Close()
+			attributes, err = d.Detect(context.Background())
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, attributes)
+		})
+	}
+}
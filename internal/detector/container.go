@@ -0,0 +1,106 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package detector
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// containerIDRegex matches the 64 character hex IDs used by docker, containerd, and cri-o
+var containerIDRegex = regexp.MustCompile(`[0-9a-f]{64}`)
+
+// containerDetector detects the ID of the container the agent is running in from the proc filesystem
+type containerDetector struct {
+	cgroupPath    string
+	mountInfoPath string
+}
+
+func newContainerDetector() *containerDetector {
+	return &containerDetector{
+		cgroupPath:    "/proc/self/cgroup",
+		mountInfoPath: "/proc/self/mountinfo",
+	}
+}
+
+// Type returns the type of the detector
+func (d *containerDetector) Type() string {
+	return TypeContainer
+}
+
+// Detect returns the container ID, if the agent is running in a container
+func (d *containerDetector) Detect(_ context.Context) (map[string]string, error) {
+	// cgroup v1 includes the container ID in the cgroup path
+	containerID, err := findContainerID(d.cgroupPath, func(string) bool { return true })
+	if err != nil {
+		return nil, err
+	}
+
+	// cgroup v2 doesn't, but the container's own files are mounted from the runtime's container directory
+	if containerID == "" {
+		containerID, err = findContainerID(d.mountInfoPath, func(line string) bool {
+			return strings.Contains(line, "/containers/")
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if containerID == "" {
+		return nil, nil
+	}
+
+	return map[string]string{
+		"container.id": containerID,
+	}, nil
+}
+
+// findContainerID returns the first container ID found in lines of the file that match the filter.
+// A missing file is not an error, as it just means the agent isn't running in a container.
+func findContainerID(path string, filter func(line string) bool) (string, error) {
+	f, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !filter(line) {
+			continue
+		}
+		if containerID := containerIDRegex.FindString(line); containerID != "" {
+			return containerID, nil
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	return "", nil
+}
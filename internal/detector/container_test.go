@@ -0,0 +1,78 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package detector
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestContainerDetector(t *testing.T) {
+	containerID := "3c1e8fbf4d9a2b7c6e5f0a1b2c3d4e5f6a7b8c9d0e1f2a3b4c5d6e7f8a9b0c1d"
+
+	testCases := []struct {
+		name      string
+		cgroup    string
+		mountInfo string
+		expected  map[string]string
+	}{
+		{
+			name:      "cgroup v1",
+			cgroup:    "12:pids:/docker/" + containerID + "\n11:memory:/docker/" + containerID + "\n",
+			mountInfo: "",
+			expected:  map[string]string{"container.id": containerID},
+		},
+		{
+			name:      "cgroup v2",
+			cgroup:    "0::/\n",
+			mountInfo: "1 0 0:1 / / rw - overlay overlay rw\n2 1 8:1 /var/lib/docker/containers/" + containerID + "/hostname /etc/hostname rw - ext4 /dev/sda1 rw\n",
+			expected:  map[string]string{"container.id": containerID},
+		},
+		{
+			name:      "Not in a container",
+			cgroup:    "0::/user.slice/user-1000.slice\n",
+			mountInfo: "1 0 8:1 / / rw - ext4 /dev/sda1 rw\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			d := newContainerDetector()
+			d.cgroupPath = filepath.Join(tmpDir, "cgroup")
+			d.mountInfoPath = filepath.Join(tmpDir, "mountinfo")
+			require.NoError(t, os.WriteFile(d.cgroupPath, []byte(tc.cgroup), 0600))
+			require.NoError(t, os.WriteFile(d.mountInfoPath, []byte(tc.mountInfo), 0600))
+
+			attributes, err := d.Detect(context.Background())
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, attributes)
+		})
+	}
+
+	t.Run("Missing proc files", func(t *testing.T) {
+		d := newContainerDetector()
+		d.cgroupPath = filepath.Join(t.TempDir(), "cgroup")
+		d.mountInfoPath = filepath.Join(t.TempDir(), "mountinfo")
+
+		attributes, err := d.Detect(context.Background())
+		require.NoError(t, err)
+		require.Nil(t, attributes)
+	})
+}
@@ -0,0 +1,155 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package detector contains detectors that discover attributes describing the environment the agent runs in
+package detector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// TypeCloud detects the cloud provider, region, and instance of the host
+	TypeCloud = "cloud"
+	// TypeContainer detects the ID of the container the agent runs in
+	TypeContainer = "container"
+	// TypeKubernetes detects the Kubernetes pod, namespace, and node the agent runs on
+	TypeKubernetes = "kubernetes"
+	// TypeFile reads attributes from a local YAML file
+	TypeFile = "file"
+	// TypeEnv reads attributes from environment variables
+	TypeEnv = "env"
+)
+
+// DefaultEnvPrefix is the prefix of environment variables read by the env detector if none is configured
+const DefaultEnvPrefix = "OPAMP_ATTRIBUTE_"
+
+// Detector detects attributes describing the agent's environment
+type Detector interface {
+	// Type returns the type of the detector
+	Type() string
+
+	// Detect returns the detected attributes. Attributes that don't apply to the environment are omitted.
+	Detect(ctx context.Context) (map[string]string, error)
+}
+
+// Config configures which detectors are run
+type Config struct {
+	// Detectors is the list of detectors to run. Attributes from later detectors take precedence.
+	Detectors []string `yaml:"detectors,omitempty"`
+
+	// File is the path of the YAML file of attributes read by the file detector
+	File string `yaml:"file,omitempty"`
+
+	// EnvPrefix is the prefix of environment variables read by the env detector
+	EnvPrefix string `yaml:"env_prefix,omitempty"`
+
+	// IdentifyingAttributes lists the detected attributes that are sent as identifying attributes.
+	// All other detected attributes are sent as non-identifying attributes.
+	IdentifyingAttributes []string `yaml:"identifying_attributes,omitempty"`
+}
+
+// Validate returns an error if the config is invalid
+func (c Config) Validate() error {
+	for _, detectorType := range c.Detectors {
+		switch detectorType {
+		case TypeCloud, TypeContainer, TypeKubernetes, TypeEnv:
+		case TypeFile:
+			if c.File == "" {
+				return errors.New("file must be specified for the file detector")
+			}
+		default:
+			return fmt.Errorf("unknown detector %q", detectorType)
+		}
+	}
+
+	return nil
+}
+
+// Copy creates a deep copy of this config
+func (c Config) Copy() *Config {
+	cfgCopy := &Config{
+		File:      c.File,
+		EnvPrefix: c.EnvPrefix,
+	}
+
+	if c.Detectors != nil {
+		cfgCopy.Detectors = append([]string{}, c.Detectors...)
+	}
+	if c.IdentifyingAttributes != nil {
+		cfgCopy.IdentifyingAttributes = append([]string{}, c.IdentifyingAttributes...)
+	}
+
+	return cfgCopy
+}
+
+// New creates the detectors for the config. The config is assumed to be valid.
+func New(cfg Config) []Detector {
+	detectors := make([]Detector, 0, len(cfg.Detectors))
+	for _, detectorType := range cfg.Detectors {
+		switch detectorType {
+		case TypeCloud:
+			detectors = append(detectors, newCloudDetector())
+		case TypeContainer:
+			detectors = append(detectors, newContainerDetector())
+		case TypeKubernetes:
+			detectors = append(detectors, newKubernetesDetector())
+		case TypeFile:
+			detectors = append(detectors, newFileDetector(cfg.File))
+		case TypeEnv:
+			prefix := cfg.EnvPrefix
+			if prefix == "" {
+				prefix = DefaultEnvPrefix
+			}
+			detectors = append(detectors, newEnvDetector(prefix))
+		}
+	}
+
+	return detectors
+}
+
+// Detect runs each detector and merges the detected attributes.
+// Detectors that fail are logged and skipped, so a partial result is always returned.
+func Detect(ctx context.Context, logger *zap.Logger, detectors []Detector) map[string]string {
+	attributes := make(map[string]string)
+	for _, d := range detectors {
+		detected, err := d.Detect(ctx)
+		if err != nil {
+			logger.Warn("Failed to detect identity attributes", zap.String("detector", d.Type()), zap.Error(err))
+			continue
+		}
+
+		for key, value := range detected {
+			attributes[key] = value
+		}
+	}
+
+	return attributes
+}
+
+// SortedKeys returns the keys of the attributes in sorted order
+func SortedKeys(attributes map[string]string) []string {
+	keys := make([]string, 0, len(attributes))
+	for key := range attributes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
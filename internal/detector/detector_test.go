@@ -0,0 +1,126 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package detector
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestConfigValidate(t *testing.T) {
+	testCases := []struct {
+		name        string
+		cfg         Config
+		expectedErr string
+	}{
+		{
+			name: "No detectors",
+			cfg:  Config{},
+		},
+		{
+			name: "All detectors",
+			cfg: Config{
+				Detectors: []string{TypeCloud, TypeContainer, TypeKubernetes, TypeFile, TypeEnv},
+				File:      "attributes.yaml",
+			},
+		},
+		{
+			name: "Unknown detector",
+			cfg: Config{
+				Detectors: []string{"ec2"},
+			},
+			expectedErr: `unknown detector "ec2"`,
+		},
+		{
+			name: "File detector without file",
+			cfg: Config{
+				Detectors: []string{TypeFile},
+			},
+			expectedErr: "file must be specified for the file detector",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.cfg.Validate()
+			if tc.expectedErr != "" {
+				require.EqualError(t, err, tc.expectedErr)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestConfigCopy(t *testing.T) {
+	cfg := Config{
+		Detectors:             []string{TypeFile, TypeEnv},
+		File:                  "attributes.yaml",
+		EnvPrefix:             "MY_PREFIX_",
+		IdentifyingAttributes: []string{"team"},
+	}
+
+	cfgCopy := cfg.Copy()
+	require.Equal(t, cfg, *cfgCopy)
+
+	cfgCopy.Detectors[0] = TypeCloud
+	cfgCopy.IdentifyingAttributes[0] = "host.id"
+	require.Equal(t, TypeFile, cfg.Detectors[0])
+	require.Equal(t, "team", cfg.IdentifyingAttributes[0])
+}
+
+func TestNew(t *testing.T) {
+	detectors := New(Config{
+		Detectors: []string{TypeCloud, TypeContainer, TypeKubernetes, TypeFile, TypeEnv},
+		File:      "attributes.yaml",
+	})
+
+	types := make([]string, 0, len(detectors))
+	for _, d := range detectors {
+		types = append(types, d.Type())
+	}
+	require.Equal(t, []string{TypeCloud, TypeContainer, TypeKubernetes, TypeFile, TypeEnv}, types)
+	require.Equal(t, DefaultEnvPrefix, detectors[4].(*envDetector).prefix)
+}
+
+// staticDetector is a Detector that returns fixed results
+type staticDetector struct {
+	attributes map[string]string
+	err        error
+}
+
+func (d staticDetector) Type() string {
+	return "static"
+}
+
+func (d staticDetector) Detect(_ context.Context) (map[string]string, error) {
+	return d.attributes, d.err
+}
+
+func TestDetect(t *testing.T) {
+	detectors := []Detector{
+		staticDetector{attributes: map[string]string{"one": "1", "two": "2"}},
+		staticDetector{err: errors.New("oops")},
+		staticDetector{attributes: map[string]string{"two": "two", "three": "3"}},
+	}
+
+	attributes := Detect(context.Background(), zap.NewNop(), detectors)
+	require.Equal(t, map[string]string{"one": "1", "two": "two", "three": "3"}, attributes)
+	require.Equal(t, []string{"one", "three", "two"}, SortedKeys(attributes))
+}
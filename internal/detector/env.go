@@ -0,0 +1,53 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package detector
+
+import (
+	"context"
+	"os"
+	"strings"
+)
+
+// envDetector reads attributes from environment variables with a prefix.
+// The attribute key is the rest of the variable name in lower case, so OPAMP_ATTRIBUTE_TEAM=core becomes team=core.
+type envDetector struct {
+	prefix string
+}
+
+func newEnvDetector(prefix string) *envDetector {
+	return &envDetector{
+		prefix: prefix,
+	}
+}
+
+// Type returns the type of the detector
+func (d *envDetector) Type() string {
+	return TypeEnv
+}
+
+// Detect returns the attributes from the environment
+func (d *envDetector) Detect(_ context.Context) (map[string]string, error) {
+	attributes := map[string]string{}
+	for _, env := range os.Environ() {
+		name, value, found := strings.Cut(env, "=")
+		if !found || !strings.HasPrefix(name, d.prefix) || len(name) == len(d.prefix) {
+			continue
+		}
+
+		attributes[strings.ToLower(strings.TrimPrefix(name, d.prefix))] = value
+	}
+
+	return attributes, nil
+}
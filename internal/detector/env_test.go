@@ -0,0 +1,33 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package detector
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnvDetector(t *testing.T) {
+	t.Setenv("TEST_ATTR_TEAM", "core")
+	t.Setenv("TEST_ATTR_Data_Center", "east")
+	t.Setenv("TEST_ATTR_", "ignored")
+	t.Setenv("OTHER_TEAM", "ignored")
+
+	attributes, err := newEnvDetector("TEST_ATTR_").Detect(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"team": "core", "data_center": "east"}, attributes)
+}
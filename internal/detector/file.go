@@ -0,0 +1,56 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package detector
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// fileDetector reads attributes from a YAML file of key/value pairs.
+// The file is read on every detection, so changes are picked up on reconnect.
+type fileDetector struct {
+	path string
+}
+
+func newFileDetector(path string) *fileDetector {
+	return &fileDetector{
+		path: path,
+	}
+}
+
+// Type returns the type of the detector
+func (d *fileDetector) Type() string {
+	return TypeFile
+}
+
+// Detect returns the attributes in the file
+func (d *fileDetector) Detect(_ context.Context) (map[string]string, error) {
+	contents, err := os.ReadFile(filepath.Clean(d.path))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read attributes file: %w", err)
+	}
+
+	var attributes map[string]string
+	if err := yaml.Unmarshal(contents, &attributes); err != nil {
+		return nil, fmt.Errorf("failed to parse attributes file: %w", err)
+	}
+
+	return attributes, nil
+}
@@ -0,0 +1,41 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package detector
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileDetector(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "attributes.yaml")
+	d := newFileDetector(path)
+
+	_, err := d.Detect(context.Background())
+	require.ErrorContains(t, err, "failed to read attributes file")
+
+	require.NoError(t, os.WriteFile(path, []byte("team: core\ndatacenter: east\n"), 0600))
+	attributes, err := d.Detect(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"team": "core", "datacenter": "east"}, attributes)
+
+	require.NoError(t, os.WriteFile(path, []byte("- not a map"), 0600))
+	_, err = d.Detect(context.Background())
+	require.ErrorContains(t, err, "failed to parse attributes file")
+}
@@ -0,0 +1,94 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package detector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// kubernetesDetector detects the pod, namespace, and node the agent is running on.
+// Values are read from environment variables, which are typically populated using the downward API.
+type kubernetesDetector struct {
+	namespacePath string
+}
+
+func newKubernetesDetector() *kubernetesDetector {
+	return &kubernetesDetector{
+		namespacePath: "/var/run/secrets/kubernetes.io/serviceaccount/namespace",
+	}
+}
+
+// Type returns the type of the detector
+func (d *kubernetesDetector) Type() string {
+	return TypeKubernetes
+}
+
+// Detect returns the Kubernetes attributes, if the agent is running in a Kubernetes pod
+func (d *kubernetesDetector) Detect(_ context.Context) (map[string]string, error) {
+	// Kubernetes sets this for every container
+	if os.Getenv("KUBERNETES_SERVICE_HOST") == "" {
+		return nil, nil
+	}
+
+	attributes := map[string]string{}
+
+	// The pod's hostname is the pod name unless it's overridden in the pod spec
+	podName := getenv("K8S_POD_NAME", "POD_NAME")
+	if podName == "" {
+		hostname, err := os.Hostname()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get hostname: %w", err)
+		}
+		podName = hostname
+	}
+	attributes["k8s.pod.name"] = podName
+
+	namespace := getenv("K8S_NAMESPACE", "POD_NAMESPACE")
+	if namespace == "" {
+		contents, err := os.ReadFile(filepath.Clean(d.namespacePath))
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("failed to read namespace: %w", err)
+		}
+		namespace = strings.TrimSpace(string(contents))
+	}
+	if namespace != "" {
+		attributes["k8s.namespace.name"] = namespace
+	}
+
+	if uid := getenv("K8S_POD_UID", "POD_UID"); uid != "" {
+		attributes["k8s.pod.uid"] = uid
+	}
+
+	if nodeName := getenv("K8S_NODE_NAME", "NODE_NAME"); nodeName != "" {
+		attributes["k8s.node.name"] = nodeName
+	}
+
+	return attributes, nil
+}
+
+// getenv returns the value of the first of the environment variables that is set
+func getenv(keys ...string) string {
+	for _, key := range keys {
+		if value := os.Getenv(key); value != "" {
+			return value
+		}
+	}
+	return ""
+}
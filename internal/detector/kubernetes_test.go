@@ -0,0 +1,72 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package detector
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestKubernetesDetector(t *testing.T) {
+	t.Run("Not in Kubernetes", func(t *testing.T) {
+		t.Setenv("KUBERNETES_SERVICE_HOST", "")
+
+		attributes, err := newKubernetesDetector().Detect(context.Background())
+		require.NoError(t, err)
+		require.Nil(t, attributes)
+	})
+
+	t.Run("Downward API environment", func(t *testing.T) {
+		t.Setenv("KUBERNETES_SERVICE_HOST", "10.0.0.1")
+		t.Setenv("K8S_POD_NAME", "agent-abcde")
+		t.Setenv("K8S_NAMESPACE", "observability")
+		t.Setenv("K8S_POD_UID", "0c8a7b0e-3a8f-4b4e-9d55-0f7e3a7e2c11")
+		t.Setenv("K8S_NODE_NAME", "node-1")
+
+		attributes, err := newKubernetesDetector().Detect(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{
+			"k8s.pod.name":       "agent-abcde",
+			"k8s.namespace.name": "observability",
+			"k8s.pod.uid":        "0c8a7b0e-3a8f-4b4e-9d55-0f7e3a7e2c11",
+			"k8s.node.name":      "node-1",
+		}, attributes)
+	})
+
+	t.Run("Defaults without downward API", func(t *testing.T) {
+		t.Setenv("KUBERNETES_SERVICE_HOST", "10.0.0.1")
+		for _, key := range []string{"K8S_POD_NAME", "POD_NAME", "K8S_NAMESPACE", "POD_NAMESPACE", "K8S_POD_UID", "POD_UID", "K8S_NODE_NAME", "NODE_NAME"} {
+			t.Setenv(key, "")
+		}
+
+		d := newKubernetesDetector()
+		d.namespacePath = filepath.Join(t.TempDir(), "namespace")
+		require.NoError(t, os.WriteFile(d.namespacePath, []byte("default\n"), 0600))
+
+		hostname, err := os.Hostname()
+		require.NoError(t, err)
+
+		attributes, err := d.Detect(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{
+			"k8s.pod.name":       hostname,
+			"k8s.namespace.name": "default",
+		}, attributes)
+	})
+}
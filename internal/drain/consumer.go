@@ -0,0 +1,66 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package drain
+
+import (
+	"context"
+
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+// logConsumer records the logs passed to the next consumer during a drain
+type logConsumer struct {
+	id string
+	consumer.Logs
+}
+
+// ConsumeLogs passes the logs to the next consumer and records the outcome
+func (l *logConsumer) ConsumeLogs(ctx context.Context, ld plog.Logs) error {
+	count := ld.LogRecordCount()
+	err := l.Logs.ConsumeLogs(ctx, ld)
+	tracker.record(l.id, count, err)
+	return err
+}
+
+// metricConsumer records the metric data points passed to the next consumer during a drain
+type metricConsumer struct {
+	id string
+	consumer.Metrics
+}
+
+// ConsumeMetrics passes the metrics to the next consumer and records the outcome
+func (m *metricConsumer) ConsumeMetrics(ctx context.Context, md pmetric.Metrics) error {
+	count := md.DataPointCount()
+	err := m.Metrics.ConsumeMetrics(ctx, md)
+	tracker.record(m.id, count, err)
+	return err
+}
+
+// traceConsumer records the spans passed to the next consumer during a drain
+type traceConsumer struct {
+	id string
+	consumer.Traces
+}
+
+// ConsumeTraces passes the traces to the next consumer and records the outcome
+func (t *traceConsumer) ConsumeTraces(ctx context.Context, td ptrace.Traces) error {
+	count := td.SpanCount()
+	err := t.Traces.ConsumeTraces(ctx, td)
+	tracker.record(t.id, count, err)
+	return err
+}
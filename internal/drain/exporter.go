@@ -0,0 +1,135 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package drain
+
+import (
+	"context"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/exporter"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+// WrapExporterFactory wraps the factory so that exporters created by it record what they accept during a drain
+// and stop waiting on their shutdown once the drain deadline passes.
+func WrapExporterFactory(exporterFactory exporter.Factory) exporter.Factory {
+	return exporter.NewFactory(
+		exporterFactory.Type(),
+		exporterFactory.CreateDefaultConfig,
+		exporter.WithMetrics(
+			wrapCreateMetricsExporterFunc(exporterFactory.CreateMetricsExporter), exporterFactory.MetricsExporterStability(),
+		),
+		exporter.WithLogs(
+			wrapCreateLogsExporterFunc(exporterFactory.CreateLogsExporter), exporterFactory.LogsExporterStability(),
+		),
+		exporter.WithTraces(
+			wrapCreateTracesExporterFunc(exporterFactory.CreateTracesExporter), exporterFactory.TracesExporterStability(),
+		),
+	)
+}
+
+func wrapCreateMetricsExporterFunc(createMetricsExporterFunc exporter.CreateMetricsFunc) exporter.CreateMetricsFunc {
+	return func(ctx context.Context, set exporter.CreateSettings, cfg component.Config) (exporter.Metrics, error) {
+		e, err := createMetricsExporterFunc(ctx, set, cfg)
+		if err != nil {
+			return nil, err
+		}
+		return &metricsExporter{Metrics: e, id: exporterID(set)}, nil
+	}
+}
+
+func wrapCreateLogsExporterFunc(createLogsExporterFunc exporter.CreateLogsFunc) exporter.CreateLogsFunc {
+	return func(ctx context.Context, set exporter.CreateSettings, cfg component.Config) (exporter.Logs, error) {
+		e, err := createLogsExporterFunc(ctx, set, cfg)
+		if err != nil {
+			return nil, err
+		}
+		return &logsExporter{Logs: e, id: exporterID(set)}, nil
+	}
+}
+
+func wrapCreateTracesExporterFunc(createTracesExporterFunc exporter.CreateTracesFunc) exporter.CreateTracesFunc {
+	return func(ctx context.Context, set exporter.CreateSettings, cfg component.Config) (exporter.Traces, error) {
+		e, err := createTracesExporterFunc(ctx, set, cfg)
+		if err != nil {
+			return nil, err
+		}
+		return &tracesExporter{Traces: e, id: exporterID(set)}, nil
+	}
+}
+
+// exporterID returns the ID used to record the exporter's drain outcome
+func exporterID(set exporter.CreateSettings) string {
+	return "exporter/" + set.ID.String()
+}
+
+// metricsExporter records the data points a metrics exporter accepts during a drain
+type metricsExporter struct {
+	exporter.Metrics
+	id string
+}
+
+// ConsumeMetrics exports the metrics and records the outcome
+func (e *metricsExporter) ConsumeMetrics(ctx context.Context, md pmetric.Metrics) error {
+	count := md.DataPointCount()
+	err := e.Metrics.ConsumeMetrics(ctx, md)
+	tracker.record(e.id, count, err)
+	return err
+}
+
+// Shutdown shuts down the exporter
+func (e *metricsExporter) Shutdown(ctx context.Context) error {
+	return tracker.shutdown(ctx, e.id, e.Metrics.Shutdown)
+}
+
+// logsExporter records the logs a logs exporter accepts during a drain
+type logsExporter struct {
+	exporter.Logs
+	id string
+}
+
+// ConsumeLogs exports the logs and records the outcome
+func (e *logsExporter) ConsumeLogs(ctx context.Context, ld plog.Logs) error {
+	count := ld.LogRecordCount()
+	err := e.Logs.ConsumeLogs(ctx, ld)
+	tracker.record(e.id, count, err)
+	return err
+}
+
+// Shutdown shuts down the exporter
+func (e *logsExporter) Shutdown(ctx context.Context) error {
+	return tracker.shutdown(ctx, e.id, e.Logs.Shutdown)
+}
+
+// tracesExporter records the spans a traces exporter accepts during a drain
+type tracesExporter struct {
+	exporter.Traces
+	id string
+}
+
+// ConsumeTraces exports the traces and records the outcome
+func (e *tracesExporter) ConsumeTraces(ctx context.Context, td ptrace.Traces) error {
+	count := td.SpanCount()
+	err := e.Traces.ConsumeTraces(ctx, td)
+	tracker.record(e.id, count, err)
+	return err
+}
+
+// Shutdown shuts down the exporter
+func (e *tracesExporter) Shutdown(ctx context.Context) error {
+	return tracker.shutdown(ctx, e.id, e.Traces.Shutdown)
+}
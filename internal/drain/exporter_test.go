@@ -0,0 +1,80 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package drain
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/exporter"
+	"go.opentelemetry.io/collector/exporter/exportertest"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+)
+
+// failingExporter rejects all metrics and never finishes shutting down
+type failingExporter struct {
+	component.StartFunc
+	blockChan chan struct{}
+}
+
+func (e *failingExporter) Capabilities() consumer.Capabilities {
+	return consumer.Capabilities{MutatesData: false}
+}
+
+func (e *failingExporter) ConsumeMetrics(_ context.Context, _ pmetric.Metrics) error {
+	return errors.New("export failed")
+}
+
+func (e *failingExporter) Shutdown(_ context.Context) error {
+	<-e.blockChan
+	return nil
+}
+
+func TestWrapExporterFactory(t *testing.T) {
+	blockChan := make(chan struct{})
+	defer close(blockChan)
+
+	factory := exporter.NewFactory("failing", func() component.Config { return &struct{}{} },
+		exporter.WithMetrics(func(_ context.Context, _ exporter.CreateSettings, _ component.Config) (exporter.Metrics, error) {
+			return &failingExporter{blockChan: blockChan}, nil
+		}, component.StabilityLevelBeta),
+	)
+
+	wrapped := WrapExporterFactory(factory)
+	require.Equal(t, factory.Type(), wrapped.Type())
+	require.Equal(t, component.StabilityLevelBeta, wrapped.MetricsExporterStability())
+	require.Equal(t, component.StabilityLevelUndefined, wrapped.LogsExporterStability())
+
+	set := exportertest.NewNopCreateSettings()
+	set.ID = component.NewID("failing")
+	e, err := wrapped.CreateMetricsExporter(context.Background(), set, wrapped.CreateDefaultConfig())
+	require.NoError(t, err)
+
+	md := pmetric.NewMetrics()
+	md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics().AppendEmpty().SetEmptyGauge().DataPoints().AppendEmpty()
+
+	Begin(time.Now().Add(10 * time.Millisecond))
+	require.Error(t, e.ConsumeMetrics(context.Background(), md))
+	require.ErrorIs(t, e.Shutdown(context.Background()), context.DeadlineExceeded)
+	summary := End()
+
+	require.Equal(t, int64(1), summary.Dropped)
+	require.Equal(t, []ComponentSummary{{ID: "exporter/failing", Dropped: 1, TimedOut: true}}, summary.Components)
+}
@@ -0,0 +1,150 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package drain
+
+import (
+	"context"
+	"errors"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/processor"
+)
+
+// errDiscarded is recorded for items a processor discarded when it shut down
+var errDiscarded = errors.New("discarded on shutdown")
+
+// discardReporter is implemented by processors that discard what they hold when they shut down.
+// Processors that send to a route receiver can't flush on shutdown, since receivers stop first.
+type discardReporter interface {
+	// DiscardedOnShutdown returns the number of items discarded when the processor shut down
+	DiscardedOnShutdown() int
+}
+
+// WrapProcessorFactory wraps the factory so that processors created by it record what they flush during a drain
+// and stop waiting on their shutdown once the drain deadline passes.
+func WrapProcessorFactory(processorFactory processor.Factory) processor.Factory {
+	return processor.NewFactory(
+		processorFactory.Type(),
+		processorFactory.CreateDefaultConfig,
+		processor.WithMetrics(
+			wrapCreateMetricsProcessorFunc(processorFactory.CreateMetricsProcessor), processorFactory.MetricsProcessorStability(),
+		),
+		processor.WithLogs(
+			wrapCreateLogsProcessorFunc(processorFactory.CreateLogsProcessor), processorFactory.LogsProcessorStability(),
+		),
+		processor.WithTraces(
+			wrapCreateTracesProcessorFunc(processorFactory.CreateTracesProcessor), processorFactory.TracesProcessorStability(),
+		),
+	)
+}
+
+func wrapCreateMetricsProcessorFunc(createMetricsProcessorFunc processor.CreateMetricsFunc) processor.CreateMetricsFunc {
+	return func(ctx context.Context,
+		set processor.CreateSettings,
+		cfg component.Config,
+		nextConsumer consumer.Metrics,
+	) (processor.Metrics, error) {
+		id := processorID(set)
+		wrappedConsumer := &metricConsumer{id: id, Metrics: nextConsumer}
+		p, err := createMetricsProcessorFunc(ctx, set, cfg, wrappedConsumer)
+		if err != nil {
+			return nil, err
+		}
+		return &metricsProcessor{Metrics: p, id: id}, nil
+	}
+}
+
+func wrapCreateLogsProcessorFunc(createLogsProcessorFunc processor.CreateLogsFunc) processor.CreateLogsFunc {
+	return func(ctx context.Context,
+		set processor.CreateSettings,
+		cfg component.Config,
+		nextConsumer consumer.Logs,
+	) (processor.Logs, error) {
+		id := processorID(set)
+		wrappedConsumer := &logConsumer{id: id, Logs: nextConsumer}
+		p, err := createLogsProcessorFunc(ctx, set, cfg, wrappedConsumer)
+		if err != nil {
+			return nil, err
+		}
+		return &logsProcessor{Logs: p, id: id}, nil
+	}
+}
+
+func wrapCreateTracesProcessorFunc(createTracesProcessorFunc processor.CreateTracesFunc) processor.CreateTracesFunc {
+	return func(ctx context.Context,
+		set processor.CreateSettings,
+		cfg component.Config,
+		nextConsumer consumer.Traces,
+	) (processor.Traces, error) {
+		id := processorID(set)
+		wrappedConsumer := &traceConsumer{id: id, Traces: nextConsumer}
+		p, err := createTracesProcessorFunc(ctx, set, cfg, wrappedConsumer)
+		if err != nil {
+			return nil, err
+		}
+		return &tracesProcessor{Traces: p, id: id}, nil
+	}
+}
+
+// processorID returns the ID used to record the processor's drain outcome
+func processorID(set processor.CreateSettings) string {
+	return "processor/" + set.ID.String()
+}
+
+// metricsProcessor bounds the shutdown of a metrics processor by the drain deadline
+type metricsProcessor struct {
+	processor.Metrics
+	id string
+}
+
+// Shutdown shuts down the processor
+func (p *metricsProcessor) Shutdown(ctx context.Context) error {
+	return shutdownProcessor(ctx, p.id, p.Metrics)
+}
+
+// logsProcessor bounds the shutdown of a logs processor by the drain deadline
+type logsProcessor struct {
+	processor.Logs
+	id string
+}
+
+// Shutdown shuts down the processor
+func (p *logsProcessor) Shutdown(ctx context.Context) error {
+	return shutdownProcessor(ctx, p.id, p.Logs)
+}
+
+// tracesProcessor bounds the shutdown of a traces processor by the drain deadline
+type tracesProcessor struct {
+	processor.Traces
+	id string
+}
+
+// Shutdown shuts down the processor
+func (p *tracesProcessor) Shutdown(ctx context.Context) error {
+	return shutdownProcessor(ctx, p.id, p.Traces)
+}
+
+// shutdownProcessor shuts down the processor, recording the items it discarded as dropped
+func shutdownProcessor(ctx context.Context, id string, p component.Component) error {
+	if err := tracker.shutdown(ctx, id, p.Shutdown); err != nil {
+		return err
+	}
+
+	if reporter, ok := p.(discardReporter); ok {
+		tracker.record(id, reporter.DiscardedOnShutdown(), errDiscarded)
+	}
+	return nil
+}
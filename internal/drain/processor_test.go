@@ -0,0 +1,117 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package drain
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/processor"
+	"go.opentelemetry.io/collector/processor/processortest"
+)
+
+// flushingProcessor holds logs until it is shut down
+type flushingProcessor struct {
+	component.StartFunc
+	next consumer.Logs
+	logs plog.Logs
+}
+
+func (p *flushingProcessor) Capabilities() consumer.Capabilities {
+	return consumer.Capabilities{MutatesData: false}
+}
+
+func (p *flushingProcessor) ConsumeLogs(_ context.Context, ld plog.Logs) error {
+	ld.ResourceLogs().MoveAndAppendTo(p.logs.ResourceLogs())
+	return nil
+}
+
+func (p *flushingProcessor) Shutdown(ctx context.Context) error {
+	return p.next.ConsumeLogs(ctx, p.logs)
+}
+
+func TestWrapProcessorFactory(t *testing.T) {
+	factory := processor.NewFactory("flushing", func() component.Config { return &struct{}{} },
+		processor.WithLogs(func(_ context.Context, _ processor.CreateSettings, _ component.Config, next consumer.Logs) (processor.Logs, error) {
+			return &flushingProcessor{next: next, logs: plog.NewLogs()}, nil
+		}, component.StabilityLevelAlpha),
+	)
+
+	wrapped := WrapProcessorFactory(factory)
+	require.Equal(t, factory.Type(), wrapped.Type())
+	require.Equal(t, component.StabilityLevelAlpha, wrapped.LogsProcessorStability())
+	require.Equal(t, component.StabilityLevelUndefined, wrapped.MetricsProcessorStability())
+
+	sink := &consumertest.LogsSink{}
+	set := processortest.NewNopCreateSettings()
+	set.ID = component.NewID("flushing")
+	p, err := wrapped.CreateLogsProcessor(context.Background(), set, wrapped.CreateDefaultConfig(), sink)
+	require.NoError(t, err)
+	require.NoError(t, p.Start(context.Background(), componenttest.NewNopHost()))
+
+	ld := plog.NewLogs()
+	records := ld.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords()
+	records.AppendEmpty()
+	records.AppendEmpty()
+	require.NoError(t, p.ConsumeLogs(context.Background(), ld))
+
+	Begin(time.Now().Add(time.Minute))
+	require.NoError(t, p.Shutdown(context.Background()))
+	summary := End()
+
+	require.Equal(t, 2, sink.LogRecordCount())
+	require.Equal(t, int64(2), summary.Flushed)
+	require.Equal(t, []ComponentSummary{{ID: "processor/flushing", Flushed: 2}}, summary.Components)
+}
+
+// discardingProcessor discards the logs it holds when it is shut down
+type discardingProcessor struct {
+	component.StartFunc
+	component.ShutdownFunc
+	consumer.Logs
+	discarded int
+}
+
+func (p *discardingProcessor) DiscardedOnShutdown() int {
+	return p.discarded
+}
+
+func TestWrapProcessorFactoryDiscarded(t *testing.T) {
+	factory := processor.NewFactory("discarding", func() component.Config { return &struct{}{} },
+		processor.WithLogs(func(_ context.Context, _ processor.CreateSettings, _ component.Config, next consumer.Logs) (processor.Logs, error) {
+			return &discardingProcessor{Logs: next, discarded: 3}, nil
+		}, component.StabilityLevelAlpha),
+	)
+
+	set := processortest.NewNopCreateSettings()
+	set.ID = component.NewID("discarding")
+	p, err := WrapProcessorFactory(factory).CreateLogsProcessor(context.Background(), set, factory.CreateDefaultConfig(), &consumertest.LogsSink{})
+	require.NoError(t, err)
+	require.NoError(t, p.Start(context.Background(), componenttest.NewNopHost()))
+
+	Begin(time.Now().Add(time.Minute))
+	require.NoError(t, p.Shutdown(context.Background()))
+	summary := End()
+
+	require.Equal(t, int64(3), summary.Dropped)
+	require.Equal(t, []ComponentSummary{{ID: "processor/discarding", Dropped: 3}}, summary.Components)
+}
@@ -0,0 +1,185 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package drain tracks the telemetry flushed and dropped by processors and exporters while the collector shuts down.
+package drain
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// tracker is the tracker used by all wrapped components
+var tracker = &Tracker{}
+
+// Tracker records how many items each component flushed or dropped during a drain.
+type Tracker struct {
+	draining   atomic.Bool
+	mux        sync.Mutex
+	start      time.Time
+	deadline   time.Time
+	components map[string]*ComponentSummary
+}
+
+// Summary is the outcome of a drain.
+type Summary struct {
+	// Flushed is the number of items passed on successfully during the drain
+	Flushed int64
+	// Dropped is the number of items that failed to be passed on during the drain
+	Dropped int64
+	// Duration is how long the drain took
+	Duration time.Duration
+	// Components contains the outcome for each component, sorted by ID
+	Components []ComponentSummary
+}
+
+// ComponentSummary is the outcome of a drain for a single component.
+type ComponentSummary struct {
+	// ID is the kind and ID of the component, e.g. exporter/otlp
+	ID string
+	// Flushed is the number of items the component passed on successfully during the drain
+	Flushed int64
+	// Dropped is the number of items that the component failed to pass on during the drain
+	Dropped int64
+	// TimedOut is true if the component did not shut down before the drain deadline
+	TimedOut bool
+}
+
+// Begin starts a drain. A zero deadline lets components take as long as they need to shut down.
+func Begin(deadline time.Time) {
+	tracker.Begin(deadline)
+}
+
+// End finishes the current drain and returns its summary.
+func End() Summary {
+	return tracker.End()
+}
+
+// Begin starts a drain. A zero deadline lets components take as long as they need to shut down.
+func (t *Tracker) Begin(deadline time.Time) {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+
+	t.start = time.Now()
+	t.deadline = deadline
+	t.components = make(map[string]*ComponentSummary)
+	t.draining.Store(true)
+}
+
+// End finishes the current drain and returns its summary.
+func (t *Tracker) End() Summary {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+
+	t.draining.Store(false)
+
+	summary := Summary{
+		Duration:   time.Since(t.start),
+		Components: make([]ComponentSummary, 0, len(t.components)),
+	}
+	for _, c := range t.components {
+		summary.Flushed += c.Flushed
+		summary.Dropped += c.Dropped
+		summary.Components = append(summary.Components, *c)
+	}
+
+	sort.Slice(summary.Components, func(i, j int) bool {
+		return summary.Components[i].ID < summary.Components[j].ID
+	})
+
+	t.components = nil
+	return summary
+}
+
+// record adds the outcome of passing on count items to the component's totals.
+// Nothing is recorded outside of a drain.
+func (t *Tracker) record(id string, count int, err error) {
+	if !t.draining.Load() || count == 0 {
+		return
+	}
+
+	t.mux.Lock()
+	defer t.mux.Unlock()
+
+	c := t.component(id)
+	if c == nil {
+		return
+	}
+
+	if err != nil {
+		c.Dropped += int64(count)
+	} else {
+		c.Flushed += int64(count)
+	}
+}
+
+// timedOut marks the component as having missed the drain deadline.
+func (t *Tracker) timedOut(id string) {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+
+	if c := t.component(id); c != nil {
+		c.TimedOut = true
+	}
+}
+
+// component returns the summary for the component, creating it if needed.
+// Returns nil if there is no drain in progress. The mutex must be held.
+func (t *Tracker) component(id string) *ComponentSummary {
+	if t.components == nil {
+		return nil
+	}
+
+	c, ok := t.components[id]
+	if !ok {
+		c = &ComponentSummary{ID: id}
+		t.components[id] = c
+	}
+	return c
+}
+
+// shutdown calls the shutdown function, returning early if it does not finish before the drain deadline.
+func (t *Tracker) shutdown(ctx context.Context, id string, shutdownFunc func(context.Context) error) error {
+	t.mux.Lock()
+	deadline := t.deadline
+	draining := t.draining.Load()
+	t.mux.Unlock()
+
+	if !draining || deadline.IsZero() {
+		return shutdownFunc(ctx)
+	}
+
+	ctx, cancel := context.WithDeadline(ctx, deadline)
+	defer cancel()
+
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- shutdownFunc(ctx)
+	}()
+
+	select {
+	case err := <-errChan:
+		if ctx.Err() != nil {
+			t.timedOut(id)
+		}
+		return err
+	case <-ctx.Done():
+		t.timedOut(id)
+		return fmt.Errorf("%s did not shut down before the drain deadline: %w", id, ctx.Err())
+	}
+}
@@ -0,0 +1,90 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package drain
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrackerRecord(t *testing.T) {
+	tr := &Tracker{}
+
+	// Nothing is recorded outside of a drain
+	tr.record("exporter/otlp", 5, nil)
+
+	tr.Begin(time.Time{})
+	tr.record("exporter/otlp", 10, nil)
+	tr.record("exporter/otlp", 3, errors.New("export failed"))
+	tr.record("processor/batch", 7, nil)
+	tr.timedOut("exporter/otlp")
+
+	summary := tr.End()
+	require.Equal(t, int64(17), summary.Flushed)
+	require.Equal(t, int64(3), summary.Dropped)
+	require.Equal(t, []ComponentSummary{
+		{ID: "exporter/otlp", Flushed: 10, Dropped: 3, TimedOut: true},
+		{ID: "processor/batch", Flushed: 7},
+	}, summary.Components)
+
+	// Nothing is recorded after the drain ends
+	tr.record("exporter/otlp", 5, nil)
+	tr.Begin(time.Time{})
+	require.Empty(t, tr.End().Components)
+}
+
+func TestTrackerShutdown(t *testing.T) {
+	t.Run("Not draining", func(t *testing.T) {
+		tr := &Tracker{}
+		err := tr.shutdown(context.Background(), "exporter/otlp", func(ctx context.Context) error {
+			_, hasDeadline := ctx.Deadline()
+			require.False(t, hasDeadline)
+			return nil
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("Completes before deadline", func(t *testing.T) {
+		tr := &Tracker{}
+		tr.Begin(time.Now().Add(time.Minute))
+
+		err := tr.shutdown(context.Background(), "exporter/otlp", func(ctx context.Context) error {
+			_, hasDeadline := ctx.Deadline()
+			require.True(t, hasDeadline)
+			return nil
+		})
+		require.NoError(t, err)
+		require.Empty(t, tr.End().Components)
+	})
+
+	t.Run("Exceeds deadline", func(t *testing.T) {
+		tr := &Tracker{}
+		tr.Begin(time.Now().Add(10 * time.Millisecond))
+
+		blockChan := make(chan struct{})
+		defer close(blockChan)
+
+		err := tr.shutdown(context.Background(), "exporter/otlp", func(_ context.Context) error {
+			<-blockChan
+			return nil
+		})
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		require.Equal(t, []ComponentSummary{{ID: "exporter/otlp", TimedOut: true}}, tr.End().Components)
+	})
+}
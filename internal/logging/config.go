@@ -16,10 +16,12 @@
 package logging
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
@@ -36,15 +38,53 @@ const (
 
 	// stdOutput is an output option for logging to stdout.
 	stdOutput string = "stdout"
+
+	// syslogOutput is an output option for logging to a local syslog server.
+	syslogOutput string = "syslog"
+)
+
+const (
+	// jsonEncoding is an encoding option for logging as JSON.
+	jsonEncoding string = "json"
+
+	// consoleEncoding is an encoding option for logging in a human readable format.
+	consoleEncoding string = "console"
 )
 
 // LoggerConfig is the configuration of a logger.
 type LoggerConfig struct {
-	Output string             `yaml:"output"`
-	Level  zapcore.Level      `yaml:"level"`
-	File   *lumberjack.Logger `yaml:"file,omitempty"`
+	Output string `yaml:"output"`
+
+	// Outputs are the outputs logs are written to at the same time. If set, Output is ignored.
+	Outputs []string `yaml:"outputs,omitempty"`
+
+	// Encoding is the format of the logs, either json or console. Defaults to json.
+	Encoding string `yaml:"encoding,omitempty"`
+
+	Level zapcore.Level `yaml:"level"`
+
+	// Components overrides the level of individual components.
+	// Keys are the component kind and ID, such as exporter/chronicle.
+	// An override for a component type, such as exporter/chronicle, applies to all components of that type.
+	Components map[string]zapcore.Level `yaml:"components,omitempty"`
+
+	File *lumberjack.Logger `yaml:"file,omitempty"`
+
+	Syslog   *SyslogConfig   `yaml:"syslog,omitempty"`
+	Sampling *SamplingConfig `yaml:"sampling,omitempty"`
+}
+
+// SamplingConfig limits the number of logs with the same level and message.
+// Each tick, the first Initial of these logs are written, then every Thereafter log after that.
+type SamplingConfig struct {
+	Initial    int           `yaml:"initial"`
+	Thereafter int           `yaml:"thereafter"`
+	Tick       time.Duration `yaml:"tick,omitempty"`
 }
 
+// defaultSamplingTick is the tick of sampling if one isn't specified
+const defaultSamplingTick = time.Second
+
 // NewLoggerConfig returns a logger config.
 // If configPath is not set, stdout logging will be enabled, and a default
 // configuration will be written to ./logging.yaml
@@ -84,39 +124,201 @@ func NewLoggerConfig(configPath string) (*LoggerConfig, error) {
 		conf.File.Filename = os.ExpandEnv(conf.File.Filename)
 	}
 
+	if err := conf.validate(); err != nil {
+		return nil, err
+	}
+
 	return conf, nil
 }
 
+// validate returns an error if the outputs, encoding, levels, or sampling of the config are invalid
+func (l *LoggerConfig) validate() error {
+	outputs := l.outputs()
+	if len(outputs) == 0 {
+		return errors.New("at least one output must be specified")
+	}
+
+	seen := make(map[string]bool, len(outputs))
+	for _, output := range outputs {
+		if seen[output] {
+			return fmt.Errorf("duplicate output: %s", output)
+		}
+		seen[output] = true
+
+		switch output {
+		case stdOutput:
+		case fileOutput:
+			if l.File == nil || l.File.Filename == "" {
+				return errors.New("file output requires file.filename to be set")
+			}
+		case syslogOutput:
+			syslogCfg := l.Syslog
+			if syslogCfg == nil {
+				syslogCfg = &SyslogConfig{}
+			}
+			if err := syslogCfg.validate(); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("unrecognized output type: %s", output)
+		}
+	}
+
+	switch l.Encoding {
+	case "", jsonEncoding, consoleEncoding:
+	default:
+		return fmt.Errorf("unrecognized encoding: %s", l.Encoding)
+	}
+
+	if err := ValidateComponentLevels(l.Components); err != nil {
+		return err
+	}
+
+	if l.Sampling != nil {
+		if l.Sampling.Initial <= 0 {
+			return errors.New("sampling initial must be greater than 0")
+		}
+		if l.Sampling.Thereafter < 0 {
+			return errors.New("sampling thereafter must not be negative")
+		}
+		if l.Sampling.Tick < 0 {
+			return errors.New("sampling tick must not be negative")
+		}
+	}
+
+	return nil
+}
+
+// outputs returns the outputs logs are written to
+func (l *LoggerConfig) outputs() []string {
+	if len(l.Outputs) > 0 {
+		return l.Outputs
+	}
+
+	if l.Output == "" {
+		return nil
+	}
+
+	return []string{l.Output}
+}
+
 // Options returns the LoggerConfig's zap logging options.
-func (l *LoggerConfig) Options() ([]zap.Option, error) {
-	core, err := l.core()
+// The levels of the config are applied to all loggers created from a LoggerConfig, see SetLevels.
+// The returned cleanup func closes the syslog connections of the options being replaced. Call it once
+// loggers use the new options, since loggers still using the replaced options would reconnect.
+func (l *LoggerConfig) Options() (opts []zap.Option, cleanup func(), err error) {
+	core, syslogWriters, err := l.core()
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+
+	cleanup = func() {
+		replaceSyslogWriters(syslogWriters)
 	}
 
+	l.SetLevels()
+	core = newLevelCore(core, globalLevels)
+
 	opt := zap.WrapCore(func(c zapcore.Core) zapcore.Core {
 		return core
 	})
 
-	return []zap.Option{opt}, nil
+	return []zap.Option{opt}, cleanup, nil
+}
+
+// SetLevels changes the level and component overrides of all loggers created from a LoggerConfig.
+// The change takes effect immediately, without recreating the loggers.
+func (l *LoggerConfig) SetLevels() {
+	globalLevels.set(l.Level, l.Components)
+}
+
+// OnlyLevelsChanged returns true if the config only differs from prev by its level and component overrides.
+// A change like this can be applied with SetLevels, without recreating loggers.
+func (l *LoggerConfig) OnlyLevelsChanged(prev *LoggerConfig) bool {
+	current, err := l.withoutLevels()
+	if err != nil {
+		return false
+	}
+
+	previous, err := prev.withoutLevels()
+	if err != nil {
+		return false
+	}
+
+	return bytes.Equal(current, previous)
 }
 
-// core returns the logging core specified in the config.
-// An unknown output will return a nop core.
-func (l *LoggerConfig) core() (zapcore.Core, error) {
-	switch l.Output {
+// withoutLevels returns the marshaled config, excluding the level and component overrides
+func (l *LoggerConfig) withoutLevels() ([]byte, error) {
+	c := *l
+	c.Level = zapcore.InfoLevel
+	c.Components = nil
+	return yaml.Marshal(&c)
+}
+
+// core returns the logging core specified in the config, writing to all of its outputs, and the syslog writers it uses.
+// The core enables all levels, as levels are filtered by the levelCore wrapping it.
+func (l *LoggerConfig) core() (zapcore.Core, []*syslogWriter, error) {
+	outputs := l.outputs()
+	cores := make([]zapcore.Core, 0, len(outputs))
+	var syslogWriters []*syslogWriter
+	for _, output := range outputs {
+		core, err := l.outputCore(output)
+		if err != nil {
+			return nil, nil, err
+		}
+		if sc, ok := core.(*syslogCore); ok {
+			syslogWriters = append(syslogWriters, sc.writer)
+		}
+		cores = append(cores, core)
+	}
+
+	core := zapcore.NewTee(cores...)
+
+	if l.Sampling != nil {
+		tick := l.Sampling.Tick
+		if tick == 0 {
+			tick = defaultSamplingTick
+		}
+		core = zapcore.NewSamplerWithOptions(core, tick, l.Sampling.Initial, l.Sampling.Thereafter)
+	}
+
+	return core, syslogWriters, nil
+}
+
+// outputCore returns the core writing to the output
+func (l *LoggerConfig) outputCore(output string) (zapcore.Core, error) {
+	switch output {
 	case fileOutput:
-		return zapcore.NewCore(newEncoder(), zapcore.AddSync(l.File), l.Level), nil
+		if l.File == nil {
+			return nil, errors.New("file output requires file.filename to be set")
+		}
+		return zapcore.NewCore(l.newEncoder(), zapcore.AddSync(l.File), zapcore.DebugLevel), nil
 	case stdOutput:
-		return zapcore.NewCore(newEncoder(), zapcore.Lock(os.Stdout), l.Level), nil
+		return zapcore.NewCore(l.newEncoder(), zapcore.Lock(os.Stdout), zapcore.DebugLevel), nil
+	case syslogOutput:
+		syslogCfg := l.Syslog
+		if syslogCfg == nil {
+			syslogCfg = &SyslogConfig{}
+		}
+		writer, err := newSyslogWriter(syslogCfg)
+		if err != nil {
+			return nil, err
+		}
+		return newSyslogCore(l.newEncoder(), writer), nil
 	default:
-		return nil, fmt.Errorf("unrecognized output type: %s", l.Output)
+		return nil, fmt.Errorf("unrecognized output type: %s", output)
 	}
 }
 
-func newEncoder() zapcore.Encoder {
+// newEncoder returns the encoder for the encoding of the config
+func (l *LoggerConfig) newEncoder() zapcore.Encoder {
 	encoderConfig := zap.NewProductionEncoderConfig()
 	encoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
+
+	if l.Encoding == consoleEncoding {
+		return zapcore.NewConsoleEncoder(encoderConfig)
+	}
 	return zapcore.NewJSONEncoder(encoderConfig)
 }
 
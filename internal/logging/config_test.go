@@ -17,9 +17,12 @@ package logging
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 	"gopkg.in/natefinch/lumberjack.v2"
 )
@@ -69,6 +72,74 @@ func TestNewLoggerConfig(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:       "config with component levels",
+			configPath: filepath.Join("testdata", "components.yaml"),
+			expect: &LoggerConfig{
+				Output: stdOutput,
+				Level:  zapcore.InfoLevel,
+				Components: map[string]zapcore.Level{
+					"exporter/chronicle":   zapcore.DebugLevel,
+					"receiver/filelog/app": zapcore.WarnLevel,
+				},
+			},
+		},
+		{
+			name:        "config with invalid component",
+			configPath:  filepath.Join("testdata", "invalid-component.yaml"),
+			expectedErr: `invalid component "chronicle"`,
+		},
+		{
+			name:       "config with multiple outputs",
+			configPath: filepath.Join("testdata", "multiple-outputs.yaml"),
+			expect: &LoggerConfig{
+				Output:   stdOutput,
+				Outputs:  []string{fileOutput, stdOutput},
+				Encoding: consoleEncoding,
+				Level:    zapcore.InfoLevel,
+				File: &lumberjack.Logger{
+					Filename: "log/collector.log",
+				},
+				Sampling: &SamplingConfig{
+					Initial:    10,
+					Thereafter: 100,
+					Tick:       5 * time.Second,
+				},
+			},
+		},
+		{
+			name:       "syslog config",
+			configPath: filepath.Join("testdata", "syslog.yaml"),
+			expect: &LoggerConfig{
+				Output: syslogOutput,
+				Level:  zapcore.InfoLevel,
+				Syslog: &SyslogConfig{
+					Endpoint: "udp://localhost:514",
+					Facility: "local0",
+					Tag:      "agent",
+				},
+			},
+		},
+		{
+			name:        "config with invalid encoding",
+			configPath:  filepath.Join("testdata", "invalid-encoding.yaml"),
+			expectedErr: "unrecognized encoding: xml",
+		},
+		{
+			name:        "config with remote syslog endpoint",
+			configPath:  filepath.Join("testdata", "invalid-syslog.yaml"),
+			expectedErr: "must be bound to localhost",
+		},
+		{
+			name:        "config with invalid sampling",
+			configPath:  filepath.Join("testdata", "invalid-sampling.yaml"),
+			expectedErr: "sampling initial must be greater than 0",
+		},
+		{
+			name:        "config with file output missing filename",
+			configPath:  filepath.Join("testdata", "file-missing-filename.yaml"),
+			expectedErr: "file output requires file.filename to be set",
+		},
 		{
 			name:        "config does not exist",
 			configPath:  filepath.Join("testdata", "does-not-exist.yaml"),
@@ -93,7 +164,7 @@ func TestNewLoggerConfig(t *testing.T) {
 			require.NoError(t, err)
 			require.Equal(t, tc.expect, conf)
 
-			opts, err := conf.Options()
+			opts, _, err := conf.Options()
 			require.NoError(t, err)
 			require.NotNil(t, opts)
 			require.Len(t, opts, 1)
@@ -165,3 +236,109 @@ func chDir(t *testing.T, dir string) {
 		require.NoError(t, err)
 	})
 }
+
+func TestOnlyLevelsChanged(t *testing.T) {
+	prev := &LoggerConfig{
+		Output: fileOutput,
+		Level:  zapcore.InfoLevel,
+		File:   &lumberjack.Logger{Filename: "collector.log"},
+	}
+
+	levelsChanged := &LoggerConfig{
+		Output:     fileOutput,
+		Level:      zapcore.DebugLevel,
+		Components: map[string]zapcore.Level{"exporter/otlp": zapcore.WarnLevel},
+		File:       &lumberjack.Logger{Filename: "collector.log"},
+	}
+	require.True(t, levelsChanged.OnlyLevelsChanged(prev))
+
+	fileChanged := &LoggerConfig{
+		Output: fileOutput,
+		Level:  zapcore.DebugLevel,
+		File:   &lumberjack.Logger{Filename: "agent.log"},
+	}
+	require.False(t, fileChanged.OnlyLevelsChanged(prev))
+
+	outputChanged := &LoggerConfig{
+		Output: stdOutput,
+		Level:  zapcore.InfoLevel,
+	}
+	require.False(t, outputChanged.OnlyLevelsChanged(prev))
+}
+
+func TestLoggerConfigValidate(t *testing.T) {
+	cases := []struct {
+		name        string
+		config      LoggerConfig
+		expectedErr string
+	}{
+		{
+			name:   "outputs override output",
+			config: LoggerConfig{Output: "unknown", Outputs: []string{stdOutput}},
+		},
+		{
+			name:        "no outputs",
+			config:      LoggerConfig{},
+			expectedErr: "at least one output must be specified",
+		},
+		{
+			name:        "duplicate outputs",
+			config:      LoggerConfig{Outputs: []string{stdOutput, stdOutput}},
+			expectedErr: "duplicate output: stdout",
+		},
+		{
+			name:        "unknown output",
+			config:      LoggerConfig{Outputs: []string{stdOutput, "kafka"}},
+			expectedErr: "unrecognized output type: kafka",
+		},
+		{
+			name:        "invalid syslog facility",
+			config:      LoggerConfig{Output: syslogOutput, Syslog: &SyslogConfig{Facility: "local9"}},
+			expectedErr: "invalid syslog facility: local9",
+		},
+		{
+			name:        "negative sampling tick",
+			config:      LoggerConfig{Output: stdOutput, Sampling: &SamplingConfig{Initial: 1, Tick: -time.Second}},
+			expectedErr: "sampling tick must not be negative",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.config.validate()
+			if tc.expectedErr != "" {
+				require.ErrorContains(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestOptionsSampling(t *testing.T) {
+	conf := &LoggerConfig{
+		Outputs:  []string{fileOutput},
+		Encoding: consoleEncoding,
+		Level:    zapcore.InfoLevel,
+		File:     &lumberjack.Logger{Filename: filepath.Join(t.TempDir(), "collector.log")},
+		Sampling: &SamplingConfig{Initial: 2, Thereafter: 0, Tick: time.Minute},
+	}
+
+	opts, _, err := conf.Options()
+	require.NoError(t, err)
+
+	logger := zap.NewNop().WithOptions(opts...)
+	for i := 0; i < 10; i++ {
+		logger.Info("repeated")
+	}
+	logger.Debug("filtered")
+	require.NoError(t, logger.Sync())
+
+	contents, err := os.ReadFile(conf.File.Filename)
+	require.NoError(t, err)
+
+	// Console encoded lines are tab separated
+	lines := strings.Split(strings.TrimSpace(string(contents)), "\n")
+	require.Len(t, lines, 2)
+	require.Contains(t, lines[0], "\tinfo\trepeated")
+}
@@ -0,0 +1,155 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	// kindKey and nameKey are the fields the collector adds to the logger of each component
+	kindKey = "kind"
+	nameKey = "name"
+)
+
+// componentKinds are the kinds of component a level override can be set for
+var componentKinds = []string{"receiver", "processor", "exporter", "extension", "connector"}
+
+// levels holds the log levels of all loggers created from a LoggerConfig.
+// Loggers check the levels on each entry, so changing them takes effect without rebuilding the loggers.
+type levels struct {
+	global     zap.AtomicLevel
+	components atomic.Pointer[map[string]zapcore.Level]
+}
+
+// globalLevels are the levels shared by all loggers created from a LoggerConfig
+var globalLevels = newLevels()
+
+func newLevels() *levels {
+	l := &levels{global: zap.NewAtomicLevel()}
+	l.components.Store(&map[string]zapcore.Level{})
+	return l
+}
+
+// set replaces the global level and component overrides
+func (l *levels) set(global zapcore.Level, components map[string]zapcore.Level) {
+	overrides := make(map[string]zapcore.Level, len(components))
+	for component, level := range components {
+		overrides[component] = level
+	}
+
+	l.components.Store(&overrides)
+	l.global.SetLevel(global)
+}
+
+// levelFor returns the level of the component with the kind and ID.
+// An override for the exact component ID is used over one for its type.
+func (l *levels) levelFor(kind, id string) zapcore.Level {
+	if kind != "" && id != "" {
+		overrides := *l.components.Load()
+		if level, ok := overrides[kind+"/"+id]; ok {
+			return level
+		}
+
+		componentType, _, _ := strings.Cut(id, "/")
+		if level, ok := overrides[kind+"/"+componentType]; ok {
+			return level
+		}
+	}
+
+	return l.global.Level()
+}
+
+// ValidateComponentLevels returns an error if a component override isn't in the form kind/id
+func ValidateComponentLevels(components map[string]zapcore.Level) error {
+	for component := range components {
+		kind, id, _ := strings.Cut(component, "/")
+		if id == "" || !isComponentKind(kind) {
+			return fmt.Errorf("invalid component %q, must be in the form <kind>/<id> where kind is one of %s", component, strings.Join(componentKinds, ", "))
+		}
+	}
+
+	return nil
+}
+
+func isComponentKind(kind string) bool {
+	for _, k := range componentKinds {
+		if kind == k {
+			return true
+		}
+	}
+	return false
+}
+
+// levelCore is a core that filters entries by the level of the component it logs for
+type levelCore struct {
+	zapcore.Core
+	levels *levels
+	kind   string
+	id     string
+}
+
+// newLevelCore wraps the core so entries are filtered by the shared levels.
+// The wrapped core should enable all levels.
+func newLevelCore(core zapcore.Core, levels *levels) zapcore.Core {
+	return &levelCore{
+		Core:   core,
+		levels: levels,
+	}
+}
+
+// Enabled returns whether the level is enabled for the component
+func (c *levelCore) Enabled(level zapcore.Level) bool {
+	return c.levels.levelFor(c.kind, c.id).Enabled(level)
+}
+
+// With adds fields to the core, keeping track of the component the core logs for
+func (c *levelCore) With(fields []zapcore.Field) zapcore.Core {
+	clone := &levelCore{
+		Core:   c.Core.With(fields),
+		levels: c.levels,
+		kind:   c.kind,
+		id:     c.id,
+	}
+
+	for _, field := range fields {
+		if field.Type != zapcore.StringType {
+			continue
+		}
+
+		switch field.Key {
+		case kindKey:
+			clone.kind = field.String
+		case nameKey:
+			clone.id = field.String
+		}
+	}
+
+	return clone
+}
+
+// Check passes the entry to the wrapped core if the entry's level is enabled.
+// The wrapped core is checked rather than added directly, so wrapped cores like samplers see each entry.
+func (c *levelCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return c.Core.Check(entry, checked)
+	}
+	return checked
+}
@@ -0,0 +1,88 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestLevelCore(t *testing.T) {
+	levels := newLevels()
+	observed, logs := observer.New(zapcore.DebugLevel)
+	logger := zap.New(newLevelCore(observed, levels))
+
+	chronicle := logger.With(zap.String(kindKey, "exporter"), zap.String(nameKey, "chronicle"))
+	chronicleBackup := logger.With(zap.String(nameKey, "chronicle/backup"), zap.String(kindKey, "exporter"))
+	filelog := logger.With(zap.String(kindKey, "receiver"), zap.String(nameKey, "filelog"))
+
+	levels.set(zapcore.InfoLevel, map[string]zapcore.Level{
+		"exporter/chronicle":      zapcore.DebugLevel,
+		"receiver/filelog":        zapcore.ErrorLevel,
+		"exporter/chronicle/main": zapcore.ErrorLevel,
+	})
+
+	logger.Debug("agent debug")
+	logger.Info("agent info")
+	chronicle.Debug("chronicle debug")
+	chronicleBackup.Debug("chronicle backup debug")
+	filelog.Warn("filelog warn")
+	filelog.Error("filelog error")
+
+	messages := []string{}
+	for _, entry := range logs.TakeAll() {
+		messages = append(messages, entry.Message)
+	}
+	require.Equal(t, []string{"agent info", "chronicle debug", "chronicle backup debug", "filelog error"}, messages)
+
+	// Changing levels applies to existing loggers
+	levels.set(zapcore.DebugLevel, nil)
+
+	logger.Debug("agent debug")
+	filelog.Warn("filelog warn")
+	require.Equal(t, 2, logs.Len())
+}
+
+func TestLevelFor(t *testing.T) {
+	levels := newLevels()
+	levels.set(zapcore.WarnLevel, map[string]zapcore.Level{
+		"exporter/otlp":      zapcore.DebugLevel,
+		"exporter/otlp/main": zapcore.ErrorLevel,
+	})
+
+	require.Equal(t, zapcore.WarnLevel, levels.levelFor("", ""))
+	require.Equal(t, zapcore.DebugLevel, levels.levelFor("exporter", "otlp"))
+	require.Equal(t, zapcore.DebugLevel, levels.levelFor("exporter", "otlp/backup"))
+	require.Equal(t, zapcore.ErrorLevel, levels.levelFor("exporter", "otlp/main"))
+	require.Equal(t, zapcore.WarnLevel, levels.levelFor("receiver", "otlp"))
+}
+
+func TestValidateComponentLevels(t *testing.T) {
+	require.NoError(t, ValidateComponentLevels(nil))
+	require.NoError(t, ValidateComponentLevels(map[string]zapcore.Level{
+		"receiver/filelog":       zapcore.DebugLevel,
+		"processor/batch":        zapcore.DebugLevel,
+		"exporter/otlp/main":     zapcore.DebugLevel,
+		"extension/file_storage": zapcore.DebugLevel,
+		"connector/count":        zapcore.DebugLevel,
+	}))
+	require.Error(t, ValidateComponentLevels(map[string]zapcore.Level{"otlp": zapcore.DebugLevel}))
+	require.Error(t, ValidateComponentLevels(map[string]zapcore.Level{"exporter/": zapcore.DebugLevel}))
+	require.Error(t, ValidateComponentLevels(map[string]zapcore.Level{"pipeline/logs": zapcore.DebugLevel}))
+}
@@ -0,0 +1,323 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	// unixScheme and udpScheme are the prefixes of the supported syslog endpoints
+	unixScheme = "unix://"
+	udpScheme  = "udp://"
+
+	// defaultSyslogEndpoint is the local syslog socket on most unix systems
+	defaultSyslogEndpoint = unixScheme + "/dev/log"
+)
+
+// syslogFacilities maps facility names to their syslog codes
+var syslogFacilities = map[string]int{
+	"kern":     0,
+	"user":     1,
+	"mail":     2,
+	"daemon":   3,
+	"auth":     4,
+	"syslog":   5,
+	"lpr":      6,
+	"news":     7,
+	"uucp":     8,
+	"cron":     9,
+	"authpriv": 10,
+	"ftp":      11,
+	"local0":   16,
+	"local1":   17,
+	"local2":   18,
+	"local3":   19,
+	"local4":   20,
+	"local5":   21,
+	"local6":   22,
+	"local7":   23,
+}
+
+// SyslogConfig is the configuration of the syslog output
+type SyslogConfig struct {
+	// Endpoint is the local syslog server, either a unix:// socket path or a udp:// localhost address
+	Endpoint string `yaml:"endpoint,omitempty"`
+
+	// Facility is the syslog facility of the logs, such as daemon or local0
+	Facility string `yaml:"facility,omitempty"`
+
+	// Tag identifies the agent in the syslog messages
+	Tag string `yaml:"tag,omitempty"`
+}
+
+// validate returns an error if the syslog endpoint or facility is invalid
+func (s *SyslogConfig) validate() error {
+	if _, _, err := s.network(); err != nil {
+		return err
+	}
+
+	if _, ok := syslogFacilities[s.facility()]; !ok {
+		return fmt.Errorf("invalid syslog facility: %s", s.Facility)
+	}
+
+	return nil
+}
+
+// network returns the networks to try, and the address of the syslog endpoint.
+// UDP endpoints must be bound to localhost, so logs are never sent over the network unencrypted.
+func (s *SyslogConfig) network() (networks []string, address string, err error) {
+	endpoint := s.Endpoint
+	if endpoint == "" {
+		endpoint = defaultSyslogEndpoint
+	}
+
+	switch {
+	case strings.HasPrefix(endpoint, unixScheme):
+		address = strings.TrimPrefix(endpoint, unixScheme)
+		if address == "" {
+			return nil, "", errors.New("syslog socket path must be specified")
+		}
+		// Local syslog sockets are usually datagram sockets, but some are stream sockets
+		return []string{"unixgram", "unix"}, address, nil
+	case strings.HasPrefix(endpoint, udpScheme):
+		address = strings.TrimPrefix(endpoint, udpScheme)
+		host, _, err := net.SplitHostPort(address)
+		if err != nil {
+			return nil, "", fmt.Errorf("invalid syslog endpoint %q: %w", endpoint, err)
+		}
+
+		if host != "localhost" {
+			ip := net.ParseIP(host)
+			if ip == nil || !ip.IsLoopback() {
+				return nil, "", fmt.Errorf("syslog endpoint %q must be bound to localhost", endpoint)
+			}
+		}
+		return []string{"udp"}, address, nil
+	default:
+		return nil, "", fmt.Errorf("syslog endpoint %q must start with %s or %s", endpoint, unixScheme, udpScheme)
+	}
+}
+
+func (s *SyslogConfig) facility() string {
+	if s.Facility == "" {
+		return "user"
+	}
+	return s.Facility
+}
+
+func (s *SyslogConfig) tag() string {
+	if s.Tag == "" {
+		return filepath.Base(os.Args[0])
+	}
+	return s.Tag
+}
+
+var (
+	// currentSyslogWriters are the syslog writers of the most recently created logging core
+	currentSyslogWriters []*syslogWriter
+	// currentSyslogWritersMux guards currentSyslogWriters
+	currentSyslogWritersMux sync.Mutex
+)
+
+// replaceSyslogWriters closes the connections of the current syslog writers, and replaces them with writers.
+// Loggers still using a replaced writer reconnect on their next write, so it is only called once loggers use the new writers.
+func replaceSyslogWriters(writers []*syslogWriter) {
+	currentSyslogWritersMux.Lock()
+	defer currentSyslogWritersMux.Unlock()
+
+	for _, writer := range currentSyslogWriters {
+		writer.close()
+	}
+	currentSyslogWriters = writers
+}
+
+// syslogWriter sends messages to a syslog server, reconnecting if a write fails
+type syslogWriter struct {
+	networks []string
+	address  string
+	facility int
+	tag      string
+	hostname string
+	pid      int
+
+	mutex sync.Mutex
+	conn  net.Conn
+}
+
+// newSyslogWriter creates a writer for the config. The connection is made when the first message is written.
+func newSyslogWriter(cfg *SyslogConfig) (*syslogWriter, error) {
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+
+	networks, address, _ := cfg.network()
+	hostname, _ := os.Hostname()
+
+	return &syslogWriter{
+		networks: networks,
+		address:  address,
+		facility: syslogFacilities[cfg.facility()],
+		tag:      cfg.tag(),
+		hostname: hostname,
+		pid:      os.Getpid(),
+	}, nil
+}
+
+// write sends the message with the severity of the level
+func (w *syslogWriter) write(level zapcore.Level, msg []byte) error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	formatted := w.format(level, msg)
+
+	// Retry once with a new connection, in case the syslog server restarted
+	for attempt := 0; attempt < 2; attempt++ {
+		if w.conn == nil {
+			if err := w.connect(); err != nil {
+				return err
+			}
+		}
+
+		if _, err := w.conn.Write(formatted); err == nil {
+			return nil
+		}
+
+		_ = w.conn.Close()
+		w.conn = nil
+	}
+
+	return fmt.Errorf("failed to write to syslog at %s", w.address)
+}
+
+// connect connects to the syslog server with the first network that works
+func (w *syslogWriter) connect() error {
+	var errs error
+	for _, network := range w.networks {
+		conn, err := net.DialTimeout(network, w.address, 5*time.Second)
+		if err == nil {
+			w.conn = conn
+			return nil
+		}
+		errs = errors.Join(errs, err)
+	}
+
+	return fmt.Errorf("failed to connect to syslog: %w", errs)
+}
+
+// close closes the connection to the syslog server, if there is one
+func (w *syslogWriter) close() {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if w.conn != nil {
+		_ = w.conn.Close()
+		w.conn = nil
+	}
+}
+
+// format formats the message as an RFC 3164 syslog message.
+// The hostname is omitted for unix sockets, as the local syslog server adds it.
+func (w *syslogWriter) format(level zapcore.Level, msg []byte) []byte {
+	priority := w.facility*8 + syslogSeverity(level)
+	msg = []byte(strings.TrimSuffix(string(msg), "\n"))
+
+	if w.networks[0] == "udp" {
+		return []byte(fmt.Sprintf("<%d>%s %s %s[%d]: %s\n", priority, time.Now().Format(time.Stamp), w.hostname, w.tag, w.pid, msg))
+	}
+	return []byte(fmt.Sprintf("<%d>%s %s[%d]: %s\n", priority, time.Now().Format(time.Stamp), w.tag, w.pid, msg))
+}
+
+// syslogSeverity returns the syslog severity of the level
+func syslogSeverity(level zapcore.Level) int {
+	switch level {
+	case zapcore.DebugLevel:
+		return 7
+	case zapcore.InfoLevel:
+		return 6
+	case zapcore.WarnLevel:
+		return 4
+	case zapcore.ErrorLevel:
+		return 3
+	case zapcore.DPanicLevel, zapcore.PanicLevel:
+		return 2
+	default:
+		return 0
+	}
+}
+
+// syslogCore is a core that writes entries to syslog with the severity of their level
+type syslogCore struct {
+	zapcore.LevelEnabler
+	encoder zapcore.Encoder
+	writer  *syslogWriter
+}
+
+// newSyslogCore returns a core writing entries encoded with the encoder to syslog
+func newSyslogCore(encoder zapcore.Encoder, writer *syslogWriter) zapcore.Core {
+	return &syslogCore{
+		LevelEnabler: zapcore.DebugLevel,
+		encoder:      encoder,
+		writer:       writer,
+	}
+}
+
+// With returns a copy of the core with the fields added
+func (c *syslogCore) With(fields []zapcore.Field) zapcore.Core {
+	clone := &syslogCore{
+		LevelEnabler: c.LevelEnabler,
+		encoder:      c.encoder.Clone(),
+		writer:       c.writer,
+	}
+
+	for _, field := range fields {
+		field.AddTo(clone.encoder)
+	}
+
+	return clone
+}
+
+// Check adds the core to the checked entry if the level is enabled
+func (c *syslogCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return checked.AddCore(entry, c)
+	}
+	return checked
+}
+
+// Write encodes the entry and sends it to syslog
+func (c *syslogCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	buf, err := c.encoder.EncodeEntry(entry, fields)
+	if err != nil {
+		return fmt.Errorf("failed to encode entry: %w", err)
+	}
+	defer buf.Free()
+
+	return c.writer.write(entry.Level, buf.Bytes())
+}
+
+// Sync does nothing, as messages are sent as they're written
+func (c *syslogCore) Sync() error {
+	return nil
+}
@@ -0,0 +1,187 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"net"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestSyslogConfigNetwork(t *testing.T) {
+	cases := []struct {
+		name             string
+		endpoint         string
+		expectedNetworks []string
+		expectedAddress  string
+		expectedErr      string
+	}{
+		{
+			name:             "default",
+			expectedNetworks: []string{"unixgram", "unix"},
+			expectedAddress:  "/dev/log",
+		},
+		{
+			name:             "unix socket",
+			endpoint:         "unix:///var/run/syslog",
+			expectedNetworks: []string{"unixgram", "unix"},
+			expectedAddress:  "/var/run/syslog",
+		},
+		{
+			name:             "udp localhost",
+			endpoint:         "udp://127.0.0.1:514",
+			expectedNetworks: []string{"udp"},
+			expectedAddress:  "127.0.0.1:514",
+		},
+		{
+			name:        "udp remote",
+			endpoint:    "udp://syslog.example.com:514",
+			expectedErr: "must be bound to localhost",
+		},
+		{
+			name:        "tcp",
+			endpoint:    "tcp://localhost:514",
+			expectedErr: "must start with unix:// or udp://",
+		},
+		{
+			name:        "empty unix socket",
+			endpoint:    "unix://",
+			expectedErr: "syslog socket path must be specified",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			networks, address, err := (&SyslogConfig{Endpoint: tc.endpoint}).network()
+			if tc.expectedErr != "" {
+				require.ErrorContains(t, err, tc.expectedErr)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedNetworks, networks)
+			require.Equal(t, tc.expectedAddress, address)
+		})
+	}
+}
+
+func TestSyslogCoreUDP(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer conn.Close()
+
+	writer, err := newSyslogWriter(&SyslogConfig{
+		Endpoint: "udp://" + conn.LocalAddr().String(),
+		Facility: "local0",
+		Tag:      "agent",
+	})
+	require.NoError(t, err)
+
+	logger := zap.New(newSyslogCore(zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig()), writer))
+	logger.With(zap.String("kind", "exporter")).Warn("export failed")
+
+	msg := readPacket(t, conn)
+
+	// local0 (16) * 8 + warning (4)
+	require.True(t, strings.HasPrefix(msg, "<132>"), msg)
+	require.Contains(t, msg, " agent[")
+	require.Contains(t, msg, `"msg":"export failed"`)
+	require.Contains(t, msg, `"kind":"exporter"`)
+}
+
+func TestSyslogCoreUnixgram(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("unixgram sockets are not supported on windows")
+	}
+
+	socketPath := filepath.Join(t.TempDir(), "log.sock")
+	conn, err := net.ListenPacket("unixgram", socketPath)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	writer, err := newSyslogWriter(&SyslogConfig{Endpoint: "unix://" + socketPath, Tag: "agent"})
+	require.NoError(t, err)
+
+	logger := zap.New(newSyslogCore(zapcore.NewConsoleEncoder(zap.NewProductionEncoderConfig()), writer))
+	logger.Error("failed")
+
+	msg := readPacket(t, conn)
+
+	// user (1) * 8 + error (3)
+	require.True(t, strings.HasPrefix(msg, "<11>"), msg)
+	require.Contains(t, msg, " agent[")
+	require.Contains(t, msg, "\terror\tfailed")
+}
+
+func TestOptionsClosesReplacedSyslogWriters(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer conn.Close()
+
+	conf := &LoggerConfig{
+		Output: syslogOutput,
+		Level:  zapcore.InfoLevel,
+		Syslog: &SyslogConfig{Endpoint: "udp://" + conn.LocalAddr().String()},
+	}
+
+	opts, cleanup, err := conf.Options()
+	require.NoError(t, err)
+	cleanup()
+	defer replaceSyslogWriters(nil)
+
+	zap.NewNop().WithOptions(opts...).Info("first")
+	readPacket(t, conn)
+
+	require.Len(t, currentSyslogWriters, 1)
+	replaced := currentSyslogWriters[0]
+	require.NotNil(t, replaced.conn)
+
+	// The replaced writer keeps its connection until the new options are committed
+	_, cleanup, err = conf.Options()
+	require.NoError(t, err)
+	require.NotNil(t, replaced.conn)
+	require.Same(t, replaced, currentSyslogWriters[0])
+
+	cleanup()
+	require.Nil(t, replaced.conn)
+	require.Len(t, currentSyslogWriters, 1)
+	require.NotSame(t, replaced, currentSyslogWriters[0])
+}
+
+func TestSyslogSeverity(t *testing.T) {
+	require.Equal(t, 7, syslogSeverity(zapcore.DebugLevel))
+	require.Equal(t, 6, syslogSeverity(zapcore.InfoLevel))
+	require.Equal(t, 4, syslogSeverity(zapcore.WarnLevel))
+	require.Equal(t, 3, syslogSeverity(zapcore.ErrorLevel))
+	require.Equal(t, 2, syslogSeverity(zapcore.PanicLevel))
+	require.Equal(t, 0, syslogSeverity(zapcore.FatalLevel))
+}
+
+func readPacket(t *testing.T, conn net.PacketConn) string {
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+
+	buf := make([]byte, 4096)
+	n, _, err := conn.ReadFrom(buf)
+	require.NoError(t, err)
+
+	return string(buf[:n])
+}
@@ -0,0 +1,196 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package reload applies new config files to a running collector, rolling back to the previous file on failure
+package reload
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/observiq/bindplane-agent/collector"
+	"github.com/observiq/bindplane-agent/internal/logging"
+	"github.com/observiq/bindplane-agent/internal/report"
+	"go.uber.org/zap"
+)
+
+// CollectorConfig writes the new collector config and restarts the collector with it.
+// If the collector fails to restart, the previous config is restored and the collector is restarted with it.
+func CollectorConfig(logger *zap.Logger, col collector.Collector, configName, configPath string, contents []byte) error {
+	rollbackFunc, cleanupFunc, err := PrepRollback(configPath)
+	if err != nil {
+		return fmt.Errorf("failed to prep for rollback: %w", err)
+	}
+
+	defer func() {
+		// Cleanup rollback
+		if err := cleanupFunc(); err != nil {
+			logger.Warn("Failed to cleanup rollback file", zap.Error(err))
+		}
+	}()
+
+	// Write new config file
+	if err := UpdateConfigFile(configName, configPath, contents); err != nil {
+		return err
+	}
+
+	// Reload collector
+	if err := col.Restart(context.Background()); err != nil {
+		// Rollback file
+		if rollbackErr := rollbackFunc(); rollbackErr != nil {
+			logger.Error("Rollback failed for collector config", zap.Error(rollbackErr))
+		}
+
+		// Restart collector with original file
+		if rollbackErr := col.Restart(context.Background()); rollbackErr != nil {
+			logger.Error("Collector failed for restart during rollback", zap.Error(rollbackErr))
+		}
+
+		return fmt.Errorf("collector failed to restart: %w", err)
+	}
+
+	// Reset Snapshot Reporter
+	report.GetSnapshotReporter().Reset()
+
+	return nil
+}
+
+// LoggingConfig writes the new logging config and restarts the collector with the logging options from it.
+// If only the levels of the config changed, they are applied without restarting the collector.
+// If the config is invalid or the collector fails to restart, the previous config and options are restored.
+// The new logging options are returned so the caller can update its own logger.
+func LoggingConfig(logger *zap.Logger, col collector.Collector, configName, configPath string, contents []byte) ([]zap.Option, error) {
+	// The current config is only used to check if just the levels changed, so a restart is required if it can't be read
+	prevConfig, prevErr := logging.NewLoggerConfig(configPath)
+	if prevErr != nil {
+		prevConfig = nil
+	}
+
+	rollbackFunc, cleanupFunc, err := PrepRollback(configPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prep for rollback: %w", err)
+	}
+
+	defer func() {
+		// Cleanup rollback
+		if err := cleanupFunc(); err != nil {
+			logger.Warn("Failed to cleanup rollback file", zap.Error(err))
+		}
+	}()
+
+	rollback := func() {
+		if rollbackErr := rollbackFunc(); rollbackErr != nil {
+			logger.Error("Rollback failed for logging config", zap.Error(rollbackErr))
+		}
+	}
+
+	// Write new config file
+	if err := UpdateConfigFile(configName, configPath, contents); err != nil {
+		rollback()
+		return nil, err
+	}
+
+	// Parse new logging config
+	l, err := logging.NewLoggerConfig(configPath)
+	if err != nil {
+		rollback()
+		return nil, err
+	}
+
+	// Levels are applied to existing loggers, so there is no need to restart
+	if prevConfig != nil && l.OnlyLevelsChanged(prevConfig) {
+		l.SetLevels()
+		return col.GetLoggingOpts(), nil
+	}
+
+	// Parse out options
+	opts, cleanupOpts, err := l.Options()
+	if err != nil {
+		rollback()
+		return nil, fmt.Errorf("failed updating logging config: %w", err)
+	}
+
+	// Apply logging opts to collector
+	rollbackOpts := col.GetLoggingOpts()
+	col.SetLoggingOpts(opts)
+	if err := col.Restart(context.Background()); err != nil {
+		rollback()
+
+		// Restart collector with original logging opts and levels
+		if prevConfig != nil {
+			prevConfig.SetLevels()
+		}
+		col.SetLoggingOpts(rollbackOpts)
+		if rollbackErr := col.Restart(context.Background()); rollbackErr != nil {
+			logger.Error("Collector failed for restart during rollback", zap.Error(rollbackErr))
+		}
+
+		return nil, fmt.Errorf("failed apply logging update to collector: %w", err)
+	}
+
+	// The collector now logs with the new options, so the previous syslog connections can be closed
+	cleanupOpts()
+	return opts, nil
+}
+
+// UpdateConfigFile overwrites the config file with the new contents
+func UpdateConfigFile(configName, configPath string, contents []byte) error {
+	// Write file
+	if err := os.WriteFile(configPath, contents, 0600); err != nil {
+		return fmt.Errorf("failed to update config file %s: %w", configName, err)
+	}
+
+	return nil
+}
+
+// PrepRollback copies the config file to a rollback file.
+// The returned rollbackFunc restores the config file from the rollback file, and cleanupFunc removes the rollback file.
+func PrepRollback(configPath string) (rollbackFunc func() error, cleanupFunc func() error, err error) {
+	rollbackPath := fmt.Sprintf("%s.rollback", configPath)
+
+	// Create rollback file
+	err = copyFile(configPath, rollbackPath)
+	if err != nil {
+		return
+	}
+
+	// Create rollback func
+	rollbackFunc = func() error {
+		return copyFile(rollbackPath, configPath)
+	}
+
+	// Create cleanupFUnc
+	cleanupFunc = func() error {
+		return os.Remove(rollbackPath)
+	}
+
+	return
+}
+
+func copyFile(originPath, newPath string) error {
+	cleanOriginPath := filepath.Clean(originPath)
+	data, err := os.ReadFile(cleanOriginPath)
+	if err != nil {
+		return fmt.Errorf("failed to read origin file: %w", err)
+	}
+
+	err = os.WriteFile(newPath, data, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to write new file: %w", err)
+	}
+
+	return nil
+}
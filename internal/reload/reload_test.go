@@ -0,0 +1,47 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reload
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrepRollback(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(configPath, []byte("original"), 0600))
+
+	rollbackFunc, cleanupFunc, err := PrepRollback(configPath)
+	require.NoError(t, err)
+	require.FileExists(t, configPath+".rollback")
+
+	require.NoError(t, UpdateConfigFile("config.yaml", configPath, []byte("updated")))
+	require.NoError(t, rollbackFunc())
+
+	contents, err := os.ReadFile(configPath)
+	require.NoError(t, err)
+	require.Equal(t, "original", string(contents))
+
+	require.NoError(t, cleanupFunc())
+	require.NoFileExists(t, configPath+".rollback")
+}
+
+func TestPrepRollbackMissingConfig(t *testing.T) {
+	_, _, err := PrepRollback(filepath.Join(t.TempDir(), "config.yaml"))
+	require.ErrorContains(t, err, "failed to read origin file")
+}
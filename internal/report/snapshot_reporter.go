@@ -135,6 +135,23 @@ func (s *SnapshotReporter) Report(cfg any) error {
 	return nil
 }
 
+// Snapshot returns the buffered snapshot of the component for the pipeline type, as marshaled OTLP protobuf.
+// An empty payload is returned if nothing has been buffered for the component.
+func (s *SnapshotReporter) Snapshot(componentID, pipelineType string) ([]byte, error) {
+	switch pipelineType {
+	case "logs", "metrics", "traces":
+	default:
+		return nil, fmt.Errorf("unsupported pipeline type %q", pipelineType)
+	}
+
+	payload, err := s.prepRequestPayload(componentID, pipelineType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to construct snapshot: %w", err)
+	}
+
+	return payload, nil
+}
+
 // Reset clears all buffers
 func (s *SnapshotReporter) Reset() {
 	s.logLock.Lock()
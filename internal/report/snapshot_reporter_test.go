@@ -114,6 +114,34 @@ func TestSnapshotReporterSaveMetrics(t *testing.T) {
 	require.Equal(t, 1, buffer.Len())
 }
 
+func TestSnapshotReporterSnapshot(t *testing.T) {
+	componentID := "snapshot/one"
+
+	reporter := NewSnapshotReporter(nil)
+
+	toAdd := plog.NewLogs()
+	rl := toAdd.ResourceLogs().AppendEmpty()
+	sl := rl.ScopeLogs().AppendEmpty()
+	sl.LogRecords().AppendEmpty().Body().SetStr("hello")
+
+	reporter.SaveLogs(componentID, toAdd)
+
+	payload, err := reporter.Snapshot(componentID, "logs")
+	require.NoError(t, err)
+
+	unmarshaler := plog.ProtoUnmarshaler{}
+	logs, err := unmarshaler.UnmarshalLogs(payload)
+	require.NoError(t, err)
+	require.Equal(t, 1, logs.LogRecordCount())
+
+	payload, err = reporter.Snapshot("snapshot/missing", "metrics")
+	require.NoError(t, err)
+	require.Empty(t, payload)
+
+	_, err = reporter.Snapshot(componentID, "profiles")
+	require.EqualError(t, err, `unsupported pipeline type "profiles"`)
+}
+
 func TestSnapshotReporterReport(t *testing.T) {
 	testCases := []struct {
 		desc     string
@@ -24,9 +24,19 @@ import (
 
 const (
 	startTimeout = 10 * time.Second
-	stopTimeout  = 10 * time.Second
+
+	// DefaultStopTimeout is the default amount of time the service has to stop and drain in-flight telemetry.
+	DefaultStopTimeout = 10 * time.Second
 )
 
+// stopTimeout is the amount of time the service has to stop and drain in-flight telemetry.
+var stopTimeout = DefaultStopTimeout
+
+// SetStopTimeout sets the amount of time the service has to stop and drain in-flight telemetry.
+func SetStopTimeout(timeout time.Duration) {
+	stopTimeout = timeout
+}
+
 // RunnableService may be run as a service.
 //
 //go:generate mockery --name RunnableService --filename mock_runnable_service.go --structname MockRunnableService
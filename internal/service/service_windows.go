@@ -31,9 +31,9 @@ import (
 	"golang.org/x/sys/windows/svc"
 )
 
-// windowsServiceShutdownTimeout is the amount of time to wait for the underlying service to stop before
-// forcefully stopping the process.
-var windowsServiceShutdownTimeout = 20 * time.Second
+// windowsServiceShutdownGrace is the amount of time past the stop timeout to wait for the underlying service
+// to stop before forcefully stopping the process.
+var windowsServiceShutdownGrace = 10 * time.Second
 
 // The following constants specify error codes for the service.
 // See https://docs.microsoft.com/en-us/windows/win32/debug/system-error-codes--1000-1299-
@@ -155,10 +155,12 @@ func (sh windowsServiceHandler) shutdown(s chan<- svc.Status) error {
 		stopErrChan <- sh.svc.Stop(stopTimeoutCtx)
 	}()
 
+	shutdownTimeout := stopTimeout + windowsServiceShutdownGrace
+
 	var err error
 	select {
-	case <-time.After(windowsServiceShutdownTimeout):
-		err = fmt.Errorf("the service failed to shut down in a timely manner (timeout: %s)", windowsServiceShutdownTimeout)
+	case <-time.After(shutdownTimeout):
+		err = fmt.Errorf("the service failed to shut down in a timely manner (timeout: %s)", shutdownTimeout)
 	case stopErr := <-stopErrChan:
 		err = stopErr
 	}
@@ -596,9 +596,9 @@ func TestWindowsServiceHandler(t *testing.T) {
 }
 
 func setWindowsServiceTimeout(t *testing.T, d time.Duration) {
-	old := windowsServiceShutdownTimeout
-	windowsServiceShutdownTimeout = d
+	oldStopTimeout, oldGrace := stopTimeout, windowsServiceShutdownGrace
+	stopTimeout, windowsServiceShutdownGrace = d, 0
 	t.Cleanup(func() {
-		windowsServiceShutdownTimeout = old
+		stopTimeout, windowsServiceShutdownGrace = oldStopTimeout, oldGrace
 	})
 }
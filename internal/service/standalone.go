@@ -21,24 +21,46 @@ import (
 	"sync"
 
 	"github.com/observiq/bindplane-agent/collector"
+	"github.com/observiq/bindplane-agent/internal/control"
+	"go.uber.org/zap"
 )
 
 // StandaloneCollectorService is a RunnableService that runs the collector in standalone mode.
 type StandaloneCollectorService struct {
 	col      collector.Collector
+	control  *control.Server
 	doneChan chan struct{}
 	errChan  chan error
 	wg       *sync.WaitGroup
+
+	// pauseChan receives a channel from restarts made through the control API.
+	// Status monitoring is paused until that channel is closed.
+	pauseChan chan chan struct{}
 }
 
 // NewStandaloneCollectorService creates a new StandaloneCollectorService
 func NewStandaloneCollectorService(c collector.Collector) StandaloneCollectorService {
 	return StandaloneCollectorService{
-		col:      c,
-		doneChan: make(chan struct{}, 1),
-		errChan:  make(chan error, 1),
-		wg:       &sync.WaitGroup{},
+		col:       c,
+		doneChan:  make(chan struct{}, 1),
+		errChan:   make(chan error, 1),
+		wg:        &sync.WaitGroup{},
+		pauseChan: make(chan chan struct{}),
+	}
+}
+
+// NewStandaloneCollectorServiceWithControl creates a new StandaloneCollectorService
+// that also serves the local control API with the given settings
+func NewStandaloneCollectorServiceWithControl(c collector.Collector, logger *zap.Logger, settings control.Settings) (StandaloneCollectorService, error) {
+	s := NewStandaloneCollectorService(c)
+
+	server, err := control.NewServer(logger, pausingCollector{Collector: c, svc: s}, settings)
+	if err != nil {
+		return StandaloneCollectorService{}, fmt.Errorf("failed to create control API: %w", err)
 	}
+
+	s.control = server
+	return s, nil
 }
 
 // Start starts the collector
@@ -51,6 +73,17 @@ func (s StandaloneCollectorService) Start(ctx context.Context) error {
 	// monitor status for errors, so we don't zombie the service
 	s.wg.Add(1)
 	go s.monitorStatus()
+
+	if s.control != nil {
+		if err := s.control.Start(); err != nil {
+			// Stop the collector and status monitoring, since the service isn't stopped after a failed start
+			close(s.doneChan)
+			s.col.Stop(ctx)
+			s.wg.Wait()
+			return fmt.Errorf("failed while starting control API: %w", err)
+		}
+	}
+
 	return nil
 }
 
@@ -69,6 +102,14 @@ func (s StandaloneCollectorService) monitorStatus() {
 				// If we aren't running, bail out. Otherwise the collector is effectively a "zombie" process.
 				s.errChan <- errors.New("collector unexpectedly stopped running")
 			}
+		case resumeChan := <-s.pauseChan:
+			select {
+			case <-resumeChan:
+				// The collector was restarted, so it has a new status channel
+				statusChan = s.col.Status()
+			case <-s.doneChan:
+				return
+			}
 		case <-s.doneChan:
 			return
 		}
@@ -82,6 +123,15 @@ func (s StandaloneCollectorService) Error() <-chan error {
 
 // Stop shuts down the underlying collector
 func (s StandaloneCollectorService) Stop(ctx context.Context) error {
+	// Stop the control API first, so no changes are applied while shutting down.
+	// The collector is stopped even if the control API fails to stop.
+	var controlErr error
+	if s.control != nil {
+		if err := s.control.Stop(ctx); err != nil {
+			controlErr = fmt.Errorf("failed while stopping control API: %w", err)
+		}
+	}
+
 	close(s.doneChan)
 
 	collectorStoppedChan := make(chan struct{})
@@ -93,8 +143,30 @@ func (s StandaloneCollectorService) Stop(ctx context.Context) error {
 
 	select {
 	case <-collectorStoppedChan:
-		return nil
+		return controlErr
 	case <-ctx.Done():
-		return fmt.Errorf("failed while waiting for service shutdown: %w", ctx.Err())
+		return errors.Join(controlErr, fmt.Errorf("failed while waiting for service shutdown: %w", ctx.Err()))
 	}
 }
+
+// pausingCollector is the collector given to the control API.
+// It pauses status monitoring while restarting, so the restart isn't treated as the collector unexpectedly stopping.
+type pausingCollector struct {
+	collector.Collector
+	svc StandaloneCollectorService
+}
+
+// Restart restarts the collector while status monitoring is paused
+func (p pausingCollector) Restart(ctx context.Context) error {
+	resumeChan := make(chan struct{})
+	select {
+	case p.svc.pauseChan <- resumeChan:
+	case <-p.svc.doneChan:
+		return errors.New("collector is shutting down")
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	defer close(resumeChan)
+
+	return p.Collector.Restart(ctx)
+}
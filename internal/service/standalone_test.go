@@ -17,13 +17,16 @@ package service
 import (
 	"context"
 	"errors"
+	"path/filepath"
 	"testing"
 	"time"
 
 	"github.com/observiq/bindplane-agent/collector"
 	"github.com/observiq/bindplane-agent/collector/mocks"
+	"github.com/observiq/bindplane-agent/internal/control"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
 )
 
 func TestStandaloneCollectorService(t *testing.T) {
@@ -231,4 +234,65 @@ func TestStandaloneCollectorService(t *testing.T) {
 
 		require.Equal(t, 0, len(srv.Error()), "error channel has elements in it!")
 	})
+
+	t.Run("Restart through the control API is not reported as an error", func(t *testing.T) {
+		col := mocks.NewMockCollector(t)
+		oldStatus := make(chan *collector.Status, 1)
+		newStatus := make(chan *collector.Status, 1)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		col.On("Run", ctx).Return(nil)
+		col.On("Status").Return((<-chan *collector.Status)(oldStatus)).Once()
+		col.On("Status").Return((<-chan *collector.Status)(newStatus)).Once()
+		col.On("Restart", mock.Anything).Run(func(args mock.Arguments) {
+			// Stopping the collector reports it is no longer running
+			oldStatus <- &collector.Status{Running: false}
+		}).Return(nil)
+		col.On("Stop", mock.Anything).Return(nil)
+
+		srv, err := NewStandaloneCollectorServiceWithControl(col, zap.NewNop(), control.Settings{
+			Endpoint: "unix://" + filepath.Join(t.TempDir(), "control.sock"),
+		})
+		require.NoError(t, err)
+
+		require.NoError(t, srv.Start(ctx))
+
+		require.NoError(t, pausingCollector{Collector: col, svc: srv}.Restart(ctx))
+
+		// Errors are reported from the new status channel after the restart
+		colStatusErr := errors.New("Collector errored")
+		newStatus <- &collector.Status{Running: false, Err: colStatusErr}
+
+		select {
+		case err := <-srv.Error():
+			require.Equal(t, colStatusErr, err)
+		case <-time.After(time.Second):
+			t.Fatalf("Timed out waiting for error")
+		}
+
+		require.NoError(t, srv.Stop(context.Background()))
+	})
+
+	t.Run("Control API fails to start", func(t *testing.T) {
+		col := mocks.NewMockCollector(t)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		col.On("Run", ctx).Return(nil)
+		col.On("Status").Return((<-chan *collector.Status)(make(chan *collector.Status))).Maybe()
+		col.On("Stop", ctx).Return(nil).Once()
+
+		// The socket can't be created in a directory that doesn't exist
+		srv, err := NewStandaloneCollectorServiceWithControl(col, zap.NewNop(), control.Settings{
+			Endpoint: "unix://" + filepath.Join(t.TempDir(), "missing", "control.sock"),
+		})
+		require.NoError(t, err)
+
+		err = srv.Start(ctx)
+		require.ErrorContains(t, err, "failed while starting control API")
+		col.AssertCalled(t, "Stop", ctx)
+	})
 }
@@ -16,44 +16,70 @@ package throughputwrapper
 
 import (
 	"context"
+	"fmt"
 
-	"go.opencensus.io/stats"
-	"go.opencensus.io/tag"
+	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/plog"
-	"go.uber.org/zap"
 )
 
 var _ consumer.Logs = (*logConsumer)(nil)
 
 type logConsumer struct {
-	logger       *zap.Logger
-	mutators     []tag.Mutator
+	measurer     *measurer
 	logsSizer    plog.MarshalSizer
 	baseConsumer consumer.Logs
 }
 
-func newLogConsumer(logger *zap.Logger, componentID string, baseConsumer consumer.Logs) *logConsumer {
+func newLogConsumer(telemetry component.TelemetrySettings, componentID string, baseConsumer consumer.Logs) (*logConsumer, error) {
+	counter, err := newSizeCounter(telemetry.MeterProvider, logThroughputSizeName, "Size of the log package emitted from the component")
+	if err != nil {
+		return nil, fmt.Errorf("create log throughput counter: %w", err)
+	}
+
 	return &logConsumer{
-		logger:       logger,
-		mutators:     []tag.Mutator{tag.Upsert(componentTagKey, componentID, tag.WithTTL(tag.TTLNoPropagation))},
+		measurer:     newMeasurer(telemetry.Logger, componentID, counter),
 		logsSizer:    &plog.ProtoMarshaler{},
 		baseConsumer: baseConsumer,
-	}
+	}, nil
 }
 
 // ConsumeLogs measures the plog.Logs size before passing it onto the baseConsumer
 func (l *logConsumer) ConsumeLogs(ctx context.Context, ld plog.Logs) error {
-	if err := stats.RecordWithTags(
-		ctx,
-		l.mutators,
-		logThroughputSize.M(int64(l.logsSizer.LogsSize(ld))),
-	); err != nil {
-		l.logger.Warn("Error while measuring receiver log throughput", zap.Error(err))
+	if l.measurer.sample() {
+		l.measure(ctx, ld)
 	}
 	return l.baseConsumer.ConsumeLogs(ctx, ld)
 }
 
+// measure records the size of the payload, broken down by resource attributes if configured
+func (l *logConsumer) measure(ctx context.Context, ld plog.Logs) {
+	if !l.measurer.breakdown() {
+		l.measurer.record(ctx, l.logsSizer.LogsSize(ld), l.measurer.componentOpt)
+		return
+	}
+
+	resources := ld.ResourceLogs()
+	groups := l.measurer.group(resources.Len(), func(i int) pcommon.Resource {
+		return resources.At(i).Resource()
+	})
+
+	// Avoid copying the payload when all resources share the same attributes
+	if len(groups) == 1 {
+		l.measurer.record(ctx, l.logsSizer.LogsSize(ld), groups[0].option)
+		return
+	}
+
+	for _, g := range groups {
+		grouped := plog.NewLogs()
+		for _, i := range g.indices {
+			resources.At(i).CopyTo(grouped.ResourceLogs().AppendEmpty())
+		}
+		l.measurer.record(ctx, l.logsSizer.LogsSize(grouped), g.option)
+	}
+}
+
 // Capabilities returns the baseConsumer's capabilities
 func (l *logConsumer) Capabilities() consumer.Capabilities {
 	return l.baseConsumer.Capabilities()
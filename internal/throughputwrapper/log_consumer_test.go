@@ -19,43 +19,87 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/consumer/consumertest"
 	"go.opentelemetry.io/collector/pdata/plog"
-	"go.uber.org/zap"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 func Test_newLogConsumer(t *testing.T) {
-	nopLogger := zap.NewNop()
 	componentID := "id"
 	baseConsumer := consumertest.NewNop()
-	lConsumer := newLogConsumer(nopLogger, componentID, baseConsumer)
+	lConsumer, err := newLogConsumer(componenttest.NewNopTelemetrySettings(), componentID, baseConsumer)
+	require.NoError(t, err)
 
-	require.Equal(t, nopLogger, lConsumer.logger)
 	require.Equal(t, baseConsumer, lConsumer.baseConsumer)
-	require.Len(t, lConsumer.mutators, 1)
+	require.NotNil(t, lConsumer.measurer)
 	require.Equal(t, &plog.ProtoMarshaler{}, lConsumer.logsSizer)
 }
 
 func Test_logConsumer_ConsumeLogs(t *testing.T) {
-	nopLogger := zap.NewNop()
+	telemetry, reader := newTestTelemetry(t)
 	componentID := "id"
 	baseConsumer := new(consumertest.LogsSink)
-	lConsumer := newLogConsumer(nopLogger, componentID, baseConsumer)
+	lConsumer, err := newLogConsumer(telemetry, componentID, baseConsumer)
+	require.NoError(t, err)
 
 	ld := plog.NewLogs()
 	ld.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords().AppendEmpty()
 
-	err := lConsumer.ConsumeLogs(context.Background(), ld)
+	err = lConsumer.ConsumeLogs(context.Background(), ld)
 	require.NoError(t, err)
 
 	require.Equal(t, 1, baseConsumer.LogRecordCount())
+
+	sums := collectSums(t, reader, logThroughputSizeName)
+	require.Len(t, sums, 1)
+	require.Equal(t, int64((&plog.ProtoMarshaler{}).LogsSize(ld)), sums[0].Value)
+	require.Equal(t, attribute.NewSet(attribute.String("component", componentID)), sums[0].Attributes)
+}
+
+func Test_logConsumer_ConsumeLogsBreakdown(t *testing.T) {
+	s := DefaultSettings()
+	s.Attributes = []string{"service.name"}
+	setTestSettings(t, s)
+
+	telemetry, reader := newTestTelemetry(t)
+	lConsumer, err := newLogConsumer(telemetry, "id", consumertest.NewNop())
+	require.NoError(t, err)
+
+	ld := plog.NewLogs()
+	for _, service := range []string{"a", "b", "bb"} {
+		rl := ld.ResourceLogs().AppendEmpty()
+		rl.Resource().Attributes().PutStr("service.name", service)
+		rl.ScopeLogs().AppendEmpty().LogRecords().AppendEmpty().Body().SetStr(service)
+	}
+
+	err = lConsumer.ConsumeLogs(context.Background(), ld)
+	require.NoError(t, err)
+
+	sums := map[string]int64{}
+	for _, dp := range collectSums(t, reader, logThroughputSizeName) {
+		service, ok := dp.Attributes.Value("service.name")
+		require.True(t, ok)
+		sums[service.AsString()] = dp.Value
+	}
+
+	// Each service is measured as if it were sent on its own
+	sizer := &plog.ProtoMarshaler{}
+	expected := map[string]int64{}
+	for i := 0; i < ld.ResourceLogs().Len(); i++ {
+		single := plog.NewLogs()
+		ld.ResourceLogs().At(i).CopyTo(single.ResourceLogs().AppendEmpty())
+		service, _ := single.ResourceLogs().At(0).Resource().Attributes().Get("service.name")
+		expected[service.Str()] = int64(sizer.LogsSize(single))
+	}
+	require.Equal(t, expected, sums)
 }
 
 func Test_logConsumer_Capabilities(t *testing.T) {
-	nopLogger := zap.NewNop()
 	componentID := "id"
 	baseConsumer := consumertest.NewNop()
-	lConsumer := newLogConsumer(nopLogger, componentID, baseConsumer)
+	lConsumer, err := newLogConsumer(componenttest.NewNopTelemetrySettings(), componentID, baseConsumer)
+	require.NoError(t, err)
 
 	require.Equal(t, baseConsumer.Capabilities(), lConsumer.Capabilities())
 }
@@ -0,0 +1,148 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package throughputwrapper
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"sync"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.uber.org/zap"
+)
+
+// measurer records payload sizes for a single receiver, broken down by the configured resource attributes
+type measurer struct {
+	logger         *zap.Logger
+	counter        metric.Int64Counter
+	samplingRatio  float64
+	attributes     []string
+	maxCardinality int
+	componentAttr  attribute.KeyValue
+	componentOpt   metric.MeasurementOption
+	overflowKey    attribute.Distinct
+	overflowOpt    metric.MeasurementOption
+
+	mux  sync.Mutex
+	sets map[attribute.Distinct]metric.MeasurementOption
+}
+
+// resourceGroup is a set of resources in a payload that share the same attributes
+type resourceGroup struct {
+	option  metric.MeasurementOption
+	indices []int
+}
+
+func newMeasurer(logger *zap.Logger, componentID string, counter metric.Int64Counter) *measurer {
+	s := currentSettings()
+	componentAttr := attribute.String(attributeComponent, componentID)
+	overflowSet := attribute.NewSet(componentAttr, attribute.Bool(attributeOverflow, true))
+
+	return &measurer{
+		logger:         logger,
+		counter:        counter,
+		samplingRatio:  s.SamplingRatio,
+		attributes:     s.Attributes,
+		maxCardinality: s.MaxCardinality,
+		componentAttr:  componentAttr,
+		componentOpt:   metric.WithAttributeSet(attribute.NewSet(componentAttr)),
+		overflowKey:    overflowSet.Equivalent(),
+		overflowOpt:    metric.WithAttributeSet(overflowSet),
+		sets:           make(map[attribute.Distinct]metric.MeasurementOption),
+	}
+}
+
+// sample returns true if the next payload should be measured
+func (m *measurer) sample() bool {
+	if m.samplingRatio >= 1.0 {
+		return true
+	}
+
+	//#nosec G404 -- randomly generated number is not used for security purposes. It's ok if it's weak
+	return rand.Float64() < m.samplingRatio
+}
+
+// breakdown returns true if measurements are broken down by resource attributes
+func (m *measurer) breakdown() bool {
+	return len(m.attributes) > 0
+}
+
+// record records the size of a payload. Sizes are scaled by the sampling ratio so that sums estimate the total size.
+func (m *measurer) record(ctx context.Context, size int, opt metric.MeasurementOption) {
+	value := int64(size)
+	if m.samplingRatio > 0.0 && m.samplingRatio < 1.0 {
+		value = int64(math.Round(float64(size) / m.samplingRatio))
+	}
+
+	m.counter.Add(ctx, value, opt)
+}
+
+// group groups the resources of a payload by their measured attributes
+func (m *measurer) group(count int, resourceAt func(i int) pcommon.Resource) []*resourceGroup {
+	groups := make([]*resourceGroup, 0, 1)
+	groupsByKey := make(map[attribute.Distinct]*resourceGroup, 1)
+
+	for i := 0; i < count; i++ {
+		key, opt := m.attributeOption(resourceAt(i).Attributes())
+
+		g, ok := groupsByKey[key]
+		if !ok {
+			g = &resourceGroup{option: opt}
+			groupsByKey[key] = g
+			groups = append(groups, g)
+		}
+		g.indices = append(g.indices, i)
+	}
+
+	return groups
+}
+
+// attributeOption returns the measurement option for the resource attributes.
+// Once the cardinality cap is reached, new attribute combinations are measured as an overflow.
+func (m *measurer) attributeOption(resourceAttrs pcommon.Map) (attribute.Distinct, metric.MeasurementOption) {
+	kvs := make([]attribute.KeyValue, 0, len(m.attributes)+1)
+	kvs = append(kvs, m.componentAttr)
+	for _, key := range m.attributes {
+		if v, ok := resourceAttrs.Get(key); ok {
+			kvs = append(kvs, attribute.String(key, v.AsString()))
+		}
+	}
+
+	set := attribute.NewSet(kvs...)
+	key := set.Equivalent()
+
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	if opt, ok := m.sets[key]; ok {
+		return key, opt
+	}
+
+	if len(m.sets) >= m.maxCardinality {
+		return m.overflowKey, m.overflowOpt
+	}
+
+	opt := metric.WithAttributeSet(set)
+	m.sets[key] = opt
+	if len(m.sets) == m.maxCardinality {
+		m.logger.Warn("Throughput attribute cardinality cap reached, further attribute combinations are measured as an overflow",
+			zap.Int("max_cardinality", m.maxCardinality))
+	}
+
+	return key, opt
+}
@@ -0,0 +1,84 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package throughputwrapper
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.uber.org/zap"
+)
+
+func TestMeasurerCardinalityCap(t *testing.T) {
+	s := DefaultSettings()
+	s.Attributes = []string{"service.name"}
+	s.MaxCardinality = 2
+	setTestSettings(t, s)
+
+	telemetry, reader := newTestTelemetry(t)
+	counter, err := newSizeCounter(telemetry.MeterProvider, logThroughputSizeName, "")
+	require.NoError(t, err)
+	m := newMeasurer(zap.NewNop(), "filelog", counter)
+
+	for _, service := range []string{"a", "b", "c", "d", "a"} {
+		attrs := pcommon.NewMap()
+		attrs.PutStr("service.name", service)
+		_, opt := m.attributeOption(attrs)
+		m.record(context.Background(), 10, opt)
+	}
+
+	sums := map[string]int64{}
+	for _, dp := range collectSums(t, reader, logThroughputSizeName) {
+		component, _ := dp.Attributes.Value("component")
+		require.Equal(t, "filelog", component.AsString())
+
+		if _, ok := dp.Attributes.Value(attributeOverflow); ok {
+			sums[attributeOverflow] = dp.Value
+			continue
+		}
+		service, _ := dp.Attributes.Value("service.name")
+		sums[service.AsString()] = dp.Value
+	}
+
+	require.Equal(t, map[string]int64{
+		"a":               20,
+		"b":               10,
+		attributeOverflow: 20,
+	}, sums)
+}
+
+func TestMeasurerSampling(t *testing.T) {
+	s := DefaultSettings()
+	s.SamplingRatio = 0.25
+	setTestSettings(t, s)
+
+	telemetry, reader := newTestTelemetry(t)
+	counter, err := newSizeCounter(telemetry.MeterProvider, logThroughputSizeName, "")
+	require.NoError(t, err)
+	m := newMeasurer(zap.NewNop(), "filelog", counter)
+
+	// Sampled sizes are scaled up to estimate the total size
+	m.record(context.Background(), 10, m.componentOpt)
+
+	sums := collectSums(t, reader, logThroughputSizeName)
+	require.Len(t, sums, 1)
+	require.Equal(t, int64(40), sums[0].Value)
+
+	s.SamplingRatio = 0
+	setTestSettings(t, s)
+	require.False(t, newMeasurer(zap.NewNop(), "filelog", counter).sample())
+}
@@ -16,45 +16,70 @@ package throughputwrapper
 
 import (
 	"context"
+	"fmt"
 
-	"go.opencensus.io/stats"
-	"go.opencensus.io/tag"
+	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/pmetric"
-	"go.uber.org/zap"
 )
 
 var _ consumer.Metrics = (*metricConsumer)(nil)
 
 type metricConsumer struct {
-	logger       *zap.Logger
-	mutators     []tag.Mutator
+	measurer     *measurer
 	metricsSizer pmetric.MarshalSizer
 	baseConsumer consumer.Metrics
 }
 
-func newMetricConsumer(logger *zap.Logger, componentID string, baseConsumer consumer.Metrics) *metricConsumer {
+func newMetricConsumer(telemetry component.TelemetrySettings, componentID string, baseConsumer consumer.Metrics) (*metricConsumer, error) {
+	counter, err := newSizeCounter(telemetry.MeterProvider, metricThroughputSizeName, "Size of the metric package emitted from the component")
+	if err != nil {
+		return nil, fmt.Errorf("create metric throughput counter: %w", err)
+	}
+
 	return &metricConsumer{
-		logger:       logger,
-		mutators:     []tag.Mutator{tag.Upsert(componentTagKey, componentID, tag.WithTTL(tag.TTLNoPropagation))},
+		measurer:     newMeasurer(telemetry.Logger, componentID, counter),
 		metricsSizer: &pmetric.ProtoMarshaler{},
 		baseConsumer: baseConsumer,
-	}
+	}, nil
 }
 
 // ConsumeMetrics measures the pmetric.Metrics size before passing it onto the baseConsumer
 func (m *metricConsumer) ConsumeMetrics(ctx context.Context, md pmetric.Metrics) error {
-	if err := stats.RecordWithTags(
-		ctx,
-		m.mutators,
-		metricThroughputSize.M(int64(m.metricsSizer.MetricsSize(md))),
-	); err != nil {
-		m.logger.Warn("Error while measuring receiver metric throughput", zap.Error(err))
+	if m.measurer.sample() {
+		m.measure(ctx, md)
 	}
-
 	return m.baseConsumer.ConsumeMetrics(ctx, md)
 }
 
+// measure records the size of the payload, broken down by resource attributes if configured
+func (m *metricConsumer) measure(ctx context.Context, md pmetric.Metrics) {
+	if !m.measurer.breakdown() {
+		m.measurer.record(ctx, m.metricsSizer.MetricsSize(md), m.measurer.componentOpt)
+		return
+	}
+
+	resources := md.ResourceMetrics()
+	groups := m.measurer.group(resources.Len(), func(i int) pcommon.Resource {
+		return resources.At(i).Resource()
+	})
+
+	// Avoid copying the payload when all resources share the same attributes
+	if len(groups) == 1 {
+		m.measurer.record(ctx, m.metricsSizer.MetricsSize(md), groups[0].option)
+		return
+	}
+
+	for _, g := range groups {
+		grouped := pmetric.NewMetrics()
+		for _, i := range g.indices {
+			resources.At(i).CopyTo(grouped.ResourceMetrics().AppendEmpty())
+		}
+		m.measurer.record(ctx, m.metricsSizer.MetricsSize(grouped), g.option)
+	}
+}
+
 // Capabilities returns the baseConsumer's capabilities
 func (m *metricConsumer) Capabilities() consumer.Capabilities {
 	return m.baseConsumer.Capabilities()
@@ -19,45 +19,89 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/consumer/consumertest"
 	"go.opentelemetry.io/collector/pdata/pmetric"
-	"go.uber.org/zap"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 func Test_newMetricConsumer(t *testing.T) {
-	nopLogger := zap.NewNop()
 	componentID := "id"
 	baseConsumer := consumertest.NewNop()
-	mConsumer := newMetricConsumer(nopLogger, componentID, baseConsumer)
+	mConsumer, err := newMetricConsumer(componenttest.NewNopTelemetrySettings(), componentID, baseConsumer)
+	require.NoError(t, err)
 
-	require.Equal(t, nopLogger, mConsumer.logger)
 	require.Equal(t, baseConsumer, mConsumer.baseConsumer)
-	require.Len(t, mConsumer.mutators, 1)
+	require.NotNil(t, mConsumer.measurer)
 	require.Equal(t, &pmetric.ProtoMarshaler{}, mConsumer.metricsSizer)
 }
 
 func Test_metricConsumer_ConsumeMetrics(t *testing.T) {
-	nopLogger := zap.NewNop()
+	telemetry, reader := newTestTelemetry(t)
 	componentID := "id"
 	baseConsumer := new(consumertest.MetricsSink)
-	mConsumer := newMetricConsumer(nopLogger, componentID, baseConsumer)
+	mConsumer, err := newMetricConsumer(telemetry, componentID, baseConsumer)
+	require.NoError(t, err)
 
 	md := pmetric.NewMetrics()
 	metric := md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
 	metric.SetEmptyGauge()
 	metric.Gauge().DataPoints().AppendEmpty()
 
-	err := mConsumer.ConsumeMetrics(context.Background(), md)
+	err = mConsumer.ConsumeMetrics(context.Background(), md)
 	require.NoError(t, err)
 
 	require.Equal(t, 1, baseConsumer.DataPointCount())
+
+	sums := collectSums(t, reader, metricThroughputSizeName)
+	require.Len(t, sums, 1)
+	require.Equal(t, int64((&pmetric.ProtoMarshaler{}).MetricsSize(md)), sums[0].Value)
+	require.Equal(t, attribute.NewSet(attribute.String("component", componentID)), sums[0].Attributes)
+}
+
+func Test_metricConsumer_ConsumeMetricsBreakdown(t *testing.T) {
+	s := DefaultSettings()
+	s.Attributes = []string{"service.name"}
+	setTestSettings(t, s)
+
+	telemetry, reader := newTestTelemetry(t)
+	mConsumer, err := newMetricConsumer(telemetry, "id", consumertest.NewNop())
+	require.NoError(t, err)
+
+	md := pmetric.NewMetrics()
+	for _, service := range []string{"a", "b", "bb"} {
+		rm := md.ResourceMetrics().AppendEmpty()
+		rm.Resource().Attributes().PutStr("service.name", service)
+		rm.ScopeMetrics().AppendEmpty().Metrics().AppendEmpty().SetEmptyGauge().DataPoints().AppendEmpty()
+	}
+
+	err = mConsumer.ConsumeMetrics(context.Background(), md)
+	require.NoError(t, err)
+
+	sums := map[string]int64{}
+	for _, dp := range collectSums(t, reader, metricThroughputSizeName) {
+		service, ok := dp.Attributes.Value("service.name")
+		require.True(t, ok)
+		sums[service.AsString()] = dp.Value
+	}
+
+	// Each service is measured as if it were sent on its own
+	sizer := &pmetric.ProtoMarshaler{}
+	expected := map[string]int64{}
+	for i := 0; i < md.ResourceMetrics().Len(); i++ {
+		single := pmetric.NewMetrics()
+		md.ResourceMetrics().At(i).CopyTo(single.ResourceMetrics().AppendEmpty())
+		service, _ := single.ResourceMetrics().At(0).Resource().Attributes().Get("service.name")
+		expected[service.Str()] = int64(sizer.MetricsSize(single))
+	}
+	require.Equal(t, expected, sums)
 }
 
 func Test_metricConsumer_Capabilities(t *testing.T) {
-	nopLogger := zap.NewNop()
 	componentID := "id"
 	baseConsumer := consumertest.NewNop()
-	mConsumer := newMetricConsumer(nopLogger, componentID, baseConsumer)
+	mConsumer, err := newMetricConsumer(componenttest.NewNopTelemetrySettings(), componentID, baseConsumer)
+	require.NoError(t, err)
 
 	require.Equal(t, baseConsumer.Capabilities(), mConsumer.Capabilities())
 }
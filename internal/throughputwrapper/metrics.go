@@ -15,44 +15,29 @@
 package throughputwrapper
 
 import (
-	"go.opencensus.io/stats"
-	"go.opencensus.io/stats/view"
-	"go.opencensus.io/tag"
+	"go.opentelemetry.io/otel/metric"
 )
 
-const tagComponentKey = "component"
+const (
+	// scopeName is the instrumentation scope of the throughput metrics
+	scopeName = "github.com/observiq/bindplane-agent/internal/throughputwrapper"
 
-var (
-	componentTagKey      = tag.MustNewKey(tagComponentKey)
-	logThroughputSize    = stats.Int64("log_throughput_size", "Size of the log package emitted from the component", stats.UnitBytes)
-	metricThroughputSize = stats.Int64("metric_throughput_size", "Size of the metric package emitted from the component", stats.UnitBytes)
-	traceThroughputSize  = stats.Int64("trace_throughput_size", "Size of the trace package emitted from the component", stats.UnitBytes)
-)
+	// attributeComponent is the attribute containing the ID of the measured receiver
+	attributeComponent = "component"
+
+	// attributeOverflow marks measurements for attribute combinations past the cardinality cap
+	attributeOverflow = "otel.metric.overflow"
 
-func metricViews() []*view.View {
-	componentTagKeys := []tag.Key{componentTagKey}
+	logThroughputSizeName    = "component/log_throughput_size"
+	metricThroughputSizeName = "component/metric_throughput_size"
+	traceThroughputSizeName  = "component/trace_throughput_size"
+)
 
-	return []*view.View{
-		{
-			Name:        tagComponentKey + "/" + logThroughputSize.Name(),
-			Description: logThroughputSize.Description(),
-			Measure:     logThroughputSize,
-			TagKeys:     componentTagKeys,
-			Aggregation: view.Sum(),
-		},
-		{
-			Name:        tagComponentKey + "/" + metricThroughputSize.Name(),
-			Description: metricThroughputSize.Description(),
-			Measure:     metricThroughputSize,
-			TagKeys:     componentTagKeys,
-			Aggregation: view.Sum(),
-		},
-		{
-			Name:        tagComponentKey + "/" + traceThroughputSize.Name(),
-			Description: traceThroughputSize.Description(),
-			Measure:     traceThroughputSize,
-			TagKeys:     componentTagKeys,
-			Aggregation: view.Sum(),
-		},
-	}
+// newSizeCounter creates a counter measuring the size in bytes of payloads emitted from receivers
+func newSizeCounter(mp metric.MeterProvider, name, description string) (metric.Int64Counter, error) {
+	return mp.Meter(scopeName).Int64Counter(
+		name,
+		metric.WithDescription(description),
+		metric.WithUnit("By"),
+	)
 }
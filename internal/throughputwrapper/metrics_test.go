@@ -15,20 +15,75 @@
 package throughputwrapper
 
 import (
+	"context"
 	"testing"
 
-	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
 )
 
-func TestMetricViews(t *testing.T) {
-	expectedViewNames := []string{
-		"component/log_throughput_size",
-		"component/metric_throughput_size",
-		"component/trace_throughput_size",
-	}
+func TestNewSizeCounter(t *testing.T) {
+	telemetry, reader := newTestTelemetry(t)
+
+	counter, err := newSizeCounter(telemetry.MeterProvider, logThroughputSizeName, "description")
+	require.NoError(t, err)
+	counter.Add(context.Background(), 10)
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	require.Len(t, rm.ScopeMetrics, 1)
+	require.Equal(t, scopeName, rm.ScopeMetrics[0].Scope.Name)
+
+	m := rm.ScopeMetrics[0].Metrics[0]
+	require.Equal(t, "component/log_throughput_size", m.Name)
+	require.Equal(t, "description", m.Description)
+	require.Equal(t, "By", m.Unit)
+}
+
+// newTestTelemetry returns telemetry settings with a meter provider that can be read from
+func newTestTelemetry(t *testing.T) (component.TelemetrySettings, *sdkmetric.ManualReader) {
+	t.Helper()
 
-	views := metricViews()
-	for i, viewName := range expectedViewNames {
-		assert.Equal(t, viewName, views[i].Name)
+	reader := sdkmetric.NewManualReader()
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	t.Cleanup(func() {
+		require.NoError(t, mp.Shutdown(context.Background()))
+	})
+
+	telemetry := componenttest.NewNopTelemetrySettings()
+	telemetry.MeterProvider = mp
+	return telemetry, reader
+}
+
+// collectSums returns the data points of the named counter
+func collectSums(t *testing.T, reader *sdkmetric.ManualReader, name string) []metricdata.DataPoint[int64] {
+	t.Helper()
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name == name {
+				sum, ok := m.Data.(metricdata.Sum[int64])
+				require.True(t, ok)
+				return sum.DataPoints
+			}
+		}
 	}
+
+	return nil
+}
+
+// setTestSettings sets the settings for the duration of the test
+func setTestSettings(t *testing.T, s Settings) {
+	t.Helper()
+
+	require.NoError(t, SetSettings(s))
+	t.Cleanup(func() {
+		require.NoError(t, SetSettings(DefaultSettings()))
+	})
 }
@@ -18,19 +18,11 @@ package throughputwrapper
 import (
 	"context"
 
-	"go.opencensus.io/stats/view"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/receiver"
 )
 
-// RegisterMetricViews unregisters old metric views if they exist and registers new ones
-func RegisterMetricViews() error {
-	views := metricViews()
-	view.Unregister(views...)
-	return view.Register(views...)
-}
-
 // WrapReceiverFactory creates a wrapper factory that around the passed in factory. Injecting consumers to measure output from the passed in receiver.
 func WrapReceiverFactory(receiverFactory receiver.Factory) receiver.Factory {
 	opts := make([]receiver.FactoryOption, 0, 3)
@@ -63,7 +55,10 @@ func wrapCreateMetricsReceiverFunc(createMetricsReceiverFunc receiver.CreateMetr
 		rConf component.Config,
 		nextConsumer consumer.Metrics,
 	) (receiver.Metrics, error) {
-		wrappedConsumer := newMetricConsumer(set.Logger, set.ID.String(), nextConsumer)
+		wrappedConsumer, err := newMetricConsumer(set.TelemetrySettings, set.ID.String(), nextConsumer)
+		if err != nil {
+			return nil, err
+		}
 		return createMetricsReceiverFunc(ctx, set, rConf, wrappedConsumer)
 	}
 }
@@ -74,7 +69,10 @@ func wrapCreateLogReceiverFunc(createLogsReceiverFunc receiver.CreateLogsFunc) r
 		rConf component.Config,
 		nextConsumer consumer.Logs,
 	) (receiver.Logs, error) {
-		wrappedConsumer := newLogConsumer(set.Logger, set.ID.String(), nextConsumer)
+		wrappedConsumer, err := newLogConsumer(set.TelemetrySettings, set.ID.String(), nextConsumer)
+		if err != nil {
+			return nil, err
+		}
 		return createLogsReceiverFunc(ctx, set, rConf, wrappedConsumer)
 	}
 }
@@ -85,8 +83,10 @@ func wrapCreateTraceReceiverFunc(createTracesReceiverFunc receiver.CreateTracesF
 		rConf component.Config,
 		nextConsumer consumer.Traces,
 	) (receiver.Traces, error) {
-		wrappedConsumer := newTraceConsumer(set.Logger, set.ID.String(), nextConsumer)
-
+		wrappedConsumer, err := newTraceConsumer(set.TelemetrySettings, set.ID.String(), nextConsumer)
+		if err != nil {
+			return nil, err
+		}
 		return createTracesReceiverFunc(ctx, set, rConf, wrappedConsumer)
 	}
 }
@@ -0,0 +1,109 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package throughputwrapper
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+const (
+	// maxAttributes is the maximum number of resource attributes measurements may be broken down by
+	maxAttributes = 3
+
+	// maxCardinalityLimit is the highest cardinality cap that may be configured
+	maxCardinalityLimit = 1000
+)
+
+var (
+	errInvalidSamplingRatio = errors.New("sampling ratio must be between 0.0 and 1.0")
+	errInvalidCardinality   = fmt.Errorf("max cardinality must be between 1 and %d", maxCardinalityLimit)
+)
+
+var (
+	settings    = DefaultSettings()
+	settingsMux sync.RWMutex
+)
+
+// Settings configures the measurements taken for wrapped receivers
+type Settings struct {
+	// SamplingRatio is the ratio of payloads that are measured. Values between 0.0 and 1.0 are valid.
+	SamplingRatio float64
+
+	// Attributes are the resource attributes measurements are broken down by
+	Attributes []string
+
+	// MaxCardinality is the maximum number of attribute combinations measured per receiver.
+	// Any further combinations are measured together as an overflow.
+	MaxCardinality int
+}
+
+// DefaultSettings returns the default settings, which measure every payload without a breakdown
+func DefaultSettings() Settings {
+	return Settings{
+		SamplingRatio:  1.0,
+		MaxCardinality: 100,
+	}
+}
+
+// Validate validates the settings
+func (s Settings) Validate() error {
+	if s.SamplingRatio < 0.0 || s.SamplingRatio > 1.0 {
+		return errInvalidSamplingRatio
+	}
+
+	if len(s.Attributes) > maxAttributes {
+		return fmt.Errorf("at most %d attributes may be specified, got %d", maxAttributes, len(s.Attributes))
+	}
+
+	seen := make(map[string]struct{}, len(s.Attributes))
+	for _, attr := range s.Attributes {
+		if attr == "" {
+			return errors.New("attributes must not be empty")
+		}
+		if _, ok := seen[attr]; ok {
+			return fmt.Errorf("duplicate attribute %q", attr)
+		}
+		seen[attr] = struct{}{}
+	}
+
+	if s.MaxCardinality < 1 || s.MaxCardinality > maxCardinalityLimit {
+		return errInvalidCardinality
+	}
+
+	return nil
+}
+
+// SetSettings sets the settings used by receivers created after this call
+func SetSettings(s Settings) error {
+	if err := s.Validate(); err != nil {
+		return fmt.Errorf("invalid throughput settings: %w", err)
+	}
+
+	settingsMux.Lock()
+	defer settingsMux.Unlock()
+
+	settings = s
+	return nil
+}
+
+// currentSettings returns the settings for newly created receivers
+func currentSettings() Settings {
+	settingsMux.RLock()
+	defer settingsMux.RUnlock()
+
+	return settings
+}
@@ -0,0 +1,120 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package throughputwrapper
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSettingsValidate(t *testing.T) {
+	testCases := []struct {
+		name        string
+		settings    func() Settings
+		expectedErr string
+	}{
+		{
+			name:     "Default",
+			settings: DefaultSettings,
+		},
+		{
+			name: "Valid breakdown",
+			settings: func() Settings {
+				s := DefaultSettings()
+				s.SamplingRatio = 0.5
+				s.Attributes = []string{"service.name", "log_type"}
+				return s
+			},
+		},
+		{
+			name: "Invalid sampling ratio",
+			settings: func() Settings {
+				s := DefaultSettings()
+				s.SamplingRatio = 1.5
+				return s
+			},
+			expectedErr: errInvalidSamplingRatio.Error(),
+		},
+		{
+			name: "Too many attributes",
+			settings: func() Settings {
+				s := DefaultSettings()
+				s.Attributes = []string{"a", "b", "c", "d"}
+				return s
+			},
+			expectedErr: "at most 3 attributes may be specified, got 4",
+		},
+		{
+			name: "Empty attribute",
+			settings: func() Settings {
+				s := DefaultSettings()
+				s.Attributes = []string{""}
+				return s
+			},
+			expectedErr: "attributes must not be empty",
+		},
+		{
+			name: "Duplicate attribute",
+			settings: func() Settings {
+				s := DefaultSettings()
+				s.Attributes = []string{"service.name", "service.name"}
+				return s
+			},
+			expectedErr: `duplicate attribute "service.name"`,
+		},
+		{
+			name: "Cardinality too low",
+			settings: func() Settings {
+				s := DefaultSettings()
+				s.MaxCardinality = 0
+				return s
+			},
+			expectedErr: errInvalidCardinality.Error(),
+		},
+		{
+			name: "Cardinality too high",
+			settings: func() Settings {
+				s := DefaultSettings()
+				s.MaxCardinality = 1001
+				return s
+			},
+			expectedErr: errInvalidCardinality.Error(),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.settings().Validate()
+			if tc.expectedErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.EqualError(t, err, tc.expectedErr)
+		})
+	}
+}
+
+func TestSetSettings(t *testing.T) {
+	s := DefaultSettings()
+	s.Attributes = []string{"service.name"}
+	setTestSettings(t, s)
+	require.Equal(t, s, currentSettings())
+
+	s.SamplingRatio = -1
+	require.Error(t, SetSettings(s))
+	require.Equal(t, []string{"service.name"}, currentSettings().Attributes)
+	require.Equal(t, 1.0, currentSettings().SamplingRatio)
+}
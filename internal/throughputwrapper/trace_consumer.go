@@ -16,44 +16,70 @@ package throughputwrapper
 
 import (
 	"context"
+	"fmt"
 
-	"go.opencensus.io/stats"
-	"go.opencensus.io/tag"
+	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/ptrace"
-	"go.uber.org/zap"
 )
 
 var _ consumer.Traces = (*traceConsumer)(nil)
 
 type traceConsumer struct {
-	logger       *zap.Logger
-	mutators     []tag.Mutator
+	measurer     *measurer
 	tracesSizer  ptrace.MarshalSizer
 	baseConsumer consumer.Traces
 }
 
-func newTraceConsumer(logger *zap.Logger, componentID string, baseConsumer consumer.Traces) *traceConsumer {
+func newTraceConsumer(telemetry component.TelemetrySettings, componentID string, baseConsumer consumer.Traces) (*traceConsumer, error) {
+	counter, err := newSizeCounter(telemetry.MeterProvider, traceThroughputSizeName, "Size of the trace package emitted from the component")
+	if err != nil {
+		return nil, fmt.Errorf("create trace throughput counter: %w", err)
+	}
+
 	return &traceConsumer{
-		logger:       logger,
-		mutators:     []tag.Mutator{tag.Upsert(componentTagKey, componentID, tag.WithTTL(tag.TTLNoPropagation))},
+		measurer:     newMeasurer(telemetry.Logger, componentID, counter),
 		tracesSizer:  &ptrace.ProtoMarshaler{},
 		baseConsumer: baseConsumer,
-	}
+	}, nil
 }
 
 // ConsumeTraces measures the ptrace.Traces size before passing it onto the baseConsumer
 func (t *traceConsumer) ConsumeTraces(ctx context.Context, td ptrace.Traces) error {
-	if err := stats.RecordWithTags(
-		ctx,
-		t.mutators,
-		traceThroughputSize.M(int64(t.tracesSizer.TracesSize(td))),
-	); err != nil {
-		t.logger.Warn("Error while measuring receiver trace throughput", zap.Error(err))
+	if t.measurer.sample() {
+		t.measure(ctx, td)
 	}
 	return t.baseConsumer.ConsumeTraces(ctx, td)
 }
 
+// measure records the size of the payload, broken down by resource attributes if configured
+func (t *traceConsumer) measure(ctx context.Context, td ptrace.Traces) {
+	if !t.measurer.breakdown() {
+		t.measurer.record(ctx, t.tracesSizer.TracesSize(td), t.measurer.componentOpt)
+		return
+	}
+
+	resources := td.ResourceSpans()
+	groups := t.measurer.group(resources.Len(), func(i int) pcommon.Resource {
+		return resources.At(i).Resource()
+	})
+
+	// Avoid copying the payload when all resources share the same attributes
+	if len(groups) == 1 {
+		t.measurer.record(ctx, t.tracesSizer.TracesSize(td), groups[0].option)
+		return
+	}
+
+	for _, g := range groups {
+		grouped := ptrace.NewTraces()
+		for _, i := range g.indices {
+			resources.At(i).CopyTo(grouped.ResourceSpans().AppendEmpty())
+		}
+		t.measurer.record(ctx, t.tracesSizer.TracesSize(grouped), g.option)
+	}
+}
+
 // Capabilities returns the baseConsumer's capabilities
 func (t *traceConsumer) Capabilities() consumer.Capabilities {
 	return t.baseConsumer.Capabilities()
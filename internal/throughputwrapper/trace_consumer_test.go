@@ -19,43 +19,87 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/consumer/consumertest"
 	"go.opentelemetry.io/collector/pdata/ptrace"
-	"go.uber.org/zap"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 func Test_newTraceConsumer(t *testing.T) {
-	nopLogger := zap.NewNop()
 	componentID := "id"
 	baseConsumer := consumertest.NewNop()
-	tConsumer := newTraceConsumer(nopLogger, componentID, baseConsumer)
+	tConsumer, err := newTraceConsumer(componenttest.NewNopTelemetrySettings(), componentID, baseConsumer)
+	require.NoError(t, err)
 
-	require.Equal(t, nopLogger, tConsumer.logger)
 	require.Equal(t, baseConsumer, tConsumer.baseConsumer)
-	require.Len(t, tConsumer.mutators, 1)
+	require.NotNil(t, tConsumer.measurer)
 	require.Equal(t, &ptrace.ProtoMarshaler{}, tConsumer.tracesSizer)
 }
 
 func Test_traceConsumer_ConsumeTraces(t *testing.T) {
-	nopLogger := zap.NewNop()
+	telemetry, reader := newTestTelemetry(t)
 	componentID := "id"
 	baseConsumer := new(consumertest.TracesSink)
-	tConsumer := newTraceConsumer(nopLogger, componentID, baseConsumer)
+	tConsumer, err := newTraceConsumer(telemetry, componentID, baseConsumer)
+	require.NoError(t, err)
 
 	td := ptrace.NewTraces()
 	td.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans().AppendEmpty()
 
-	err := tConsumer.ConsumeTraces(context.Background(), td)
+	err = tConsumer.ConsumeTraces(context.Background(), td)
 	require.NoError(t, err)
 
 	require.Equal(t, 1, baseConsumer.SpanCount())
+
+	sums := collectSums(t, reader, traceThroughputSizeName)
+	require.Len(t, sums, 1)
+	require.Equal(t, int64((&ptrace.ProtoMarshaler{}).TracesSize(td)), sums[0].Value)
+	require.Equal(t, attribute.NewSet(attribute.String("component", componentID)), sums[0].Attributes)
+}
+
+func Test_traceConsumer_ConsumeTracesBreakdown(t *testing.T) {
+	s := DefaultSettings()
+	s.Attributes = []string{"service.name"}
+	setTestSettings(t, s)
+
+	telemetry, reader := newTestTelemetry(t)
+	tConsumer, err := newTraceConsumer(telemetry, "id", consumertest.NewNop())
+	require.NoError(t, err)
+
+	td := ptrace.NewTraces()
+	for _, service := range []string{"a", "b", "bb"} {
+		rs := td.ResourceSpans().AppendEmpty()
+		rs.Resource().Attributes().PutStr("service.name", service)
+		rs.ScopeSpans().AppendEmpty().Spans().AppendEmpty().SetName(service)
+	}
+
+	err = tConsumer.ConsumeTraces(context.Background(), td)
+	require.NoError(t, err)
+
+	sums := map[string]int64{}
+	for _, dp := range collectSums(t, reader, traceThroughputSizeName) {
+		service, ok := dp.Attributes.Value("service.name")
+		require.True(t, ok)
+		sums[service.AsString()] = dp.Value
+	}
+
+	// Each service is measured as if it were sent on its own
+	sizer := &ptrace.ProtoMarshaler{}
+	expected := map[string]int64{}
+	for i := 0; i < td.ResourceSpans().Len(); i++ {
+		single := ptrace.NewTraces()
+		td.ResourceSpans().At(i).CopyTo(single.ResourceSpans().AppendEmpty())
+		service, _ := single.ResourceSpans().At(0).Resource().Attributes().Get("service.name")
+		expected[service.Str()] = int64(sizer.TracesSize(single))
+	}
+	require.Equal(t, expected, sums)
 }
 
 func Test_traceConsumer_Capabilities(t *testing.T) {
-	nopLogger := zap.NewNop()
 	componentID := "id"
 	baseConsumer := consumertest.NewNop()
-	tConsumer := newTraceConsumer(nopLogger, componentID, baseConsumer)
+	tConsumer, err := newTraceConsumer(componenttest.NewNopTelemetrySettings(), componentID, baseConsumer)
+	require.NoError(t, err)
 
 	require.Equal(t, baseConsumer.Capabilities(), tConsumer.Capabilities())
 }
@@ -22,6 +22,7 @@ import (
 	"os"
 	"path/filepath"
 
+	"github.com/observiq/bindplane-agent/internal/detector"
 	"gopkg.in/yaml.v3"
 )
 
@@ -43,6 +44,12 @@ var (
 
 	// errInvalidCAFile for ca file that is not readable
 	errInvalidCAFile = "failed to read TLS CA file"
+
+	// errMissingPackageDir is the error when a package does not specify its install directory
+	errMissingPackageDir = "must specify dir for package"
+
+	// errInvalidIdentity is the error prefix when the identity config is invalid
+	errInvalidIdentity = "invalid identity config"
 )
 
 // Config contains the configuration for the collector to communicate with an OpAmp enabled platform.
@@ -52,11 +59,28 @@ type Config struct {
 	AgentID   string     `yaml:"agent_id"`
 	TLS       *TLSConfig `yaml:"tls_config,omitempty"`
 
+	// Packages contains the non-collector packages that may be installed, keyed by package name
+	Packages map[string]PackageConfig `yaml:"packages,omitempty"`
+
+	// Identity configures detection of additional attributes sent in the agent description
+	Identity *detector.Config `yaml:"identity,omitempty"`
+
 	// Updatable fields
 	Labels    *string `yaml:"labels,omitempty"`
 	AgentName *string `yaml:"agent_name,omitempty"`
 }
 
+// PackageConfig describes how a non-collector package offered by the OpAmp server is installed
+type PackageConfig struct {
+	// Dir is the directory the package's files are installed into
+	Dir string `yaml:"dir"`
+	// FileName is the name the downloaded file is installed as, if it is not an archive.
+	// Defaults to the file name in the download URL.
+	FileName string `yaml:"file_name,omitempty"`
+	// RestartCollector is whether the collector must be restarted after installing the package
+	RestartCollector bool `yaml:"restart_collector,omitempty"`
+}
+
 // TLSConfig represents the TLS config to connect to OpAmp server
 type TLSConfig struct {
 	InsecureSkipVerify bool    `yaml:"insecure_skip_verify"`
@@ -146,6 +170,19 @@ func ParseConfig(configLocation string) (*Config, error) {
 			return nil, errors.New(errMissingTLSFiles)
 		}
 	}
+
+	for name, pkg := range config.Packages {
+		if pkg.Dir == "" {
+			return nil, fmt.Errorf("%s %s", errMissingPackageDir, name)
+		}
+	}
+
+	if config.Identity != nil {
+		if err := config.Identity.Validate(); err != nil {
+			return nil, fmt.Errorf("%s: %w", errInvalidIdentity, err)
+		}
+	}
+
 	return &config, nil
 }
 
@@ -172,6 +209,15 @@ func (c Config) Copy() *Config {
 	if c.TLS != nil {
 		cfgCopy.TLS = c.TLS.copy()
 	}
+	if c.Packages != nil {
+		cfgCopy.Packages = make(map[string]PackageConfig, len(c.Packages))
+		for name, pkg := range c.Packages {
+			cfgCopy.Packages[name] = pkg
+		}
+	}
+	if c.Identity != nil {
+		cfgCopy.Identity = c.Identity.Copy()
+	}
 
 	return cfgCopy
 }
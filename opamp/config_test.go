@@ -22,6 +22,7 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/observiq/bindplane-agent/internal/detector"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -267,6 +268,116 @@ agent_name: "My Agent"
 				assert.Equal(t, expectedConfig, cfg)
 			},
 		},
+		{
+			desc: "Successful Parse with Packages",
+			testFunc: func(t *testing.T) {
+				configContents := `
+endpoint: localhost:1234
+agent_id: 8321f735-a52c-4f49-aca9-66f9266c5fe5
+packages:
+  plugins:
+    dir: ./plugins
+    restart_collector: true
+  lookup-tables:
+    dir: /var/lib/lookups
+`
+
+				tmpDir := t.TempDir()
+				configPath := filepath.Join(tmpDir, "manager.yml")
+
+				err := os.WriteFile(configPath, []byte(configContents), os.ModePerm)
+				require.NoError(t, err)
+
+				expectedConfig := &Config{
+					Endpoint: "localhost:1234",
+					AgentID:  "8321f735-a52c-4f49-aca9-66f9266c5fe5",
+					Packages: map[string]PackageConfig{
+						"plugins":       {Dir: "./plugins", RestartCollector: true},
+						"lookup-tables": {Dir: "/var/lib/lookups"},
+					},
+				}
+
+				cfg, err := ParseConfig(configPath)
+				assert.NoError(t, err)
+				assert.Equal(t, expectedConfig, cfg)
+			},
+		},
+		{
+			desc: "Package missing dir",
+			testFunc: func(t *testing.T) {
+				configContents := `
+endpoint: localhost:1234
+agent_id: 8321f735-a52c-4f49-aca9-66f9266c5fe5
+packages:
+  plugins:
+    restart_collector: true
+`
+
+				tmpDir := t.TempDir()
+				configPath := filepath.Join(tmpDir, "manager.yml")
+
+				err := os.WriteFile(configPath, []byte(configContents), os.ModePerm)
+				require.NoError(t, err)
+
+				cfg, err := ParseConfig(configPath)
+				assert.ErrorContains(t, err, errMissingPackageDir)
+				assert.Nil(t, cfg)
+			},
+		},
+		{
+			desc: "Successful Parse with Identity",
+			testFunc: func(t *testing.T) {
+				configContents := `
+endpoint: localhost:1234
+agent_id: 8321f735-a52c-4f49-aca9-66f9266c5fe5
+identity:
+  detectors: [cloud, file]
+  file: /etc/agent-attributes.yaml
+  identifying_attributes: [host.id]
+`
+
+				tmpDir := t.TempDir()
+				configPath := filepath.Join(tmpDir, "manager.yml")
+
+				err := os.WriteFile(configPath, []byte(configContents), os.ModePerm)
+				require.NoError(t, err)
+
+				expectedConfig := &Config{
+					Endpoint: "localhost:1234",
+					AgentID:  "8321f735-a52c-4f49-aca9-66f9266c5fe5",
+					Identity: &detector.Config{
+						Detectors:             []string{"cloud", "file"},
+						File:                  "/etc/agent-attributes.yaml",
+						IdentifyingAttributes: []string{"host.id"},
+					},
+				}
+
+				cfg, err := ParseConfig(configPath)
+				assert.NoError(t, err)
+				assert.Equal(t, expectedConfig, cfg)
+			},
+		},
+		{
+			desc: "Invalid Identity",
+			testFunc: func(t *testing.T) {
+				configContents := `
+endpoint: localhost:1234
+agent_id: 8321f735-a52c-4f49-aca9-66f9266c5fe5
+identity:
+  detectors: [unknown]
+`
+
+				tmpDir := t.TempDir()
+				configPath := filepath.Join(tmpDir, "manager.yml")
+
+				err := os.WriteFile(configPath, []byte(configContents), os.ModePerm)
+				require.NoError(t, err)
+
+				cfg, err := ParseConfig(configPath)
+				assert.ErrorContains(t, err, errInvalidIdentity)
+				assert.Nil(t, cfg)
+			},
+		},
 		{
 			desc: "Successful Partial Parse",
 			testFunc: func(t *testing.T) {
@@ -849,6 +960,13 @@ func TestConfigCopy(t *testing.T) {
 		Labels:    &labelsContents,
 		AgentName: &agentNameContents,
 		TLS:       &tlscfg,
+		Packages: map[string]PackageConfig{
+			"plugins": {Dir: "./plugins", RestartCollector: true},
+		},
+		Identity: &detector.Config{
+			Detectors: []string{"env"},
+			EnvPrefix: "MY_ATTR_",
+		},
 	}
 
 	copyCfg := cfg.Copy()
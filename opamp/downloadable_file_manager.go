@@ -28,6 +28,17 @@ type DownloadableFileManager interface {
 	// If it matches, the archive is extracted.
 	// If the archive cannot be extracted, downloaded, or verified, then an error is returned.
 	FetchAndExtractArchive(*protobufs.DownloadableFile) error
+	// FetchAndApplyDelta fetches the delta archive at the specified URL.
+	// It then checks to see if it matches the expected sha256 sum of the file.
+	// If it matches, the patches in the archive are applied against the current installation
+	// to build the new artifacts, and the resulting files are verified against the archive's manifest.
+	// If the delta cannot be downloaded, verified, or applied, then an error is returned.
+	FetchAndApplyDelta(*protobufs.DownloadableFile) error
+	// FetchAndInstallPackage fetches the file at the specified URL for the named non-collector package.
+	// It then checks to see if it matches the expected sha256 sum of the file.
+	// If it matches, the file (or the contents of the file, if it is an archive) is installed as described by the PackageConfig.
+	// If the file cannot be downloaded, verified, or installed, then an error is returned.
+	FetchAndInstallPackage(file *protobufs.DownloadableFile, name string, cfg PackageConfig) error
 	// CleanupArtifacts removes temporary artifacts from previous download/installs
 	CleanupArtifacts()
 }
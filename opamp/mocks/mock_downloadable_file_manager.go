@@ -3,6 +3,7 @@
 package mocks
 
 import (
+	opamp "github.com/observiq/bindplane-agent/opamp"
 	mock "github.com/stretchr/testify/mock"
 
 	protobufs "github.com/open-telemetry/opamp-go/protobufs"
@@ -18,6 +19,20 @@ func (_m *MockDownloadableFileManager) CleanupArtifacts() {
 	_m.Called()
 }
 
+// FetchAndApplyDelta provides a mock function with given fields: _a0
+func (_m *MockDownloadableFileManager) FetchAndApplyDelta(_a0 *protobufs.DownloadableFile) error {
+	ret := _m.Called(_a0)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*protobufs.DownloadableFile) error); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // FetchAndExtractArchive provides a mock function with given fields: _a0
 func (_m *MockDownloadableFileManager) FetchAndExtractArchive(_a0 *protobufs.DownloadableFile) error {
 	ret := _m.Called(_a0)
@@ -32,6 +47,20 @@ func (_m *MockDownloadableFileManager) FetchAndExtractArchive(_a0 *protobufs.Dow
 	return r0
 }
 
+// FetchAndInstallPackage provides a mock function with given fields: file, name, cfg
+func (_m *MockDownloadableFileManager) FetchAndInstallPackage(file *protobufs.DownloadableFile, name string, cfg opamp.PackageConfig) error {
+	ret := _m.Called(file, name, cfg)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*protobufs.DownloadableFile, string, opamp.PackageConfig) error); ok {
+		r0 = rf(file, name, cfg)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // NewMockDownloadableFileManager creates a new instance of MockDownloadableFileManager. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewMockDownloadableFileManager(t interface {
@@ -17,6 +17,7 @@ package observiq
 import (
 	"runtime"
 
+	"github.com/observiq/bindplane-agent/internal/detector"
 	ios "github.com/observiq/bindplane-agent/internal/os"
 	"github.com/observiq/bindplane-agent/opamp"
 	"github.com/open-telemetry/opamp-go/protobufs"
@@ -35,6 +36,11 @@ type identity struct {
 	oSFamily    string
 	hostname    string
 	mac         string
+
+	// detected contains the attributes found by the identity detectors
+	detected map[string]string
+	// identifyingKeys contains the detected attributes that are sent as identifying attributes
+	identifyingKeys map[string]bool
 }
 
 // newIdentity constructs a new identity for this collector
@@ -50,6 +56,14 @@ func newIdentity(logger *zap.Logger, config opamp.Config, version string) *ident
 		logger.Warn("Failed to retrieve host details on collector. Creating partial identity", zap.Error(err))
 	}
 
+	var identifyingKeys map[string]bool
+	if config.Identity != nil && len(config.Identity.IdentifyingAttributes) > 0 {
+		identifyingKeys = make(map[string]bool, len(config.Identity.IdentifyingAttributes))
+		for _, key := range config.Identity.IdentifyingAttributes {
+			identifyingKeys[key] = true
+		}
+	}
+
 	return &identity{
 		agentID:     config.AgentID,
 		agentName:   config.AgentName,
@@ -61,6 +75,8 @@ func newIdentity(logger *zap.Logger, config opamp.Config, version string) *ident
 		oSFamily:    runtime.GOOS,
 		hostname:    hostname,
 		mac:         ios.MACAddress(),
+
+		identifyingKeys: identifyingKeys,
 	}
 }
 
@@ -87,9 +103,37 @@ func (i identity) Copy() *identity {
 		*identCpy.labels = *i.labels
 	}
 
+	if i.detected != nil {
+		identCpy.detected = make(map[string]string, len(i.detected))
+		for key, value := range i.detected {
+			identCpy.detected[key] = value
+		}
+	}
+
+	if i.identifyingKeys != nil {
+		identCpy.identifyingKeys = make(map[string]bool, len(i.identifyingKeys))
+		for key, value := range i.identifyingKeys {
+			identCpy.identifyingKeys[key] = value
+		}
+	}
+
 	return identCpy
 }
 
+// setDetected replaces the detected attributes, returning true if they changed
+func (i *identity) setDetected(detected map[string]string) bool {
+	changed := len(detected) != len(i.detected)
+	for key, value := range detected {
+		if existing, ok := i.detected[key]; !ok || existing != value {
+			changed = true
+			break
+		}
+	}
+
+	i.detected = detected
+	return changed
+}
+
 func (i *identity) ToAgentDescription() *protobufs.AgentDescription {
 	identifyingAttributes := []*protobufs.KeyValue{
 		opamp.StringKeyValue("service.instance.id", i.agentID),
@@ -115,6 +159,28 @@ func (i *identity) ToAgentDescription() *protobufs.AgentDescription {
 		nonIdentifyingAttributes = append(nonIdentifyingAttributes, opamp.StringKeyValue("service.labels", *i.labels))
 	}
 
+	// Add detected attributes, in a stable order so the description only changes when the attributes do
+	reserved := make(map[string]bool, len(identifyingAttributes)+len(nonIdentifyingAttributes))
+	for _, kv := range identifyingAttributes {
+		reserved[kv.Key] = true
+	}
+	for _, kv := range nonIdentifyingAttributes {
+		reserved[kv.Key] = true
+	}
+
+	for _, key := range detector.SortedKeys(i.detected) {
+		// Detected attributes can't override the attributes the agent always sends
+		if reserved[key] {
+			continue
+		}
+
+		if i.identifyingKeys[key] {
+			identifyingAttributes = append(identifyingAttributes, opamp.StringKeyValue(key, i.detected[key]))
+		} else {
+			nonIdentifyingAttributes = append(nonIdentifyingAttributes, opamp.StringKeyValue(key, i.detected[key]))
+		}
+	}
+
 	agentDesc := &protobufs.AgentDescription{
 		IdentifyingAttributes:    identifyingAttributes,
 		NonIdentifyingAttributes: nonIdentifyingAttributes,
@@ -18,6 +18,7 @@ import (
 	"runtime"
 	"testing"
 
+	"github.com/observiq/bindplane-agent/internal/detector"
 	"github.com/observiq/bindplane-agent/opamp"
 	"github.com/open-telemetry/opamp-go/protobufs"
 	"github.com/stretchr/testify/assert"
@@ -36,6 +37,9 @@ func Test_newIdentity(t *testing.T) {
 		AgentID:   "8321f735-a52c-4f49-aca9-66f9266c5fe5",
 		Labels:    &labelsContents,
 		AgentName: &agentNameContents,
+		Identity: &detector.Config{
+			IdentifyingAttributes: []string{"host.id"},
+		},
 	}
 
 	expectedVersion := "0.0.0"
@@ -46,6 +50,7 @@ func Test_newIdentity(t *testing.T) {
 	require.Equal(t, cfg.AgentID, got.agentID)
 	require.Equal(t, cfg.AgentName, got.agentName)
 	require.Equal(t, cfg.Labels, got.labels)
+	require.Equal(t, map[string]bool{"host.id": true}, got.identifyingKeys)
 
 	// Check fields that must not be empty
 	require.NotEmpty(t, got.oSDetails)
@@ -128,6 +133,46 @@ func TestToAgentDescription(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc: "With detected attributes",
+			ident: &identity{
+				agentID:     "4322d8d1-f3e0-46db-b68d-b01a4689ef19",
+				serviceName: "com.observiq.collector",
+				version:     "v1.2.3",
+				oSArch:      "amd64",
+				oSDetails:   "os details",
+				oSFamily:    "linux",
+				hostname:    "my-linux-box",
+				mac:         "68-C7-B4-EB-A8-D2",
+				detected: map[string]string{
+					"host.id":        "i-0123456789abcdef0",
+					"cloud.provider": "aws",
+					"cloud.region":   "us-east-1",
+					"host.name":      "overridden",
+				},
+				identifyingKeys: map[string]bool{
+					"host.id": true,
+				},
+			},
+			expected: &protobufs.AgentDescription{
+				IdentifyingAttributes: []*protobufs.KeyValue{
+					opamp.StringKeyValue("service.instance.id", "4322d8d1-f3e0-46db-b68d-b01a4689ef19"),
+					opamp.StringKeyValue("service.name", "com.observiq.collector"),
+					opamp.StringKeyValue("service.version", "v1.2.3"),
+					opamp.StringKeyValue("service.instance.name", "my-linux-box"),
+					opamp.StringKeyValue("host.id", "i-0123456789abcdef0"),
+				},
+				NonIdentifyingAttributes: []*protobufs.KeyValue{
+					opamp.StringKeyValue("os.arch", "amd64"),
+					opamp.StringKeyValue("os.details", "os details"),
+					opamp.StringKeyValue("os.family", "linux"),
+					opamp.StringKeyValue("host.name", "my-linux-box"),
+					opamp.StringKeyValue("host.mac_address", "68-C7-B4-EB-A8-D2"),
+					opamp.StringKeyValue("cloud.provider", "aws"),
+					opamp.StringKeyValue("cloud.region", "us-east-1"),
+				},
+			},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -152,9 +197,27 @@ func Test_identityCopy(t *testing.T) {
 		oSFamily:    "linux",
 		hostname:    "my-linux-box",
 		mac:         "68-C7-B4-EB-A8-D2",
+		detected: map[string]string{
+			"cloud.provider": "aws",
+		},
+		identifyingKeys: map[string]bool{
+			"host.id": true,
+		},
 	}
 
 	copyIdent := ident.Copy()
 
 	require.Equal(t, ident, copyIdent)
 }
+
+func Test_identitySetDetected(t *testing.T) {
+	ident := &identity{}
+
+	require.False(t, ident.setDetected(map[string]string{}))
+	require.True(t, ident.setDetected(map[string]string{"cloud.provider": "aws"}))
+	require.False(t, ident.setDetected(map[string]string{"cloud.provider": "aws"}))
+	require.True(t, ident.setDetected(map[string]string{"cloud.provider": "gcp"}))
+	require.True(t, ident.setDetected(map[string]string{"cloud.provider": "gcp", "host.id": "1"}))
+	require.True(t, ident.setDetected(map[string]string{}))
+	require.Equal(t, map[string]string{}, ident.detected)
+}
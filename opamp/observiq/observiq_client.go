@@ -23,8 +23,10 @@ import (
 	"net/http"
 	"net/url"
 	"sync"
+	"time"
 
 	"github.com/observiq/bindplane-agent/collector"
+	"github.com/observiq/bindplane-agent/internal/detector"
 	"github.com/observiq/bindplane-agent/internal/report"
 	"github.com/observiq/bindplane-agent/internal/version"
 	"github.com/observiq/bindplane-agent/opamp"
@@ -40,6 +42,16 @@ var (
 	ErrUnsupportedURL = errors.New("unsupported URL")
 )
 
+// detectIdentityTimeout bounds how long the identity detectors may run
+const detectIdentityTimeout = 5 * time.Second
+
+// jmxJarFileName is the file name of the OpenTelemetry JMX metrics jar
+const jmxJarFileName = "opentelemetry-java-contrib-jmx-metrics.jar"
+
+// jmxJarDir is the directory the JMX metrics jar is installed in, relative to the install directory.
+// Linux and macOS installs keep the jar in the parent of the install directory, the same as the updater.
+const jmxJarDir = ".."
+
 const capabilities = protobufs.AgentCapabilities_AgentCapabilities_ReportsStatus |
 	protobufs.AgentCapabilities_AgentCapabilities_AcceptsPackages |
 	protobufs.AgentCapabilities_AgentCapabilities_ReportsPackageStatuses |
@@ -73,6 +85,15 @@ type Client struct {
 	collectorMntrWg     sync.WaitGroup
 
 	currentConfig opamp.Config
+
+	// Used to detect additional identity attributes
+	detectors []detector.Detector
+	// identMutex guards ident, which is updated by the identity detectors in the background
+	identMutex sync.Mutex
+	// detectMutex guards detectCancel, so only one detection runs at a time
+	detectMutex  sync.Mutex
+	detectCancel context.CancelFunc
+	detectWg     sync.WaitGroup
 }
 
 // NewClientArgs arguments passed when creating a new client
@@ -122,6 +143,10 @@ func NewClient(args *NewClientArgs) (opamp.Client, error) {
 		reportManager:           reportManager,
 	}
 
+	if args.Config.Identity != nil {
+		observiqClient.detectors = detector.New(*args.Config.Identity)
+	}
+
 	// Parse URL to determin scheme
 	opampURL, err := url.Parse(args.Config.Endpoint)
 	if err != nil {
@@ -185,6 +210,9 @@ func (c *Client) Connect(ctx context.Context) error {
 		return err
 	}
 
+	// Detected attributes are added to the description once detection completes, so a slow detector doesn't delay connecting
+	c.startIdentityDetection()
+
 	tlsCfg, err := c.currentConfig.ToTLS()
 	if err != nil {
 		// Set package status file for error (for Updater to pick up), but do not force send to Server
@@ -248,16 +276,77 @@ func (c *Client) Disconnect(ctx context.Context) error {
 	// Ensure we're no longer monitoring the collector as we shutdown to avoid error messages due to shutdown
 	c.stopCollectorMonitoring()
 
+	// Set before stopping detection, so a reconnect in progress doesn't start it again
 	c.safeSetDisconnecting(true)
+	c.stopIdentityDetection()
 	c.collector.Stop(ctx)
 	return c.opampClient.Stop(ctx)
 }
 
+// startIdentityDetection runs the identity detectors in the background, cancelling any detection already running.
+// The agent description is sent again if the detected attributes changed.
+func (c *Client) startIdentityDetection() {
+	if len(c.detectors) == 0 {
+		return
+	}
+
+	c.detectMutex.Lock()
+	defer c.detectMutex.Unlock()
+
+	c.cancelIdentityDetection()
+	if c.safeGetDisconnecting() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), detectIdentityTimeout)
+	c.detectCancel = cancel
+
+	c.detectWg.Add(1)
+	go func() {
+		defer c.detectWg.Done()
+		defer cancel()
+
+		detected := detector.Detect(ctx, c.logger, c.detectors)
+
+		c.identMutex.Lock()
+		defer c.identMutex.Unlock()
+
+		if !c.ident.setDetected(detected) {
+			return
+		}
+
+		c.logger.Info("Detected identity attributes changed")
+		if err := c.opampClient.SetAgentDescription(c.ident.ToAgentDescription()); err != nil {
+			c.logger.Error("Error while setting agent description", zap.Error(err))
+		}
+	}()
+}
+
+// stopIdentityDetection cancels the identity detectors and waits for them to stop
+func (c *Client) stopIdentityDetection() {
+	c.detectMutex.Lock()
+	defer c.detectMutex.Unlock()
+
+	c.cancelIdentityDetection()
+}
+
+// cancelIdentityDetection cancels the identity detectors and waits for them to stop. detectMutex must be held.
+func (c *Client) cancelIdentityDetection() {
+	if c.detectCancel != nil {
+		c.detectCancel()
+	}
+	c.detectWg.Wait()
+}
+
 // client callbacks
 
 func (c *Client) onConnectHandler() {
 	c.logger.Info("Successfully connected to server")
 
+	// Attributes such as cloud metadata may have changed while disconnected, so detect them again.
+	// This runs in the background, since waiting for a previous detection to stop would block the callback.
+	go c.startIdentityDetection()
+
 	// See if we can retrieve the PackageStatuses where the collector package is in an installing state
 	pkgStatuses, err := c.getVerifiedPackageStatuses()
 	if err != nil {
@@ -391,6 +480,12 @@ func (c *Client) onPackagesAvailableHandler(availablePkgs *protobufs.PackagesAva
 
 	// Loop through all of the available packages sent from the server and create initial PackageStatuses
 	for pkgName, availablePkg := range availablePkgs.Packages {
+		// The delta package is only used to install the collector package, so it has no status of its own.
+		// The result of applying it is reported on the collector package status.
+		if pkgName == packagestate.CollectorDeltaPackageName {
+			continue
+		}
+
 		lastPkgStatus := lastPkgStatusMap[pkgName]
 		curPkgStatuses.Packages[pkgName] = c.buildInitialPackageStatus(pkgName, availablePkg, lastPkgStatus)
 	}
@@ -404,10 +499,39 @@ func (c *Client) onPackagesAvailableHandler(availablePkgs *protobufs.PackagesAva
 		return fmt.Errorf("opamp client failed to set package statuses: %w", err)
 	}
 
+	installCollector := curPkgStatuses.Packages[packagestate.CollectorPackageName].GetStatus() == protobufs.PackageStatusEnum_PackageStatusEnum_Installing
+	collectorDownloadableFile := availablePkgs.GetPackages()[packagestate.CollectorPackageName].GetFile()
+	deltaDownloadableFile := c.collectorDeltaFile(availablePkgs)
+
+	// Gather any other packages that need to be installed
+	addonFiles := make(map[string]*protobufs.DownloadableFile)
+	for pkgName, pkgStatus := range curPkgStatuses.Packages {
+		if pkgName == packagestate.CollectorPackageName {
+			continue
+		}
+		if pkgStatus.GetStatus() == protobufs.PackageStatusEnum_PackageStatusEnum_Installing {
+			addonFiles[pkgName] = availablePkgs.GetPackages()[pkgName].GetFile()
+		}
+	}
+
+	// Other packages are installed before the collector, as the collector update restarts the agent
+	if len(addonFiles) > 0 {
+		c.safeSetUpdatingPackage(true)
+		go func() {
+			c.installAddonPackages(curPkgStatuses, addonFiles, !installCollector)
+			if installCollector {
+				c.startCollectorPackageInstall(curPkgStatuses, collectorDownloadableFile, deltaDownloadableFile)
+				return
+			}
+			c.safeSetUpdatingPackage(false)
+		}()
+
+		return nil
+	}
+
 	// Start update if applicable
-	if curPkgStatuses.Packages[packagestate.CollectorPackageName].Status == protobufs.PackageStatusEnum_PackageStatusEnum_Installing {
-		collectorDownloadableFile := availablePkgs.GetPackages()[packagestate.CollectorPackageName].GetFile()
-		c.startCollectorPackageInstall(curPkgStatuses, collectorDownloadableFile)
+	if installCollector {
+		c.startCollectorPackageInstall(curPkgStatuses, collectorDownloadableFile, deltaDownloadableFile)
 	}
 
 	return nil
@@ -423,6 +547,11 @@ func (c *Client) buildInitialPackageStatus(pkgName string, availablePkg *protobu
 		initPkgStatus = c.buildInitialCollectorPackageStatus(pkgName, availablePkg, lastPkgStatus)
 	// If it's not an expected package, return a failed status
 	default:
+		if _, ok := c.packageConfig(pkgName); ok {
+			initPkgStatus = c.buildInitialAddonPackageStatus(pkgName, availablePkg, lastPkgStatus)
+			break
+		}
+
 		c.logger.Error(
 			"Package update failed because it is not supported",
 			zap.String("package", pkgName))
@@ -498,26 +627,167 @@ func (c *Client) buildInitialCollectorPackageStatus(pkgName string, availablePkg
 	return initPkgStatus
 }
 
-// startCollectorPackageInstall attempts to start updating the collector using a new tarball
-func (c *Client) startCollectorPackageInstall(curPkgStatuses *protobufs.PackageStatuses, collectorFile *protobufs.DownloadableFile) {
+// buildInitialAddonPackageStatus sets up the initial package status message for a configured non-collector package
+func (c *Client) buildInitialAddonPackageStatus(pkgName string, availablePkg *protobufs.PackageAvailable,
+	lastPkgStatus *protobufs.PackageStatus) *protobufs.PackageStatus {
+	initPkgStatus := &protobufs.PackageStatus{
+		Name:                 pkgName,
+		AgentHasVersion:      lastPkgStatus.GetAgentHasVersion(),
+		AgentHasHash:         lastPkgStatus.GetAgentHasHash(),
+		ServerOfferedVersion: availablePkg.GetVersion(),
+		ServerOfferedHash:    availablePkg.GetHash(),
+		Status:               protobufs.PackageStatusEnum_PackageStatusEnum_Installed,
+	}
+
+	// If the offered version is the version we last installed, we are already installed
+	if availablePkg.GetVersion() != "" && initPkgStatus.AgentHasVersion == availablePkg.GetVersion() {
+		c.logger.Info("Package update ignored because no new version offered",
+			zap.String("package", pkgName))
+		initPkgStatus.AgentHasHash = availablePkg.GetHash()
+
+		return initPkgStatus
+	}
+
+	// Bad install if no version is given
+	if availablePkg.GetVersion() == "" {
+		c.logger.Info("Packaged update failed because no new version detected",
+			zap.String("package", pkgName))
+		initPkgStatus.ErrorMessage = "Packaged update failed because no new version detected"
+		initPkgStatus.Status = protobufs.PackageStatusEnum_PackageStatusEnum_InstallFailed
+
+		return initPkgStatus
+	}
+
+	// Bad install if no file is given
+	if availablePkg.File == nil {
+		c.logger.Info("Packaged update failed because no downloadable file detected",
+			zap.String("package", pkgName))
+		initPkgStatus.ErrorMessage = "Packaged update failed because no downloadable file detected"
+		initPkgStatus.Status = protobufs.PackageStatusEnum_PackageStatusEnum_InstallFailed
+
+		return initPkgStatus
+	}
+
+	initPkgStatus.Status = protobufs.PackageStatusEnum_PackageStatusEnum_Installing
+
+	return initPkgStatus
+}
+
+// packageConfig returns the install config for a non-collector package, and whether the package is supported.
+// Packages configured in the manager config take precedence over the built in packages.
+func (c *Client) packageConfig(pkgName string) (opamp.PackageConfig, bool) {
+	if cfg, ok := c.currentConfig.Packages[pkgName]; ok {
+		return cfg, true
+	}
+
+	if pkgName == packagestate.JMXJarPackageName {
+		return opamp.PackageConfig{
+			Dir:      jmxJarDir,
+			FileName: jmxJarFileName,
+		}, true
+	}
+
+	return opamp.PackageConfig{}, false
+}
+
+// installAddonPackages installs each of the given non-collector packages, updating their statuses in pkgStatuses.
+// The new statuses are saved and sent to the server. If allowRestart is set, the collector is restarted
+// if any of the installed packages require it.
+func (c *Client) installAddonPackages(pkgStatuses *protobufs.PackageStatuses, files map[string]*protobufs.DownloadableFile, allowRestart bool) {
+	restartCollector := false
+	for pkgName, file := range files {
+		pkgStatus := pkgStatuses.Packages[pkgName]
+		cfg, _ := c.packageConfig(pkgName)
+
+		c.logger.Info("Package install started", zap.String("package", pkgName))
+		if err := c.downloadableFileManager.FetchAndInstallPackage(file, pkgName, cfg); err != nil {
+			c.logger.Error("Package install failed", zap.String("package", pkgName), zap.Error(err))
+			pkgStatus.Status = protobufs.PackageStatusEnum_PackageStatusEnum_InstallFailed
+			pkgStatus.ErrorMessage = fmt.Sprintf("Failed to install package: %s", err)
+			continue
+		}
+
+		c.logger.Info("Package install succeeded", zap.String("package", pkgName))
+		pkgStatus.Status = protobufs.PackageStatusEnum_PackageStatusEnum_Installed
+		pkgStatus.AgentHasVersion = pkgStatus.GetServerOfferedVersion()
+		pkgStatus.AgentHasHash = pkgStatus.GetServerOfferedHash()
+		restartCollector = restartCollector || cfg.RestartCollector
+	}
+
+	if err := c.packagesStateProvider.SetLastReportedStatuses(pkgStatuses); err != nil {
+		c.logger.Error("Failed to save last reported package statuses", zap.Error(err))
+	}
+
+	if err := c.opampClient.SetPackageStatuses(pkgStatuses); err != nil {
+		c.logger.Error("OpAMP client failed to set package statuses", zap.Error(err))
+	}
+
+	if allowRestart && restartCollector {
+		c.restartCollector()
+	}
+}
+
+// restartCollector restarts the collector so that it picks up newly installed packages
+func (c *Client) restartCollector() {
+	c.logger.Info("Restarting collector after package install")
+
+	// Stop collector monitoring as we are going to restart it
+	c.stopCollectorMonitoring()
+
+	// Setup new monitoring after collector has been restarted
+	defer c.startCollectorMonitoring(context.Background())
+
+	if err := c.collector.Restart(context.Background()); err != nil {
+		c.logger.Error("Failed to restart collector after package install", zap.Error(err))
+	}
+}
+
+// collectorDeltaFile returns the downloadable file of the collector delta package, if one is offered
+// for the same version as the collector package. Otherwise nil is returned.
+func (c *Client) collectorDeltaFile(availablePkgs *protobufs.PackagesAvailable) *protobufs.DownloadableFile {
+	deltaPkg, ok := availablePkgs.GetPackages()[packagestate.CollectorDeltaPackageName]
+	if !ok {
+		return nil
+	}
+
+	collectorVersion := availablePkgs.GetPackages()[packagestate.CollectorPackageName].GetVersion()
+	if deltaPkg.GetVersion() != collectorVersion {
+		c.logger.Info("Ignoring delta package because its version does not match the collector package",
+			zap.String("deltaVersion", deltaPkg.GetVersion()),
+			zap.String("collectorVersion", collectorVersion))
+		return nil
+	}
+
+	return deltaPkg.GetFile()
+}
+
+// startCollectorPackageInstall attempts to start updating the collector using a new tarball.
+// If deltaFile is not nil, it is tried first, falling back to the full tarball if it cannot be applied.
+func (c *Client) startCollectorPackageInstall(curPkgStatuses *protobufs.PackageStatuses, collectorFile, deltaFile *protobufs.DownloadableFile) {
 	c.logger.Info("Package update started",
 		zap.String("AllPackagesHash", hex.EncodeToString(curPkgStatuses.ServerProvidedAllPackagesHash)),
 		zap.String("package", packagestate.CollectorPackageName))
 	// Start installing from file if applicable
 	if collectorFile != nil {
 		c.safeSetUpdatingPackage(true)
-		go c.installPackageFromFile(collectorFile)
+		go c.installPackageFromFile(collectorFile, deltaFile)
 	} else {
 		c.tryToFailPackageInstall("No valid downloadable file found", true)
 	}
 }
 
 // installPackageFromFile tries to download and extract the given tarball and then start up the new
-// Updater binary that was inside of it
-func (c *Client) installPackageFromFile(file *protobufs.DownloadableFile) {
+// Updater binary that was inside of it. If a delta file is given, the delta is applied instead of
+// downloading the full tarball, unless it fails to apply.
+func (c *Client) installPackageFromFile(file, deltaFile *protobufs.DownloadableFile) {
 	// There should be no reason for us to exit this function unless there is a problem with the Updater's installation
 	defer c.safeSetUpdatingPackage(false)
 
+	if c.applyDeltaFile(deltaFile) {
+		c.startUpdater()
+		return
+	}
+
 	if fileManagerErr := c.downloadableFileManager.FetchAndExtractArchive(file); fileManagerErr != nil {
 		// Remove the update artifacts that may exist, depending on where FetchAndExtractArchive failed.
 		c.downloadableFileManager.CleanupArtifacts()
@@ -527,6 +797,27 @@ func (c *Client) installPackageFromFile(file *protobufs.DownloadableFile) {
 		return
 	}
 
+	c.startUpdater()
+}
+
+// applyDeltaFile tries to build the new artifacts by patching the current installation with the given delta file.
+// Returns true if the delta was applied successfully.
+func (c *Client) applyDeltaFile(deltaFile *protobufs.DownloadableFile) bool {
+	if deltaFile == nil {
+		return false
+	}
+
+	if err := c.downloadableFileManager.FetchAndApplyDelta(deltaFile); err != nil {
+		c.logger.Warn("Failed to apply delta package, falling back to full package", zap.Error(err))
+		return false
+	}
+
+	c.logger.Info("Applied delta package", zap.String("package", packagestate.CollectorDeltaPackageName))
+	return true
+}
+
+// startUpdater starts the Updater binary from the new artifacts
+func (c *Client) startUpdater() {
 	if monitorErr := c.updaterManager.StartAndMonitorUpdater(); monitorErr != nil {
 		// Remove the update artifacts
 		c.downloadableFileManager.CleanupArtifacts()
@@ -27,6 +27,7 @@ import (
 
 	"github.com/observiq/bindplane-agent/collector"
 	colmocks "github.com/observiq/bindplane-agent/collector/mocks"
+	"github.com/observiq/bindplane-agent/internal/detector"
 	"github.com/observiq/bindplane-agent/internal/report"
 	"github.com/observiq/bindplane-agent/internal/version"
 	"github.com/observiq/bindplane-agent/opamp"
@@ -515,6 +516,95 @@ func TestClientDisconnect(t *testing.T) {
 	mockOpAmpClient.AssertExpectations(t)
 }
 
+func TestClient_startIdentityDetection(t *testing.T) {
+	t.Run("Detected identity changed", func(t *testing.T) {
+		mockOpAmpClient := mocks.NewMockOpAMPClient(t)
+		mockOpAmpClient.On("SetAgentDescription", mock.Anything).Return(nil).Once().Run(func(args mock.Arguments) {
+			desc := args.Get(0).(*protobufs.AgentDescription)
+			lastAttr := desc.NonIdentifyingAttributes[len(desc.NonIdentifyingAttributes)-1]
+			assert.Equal(t, "team", lastAttr.GetKey())
+			assert.Equal(t, "core", lastAttr.GetValue().GetStringValue())
+		})
+
+		t.Setenv("TEST_ATTR_TEAM", "core")
+		c := &Client{
+			ident:       &identity{},
+			opampClient: mockOpAmpClient,
+			logger:      zap.NewNop(),
+			detectors: detector.New(detector.Config{
+				Detectors: []string{detector.TypeEnv},
+				EnvPrefix: "TEST_ATTR_",
+			}),
+		}
+
+		c.startIdentityDetection()
+		c.stopIdentityDetection()
+
+		// Nothing changed, so the description is not sent again
+		c.startIdentityDetection()
+		c.stopIdentityDetection()
+	})
+
+	t.Run("No detectors", func(t *testing.T) {
+		mockOpAmpClient := mocks.NewMockOpAMPClient(t)
+		c := &Client{
+			ident:       &identity{},
+			opampClient: mockOpAmpClient,
+			logger:      zap.NewNop(),
+		}
+
+		c.startIdentityDetection()
+		c.stopIdentityDetection()
+	})
+}
+
+func TestClient_onConnectHandlerRedetectsIdentity(t *testing.T) {
+	var teams []string
+	var teamsMutex sync.Mutex
+	mockOpAmpClient := mocks.NewMockOpAMPClient(t)
+	mockOpAmpClient.On("SetAgentDescription", mock.Anything).Return(nil).Twice().Run(func(args mock.Arguments) {
+		desc := args.Get(0).(*protobufs.AgentDescription)
+		lastAttr := desc.NonIdentifyingAttributes[len(desc.NonIdentifyingAttributes)-1]
+
+		teamsMutex.Lock()
+		defer teamsMutex.Unlock()
+		teams = append(teams, lastAttr.GetValue().GetStringValue())
+	})
+
+	mockStateProvider := mocks.NewMockPackagesStateProvider(t)
+	mockStateProvider.On("LastReportedStatuses").Return(&protobufs.PackageStatuses{
+		Packages: make(map[string]*protobufs.PackageStatus),
+	}, nil)
+
+	t.Setenv("TEST_ATTR_TEAM", "core")
+	c := &Client{
+		ident:                 &identity{},
+		opampClient:           mockOpAmpClient,
+		packagesStateProvider: mockStateProvider,
+		logger:                zap.NewNop(),
+		detectors: detector.New(detector.Config{
+			Detectors: []string{detector.TypeEnv},
+			EnvPrefix: "TEST_ATTR_",
+		}),
+	}
+
+	c.startIdentityDetection()
+	c.detectWg.Wait()
+
+	// The attribute changes while disconnected, so reconnecting sends the new description
+	t.Setenv("TEST_ATTR_TEAM", "platform")
+	c.onConnectHandler()
+
+	require.Eventually(t, func() bool {
+		teamsMutex.Lock()
+		defer teamsMutex.Unlock()
+		return len(teams) == 2
+	}, 2*time.Second, 10*time.Millisecond)
+	c.stopIdentityDetection()
+
+	require.Equal(t, []string{"core", "platform"}, teams)
+}
+
 func TestClient_onConnectHandler(t *testing.T) {
 	testCases := []struct {
 		desc     string
@@ -1329,6 +1419,349 @@ func TestClient_onPackagesAvailableHandler(t *testing.T) {
 				assert.False(t, c.safeGetUpdatingPackage())
 			},
 		},
+		{
+			desc: "New PackagesAvailable version with delta package",
+			testFunc: func(t *testing.T) {
+				packagesNew := map[string]*protobufs.PackageAvailable{
+					collectorPackageName: {
+						Version: newVersion,
+						Hash:    newPackageHash,
+						File:    &protobufs.DownloadableFile{},
+					},
+					packagestate.CollectorDeltaPackageName: {
+						Version: newVersion,
+						Hash:    newPackageHash,
+						File:    &protobufs.DownloadableFile{DownloadUrl: "http://example.com/delta.tar.gz"},
+					},
+				}
+				packagesAvailableNew := &protobufs.PackagesAvailable{
+					AllPackagesHash: newAllHash,
+					Packages:        packagesNew,
+				}
+				savedStatuses := map[string]*protobufs.PackageStatus{
+					collectorPackageName: {
+						Name:                 collectorPackageName,
+						AgentHasVersion:      version.Version(),
+						AgentHasHash:         packageHash,
+						ServerOfferedVersion: newVersion,
+						ServerOfferedHash:    newPackageHash,
+						Status:               protobufs.PackageStatusEnum_PackageStatusEnum_Installing,
+					},
+				}
+				savedPackageStatuses := &protobufs.PackageStatuses{
+					ServerProvidedAllPackagesHash: newAllHash,
+					Packages:                      savedStatuses,
+				}
+				wg := sync.WaitGroup{}
+				wg.Add(2)
+				mockUpdaterManager := mocks.NewMockUpdaterManager(t)
+				mockUpdaterManager.On("StartAndMonitorUpdater").Return(expectedErr)
+				mockProvider := mocks.NewMockPackagesStateProvider(t)
+				mockProvider.On("LastReportedStatuses").Return(packageStatuses, nil).Once()
+				mockProvider.On("LastReportedStatuses").Return(savedPackageStatuses, nil)
+				mockProvider.On("SetLastReportedStatuses", mock.Anything).Return(nil)
+				mockFileManager := mocks.NewMockDownloadableFileManager(t)
+				mockFileManager.On("FetchAndApplyDelta", packagesNew[packagestate.CollectorDeltaPackageName].File).Return(nil).Run(func(args mock.Arguments) {
+					wg.Done()
+				})
+				mockFileManager.On("CleanupArtifacts").Return().Times(1)
+
+				mockOpAmpClient := mocks.NewMockOpAMPClient(t)
+				mockOpAmpClient.On("SetPackageStatuses", mock.Anything).Return(nil).Once().Run(func(args mock.Arguments) {
+					status := args.Get(0).(*protobufs.PackageStatuses)
+
+					assert.NotNil(t, status)
+					assert.Equal(t, "", status.ErrorMessage)
+					assert.Equal(t, packagesAvailableNew.AllPackagesHash, status.ServerProvidedAllPackagesHash)
+					assert.Equal(t, 1, len(status.Packages))
+					assert.NotContains(t, status.Packages, packagestate.CollectorDeltaPackageName)
+					assert.Equal(t, packagesAvailableNew.Packages[collectorPackageName].Version, status.Packages[collectorPackageName].ServerOfferedVersion)
+					assert.Equal(t, packagesAvailableNew.Packages[collectorPackageName].Hash, status.Packages[collectorPackageName].ServerOfferedHash)
+					assert.Equal(t, "", status.Packages[collectorPackageName].ErrorMessage)
+					assert.Equal(t, protobufs.PackageStatusEnum_PackageStatusEnum_Installing, status.Packages[collectorPackageName].Status)
+					assert.Equal(t, collectorPackageName, status.Packages[collectorPackageName].Name)
+					assert.Equal(t, packageStatuses.Packages[collectorPackageName].AgentHasHash, status.Packages[collectorPackageName].AgentHasHash)
+					assert.Equal(t, packageStatuses.Packages[collectorPackageName].AgentHasVersion, status.Packages[collectorPackageName].AgentHasVersion)
+				})
+				mockOpAmpClient.On("SetPackageStatuses", mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+					status := args.Get(0).(*protobufs.PackageStatuses)
+
+					assert.NotNil(t, status)
+					assert.Equal(t, "", status.ErrorMessage)
+					assert.Equal(t, packagesAvailableNew.AllPackagesHash, status.ServerProvidedAllPackagesHash)
+					assert.Equal(t, 1, len(status.Packages))
+					assert.Equal(t, packagesAvailableNew.Packages[collectorPackageName].Version, status.Packages[collectorPackageName].ServerOfferedVersion)
+					assert.Equal(t, packagesAvailableNew.Packages[collectorPackageName].Hash, status.Packages[collectorPackageName].ServerOfferedHash)
+					assert.Equal(t, "Failed to run the latest Updater: oops", status.Packages[collectorPackageName].ErrorMessage)
+					assert.Equal(t, protobufs.PackageStatusEnum_PackageStatusEnum_InstallFailed, status.Packages[collectorPackageName].Status)
+					assert.Equal(t, collectorPackageName, status.Packages[collectorPackageName].Name)
+					assert.Equal(t, packageStatuses.Packages[collectorPackageName].AgentHasHash, status.Packages[collectorPackageName].AgentHasHash)
+					assert.Equal(t, packageStatuses.Packages[collectorPackageName].AgentHasVersion, status.Packages[collectorPackageName].AgentHasVersion)
+					wg.Done()
+				})
+
+				c := &Client{
+					packagesStateProvider:   mockProvider,
+					downloadableFileManager: mockFileManager,
+					opampClient:             mockOpAmpClient,
+					logger:                  zap.NewNop(),
+					updaterManager:          mockUpdaterManager,
+				}
+
+				err := c.onPackagesAvailableHandler(packagesAvailableNew)
+				assert.NoError(t, err)
+				wg.Wait()
+				assert.False(t, c.safeGetUpdatingPackage())
+			},
+		},
+		{
+			desc: "New PackagesAvailable version with delta package that fails to apply",
+			testFunc: func(t *testing.T) {
+				packagesNew := map[string]*protobufs.PackageAvailable{
+					collectorPackageName: {
+						Version: newVersion,
+						Hash:    newPackageHash,
+						File:    &protobufs.DownloadableFile{},
+					},
+					packagestate.CollectorDeltaPackageName: {
+						Version: newVersion,
+						Hash:    newPackageHash,
+						File:    &protobufs.DownloadableFile{DownloadUrl: "http://example.com/delta.tar.gz"},
+					},
+				}
+				packagesAvailableNew := &protobufs.PackagesAvailable{
+					AllPackagesHash: newAllHash,
+					Packages:        packagesNew,
+				}
+				savedStatuses := map[string]*protobufs.PackageStatus{
+					collectorPackageName: {
+						Name:                 collectorPackageName,
+						AgentHasVersion:      version.Version(),
+						AgentHasHash:         packageHash,
+						ServerOfferedVersion: newVersion,
+						ServerOfferedHash:    newPackageHash,
+						Status:               protobufs.PackageStatusEnum_PackageStatusEnum_Installing,
+					},
+				}
+				savedPackageStatuses := &protobufs.PackageStatuses{
+					ServerProvidedAllPackagesHash: newAllHash,
+					Packages:                      savedStatuses,
+				}
+				wg := sync.WaitGroup{}
+				wg.Add(2)
+				mockUpdaterManager := mocks.NewMockUpdaterManager(t)
+				mockUpdaterManager.On("StartAndMonitorUpdater").Return(expectedErr)
+				mockProvider := mocks.NewMockPackagesStateProvider(t)
+				mockProvider.On("LastReportedStatuses").Return(packageStatuses, nil).Once()
+				mockProvider.On("LastReportedStatuses").Return(savedPackageStatuses, nil)
+				mockProvider.On("SetLastReportedStatuses", mock.Anything).Return(nil)
+				mockFileManager := mocks.NewMockDownloadableFileManager(t)
+				mockFileManager.On("FetchAndApplyDelta", packagesNew[packagestate.CollectorDeltaPackageName].File).Return(errors.New("patch failed"))
+				mockFileManager.On("FetchAndExtractArchive", packagesNew[collectorPackageName].File).Return(nil).Run(func(args mock.Arguments) {
+					wg.Done()
+				})
+				mockFileManager.On("CleanupArtifacts").Return().Times(1)
+
+				mockOpAmpClient := mocks.NewMockOpAMPClient(t)
+				mockOpAmpClient.On("SetPackageStatuses", mock.Anything).Return(nil).Once().Run(func(args mock.Arguments) {
+					status := args.Get(0).(*protobufs.PackageStatuses)
+
+					assert.NotNil(t, status)
+					assert.Equal(t, "", status.ErrorMessage)
+					assert.Equal(t, packagesAvailableNew.AllPackagesHash, status.ServerProvidedAllPackagesHash)
+					assert.Equal(t, 1, len(status.Packages))
+					assert.NotContains(t, status.Packages, packagestate.CollectorDeltaPackageName)
+					assert.Equal(t, packagesAvailableNew.Packages[collectorPackageName].Version, status.Packages[collectorPackageName].ServerOfferedVersion)
+					assert.Equal(t, packagesAvailableNew.Packages[collectorPackageName].Hash, status.Packages[collectorPackageName].ServerOfferedHash)
+					assert.Equal(t, "", status.Packages[collectorPackageName].ErrorMessage)
+					assert.Equal(t, protobufs.PackageStatusEnum_PackageStatusEnum_Installing, status.Packages[collectorPackageName].Status)
+					assert.Equal(t, collectorPackageName, status.Packages[collectorPackageName].Name)
+					assert.Equal(t, packageStatuses.Packages[collectorPackageName].AgentHasHash, status.Packages[collectorPackageName].AgentHasHash)
+					assert.Equal(t, packageStatuses.Packages[collectorPackageName].AgentHasVersion, status.Packages[collectorPackageName].AgentHasVersion)
+				})
+				mockOpAmpClient.On("SetPackageStatuses", mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+					status := args.Get(0).(*protobufs.PackageStatuses)
+
+					assert.NotNil(t, status)
+					assert.Equal(t, "", status.ErrorMessage)
+					assert.Equal(t, packagesAvailableNew.AllPackagesHash, status.ServerProvidedAllPackagesHash)
+					assert.Equal(t, 1, len(status.Packages))
+					assert.Equal(t, packagesAvailableNew.Packages[collectorPackageName].Version, status.Packages[collectorPackageName].ServerOfferedVersion)
+					assert.Equal(t, packagesAvailableNew.Packages[collectorPackageName].Hash, status.Packages[collectorPackageName].ServerOfferedHash)
+					assert.Equal(t, "Failed to run the latest Updater: oops", status.Packages[collectorPackageName].ErrorMessage)
+					assert.Equal(t, protobufs.PackageStatusEnum_PackageStatusEnum_InstallFailed, status.Packages[collectorPackageName].Status)
+					assert.Equal(t, collectorPackageName, status.Packages[collectorPackageName].Name)
+					assert.Equal(t, packageStatuses.Packages[collectorPackageName].AgentHasHash, status.Packages[collectorPackageName].AgentHasHash)
+					assert.Equal(t, packageStatuses.Packages[collectorPackageName].AgentHasVersion, status.Packages[collectorPackageName].AgentHasVersion)
+					wg.Done()
+				})
+
+				c := &Client{
+					packagesStateProvider:   mockProvider,
+					downloadableFileManager: mockFileManager,
+					opampClient:             mockOpAmpClient,
+					logger:                  zap.NewNop(),
+					updaterManager:          mockUpdaterManager,
+				}
+
+				err := c.onPackagesAvailableHandler(packagesAvailableNew)
+				assert.NoError(t, err)
+				wg.Wait()
+				assert.False(t, c.safeGetUpdatingPackage())
+			},
+		},
+		{
+			desc: "New PackagesAvailable version of configured package",
+			testFunc: func(t *testing.T) {
+				pluginPackageName := "my-plugin"
+				pluginConfig := opamp.PackageConfig{
+					Dir:              "plugins",
+					RestartCollector: true,
+				}
+				packagesNew := map[string]*protobufs.PackageAvailable{
+					collectorPackageName: {
+						Version: version.Version(),
+						Hash:    packageHash,
+						File:    &protobufs.DownloadableFile{},
+					},
+					pluginPackageName: {
+						Version: newVersion,
+						Hash:    newPackageHash,
+						File:    &protobufs.DownloadableFile{DownloadUrl: "http://example.com/plugin.tar.gz"},
+					},
+				}
+				packagesAvailableNew := &protobufs.PackagesAvailable{
+					AllPackagesHash: newAllHash,
+					Packages:        packagesNew,
+				}
+
+				wg := sync.WaitGroup{}
+				wg.Add(2)
+				mockCollector := colmocks.NewMockCollector(t)
+				statusChannel := make(chan *collector.Status)
+				mockCollector.On("Status").Return((<-chan *collector.Status)(statusChannel))
+				mockCollector.On("Restart", mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+					wg.Done()
+				})
+				mockProvider := mocks.NewMockPackagesStateProvider(t)
+				mockProvider.On("LastReportedStatuses").Return(packageStatuses, nil)
+				mockProvider.On("SetLastReportedStatuses", mock.Anything).Return(nil)
+				mockFileManager := mocks.NewMockDownloadableFileManager(t)
+				mockFileManager.On("FetchAndInstallPackage", packagesNew[pluginPackageName].File, pluginPackageName, pluginConfig).Return(nil)
+
+				mockOpAmpClient := mocks.NewMockOpAMPClient(t)
+				mockOpAmpClient.On("SetPackageStatuses", mock.Anything).Return(nil).Once().Run(func(args mock.Arguments) {
+					status := args.Get(0).(*protobufs.PackageStatuses)
+
+					assert.NotNil(t, status)
+					assert.Equal(t, 2, len(status.Packages))
+					assert.Equal(t, protobufs.PackageStatusEnum_PackageStatusEnum_Installed, status.Packages[collectorPackageName].Status)
+					assert.Equal(t, protobufs.PackageStatusEnum_PackageStatusEnum_Installing, status.Packages[pluginPackageName].Status)
+					assert.Equal(t, "", status.Packages[pluginPackageName].AgentHasVersion)
+				})
+				mockOpAmpClient.On("SetPackageStatuses", mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+					status := args.Get(0).(*protobufs.PackageStatuses)
+
+					assert.NotNil(t, status)
+					assert.Equal(t, 2, len(status.Packages))
+					assert.Equal(t, protobufs.PackageStatusEnum_PackageStatusEnum_Installed, status.Packages[pluginPackageName].Status)
+					assert.Equal(t, "", status.Packages[pluginPackageName].ErrorMessage)
+					assert.Equal(t, newVersion, status.Packages[pluginPackageName].AgentHasVersion)
+					assert.Equal(t, newPackageHash, status.Packages[pluginPackageName].AgentHasHash)
+					wg.Done()
+				})
+
+				c := &Client{
+					packagesStateProvider:   mockProvider,
+					downloadableFileManager: mockFileManager,
+					opampClient:             mockOpAmpClient,
+					collector:               mockCollector,
+					logger:                  zap.NewNop(),
+					currentConfig: opamp.Config{
+						Packages: map[string]opamp.PackageConfig{
+							pluginPackageName: pluginConfig,
+						},
+					},
+				}
+
+				// Setup Context to mock out already running collector monitor
+				c.collectorMntrCtx, c.collectorMntrCancel = context.WithCancel(context.Background())
+
+				err := c.onPackagesAvailableHandler(packagesAvailableNew)
+				assert.NoError(t, err)
+				wg.Wait()
+				assert.Eventually(t, func() bool {
+					return !c.safeGetUpdatingPackage()
+				}, 2*time.Second, 100*time.Millisecond)
+
+				// Cleanup
+				c.stopCollectorMonitoring()
+			},
+		},
+		{
+			desc: "New PackagesAvailable version of configured package that fails to install",
+			testFunc: func(t *testing.T) {
+				pluginPackageName := "my-plugin"
+				pluginConfig := opamp.PackageConfig{
+					Dir: "plugins",
+				}
+				packagesNew := map[string]*protobufs.PackageAvailable{
+					collectorPackageName: {
+						Version: version.Version(),
+						Hash:    packageHash,
+						File:    &protobufs.DownloadableFile{},
+					},
+					pluginPackageName: {
+						Version: newVersion,
+						Hash:    newPackageHash,
+						File:    &protobufs.DownloadableFile{DownloadUrl: "http://example.com/plugin.tar.gz"},
+					},
+				}
+				packagesAvailableNew := &protobufs.PackagesAvailable{
+					AllPackagesHash: newAllHash,
+					Packages:        packagesNew,
+				}
+
+				wg := sync.WaitGroup{}
+				wg.Add(1)
+				mockProvider := mocks.NewMockPackagesStateProvider(t)
+				mockProvider.On("LastReportedStatuses").Return(packageStatuses, nil)
+				mockProvider.On("SetLastReportedStatuses", mock.Anything).Return(nil)
+				mockFileManager := mocks.NewMockDownloadableFileManager(t)
+				mockFileManager.On("FetchAndInstallPackage", packagesNew[pluginPackageName].File, pluginPackageName, pluginConfig).Return(expectedErr)
+
+				mockOpAmpClient := mocks.NewMockOpAMPClient(t)
+				mockOpAmpClient.On("SetPackageStatuses", mock.Anything).Return(nil).Once()
+				mockOpAmpClient.On("SetPackageStatuses", mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+					status := args.Get(0).(*protobufs.PackageStatuses)
+
+					assert.NotNil(t, status)
+					assert.Equal(t, protobufs.PackageStatusEnum_PackageStatusEnum_InstallFailed, status.Packages[pluginPackageName].Status)
+					assert.Equal(t, "Failed to install package: oops", status.Packages[pluginPackageName].ErrorMessage)
+					assert.Equal(t, "", status.Packages[pluginPackageName].AgentHasVersion)
+					wg.Done()
+				})
+
+				c := &Client{
+					packagesStateProvider:   mockProvider,
+					downloadableFileManager: mockFileManager,
+					opampClient:             mockOpAmpClient,
+					logger:                  zap.NewNop(),
+					currentConfig: opamp.Config{
+						Packages: map[string]opamp.PackageConfig{
+							pluginPackageName: pluginConfig,
+						},
+					},
+				}
+
+				err := c.onPackagesAvailableHandler(packagesAvailableNew)
+				assert.NoError(t, err)
+				wg.Wait()
+				assert.Eventually(t, func() bool {
+					return !c.safeGetUpdatingPackage()
+				}, 2*time.Second, 100*time.Millisecond)
+			},
+		},
 		{
 			desc: "New PackagesAvailable version while already installing",
 			testFunc: func(t *testing.T) {
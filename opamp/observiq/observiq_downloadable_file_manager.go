@@ -20,33 +20,41 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"net/http"
 	"net/url"
 	"os"
 	"path/filepath"
 
 	archiver "github.com/mholt/archiver/v3"
+	"github.com/observiq/bindplane-agent/internal/delta"
+	"github.com/observiq/bindplane-agent/internal/version"
 	"github.com/observiq/bindplane-agent/opamp"
 	"github.com/open-telemetry/opamp-go/protobufs"
 	"go.uber.org/zap"
 )
 
 const extractFolder = "latest"
+const deltaFolder = "delta"
+const packagesFolder = "packages"
 
 // Ensure interface is satisfied
 var _ opamp.DownloadableFileManager = (*DownloadableFileManager)(nil)
 
 // DownloadableFileManager handles DownloadableFile's from a PackagesAvailable message
 type DownloadableFileManager struct {
-	tmpPath string
-	logger  *zap.Logger
+	tmpPath    string
+	installDir string
+	logger     *zap.Logger
 }
 
 // newDownloadableFileManager creates a new OpAmp DownloadableFileManager
 func newDownloadableFileManager(logger *zap.Logger, tmpPath string) *DownloadableFileManager {
+	cleanTmpPath := filepath.Clean(tmpPath)
 	return &DownloadableFileManager{
-		tmpPath: filepath.Clean(tmpPath),
-		logger:  logger,
+		tmpPath:    cleanTmpPath,
+		installDir: filepath.Dir(cleanTmpPath),
+		logger:     logger,
 	}
 }
 
@@ -55,21 +63,13 @@ func newDownloadableFileManager(logger *zap.Logger, tmpPath string) *Downloadabl
 // If it matches, the archive is extracted into the $dir/latest directory.
 // If the archive cannot be extracted, downloaded, or verified, then an error is returned.
 func (m DownloadableFileManager) FetchAndExtractArchive(file *protobufs.DownloadableFile) error {
-	archiveFilePath, err := getOutputFilePath(m.tmpPath, file.GetDownloadUrl())
+	archiveFilePath, err := m.fetchAndVerify(file, m.tmpPath)
 	if err != nil {
-		return fmt.Errorf("failed to determine archive download path: %w", err)
-	}
-
-	if err := m.downloadFile(file.GetDownloadUrl(), archiveFilePath); err != nil {
-		return fmt.Errorf("failed to download file: %w", err)
+		return err
 	}
 
 	extractPath := filepath.Join(m.tmpPath, extractFolder)
 
-	if err := m.verifyContentHash(archiveFilePath, file.GetContentHash()); err != nil {
-		return fmt.Errorf("content hash could not be verified: %w", err)
-	}
-
 	// Clean the "latest" dir before extraction
 	if err := os.RemoveAll(extractPath); err != nil {
 		return fmt.Errorf("error cleaning archive extraction target path: %w", err)
@@ -82,6 +82,242 @@ func (m DownloadableFileManager) FetchAndExtractArchive(file *protobufs.Download
 	return nil
 }
 
+// FetchAndApplyDelta fetches the delta archive at the specified URL and verifies its content hash.
+// The archive is extracted into the $dir/delta directory, and the patches described by its manifest are
+// applied against the current installation, building the new artifacts into the $dir/latest directory.
+// The hash of every resulting file is verified against the manifest.
+// If the delta cannot be downloaded, verified, or applied, then an error is returned and $dir/latest is removed.
+func (m DownloadableFileManager) FetchAndApplyDelta(file *protobufs.DownloadableFile) error {
+	archiveFilePath, err := m.fetchAndVerify(file, m.tmpPath)
+	if err != nil {
+		return err
+	}
+
+	deltaPath := filepath.Join(m.tmpPath, deltaFolder)
+
+	// Clean the "delta" dir before extraction
+	if err := os.RemoveAll(deltaPath); err != nil {
+		return fmt.Errorf("error cleaning delta extraction target path: %w", err)
+	}
+
+	// The extracted delta is only needed until the new artifacts are built
+	defer func() {
+		if err := os.RemoveAll(deltaPath); err != nil {
+			m.logger.Warn("Failed to remove delta directory", zap.Error(err))
+		}
+	}()
+
+	if err := archiver.Unarchive(archiveFilePath, deltaPath); err != nil {
+		return fmt.Errorf("failed to extract file: %w", err)
+	}
+
+	manifest, err := delta.LoadManifest(deltaPath)
+	if err != nil {
+		return fmt.Errorf("failed to load delta manifest: %w", err)
+	}
+
+	if manifest.BaseVersion != version.Version() {
+		return fmt.Errorf("delta is for base version %s, but current version is %s", manifest.BaseVersion, version.Version())
+	}
+
+	extractPath := filepath.Join(m.tmpPath, extractFolder)
+
+	// Clean the "latest" dir before building the new artifacts
+	if err := os.RemoveAll(extractPath); err != nil {
+		return fmt.Errorf("error cleaning delta target path: %w", err)
+	}
+
+	if err := manifest.Apply(m.installDir, deltaPath, extractPath); err != nil {
+		// Don't leave partially patched artifacts around for the full archive to be extracted over
+		if removeErr := os.RemoveAll(extractPath); removeErr != nil {
+			m.logger.Warn("Failed to remove partially applied delta", zap.Error(removeErr))
+		}
+		return fmt.Errorf("failed to apply delta: %w", err)
+	}
+
+	return nil
+}
+
+// FetchAndInstallPackage fetches the file at the specified URL for the named package, and verifies its content hash.
+// If the file is an archive, its contents are installed into cfg.Dir. Otherwise, the file itself is installed into cfg.Dir,
+// named cfg.FileName if it is set. A relative cfg.Dir is relative to the install directory. Files in cfg.Dir that are overwritten are backed up first, and are restored if the install fails.
+func (m DownloadableFileManager) FetchAndInstallPackage(file *protobufs.DownloadableFile, name string, cfg opamp.PackageConfig) error {
+	pkgPath := filepath.Join(m.tmpPath, packagesFolder, name)
+
+	// Clean up any previous attempt to install this package
+	if err := os.RemoveAll(pkgPath); err != nil {
+		return fmt.Errorf("error cleaning package path: %w", err)
+	}
+
+	defer func() {
+		if err := os.RemoveAll(pkgPath); err != nil {
+			m.logger.Warn("Failed to remove package directory", zap.String("package", name), zap.Error(err))
+		}
+	}()
+
+	filePath, err := m.fetchAndVerify(file, pkgPath)
+	if err != nil {
+		return err
+	}
+
+	stagePath := filepath.Join(pkgPath, "staged")
+	if err := stagePackageFile(filePath, stagePath, cfg.FileName); err != nil {
+		return fmt.Errorf("failed to stage package: %w", err)
+	}
+
+	installPath := cfg.Dir
+	if !filepath.IsAbs(installPath) {
+		installPath = filepath.Join(m.installDir, installPath)
+	}
+
+	if err := m.installStagedFiles(stagePath, installPath, filepath.Join(pkgPath, "backup")); err != nil {
+		return fmt.Errorf("failed to install package: %w", err)
+	}
+
+	return nil
+}
+
+// stagePackageFile extracts the file into stagePath if it is an archive, otherwise it copies the file into stagePath.
+// Non-archive files are named fileName, or keep their own name if fileName is empty.
+func stagePackageFile(filePath, stagePath, fileName string) error {
+	if format, err := archiver.ByExtension(filePath); err == nil {
+		if _, ok := format.(archiver.Unarchiver); ok {
+			if err := archiver.Unarchive(filePath, stagePath); err != nil {
+				return fmt.Errorf("failed to extract file: %w", err)
+			}
+			return nil
+		}
+	}
+
+	if err := os.MkdirAll(stagePath, 0750); err != nil {
+		return fmt.Errorf("failed to create staging dir: %w", err)
+	}
+
+	if fileName == "" {
+		fileName = filepath.Base(filePath)
+	}
+
+	return copyPackageFile(filePath, filepath.Join(stagePath, filepath.Base(fileName)), 0600)
+}
+
+// installStagedFiles copies every file in stagePath into dir, backing up overwritten files to backupPath.
+// If any file fails to install, the files installed so far are restored from backupPath.
+func (m DownloadableFileManager) installStagedFiles(stagePath, dir, backupPath string) error {
+	// installed maps each installed file to whether it has a backup
+	installed := map[string]bool{}
+
+	err := filepath.WalkDir(stagePath, func(inPath string, d fs.DirEntry, err error) error {
+		switch {
+		case err != nil:
+			return err
+		case d.IsDir():
+			return nil
+		}
+
+		relPath, err := filepath.Rel(stagePath, inPath)
+		if err != nil {
+			return err
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		outPath := filepath.Join(dir, relPath)
+		_, statErr := os.Stat(outPath)
+		switch {
+		case statErr == nil:
+			if err := copyPackageFile(outPath, filepath.Join(backupPath, relPath), 0600); err != nil {
+				return fmt.Errorf("failed to back up %s: %w", relPath, err)
+			}
+			installed[relPath] = true
+		case errors.Is(statErr, os.ErrNotExist):
+			installed[relPath] = false
+		default:
+			return fmt.Errorf("failed to stat %s: %w", relPath, statErr)
+		}
+
+		return copyPackageFile(inPath, outPath, info.Mode().Perm())
+	})
+
+	if err != nil {
+		m.restoreInstalledFiles(installed, dir, backupPath)
+		return err
+	}
+
+	return nil
+}
+
+// restoreInstalledFiles undoes installStagedFiles, restoring backed up files and removing new ones.
+func (m DownloadableFileManager) restoreInstalledFiles(installed map[string]bool, dir, backupPath string) {
+	for relPath, hasBackup := range installed {
+		outPath := filepath.Join(dir, relPath)
+
+		var err error
+		if hasBackup {
+			err = copyPackageFile(filepath.Join(backupPath, relPath), outPath, 0600)
+		} else {
+			err = os.Remove(outPath)
+		}
+
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			m.logger.Error("Failed to restore file after failed package install", zap.String("file", outPath), zap.Error(err))
+		}
+	}
+}
+
+// copyPackageFile copies the file at inPath to outPath, creating any parent directories of outPath.
+// If outPath does not exist, it is created with the given mode.
+func copyPackageFile(inPath, outPath string, mode os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(outPath), 0750); err != nil {
+		return fmt.Errorf("failed to create dir: %w", err)
+	}
+
+	in, err := os.Open(filepath.Clean(inPath))
+	if err != nil {
+		return fmt.Errorf("failed to open input file: %w", err)
+	}
+	defer func() {
+		_ = in.Close()
+	}()
+
+	out, err := os.OpenFile(filepath.Clean(outPath), os.O_CREATE|os.O_TRUNC|os.O_WRONLY, mode)
+	if err != nil {
+		return fmt.Errorf("failed to open output file: %w", err)
+	}
+
+	if _, err := io.Copy(out, in); err != nil {
+		_ = out.Close()
+		return fmt.Errorf("failed to copy file: %w", err)
+	}
+
+	if err := out.Close(); err != nil {
+		return fmt.Errorf("failed to close output file: %w", err)
+	}
+
+	return nil
+}
+
+// fetchAndVerify downloads the file into dir and verifies its content hash.
+// Returns the path of the downloaded file.
+func (m DownloadableFileManager) fetchAndVerify(file *protobufs.DownloadableFile, dir string) (string, error) {
+	archiveFilePath, err := getOutputFilePath(dir, file.GetDownloadUrl())
+	if err != nil {
+		return "", fmt.Errorf("failed to determine archive download path: %w", err)
+	}
+
+	if err := m.downloadFile(file.GetDownloadUrl(), archiveFilePath); err != nil {
+		return "", fmt.Errorf("failed to download file: %w", err)
+	}
+
+	if err := m.verifyContentHash(archiveFilePath, file.GetContentHash()); err != nil {
+		return "", fmt.Errorf("content hash could not be verified: %w", err)
+	}
+
+	return archiveFilePath, nil
+}
+
 // Downloads the file into the outPath, truncating the file if it already exists
 func (m DownloadableFileManager) downloadFile(downloadURL string, outPath string) error {
 	//#nosec G107 HTTP request must be dynamic based on input
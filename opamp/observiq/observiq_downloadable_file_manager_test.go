@@ -16,7 +16,9 @@ package observiq
 
 import (
 	"bytes"
+	"crypto/sha256"
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -25,6 +27,10 @@ import (
 	"runtime"
 	"testing"
 
+	archiver "github.com/mholt/archiver/v3"
+	"github.com/observiq/bindplane-agent/internal/delta"
+	"github.com/observiq/bindplane-agent/internal/version"
+	"github.com/observiq/bindplane-agent/opamp"
 	"github.com/open-telemetry/opamp-go/protobufs"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -320,6 +326,226 @@ func TestDownloadAndVerifyInvalidURL(t *testing.T) {
 	require.ErrorContains(t, err, "failed to determine archive download path:")
 }
 
+func TestFetchAndApplyDelta(t *testing.T) {
+	baseCollector := []byte("the old collector binary, with plenty of bytes shared with the new collector binary")
+	newCollector := []byte("the new collector binary, with plenty of bytes shared with the new collector binary")
+
+	testCases := []struct {
+		name         string
+		baseVersion  string
+		installed    []byte
+		expectedErr  string
+		expectLatest bool
+	}{
+		{
+			name:         "Applies delta to installed files",
+			baseVersion:  version.Version(),
+			installed:    baseCollector,
+			expectLatest: true,
+		},
+		{
+			name:        "Delta is for a different version",
+			baseVersion: "v0.0.1",
+			installed:   baseCollector,
+			expectedErr: "delta is for base version v0.0.1",
+		},
+		{
+			name:        "Installed file does not match base",
+			baseVersion: version.Version(),
+			installed:   []byte("modified collector"),
+			expectedErr: "failed to apply delta",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			installDir := t.TempDir()
+			tmpDir := filepath.Join(installDir, "tmp")
+			require.NoError(t, os.WriteFile(filepath.Join(installDir, "collector"), tc.installed, 0600))
+
+			archivePath := writeDeltaArchive(t, delta.Manifest{
+				BaseVersion:   tc.baseVersion,
+				TargetVersion: "v9.9.9",
+				Files: []delta.ManifestFile{
+					{
+						Path:       "collector",
+						Action:     delta.ActionPatch,
+						Source:     "patches/collector",
+						BaseSHA256: sha256Hex(baseCollector),
+						SHA256:     sha256Hex(newCollector),
+					},
+				},
+			}, delta.Diff(baseCollector, newCollector))
+
+			archiveBytes, err := os.ReadFile(archivePath)
+			require.NoError(t, err)
+			archiveHash := sha256.Sum256(archiveBytes)
+
+			s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Write(archiveBytes)
+			}))
+			defer s.Close()
+
+			file := &protobufs.DownloadableFile{
+				DownloadUrl: fmt.Sprintf("%s/%s", s.URL, "delta.tar.gz"),
+				ContentHash: archiveHash[:],
+			}
+
+			downloadableFileManager := newDownloadableFileManager(zap.NewNop(), tmpDir)
+			err = downloadableFileManager.FetchAndApplyDelta(file)
+			if tc.expectedErr != "" {
+				require.ErrorContains(t, err, tc.expectedErr)
+			} else {
+				require.NoError(t, err)
+			}
+
+			if tc.expectLatest {
+				actualBytes, err := os.ReadFile(filepath.Join(tmpDir, extractFolder, "collector"))
+				require.NoError(t, err)
+				require.Equal(t, newCollector, actualBytes)
+			} else {
+				require.NoDirExists(t, filepath.Join(tmpDir, extractFolder))
+			}
+
+			require.NoDirExists(t, filepath.Join(tmpDir, deltaFolder))
+		})
+	}
+}
+
+// writeDeltaArchive creates a delta archive containing the manifest and a single patch for "collector"
+func writeDeltaArchive(t *testing.T, manifest delta.Manifest, patch []byte) string {
+	srcDir := t.TempDir()
+
+	manifestBytes, err := json.Marshal(manifest)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, delta.ManifestFileName), manifestBytes, 0600))
+	require.NoError(t, os.MkdirAll(filepath.Join(srcDir, "patches"), 0750))
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, "patches", "collector"), patch, 0600))
+
+	archivePath := filepath.Join(t.TempDir(), "delta.tar.gz")
+	err = archiver.Archive([]string{
+		filepath.Join(srcDir, delta.ManifestFileName),
+		filepath.Join(srcDir, "patches"),
+	}, archivePath)
+	require.NoError(t, err)
+
+	return archivePath
+}
+
+func sha256Hex(data []byte) string {
+	h := sha256.Sum256(data)
+	return hex.EncodeToString(h[:])
+}
+
+func TestFetchAndInstallPackage(t *testing.T) {
+	pluginContents := []byte("plugin contents")
+
+	testCases := []struct {
+		name         string
+		fileName     string
+		contents     func(t *testing.T) []byte
+		cfgFileName  string
+		existing     []byte
+		expectedPath string
+		badHash      bool
+		expectedErr  string
+	}{
+		{
+			name:     "Installs plain file",
+			fileName: "plugin.yaml",
+			contents: func(_ *testing.T) []byte {
+				return pluginContents
+			},
+			expectedPath: "plugin.yaml",
+		},
+		{
+			name:     "Installs plain file with configured name",
+			fileName: "plugin-v1.2.3.jar",
+			contents: func(_ *testing.T) []byte {
+				return pluginContents
+			},
+			cfgFileName:  "plugin.jar",
+			expectedPath: "plugin.jar",
+		},
+		{
+			name:     "Installs archive contents",
+			fileName: "plugins.tar.gz",
+			contents: func(t *testing.T) []byte {
+				srcDir := t.TempDir()
+				require.NoError(t, os.MkdirAll(filepath.Join(srcDir, "plugins"), 0750))
+				require.NoError(t, os.WriteFile(filepath.Join(srcDir, "plugins", "plugin.yaml"), pluginContents, 0600))
+
+				archivePath := filepath.Join(t.TempDir(), "plugins.tar.gz")
+				require.NoError(t, archiver.Archive([]string{filepath.Join(srcDir, "plugins")}, archivePath))
+
+				archiveBytes, err := os.ReadFile(archivePath)
+				require.NoError(t, err)
+				return archiveBytes
+			},
+			existing:     []byte("old plugin contents"),
+			expectedPath: filepath.Join("plugins", "plugin.yaml"),
+		},
+		{
+			name:     "Bad content hash",
+			fileName: "plugin.yaml",
+			contents: func(_ *testing.T) []byte {
+				return pluginContents
+			},
+			existing:     []byte("old plugin contents"),
+			expectedPath: "plugin.yaml",
+			badHash:      true,
+			expectedErr:  "content hash could not be verified",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			installDir := t.TempDir()
+			tmpDir := filepath.Join(installDir, "tmp")
+			pkgDir := filepath.Join(installDir, "pkg")
+
+			if tc.existing != nil {
+				require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(pkgDir, tc.expectedPath)), 0750))
+				require.NoError(t, os.WriteFile(filepath.Join(pkgDir, tc.expectedPath), tc.existing, 0600))
+			}
+
+			contents := tc.contents(t)
+			contentHash := sha256.Sum256(contents)
+			if tc.badHash {
+				contentHash = sha256.Sum256([]byte("something else"))
+			}
+
+			s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Write(contents)
+			}))
+			defer s.Close()
+
+			file := &protobufs.DownloadableFile{
+				DownloadUrl: fmt.Sprintf("%s/%s", s.URL, tc.fileName),
+				ContentHash: contentHash[:],
+			}
+
+			downloadableFileManager := newDownloadableFileManager(zap.NewNop(), tmpDir)
+			err := downloadableFileManager.FetchAndInstallPackage(file, "plugin", opamp.PackageConfig{
+				Dir:      "pkg",
+				FileName: tc.cfgFileName,
+			})
+
+			actualBytes, readErr := os.ReadFile(filepath.Join(pkgDir, tc.expectedPath))
+			require.NoError(t, readErr)
+			if tc.expectedErr != "" {
+				require.ErrorContains(t, err, tc.expectedErr)
+				require.Equal(t, tc.existing, actualBytes)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, pluginContents, actualBytes)
+			}
+
+			require.NoDirExists(t, filepath.Join(tmpDir, packagesFolder, "plugin"))
+		})
+	}
+}
+
 func TestCleanupArtifacts(t *testing.T) {
 	t.Run("Cleans up tmp dir if exists", func(t *testing.T) {
 		tmpDir := filepath.Join(t.TempDir(), "tmp")
@@ -17,11 +17,8 @@ package observiq
 import (
 	"context"
 	"fmt"
-	"os"
-	"path/filepath"
 
-	"github.com/observiq/bindplane-agent/internal/logging"
-	"github.com/observiq/bindplane-agent/internal/report"
+	"github.com/observiq/bindplane-agent/internal/reload"
 	"github.com/observiq/bindplane-agent/opamp"
 	"go.uber.org/zap"
 	"gopkg.in/yaml.v3"
@@ -42,7 +39,7 @@ func managerReload(client *Client, managerConfigPath string) opamp.ReloadFunc {
 		}
 
 		// Going to do an update prep a rollback
-		rollbackFunc, cleanupFunc, err := prepRollback(managerConfigPath)
+		rollbackFunc, cleanupFunc, err := reload.PrepRollback(managerConfigPath)
 		if err != nil {
 			return false, fmt.Errorf("failed to prep for rollback: %w", err)
 		}
@@ -54,6 +51,10 @@ func managerReload(client *Client, managerConfigPath string) opamp.ReloadFunc {
 			}
 		}()
 
+		// The identity detectors may update the identity in the background
+		client.identMutex.Lock()
+		defer client.identMutex.Unlock()
+
 		//create a copies for rollback
 		rollBackCfg := client.currentConfig.Copy()
 		rollbackIdent := client.ident.Copy()
@@ -80,7 +81,7 @@ func managerReload(client *Client, managerConfigPath string) opamp.ReloadFunc {
 		}
 
 		// Save config file to disk
-		if err := updateConfigFile(ManagerConfigName, managerConfigPath, newContents); err != nil {
+		if err := reload.UpdateConfigFile(ManagerConfigName, managerConfigPath, newContents); err != nil {
 			// Rollback file
 			if rollbackErr := rollbackFunc(); rollbackErr != nil {
 				client.logger.Error("Rollback failed for collector config", zap.Error(rollbackErr))
@@ -107,47 +108,16 @@ func managerReload(client *Client, managerConfigPath string) opamp.ReloadFunc {
 
 func collectorReload(client *Client, collectorConfigPath string) opamp.ReloadFunc {
 	return func(contents []byte) (bool, error) {
-		rollbackFunc, cleanupFunc, err := prepRollback(collectorConfigPath)
-		if err != nil {
-			return false, fmt.Errorf("failed to prep for rollback: %w", err)
-		}
-
-		defer func() {
-			// Cleanup rollback
-			if err := cleanupFunc(); err != nil {
-				client.logger.Warn("Failed to cleanup rollback file", zap.Error(err))
-			}
-		}()
-
-		// Write new config file
-		if err := updateConfigFile(CollectorConfigName, collectorConfigPath, contents); err != nil {
-			return false, err
-		}
-
 		// Stop collector monitoring as we are going to restart it
 		client.stopCollectorMonitoring()
 
 		// Setup new monitoring after collector has been restarted
 		defer client.startCollectorMonitoring(context.Background())
 
-		// Reload collector
-		if err := client.collector.Restart(context.Background()); err != nil {
-			// Rollback file
-			if rollbackErr := rollbackFunc(); rollbackErr != nil {
-				client.logger.Error("Rollback failed for collector config", zap.Error(rollbackErr))
-			}
-
-			// Restart collector with original file
-			if rollbackErr := client.collector.Restart(context.Background()); rollbackErr != nil {
-				client.logger.Error("Collector failed for restart during rollback", zap.Error(rollbackErr))
-			}
-
-			return false, fmt.Errorf("collector failed to restart: %w", err)
+		if err := reload.CollectorConfig(client.logger, client.collector, CollectorConfigName, collectorConfigPath, contents); err != nil {
+			return false, err
 		}
 
-		// Reset Snapshot Reporter
-		report.GetSnapshotReporter().Reset()
-
 		return true, nil
 	}
 }
@@ -165,119 +135,20 @@ func reportReload(client *Client) opamp.ReloadFunc {
 
 func loggerReload(client *Client, loggerConfigPath string) opamp.ReloadFunc {
 	return func(contents []byte) (bool, error) {
-		rollbackFunc, cleanupFunc, err := prepRollback(loggerConfigPath)
-		if err != nil {
-			return false, fmt.Errorf("failed to prep for rollback: %w", err)
-		}
-
-		defer func() {
-			// Cleanup rollback
-			if err := cleanupFunc(); err != nil {
-				client.logger.Warn("Failed to cleanup rollback file", zap.Error(err))
-			}
-		}()
-
-		// Write new config file
-		if err := updateConfigFile(LoggingConfigName, loggerConfigPath, contents); err != nil {
-			if rollbackErr := rollbackFunc(); rollbackErr != nil {
-				client.logger.Error("Rollback failed for logging config", zap.Error(rollbackErr))
-			}
-			return false, err
-		}
-
-		// Parse new logging config
-		l, err := logging.NewLoggerConfig(loggerConfigPath)
+		opts, err := reload.LoggingConfig(client.logger, client.collector, LoggingConfigName, loggerConfigPath, contents)
 		if err != nil {
-			if rollbackErr := rollbackFunc(); rollbackErr != nil {
-				client.logger.Error("Rollback failed for logging config", zap.Error(rollbackErr))
-			}
 			return false, err
 		}
 
-		// Parse out options
-		opts, err := l.Options()
-		if err != nil {
-			if rollbackErr := rollbackFunc(); rollbackErr != nil {
-				client.logger.Error("Rollback failed for logging config", zap.Error(rollbackErr))
-			}
-			return false, fmt.Errorf("failed updating logging config: %w", err)
-		}
-
 		// Create new logger for client
 		logger, err := zap.NewProduction(opts...)
 		if err != nil {
-			if rollbackErr := rollbackFunc(); rollbackErr != nil {
-				client.logger.Error("Rollback failed for logging config", zap.Error(rollbackErr))
-			}
 			return false, fmt.Errorf("failed updating logging config: %w", err)
 		}
 
-		// Apply logging opts to collector
-		rollbackOpts := client.collector.GetLoggingOpts()
-		client.collector.SetLoggingOpts(opts)
-		if err := client.collector.Restart(context.Background()); err != nil {
-			if rollbackErr := rollbackFunc(); rollbackErr != nil {
-				client.logger.Error("Rollback failed for logging config", zap.Error(rollbackErr))
-			}
-
-			// Restart collector with original logging opts
-			client.collector.SetLoggingOpts(rollbackOpts)
-			if rollbackErr := client.collector.Restart(context.Background()); rollbackErr != nil {
-				client.logger.Error("Collector failed for restart during rollback", zap.Error(rollbackErr))
-			}
-
-			return false, fmt.Errorf("failed apply logging update to collector: %w", err)
-		}
-
 		// Assign new client logger
 		client.logger = logger.Named("opamp")
 
 		return true, nil
 	}
 }
-
-func updateConfigFile(configName, configPath string, contents []byte) error {
-	// Write file
-	if err := os.WriteFile(configPath, contents, 0600); err != nil {
-		return fmt.Errorf("failed to update config file %s: %w", configName, err)
-	}
-
-	return nil
-}
-
-func prepRollback(configPath string) (rollbackFunc func() error, cleanupFunc func() error, err error) {
-	rollbackPath := fmt.Sprintf("%s.rollback", configPath)
-
-	// Create rollback file
-	err = copyFile(configPath, rollbackPath)
-	if err != nil {
-		return
-	}
-
-	// Create rollback func
-	rollbackFunc = func() error {
-		return copyFile(rollbackPath, configPath)
-	}
-
-	// Create cleanupFUnc
-	cleanupFunc = func() error {
-		return os.Remove(rollbackPath)
-	}
-
-	return
-}
-
-func copyFile(originPath, newPath string) error {
-	cleanOriginPath := filepath.Clean(originPath)
-	data, err := os.ReadFile(cleanOriginPath)
-	if err != nil {
-		return fmt.Errorf("failed to read origin file: %w", err)
-	}
-
-	err = os.WriteFile(newPath, data, 0600)
-	if err != nil {
-		return fmt.Errorf("failed to write new file: %w", err)
-	}
-
-	return nil
-}
@@ -311,7 +311,7 @@ func Test_loggerReload(t *testing.T) {
 
 				loggerFilePath := filepath.Join(tmpDir, LoggingConfigName)
 
-				currContents := []byte("current: config")
+				currContents := []byte("output: file\nlevel: debug")
 
 				// Write Config file so we can verify it remained the same
 				err := os.WriteFile(loggerFilePath, currContents, 0600)
@@ -341,6 +341,38 @@ func Test_loggerReload(t *testing.T) {
 				assert.NotNil(t, client.logger)
 			},
 		},
+		{
+			desc: "Level only update does not restart",
+			testFunc: func(t *testing.T) {
+				tmpDir := t.TempDir()
+
+				loggerFilePath := filepath.Join(tmpDir, LoggingConfigName)
+
+				err := os.WriteFile(loggerFilePath, []byte("output: stdout\nlevel: info"), 0600)
+				assert.NoError(t, err)
+
+				mockCol := colmocks.NewMockCollector(t)
+				mockCol.On("GetLoggingOpts").Return([]zap.Option{})
+
+				client := &Client{
+					collector: mockCol,
+				}
+
+				reloadFunc := loggerReload(client, loggerFilePath)
+
+				newContents := []byte("output: stdout\nlevel: debug\ncomponents:\n  exporter/otlp: warn")
+				changed, err := reloadFunc(newContents)
+				assert.NoError(t, err)
+				assert.True(t, changed)
+
+				// Verify config updated
+				data, err := os.ReadFile(loggerFilePath)
+				assert.NoError(t, err)
+				assert.Equal(t, newContents, data)
+				// Verify logger was set
+				assert.NotNil(t, client.logger)
+			},
+		},
 		{
 			desc: "Collector fails to restart, rollback",
 			testFunc: func(t *testing.T) {
@@ -348,7 +380,7 @@ func Test_loggerReload(t *testing.T) {
 
 				loggerFilePath := filepath.Join(tmpDir, LoggingConfigName)
 
-				currContents := []byte("current: config")
+				currContents := []byte("output: file\nlevel: debug")
 
 				// Write Config file so we can verify it remained the same
 				err := os.WriteFile(loggerFilePath, currContents, 0600)
@@ -28,6 +28,13 @@ import (
 // CollectorPackageName is the name for the top level packages for this collector
 const CollectorPackageName = "observiq-otel-collector"
 
+// CollectorDeltaPackageName is the name of the optional package containing a delta (binary patch) archive
+// that upgrades the currently installed collector to the version of the CollectorPackageName package
+const CollectorDeltaPackageName = "observiq-otel-collector-delta"
+
+// JMXJarPackageName is the name of the package containing the OpenTelemetry JMX metrics jar
+const JMXJarPackageName = "opentelemetry-java-contrib-jmx-metrics"
+
 // DefaultFileName is the default name of the file use to store state
 const DefaultFileName = "package_statuses.json"
 
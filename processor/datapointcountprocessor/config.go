@@ -49,6 +49,10 @@ type Config struct {
 	OTTLMatch      *string           `mapstructure:"ottl_match"`
 	Attributes     map[string]string `mapstructure:"attributes"`
 	OTTLAttributes map[string]string `mapstructure:"ottl_attributes"`
+
+	// AdjustedCountAttribute is the attribute holding the adjusted count of sampled telemetry.
+	// If set, the adjusted counts of matching telemetry are summed instead of counting each as 1.
+	AdjustedCountAttribute string `mapstructure:"adjusted_count_attribute"`
 }
 
 // Validate validates the config, returning an error if the config is invalid
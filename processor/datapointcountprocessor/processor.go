@@ -106,6 +106,15 @@ func (p *metricCountProcessor) Shutdown(_ context.Context) error {
 	return nil
 }
 
+// DiscardedOnShutdown returns the number of datapoints counted since the last interval, which are discarded on shutdown.
+// They can't be flushed, since the route receiver they are sent to stops before processors.
+func (p *metricCountProcessor) DiscardedOnShutdown() int {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+
+	return p.counter.Len()
+}
+
 // ConsumeMetrics processes the metrics.
 func (p *metricCountProcessor) ConsumeMetrics(ctx context.Context, m pmetric.Metrics) error {
 	p.mux.Lock()
@@ -134,7 +143,7 @@ func (p *metricCountProcessor) consumeMetricsExpr(m pmetric.Metrics) {
 
 			if match {
 				attrs := p.attrs.Extract(dp)
-				p.counter.Add(resource, attrs)
+				p.counter.AddAdjusted(resource, attrs, counter.AttributeAdjustedCount(dp[expr.AttributesField], p.config.AdjustedCountAttribute))
 			}
 		}
 	}
@@ -162,7 +171,7 @@ func (p *metricCountProcessor) consumeMetricsOTTL(ctx context.Context, m pmetric
 
 					if match {
 						attrs := p.OTTLattrs.ExtractAttributes(ctx, tCtx)
-						p.counter.Add(resource.Attributes().AsRaw(), attrs)
+						p.counter.AddAdjusted(resource.Attributes().AsRaw(), attrs, counter.AttributeAdjustedCount(dp.(interface{ Attributes() pcommon.Map }).Attributes(), p.config.AdjustedCountAttribute))
 					}
 				})
 			}
@@ -221,7 +230,11 @@ func (p *metricCountProcessor) createMetrics() pmetric.Metrics {
 
 			gauge := metrics.Gauge().DataPoints().AppendEmpty()
 			gauge.SetTimestamp(pcommon.NewTimestampFromTime(time.Now()))
-			gauge.SetIntValue(int64(attributes.Count()))
+			if p.config.AdjustedCountAttribute != "" {
+				gauge.SetDoubleValue(attributes.AdjustedCount())
+			} else {
+				gauge.SetIntValue(int64(attributes.Count()))
+			}
 			err = gauge.Attributes().FromRaw(attributes.Values())
 			if err != nil {
 				p.logger.Error("Failed to set metric attributes", zap.Error(err))
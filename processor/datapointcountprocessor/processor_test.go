@@ -230,6 +230,22 @@ func TestConsumeMetricsOTTL(t *testing.T) {
 	require.Equal(t, map[string]any{"dimension1": float64(60), "dimension2": "test2"}, countDP.Attributes().AsRaw())
 }
 
+func TestDiscardedOnShutdown(t *testing.T) {
+	processorFactory := NewFactory()
+	processorSettings := processor.CreateSettings{TelemetrySettings: component.TelemetrySettings{Logger: zap.NewNop()}}
+	p, err := processorFactory.CreateMetricsProcessor(context.Background(), processorSettings, createDefaultConfig(), &consumertest.MetricsSink{})
+	require.NoError(t, err)
+	require.NoError(t, p.Start(context.Background(), nil))
+
+	countProcessor := p.(*metricCountProcessor)
+	countProcessor.counter.Add(map[string]any{"resource": "test1"}, map[string]any{"attribute": "test1"})
+	countProcessor.counter.Add(map[string]any{"resource": "test1"}, map[string]any{"attribute": "test2"})
+	countProcessor.counter.Add(map[string]any{"resource": "test2"}, map[string]any{"attribute": "test1"})
+
+	require.NoError(t, p.Shutdown(context.Background()))
+	require.Equal(t, 3, countProcessor.DiscardedOnShutdown())
+}
+
 func TestConsumeLogsWithoutReceiver(t *testing.T) {
 	logger := NewTestLogger()
 	processorCfg := createDefaultConfig().(*Config)
@@ -257,3 +273,44 @@ func NewTestLogger() *TestLogger {
 	logger := zap.New(core)
 	return &TestLogger{buffer: buffer, Logger: logger}
 }
+
+func TestConsumeMetricsAdjustedCount(t *testing.T) {
+	testCases := []struct {
+		desc  string
+		match *string
+	}{
+		{
+			desc: "OTTL",
+		},
+		{
+			desc:  "Expr",
+			match: func() *string { match := "true"; return &match }(),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			processorCfg := createDefaultConfig().(*Config)
+			processorCfg.Match = tc.match
+			processorCfg.AdjustedCountAttribute = "sampling.adjusted_count"
+
+			processorSettings := processor.CreateSettings{TelemetrySettings: component.TelemetrySettings{Logger: zap.NewNop()}}
+			p, err := NewFactory().CreateMetricsProcessor(context.Background(), processorSettings, processorCfg, consumertest.NewNop())
+			require.NoError(t, err)
+
+			md := pmetric.NewMetrics()
+			dps := md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics().AppendEmpty().SetEmptyGauge().DataPoints()
+			dps.AppendEmpty().Attributes().PutDouble("sampling.adjusted_count", 10)
+			dps.AppendEmpty().Attributes().PutInt("sampling.adjusted_count", 4)
+			dps.AppendEmpty()
+
+			require.NoError(t, p.ConsumeMetrics(context.Background(), md))
+
+			// Telemetry without an adjusted count is counted as 1
+			metrics := p.(*metricCountProcessor).createMetrics()
+			dataPoints := metrics.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0).Gauge().DataPoints()
+			require.Equal(t, 1, dataPoints.Len())
+			require.Equal(t, 15.0, dataPoints.At(0).DoubleValue())
+		})
+	}
+}
@@ -19,6 +19,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/observiq/bindplane-agent/counter"
 	"go.opentelemetry.io/collector/component"
 )
 
@@ -37,6 +38,9 @@ const (
 
 	// defaultExprMatch is the default expr match expression.
 	defaultExprMatch = "true"
+
+	// defaultMaxSeries is the default maximum number of attribute sets counted per interval.
+	defaultMaxSeries = 10000
 )
 
 // Config is the config of the processor.
@@ -49,6 +53,25 @@ type Config struct {
 	OTTLMatch      *string           `mapstructure:"ottl_match"`
 	Attributes     map[string]string `mapstructure:"attributes"`
 	OTTLAttributes map[string]string `mapstructure:"ottl_attributes"`
+
+	// AdjustedCountAttribute is the attribute holding the adjusted count of sampled telemetry.
+	// If set, the adjusted counts of matching telemetry are summed instead of counting each as 1.
+	AdjustedCountAttribute string `mapstructure:"adjusted_count_attribute"`
+
+	// TopN limits the metric to the N attribute sets with the highest counts per resource.
+	// Counts of all other attribute sets are combined into an overflow attribute set. 0 is unlimited.
+	TopN int `mapstructure:"top_n"`
+
+	// RateWindow is the duration of the sliding window used to calculate the rate metric.
+	// If set, a rate metric is created in addition to the count metric.
+	RateWindow time.Duration `mapstructure:"rate_window"`
+
+	// MaxSeries is the maximum number of attribute sets counted per interval.
+	// Counts of attribute sets beyond this limit are combined into an overflow attribute set. 0 is unlimited.
+	MaxSeries int `mapstructure:"max_series"`
+
+	// Exemplars configures the exemplars sampled from counted logs and attached to the count metric.
+	Exemplars counter.ExemplarConfig `mapstructure:"exemplars"`
 }
 
 // Validate validates the config, returning an error if the config is invalid
@@ -69,6 +92,26 @@ func (c Config) Validate() error {
 		return fmt.Errorf("cannot use ottl_match with attributes")
 	}
 
+	if c.TopN < 0 {
+		return fmt.Errorf("top_n must not be negative")
+	}
+
+	if c.MaxSeries < 0 {
+		return fmt.Errorf("max_series must not be negative")
+	}
+
+	if c.RateWindow != 0 && c.RateWindow < c.Interval {
+		return fmt.Errorf("rate_window must be at least the interval")
+	}
+
+	if err := c.Exemplars.Validate(); err != nil {
+		return err
+	}
+
+	if c.Exemplars.Enabled && !c.isOTTL() {
+		return fmt.Errorf("exemplars are only supported with ottl_match and ottl_attributes")
+	}
+
 	return nil
 }
 
@@ -98,5 +141,9 @@ func createDefaultConfig() component.Config {
 		MetricName: defaultMetricName,
 		MetricUnit: defaultMetricUnit,
 		Interval:   defaultInterval,
+		MaxSeries:  defaultMaxSeries,
+		Exemplars: counter.ExemplarConfig{
+			MaxPerDatapoint: counter.DefaultMaxExemplars,
+		},
 	}
 }
@@ -15,8 +15,11 @@
 package logcountprocessor
 
 import (
+	"time"
+
 	"testing"
 
+	"github.com/observiq/bindplane-agent/counter"
 	"github.com/stretchr/testify/require"
 )
 
@@ -25,6 +28,8 @@ func TestCreateDefaultProcessorConfig(t *testing.T) {
 	require.Equal(t, defaultInterval, cfg.Interval)
 	require.Equal(t, defaultMetricName, cfg.MetricName)
 	require.Equal(t, defaultMetricUnit, cfg.MetricUnit)
+	require.Equal(t, defaultMaxSeries, cfg.MaxSeries)
+	require.Equal(t, counter.DefaultMaxExemplars, cfg.Exemplars.MaxPerDatapoint)
 }
 
 func TestConfig_Validate(t *testing.T) {
@@ -70,6 +75,51 @@ func TestConfig_Validate(t *testing.T) {
 			},
 			err: "cannot use ottl_match with attributes",
 		},
+		{
+			name: "negative top_n",
+			config: &Config{
+				TopN: -1,
+			},
+			err: "top_n must not be negative",
+		},
+		{
+			name: "negative max_series",
+			config: &Config{
+				MaxSeries: -1,
+			},
+			err: "max_series must not be negative",
+		},
+		{
+			name: "rate_window shorter than interval",
+			config: &Config{
+				Interval:   time.Minute,
+				RateWindow: time.Second,
+			},
+			err: "rate_window must be at least the interval",
+		},
+		{
+			name: "exemplars without max_per_datapoint",
+			config: &Config{
+				Exemplars: counter.ExemplarConfig{Enabled: true},
+			},
+			err: "exemplars.max_per_datapoint must be positive",
+		},
+		{
+			name: "exemplars with expr",
+			config: &Config{
+				Match:     strp("true"),
+				Exemplars: counter.ExemplarConfig{Enabled: true, MaxPerDatapoint: 1},
+			},
+			err: "exemplars are only supported with ottl_match and ottl_attributes",
+		},
+		{
+			name: "rate_window and top_n",
+			config: &Config{
+				Interval:   time.Minute,
+				RateWindow: 5 * time.Minute,
+				TopN:       10,
+			},
+		},
 	}
 
 	for _, tc := range testCases {
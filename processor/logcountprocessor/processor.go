@@ -39,6 +39,7 @@ type logCountProcessor struct {
 	OTTLmatch *expr.OTTLCondition[ottllog.TransformContext]
 	OTTLattrs *expr.OTTLAttributeMap[ottllog.TransformContext]
 	counter   *counter.TelemetryCounter
+	window    *rateWindow
 	consumer  consumer.Logs
 	logger    *zap.Logger
 	cancel    context.CancelFunc
@@ -52,7 +53,8 @@ func newExprProcessor(config *Config, consumer consumer.Logs, match *expr.Expres
 		config:   config,
 		match:    match,
 		attrs:    attrs,
-		counter:  counter.NewTelemetryCounter(),
+		counter:  newCounter(config),
+		window:   newProcessorRateWindow(config),
 		consumer: consumer,
 		logger:   logger,
 	}
@@ -68,12 +70,28 @@ func newOTTLProcessor(
 		config:    config,
 		OTTLmatch: match,
 		OTTLattrs: attrs,
-		counter:   counter.NewTelemetryCounter(),
+		counter:   newCounter(config),
+		window:    newProcessorRateWindow(config),
 		consumer:  consumer,
 		logger:    logger,
 	}
 }
 
+// newCounter returns a counter for an interval, sampling exemplars if enabled.
+func newCounter(config *Config) *counter.TelemetryCounter {
+	c := counter.NewBoundedTelemetryCounter(config.MaxSeries)
+	c.SetExemplarLimit(config.Exemplars.Limit())
+	return c
+}
+
+// newProcessorRateWindow returns the window used for the rate metric, or nil if the rate metric is disabled.
+func newProcessorRateWindow(config *Config) *rateWindow {
+	if config.RateWindow == 0 {
+		return nil
+	}
+	return newRateWindow(config.RateWindow, config.Interval)
+}
+
 func (p *logCountProcessor) isOTTL() bool {
 	return p.OTTLmatch != nil
 }
@@ -101,6 +119,15 @@ func (p *logCountProcessor) Shutdown(_ context.Context) error {
 	return nil
 }
 
+// DiscardedOnShutdown returns the number of datapoints counted since the last interval, which are discarded on shutdown.
+// They can't be flushed, since the route receiver they are sent to stops before processors.
+func (p *logCountProcessor) DiscardedOnShutdown() int {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+
+	return p.counter.Len()
+}
+
 // ConsumeLogs processes the logs.
 func (p *logCountProcessor) ConsumeLogs(ctx context.Context, pl plog.Logs) error {
 	p.mux.Lock()
@@ -123,10 +150,10 @@ func (p *logCountProcessor) consumeLogsOTTL(ctx context.Context, pl plog.Logs) {
 
 		scopeLogs := resourceLog.ScopeLogs()
 		for j := 0; j < scopeLogs.Len(); j++ {
-			scopeLog := scopeLogs.At(0)
+			scopeLog := scopeLogs.At(j)
 			logs := scopeLog.LogRecords()
 			for k := 0; k < logs.Len(); k++ {
-				log := logs.At(0)
+				log := logs.At(k)
 				logCtx := ottllog.NewTransformContext(log, scopeLog.Scope(), resource)
 				match, err := p.OTTLmatch.Match(ctx, logCtx)
 				if err != nil {
@@ -136,7 +163,12 @@ func (p *logCountProcessor) consumeLogsOTTL(ctx context.Context, pl plog.Logs) {
 
 				if match {
 					attrs := p.OTTLattrs.ExtractAttributes(ctx, logCtx)
-					p.counter.Add(resource.Attributes().AsRaw(), attrs)
+					adjustedCount := counter.AttributeAdjustedCount(log.Attributes(), p.config.AdjustedCountAttribute)
+					if p.config.Exemplars.Enabled {
+						p.counter.AddExemplar(resource.Attributes().AsRaw(), attrs, adjustedCount, p.exemplar(log, adjustedCount))
+					} else {
+						p.counter.AddAdjusted(resource.Attributes().AsRaw(), attrs, adjustedCount)
+					}
 				}
 			}
 		}
@@ -150,7 +182,7 @@ func (p *logCountProcessor) consumeLogsExpr(pl plog.Logs) {
 		for _, record := range group.Records {
 			if p.match.MatchRecord(record) {
 				attrs := p.attrs.Extract(record)
-				p.counter.Add(resource, attrs)
+				p.counter.AddAdjusted(resource, attrs, counter.AttributeAdjustedCount(record[expr.AttributesField], p.config.AdjustedCountAttribute))
 			}
 		}
 	}
@@ -177,22 +209,55 @@ func (p *logCountProcessor) sendMetrics(ctx context.Context) {
 	p.mux.Lock()
 	defer p.mux.Unlock()
 
+	if p.window != nil {
+		p.window.add(p.counter)
+	}
+
 	metrics := p.createMetrics()
+	p.resetCounter()
 	if metrics.ResourceMetrics().Len() == 0 {
 		return
 	}
 
-	p.counter.Reset()
-
 	if err := routereceiver.RouteMetrics(ctx, p.config.Route, metrics); err != nil {
 		p.logger.Error("Failed to send metrics", zap.Error(err))
 	}
 }
 
-// createMetrics creates metrics from the counter.
+// resetCounter resets the counter for the next interval.
+// If the counter is held by the rate window, a new counter is created instead.
+func (p *logCountProcessor) resetCounter() {
+	if p.window != nil {
+		p.counter = newCounter(p.config)
+		return
+	}
+
+	p.counter.Reset()
+}
+
+// createMetrics creates metrics from the counter, and the rate window if enabled.
 func (p *logCountProcessor) createMetrics() pmetric.Metrics {
 	metrics := pmetric.NewMetrics()
-	for _, resource := range p.counter.Resources() {
+	p.addMetrics(metrics, p.counter, p.config.MetricName, p.config.MetricUnit, p.setCount)
+
+	if p.window != nil && p.window.duration() > 0 {
+		seconds := p.window.duration().Seconds()
+		p.addMetrics(metrics, p.window.merged(p.config.MaxSeries), p.config.MetricName+".rate", p.config.MetricUnit+"/s",
+			func(dp pmetric.NumberDataPoint, attributes *counter.AttributeCounter) {
+				dp.SetDoubleValue(p.count(attributes) / seconds)
+			})
+	}
+
+	return metrics
+}
+
+// addMetrics adds a gauge metric for each counted attribute set of the counter.
+func (p *logCountProcessor) addMetrics(
+	metrics pmetric.Metrics,
+	c *counter.TelemetryCounter,
+	name, unit string,
+	setValue func(pmetric.NumberDataPoint, *counter.AttributeCounter)) {
+	for _, resource := range c.Resources() {
 		resourceMetrics := metrics.ResourceMetrics().AppendEmpty()
 		err := resourceMetrics.Resource().Attributes().FromRaw(resource.Values())
 		if err != nil {
@@ -201,22 +266,70 @@ func (p *logCountProcessor) createMetrics() pmetric.Metrics {
 
 		scopeMetrics := resourceMetrics.ScopeMetrics().AppendEmpty()
 		scopeMetrics.Scope().SetName(typeStr)
-		for _, attributes := range resource.Attributes() {
+		for _, attributes := range p.attributeCounters(resource) {
 			metrics := scopeMetrics.Metrics().AppendEmpty()
-			metrics.SetName(p.config.MetricName)
-			metrics.SetUnit(p.config.MetricUnit)
+			metrics.SetName(name)
+			metrics.SetUnit(unit)
 			metrics.SetEmptyGauge()
 
 			gauge := metrics.Gauge().DataPoints().AppendEmpty()
 			gauge.SetTimestamp(pcommon.NewTimestampFromTime(time.Now()))
-			gauge.SetIntValue(int64(attributes.Count()))
+			setValue(gauge, attributes)
 			err = gauge.Attributes().FromRaw(attributes.Values())
 			if err != nil {
 				p.logger.Error("Failed to set metric attributes", zap.Error(err))
 			}
+		}
+	}
+}
 
+// attributeCounters returns the attribute counters of the resource to create datapoints for.
+// If top_n is set, only the top attribute sets are returned, along with one combining the rest.
+func (p *logCountProcessor) attributeCounters(resource *counter.ResourceCounter) []*counter.AttributeCounter {
+	if p.config.TopN == 0 {
+		attributes := make([]*counter.AttributeCounter, 0, len(resource.Attributes()))
+		for _, a := range resource.Attributes() {
+			attributes = append(attributes, a)
 		}
+		return attributes
 	}
 
-	return metrics
+	top, other := resource.TopAttributes(p.config.TopN, p.config.AdjustedCountAttribute != "")
+	if other != nil {
+		top = append(top, other)
+	}
+	return top
+}
+
+// setCount sets the count of the attribute set as the value of the datapoint, and attaches its exemplars.
+func (p *logCountProcessor) setCount(dp pmetric.NumberDataPoint, attributes *counter.AttributeCounter) {
+	if p.config.AdjustedCountAttribute != "" {
+		dp.SetDoubleValue(attributes.AdjustedCount())
+	} else {
+		dp.SetIntValue(int64(attributes.Count()))
+	}
+
+	for _, e := range attributes.Exemplars() {
+		if err := e.CopyTo(dp.Exemplars().AppendEmpty(), p.config.AdjustedCountAttribute == ""); err != nil {
+			p.logger.Error("Failed to set exemplar attributes", zap.Error(err))
+		}
+	}
+}
+
+// exemplar returns an exemplar of the log, with the configured log attributes.
+func (p *logCountProcessor) exemplar(log plog.LogRecord, value float64) counter.Exemplar {
+	timestamp := log.Timestamp()
+	if timestamp == 0 {
+		timestamp = log.ObservedTimestamp()
+	}
+
+	return p.config.Exemplars.NewExemplar(timestamp, log.TraceID(), log.SpanID(), log.Attributes(), value)
+}
+
+// count returns the count of the attribute set, using the adjusted count if configured.
+func (p *logCountProcessor) count(attributes *counter.AttributeCounter) float64 {
+	if p.config.AdjustedCountAttribute != "" {
+		return attributes.AdjustedCount()
+	}
+	return float64(attributes.Count())
 }
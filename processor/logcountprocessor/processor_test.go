@@ -19,10 +19,13 @@ import (
 	"testing"
 	"time"
 
+	"github.com/observiq/bindplane-agent/counter"
 	"github.com/observiq/bindplane-agent/receiver/routereceiver"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/plog"
 	"go.opentelemetry.io/collector/pdata/pmetric"
 	"go.opentelemetry.io/collector/processor"
@@ -216,6 +219,79 @@ func TestConsumeLogsOTTL(t *testing.T) {
 	require.Equal(t, map[string]any{"dimension1": "test1", "dimension2": "test2"}, metric.Attributes().AsRaw())
 }
 
+func TestConsumeLogsOTTLMultipleScopes(t *testing.T) {
+	logConsumer := &LogConsumer{logChan: make(chan plog.Logs, 1)}
+	metricConsumer := &MetricConsumer{metricChan: make(chan pmetric.Metrics, 1)}
+
+	processorCfg := createDefaultConfig().(*Config)
+	processorCfg.Interval = time.Millisecond * 100
+	processorCfg.OTTLMatch = strp("true")
+	processorCfg.OTTLAttributes = map[string]string{
+		"message": `body["message"]`,
+	}
+
+	processorFactory := NewFactory()
+	processorSettings := processor.CreateSettings{TelemetrySettings: component.TelemetrySettings{Logger: zap.NewNop()}}
+	processor, err := processorFactory.CreateLogsProcessor(context.Background(), processorSettings, processorCfg, logConsumer)
+	require.NoError(t, err)
+
+	receiverFactory := routereceiver.NewFactory()
+	receiver, err := receiverFactory.CreateMetricsReceiver(context.Background(), receiver.CreateSettings{}, receiverFactory.CreateDefaultConfig(), metricConsumer)
+	require.NoError(t, err)
+
+	err = processor.Start(context.Background(), nil)
+	require.NoError(t, err)
+	defer processor.Shutdown(context.Background())
+
+	err = receiver.Start(context.Background(), nil)
+	require.NoError(t, err)
+	defer receiver.Shutdown(context.Background())
+
+	logs := plog.NewLogs()
+	resourceLogs := logs.ResourceLogs().AppendEmpty()
+	for _, messages := range [][]string{{"a", "b"}, {"a", "c", "c"}} {
+		records := resourceLogs.ScopeLogs().AppendEmpty().LogRecords()
+		for _, message := range messages {
+			records.AppendEmpty().Body().SetEmptyMap().PutStr("message", message)
+		}
+	}
+
+	go func() {
+		processor.ConsumeLogs(context.Background(), logs)
+	}()
+
+	<-logConsumer.logChan
+	consumedMetrics := <-metricConsumer.metricChan
+
+	counts := map[string]int64{}
+	metrics := consumedMetrics.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+	for i := 0; i < metrics.Len(); i++ {
+		dataPoint := metrics.At(i).Gauge().DataPoints().At(0)
+		message, ok := dataPoint.Attributes().Get("message")
+		require.True(t, ok)
+		counts[message.Str()] = dataPoint.IntValue()
+	}
+
+	// Every record of every scope is counted
+	require.Equal(t, map[string]int64{"a": 2, "b": 1, "c": 2}, counts)
+}
+
+func TestDiscardedOnShutdown(t *testing.T) {
+	processorFactory := NewFactory()
+	processorSettings := processor.CreateSettings{TelemetrySettings: component.TelemetrySettings{Logger: zap.NewNop()}}
+	p, err := processorFactory.CreateLogsProcessor(context.Background(), processorSettings, createDefaultConfig(), &LogConsumer{})
+	require.NoError(t, err)
+	require.NoError(t, p.Start(context.Background(), nil))
+
+	countProcessor := p.(*logCountProcessor)
+	countProcessor.counter.Add(map[string]any{"resource": "test1"}, map[string]any{"attribute": "test1"})
+	countProcessor.counter.Add(map[string]any{"resource": "test1"}, map[string]any{"attribute": "test2"})
+	countProcessor.counter.Add(map[string]any{"resource": "test2"}, map[string]any{"attribute": "test1"})
+
+	require.NoError(t, p.Shutdown(context.Background()))
+	require.Equal(t, 3, countProcessor.DiscardedOnShutdown())
+}
+
 func TestConsumeLogsWithoutReceiver(t *testing.T) {
 	logger := NewTestLogger()
 	processorCfg := createDefaultConfig().(*Config)
@@ -269,3 +345,156 @@ func NewTestLogger() *TestLogger {
 	logger := zap.New(core)
 	return &TestLogger{buffer: buffer, Logger: logger}
 }
+
+func TestConsumeLogsAdjustedCount(t *testing.T) {
+	testCases := []struct {
+		desc  string
+		match *string
+	}{
+		{
+			desc: "OTTL",
+		},
+		{
+			desc:  "Expr",
+			match: func() *string { match := "true"; return &match }(),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			processorCfg := createDefaultConfig().(*Config)
+			processorCfg.Match = tc.match
+			processorCfg.AdjustedCountAttribute = "sampling.adjusted_count"
+
+			processorSettings := processor.CreateSettings{TelemetrySettings: component.TelemetrySettings{Logger: zap.NewNop()}}
+			p, err := NewFactory().CreateLogsProcessor(context.Background(), processorSettings, processorCfg, consumertest.NewNop())
+			require.NoError(t, err)
+
+			logs := plog.NewLogs()
+			records := logs.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords()
+			records.AppendEmpty().Attributes().PutDouble("sampling.adjusted_count", 10)
+			records.AppendEmpty().Attributes().PutInt("sampling.adjusted_count", 4)
+			records.AppendEmpty()
+
+			require.NoError(t, p.ConsumeLogs(context.Background(), logs))
+
+			// Telemetry without an adjusted count is counted as 1
+			metrics := p.(*logCountProcessor).createMetrics()
+			dataPoints := metrics.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0).Gauge().DataPoints()
+			require.Equal(t, 1, dataPoints.Len())
+			require.Equal(t, 15.0, dataPoints.At(0).DoubleValue())
+		})
+	}
+}
+
+func TestCreateMetricsTopN(t *testing.T) {
+	processorCfg := createDefaultConfig().(*Config)
+	processorCfg.TopN = 2
+	processorCfg.Route = "undefined"
+	p := newOTTLProcessor(processorCfg, consumertest.NewNop(), nil, nil, zap.NewNop())
+
+	resource := map[string]any{"host": "a"}
+	for i, n := range []int{1, 5, 3, 2} {
+		for j := 0; j < n; j++ {
+			p.counter.Add(resource, map[string]any{"status": int64(i)})
+		}
+	}
+
+	metrics := p.createMetrics()
+	require.Equal(t, 3, metrics.DataPointCount())
+	values := map[string]int64{}
+	ms := metrics.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+	for i := 0; i < ms.Len(); i++ {
+		dp := ms.At(i).Gauge().DataPoints().At(0)
+		if _, ok := dp.Attributes().Get(counter.OverflowAttribute); ok {
+			values["other"] = dp.IntValue()
+			continue
+		}
+		status, _ := dp.Attributes().Get("status")
+		values[status.AsString()] = dp.IntValue()
+	}
+	require.Equal(t, map[string]int64{"1": 5, "2": 3, "other": 3}, values)
+}
+
+func TestSendMetricsRateWindow(t *testing.T) {
+	processorCfg := createDefaultConfig().(*Config)
+	processorCfg.Interval = time.Second
+	processorCfg.RateWindow = 2 * time.Second
+	processorCfg.Route = "undefined"
+	p := newOTTLProcessor(processorCfg, consumertest.NewNop(), nil, nil, zap.NewNop())
+
+	resource := map[string]any{"host": "a"}
+	attrs := map[string]any{"status": "ok"}
+	rates := func() []float64 {
+		var rates []float64
+		metrics := p.createMetrics()
+		for i := 0; i < metrics.ResourceMetrics().Len(); i++ {
+			ms := metrics.ResourceMetrics().At(i).ScopeMetrics().At(0).Metrics()
+			for j := 0; j < ms.Len(); j++ {
+				if ms.At(j).Name() == "log.count.rate" {
+					require.Equal(t, "{logs}/s", ms.At(j).Unit())
+					rates = append(rates, ms.At(j).Gauge().DataPoints().At(0).DoubleValue())
+				}
+			}
+		}
+		return rates
+	}
+
+	for i := 0; i < 4; i++ {
+		p.counter.Add(resource, attrs)
+	}
+	p.sendMetrics(context.Background())
+	// Only one interval is in the window so far
+	require.Equal(t, []float64{4}, rates())
+
+	for i := 0; i < 2; i++ {
+		p.counter.Add(resource, attrs)
+	}
+	p.sendMetrics(context.Background())
+	require.Equal(t, []float64{3}, rates())
+
+	// The first interval leaves the window
+	p.sendMetrics(context.Background())
+	require.Equal(t, []float64{1}, rates())
+
+	p.sendMetrics(context.Background())
+	require.Empty(t, rates())
+}
+
+func TestConsumeLogsExemplars(t *testing.T) {
+	processorCfg := createDefaultConfig().(*Config)
+	processorCfg.Exemplars.Enabled = true
+	processorCfg.Exemplars.Attributes = []string{"http.route"}
+	processorCfg.OTTLAttributes = map[string]string{"status": `attributes["status"]`}
+
+	processorSettings := processor.CreateSettings{TelemetrySettings: component.TelemetrySettings{Logger: zap.NewNop()}}
+	p, err := NewFactory().CreateLogsProcessor(context.Background(), processorSettings, processorCfg, consumertest.NewNop())
+	require.NoError(t, err)
+
+	traceID := pcommon.TraceID([16]byte{1, 2, 3})
+	spanID := pcommon.SpanID([8]byte{4, 5, 6})
+	timestamp := pcommon.NewTimestampFromTime(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	logs := plog.NewLogs()
+	record := logs.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords().AppendEmpty()
+	record.SetTraceID(traceID)
+	record.SetSpanID(spanID)
+	record.SetObservedTimestamp(timestamp)
+	record.Attributes().PutStr("status", "500")
+	record.Attributes().PutStr("http.route", "/users")
+	record.Attributes().PutStr("user.id", "1234")
+
+	require.NoError(t, p.ConsumeLogs(context.Background(), logs))
+
+	metrics := p.(*logCountProcessor).createMetrics()
+	dataPoint := metrics.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0).Gauge().DataPoints().At(0)
+	require.Equal(t, int64(1), dataPoint.IntValue())
+	require.Equal(t, 1, dataPoint.Exemplars().Len())
+
+	exemplar := dataPoint.Exemplars().At(0)
+	require.Equal(t, traceID, exemplar.TraceID())
+	require.Equal(t, spanID, exemplar.SpanID())
+	require.Equal(t, timestamp, exemplar.Timestamp())
+	require.Equal(t, int64(1), exemplar.IntValue())
+	require.Equal(t, map[string]any{"http.route": "/users"}, exemplar.FilteredAttributes().AsRaw())
+}
@@ -0,0 +1,67 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logcountprocessor
+
+import (
+	"time"
+
+	"github.com/observiq/bindplane-agent/counter"
+)
+
+// rateWindow holds the counters of the most recent intervals, to calculate rates over a sliding window.
+type rateWindow struct {
+	counters []*counter.TelemetryCounter
+	size     int
+	interval time.Duration
+}
+
+// newRateWindow creates a rateWindow holding enough intervals to cover the window.
+func newRateWindow(window, interval time.Duration) *rateWindow {
+	size := int((window + interval - 1) / interval)
+	if size < 1 {
+		size = 1
+	}
+
+	return &rateWindow{
+		counters: make([]*counter.TelemetryCounter, 0, size),
+		size:     size,
+		interval: interval,
+	}
+}
+
+// add adds the counter of the latest interval, removing the oldest interval if the window is full.
+func (w *rateWindow) add(c *counter.TelemetryCounter) {
+	if len(w.counters) == w.size {
+		copy(w.counters, w.counters[1:])
+		w.counters = w.counters[:w.size-1]
+	}
+
+	w.counters = append(w.counters, c)
+}
+
+// merged returns a counter with the counts of every interval in the window.
+func (w *rateWindow) merged(limit int) *counter.TelemetryCounter {
+	merged := counter.NewBoundedTelemetryCounter(limit)
+	for _, c := range w.counters {
+		merged.Merge(c)
+	}
+	return merged
+}
+
+// duration returns the time covered by the intervals in the window.
+// Until the window is full, this is less than the configured window.
+func (w *rateWindow) duration() time.Duration {
+	return time.Duration(len(w.counters)) * w.interval
+}
@@ -40,6 +40,21 @@ const (
 
 	// attributeField is the name of the attribute field
 	attributeField = "attributes"
+
+	// defaultUniqueValuesAttribute is the default unique values attribute
+	defaultUniqueValuesAttribute = "unique_values"
+
+	// defaultUniqueValuesLimit is the default number of unique values kept
+	defaultUniqueValuesLimit = 10
+
+	// defaultMaxUniqueEntries is the default number of unique logs aggregated at once
+	defaultMaxUniqueEntries = 10000
+
+	// overflowPolicyEvict emits the least recently updated aggregate early to make room for a new unique log
+	overflowPolicyEvict = "evict"
+
+	// overflowPolicyPassthrough passes new unique logs through unaggregated
+	overflowPolicyPassthrough = "passthrough"
 )
 
 // Config errors
@@ -47,6 +62,10 @@ var (
 	errInvalidLogCountAttribute = errors.New("log_count_attribute must be set")
 	errInvalidInterval          = errors.New("interval must be greater than 0")
 	errCannotExcludeBody        = errors.New("cannot exclude the entire body")
+	errIncludeAndExclude        = errors.New("include_fields and exclude_fields cannot both be set")
+	errInvalidUniqueAttribute   = errors.New("unique_values.attribute must be set")
+	errInvalidUniqueLimit       = errors.New("unique_values.limit must be greater than 0")
+	errInvalidMaxUniqueEntries  = errors.New("max_unique_entries must not be negative")
 )
 
 // Config is the config of the processor.
@@ -55,6 +74,18 @@ type Config struct {
 	Interval          time.Duration `mapstructure:"interval"`
 	Timezone          string        `mapstructure:"timezone"`
 	ExcludeFields     []string      `mapstructure:"exclude_fields"`
+	IncludeFields     []string      `mapstructure:"include_fields"`
+	OTTLMatch         string        `mapstructure:"ottl_match"`
+	UniqueValues      UniqueValues  `mapstructure:"unique_values"`
+	MaxUniqueEntries  int           `mapstructure:"max_unique_entries"`
+	OverflowPolicy    string        `mapstructure:"overflow_policy"`
+}
+
+// UniqueValues is the config for tracking the unique values of a field in deduplicated logs.
+type UniqueValues struct {
+	Field     string `mapstructure:"field"`
+	Attribute string `mapstructure:"attribute"`
+	Limit     int    `mapstructure:"limit"`
 }
 
 // createDefaultConfig returns the default config for the processor.
@@ -64,6 +95,13 @@ func createDefaultConfig() component.Config {
 		Interval:          defaultInterval,
 		Timezone:          defaultTimezone,
 		ExcludeFields:     []string{},
+		IncludeFields:     []string{},
+		UniqueValues: UniqueValues{
+			Attribute: defaultUniqueValuesAttribute,
+			Limit:     defaultUniqueValuesLimit,
+		},
+		MaxUniqueEntries: defaultMaxUniqueEntries,
+		OverflowPolicy:   overflowPolicyEvict,
 	}
 }
 
@@ -82,7 +120,30 @@ func (c Config) Validate() error {
 		return fmt.Errorf("timezone is invalid: %w", err)
 	}
 
-	return c.validateExcludeFields()
+	if c.MaxUniqueEntries < 0 {
+		return errInvalidMaxUniqueEntries
+	}
+
+	// An empty overflow policy is treated as evict
+	switch c.OverflowPolicy {
+	case "", overflowPolicyEvict, overflowPolicyPassthrough:
+	default:
+		return fmt.Errorf("invalid overflow_policy '%s': must be %s or %s", c.OverflowPolicy, overflowPolicyEvict, overflowPolicyPassthrough)
+	}
+
+	if len(c.IncludeFields) > 0 && len(c.ExcludeFields) > 0 {
+		return errIncludeAndExclude
+	}
+
+	if err := c.validateExcludeFields(); err != nil {
+		return err
+	}
+
+	if err := c.validateIncludeFields(); err != nil {
+		return err
+	}
+
+	return c.validateUniqueValues()
 }
 
 // validateExcludeFields validates that all the exclude fields
@@ -95,9 +156,8 @@ func (c Config) validateExcludeFields() error {
 			return errCannotExcludeBody
 		}
 
-		// Split and ensure the field starts with `body` or `attributes`
-		parts := strings.Split(field, fieldDelimiter)
-		if parts[0] != bodyField && parts[0] != attributeField {
+		// Ensure the field starts with `body` or `attributes`
+		if !isValidField(field) {
 			return fmt.Errorf("an excludefield must start with %s or %s", bodyField, attributeField)
 		}
 
@@ -111,3 +171,49 @@ func (c Config) validateExcludeFields() error {
 
 	return nil
 }
+
+// validateIncludeFields validates that all the include fields are body or attribute fields
+func (c Config) validateIncludeFields() error {
+	knownIncludeFields := make(map[string]struct{})
+
+	for _, field := range c.IncludeFields {
+		if !isValidField(field) {
+			return fmt.Errorf("an include_field must start with %s or %s", bodyField, attributeField)
+		}
+
+		if _, ok := knownIncludeFields[field]; ok {
+			return fmt.Errorf("duplicate include_field %s", field)
+		}
+
+		knownIncludeFields[field] = struct{}{}
+	}
+
+	return nil
+}
+
+// validateUniqueValues validates the unique values config if a field is set
+func (c Config) validateUniqueValues() error {
+	if c.UniqueValues.Field == "" {
+		return nil
+	}
+
+	if !isValidField(c.UniqueValues.Field) {
+		return fmt.Errorf("unique_values.field must start with %s or %s", bodyField, attributeField)
+	}
+
+	if c.UniqueValues.Attribute == "" {
+		return errInvalidUniqueAttribute
+	}
+
+	if c.UniqueValues.Limit <= 0 {
+		return errInvalidUniqueLimit
+	}
+
+	return nil
+}
+
+// isValidField returns true if the field starts with `body` or `attributes`
+func isValidField(field string) bool {
+	parts := strings.Split(field, fieldDelimiter)
+	return parts[0] == bodyField || parts[0] == attributeField
+}
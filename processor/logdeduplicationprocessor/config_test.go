@@ -27,6 +27,11 @@ func TestCreateDefaultProcessorConfig(t *testing.T) {
 	require.Equal(t, defaultLogCountAttribute, cfg.LogCountAttribute)
 	require.Equal(t, defaultTimezone, cfg.Timezone)
 	require.Equal(t, []string{}, cfg.ExcludeFields)
+	require.Equal(t, []string{}, cfg.IncludeFields)
+	require.Equal(t, "", cfg.OTTLMatch)
+	require.Equal(t, UniqueValues{Attribute: defaultUniqueValuesAttribute, Limit: defaultUniqueValuesLimit}, cfg.UniqueValues)
+	require.Equal(t, defaultMaxUniqueEntries, cfg.MaxUniqueEntries)
+	require.Equal(t, overflowPolicyEvict, cfg.OverflowPolicy)
 }
 
 func TestValidateConfig(t *testing.T) {
@@ -95,6 +100,98 @@ func TestValidateConfig(t *testing.T) {
 			},
 			expectedErr: errors.New("duplicate exclude_field"),
 		},
+		{
+			desc: "invalid max unique entries",
+			cfg: &Config{
+				LogCountAttribute: defaultLogCountAttribute,
+				Interval:          defaultInterval,
+				Timezone:          defaultTimezone,
+				MaxUniqueEntries:  -1,
+			},
+			expectedErr: errInvalidMaxUniqueEntries,
+		},
+		{
+			desc: "invalid overflow policy",
+			cfg: &Config{
+				LogCountAttribute: defaultLogCountAttribute,
+				Interval:          defaultInterval,
+				Timezone:          defaultTimezone,
+				OverflowPolicy:    "drop",
+			},
+			expectedErr: errors.New("invalid overflow_policy 'drop'"),
+		},
+		{
+			desc: "invalid include and exclude fields",
+			cfg: &Config{
+				LogCountAttribute: defaultLogCountAttribute,
+				Interval:          defaultInterval,
+				Timezone:          defaultTimezone,
+				ExcludeFields:     []string{"body.thing"},
+				IncludeFields:     []string{"body.message"},
+			},
+			expectedErr: errIncludeAndExclude,
+		},
+		{
+			desc: "invalid include field",
+			cfg: &Config{
+				LogCountAttribute: defaultLogCountAttribute,
+				Interval:          defaultInterval,
+				Timezone:          defaultTimezone,
+				IncludeFields:     []string{"severity"},
+			},
+			expectedErr: errors.New("an include_field must start with"),
+		},
+		{
+			desc: "invalid duplicate include field",
+			cfg: &Config{
+				LogCountAttribute: defaultLogCountAttribute,
+				Interval:          defaultInterval,
+				Timezone:          defaultTimezone,
+				IncludeFields:     []string{"body.message", "body.message"},
+			},
+			expectedErr: errors.New("duplicate include_field"),
+		},
+		{
+			desc: "invalid unique values field",
+			cfg: &Config{
+				LogCountAttribute: defaultLogCountAttribute,
+				Interval:          defaultInterval,
+				Timezone:          defaultTimezone,
+				UniqueValues:      UniqueValues{Field: "resource.host", Attribute: defaultUniqueValuesAttribute, Limit: 1},
+			},
+			expectedErr: errors.New("unique_values.field must start with"),
+		},
+		{
+			desc: "invalid unique values attribute",
+			cfg: &Config{
+				LogCountAttribute: defaultLogCountAttribute,
+				Interval:          defaultInterval,
+				Timezone:          defaultTimezone,
+				UniqueValues:      UniqueValues{Field: "attributes.host", Limit: 1},
+			},
+			expectedErr: errInvalidUniqueAttribute,
+		},
+		{
+			desc: "invalid unique values limit",
+			cfg: &Config{
+				LogCountAttribute: defaultLogCountAttribute,
+				Interval:          defaultInterval,
+				Timezone:          defaultTimezone,
+				UniqueValues:      UniqueValues{Field: "attributes.host", Attribute: defaultUniqueValuesAttribute},
+			},
+			expectedErr: errInvalidUniqueLimit,
+		},
+		{
+			desc: "valid include fields config",
+			cfg: &Config{
+				LogCountAttribute: defaultLogCountAttribute,
+				Interval:          defaultInterval,
+				Timezone:          defaultTimezone,
+				IncludeFields:     []string{"body.message", "attributes.host"},
+				UniqueValues:      UniqueValues{Field: "attributes.user", Attribute: defaultUniqueValuesAttribute, Limit: 5},
+			},
+			expectedErr: nil,
+		},
 		{
 			desc: "valid config",
 			cfg: &Config{
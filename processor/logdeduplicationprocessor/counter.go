@@ -15,6 +15,7 @@
 package logdeduplicationprocessor
 
 import (
+	"container/list"
 	"hash/fnv"
 	"time"
 
@@ -37,16 +38,36 @@ type logAggregator struct {
 	resources         map[[16]byte]*resourceAggregator
 	logCountAttribute string
 	timezone          *time.Location
+	includeFields     []*field
+	uniqueValues      UniqueValues
+	uniqueField       *field
+	maxEntries        int
+	overflowPolicy    string
+
+	// lru orders log counters from most to least recently updated
+	lru     *list.List
+	evicted plog.Logs
 }
 
 // newLogAggregator creates a new LogCounter.
-func newLogAggregator(logCountAttribute string, timezone *time.Location) *logAggregator {
-
-	return &logAggregator{
+func newLogAggregator(cfg *Config, timezone *time.Location) *logAggregator {
+	aggregator := &logAggregator{
 		resources:         make(map[[16]byte]*resourceAggregator),
-		logCountAttribute: logCountAttribute,
+		logCountAttribute: cfg.LogCountAttribute,
 		timezone:          timezone,
+		includeFields:     newFields(cfg.IncludeFields),
+		uniqueValues:      cfg.UniqueValues,
+		maxEntries:        cfg.MaxUniqueEntries,
+		overflowPolicy:    cfg.OverflowPolicy,
+		lru:               list.New(),
+		evicted:           plog.NewLogs(),
+	}
+
+	if cfg.UniqueValues.Field != "" {
+		aggregator.uniqueField = newFields([]string{cfg.UniqueValues.Field})[0]
 	}
+
+	return aggregator
 }
 
 // Export exports the counter as a Logs
@@ -61,54 +82,146 @@ func (l *logAggregator) Export() plog.Logs {
 
 		scopeLogs := resourceLogs.ScopeLogs().AppendEmpty()
 		for _, lc := range resource.logCounters {
-			lr := scopeLogs.LogRecords().AppendEmpty()
+			l.exportLogCounter(scopeLogs, lc)
+		}
+	}
 
-			baseRecord := lc.logRecord
+	return logs
+}
+
+// exportLogCounter appends the aggregated log of the counter to the scope logs
+func (l *logAggregator) exportLogCounter(scopeLogs plog.ScopeLogs, lc *logCounter) {
+	lr := scopeLogs.LogRecords().AppendEmpty()
+
+	baseRecord := lc.logRecord
+
+	// Copy contents of base record
+	baseRecord.SetObservedTimestamp(pcommon.NewTimestampFromTime(lc.firstObservedTimestamp))
+	baseRecord.Body().CopyTo(lr.Body())
 
-			// Copy contents of base record
-			baseRecord.SetObservedTimestamp(pcommon.NewTimestampFromTime(lc.firstObservedTimestamp))
-			baseRecord.Body().CopyTo(lr.Body())
+	lr.Attributes().EnsureCapacity(baseRecord.Attributes().Len())
+	baseRecord.Attributes().CopyTo(lr.Attributes())
 
-			lr.Attributes().EnsureCapacity(baseRecord.Attributes().Len())
-			baseRecord.Attributes().CopyTo(lr.Attributes())
+	lr.SetSeverityNumber(baseRecord.SeverityNumber())
+	lr.SetSeverityText(baseRecord.SeverityText())
 
-			lr.SetSeverityNumber(baseRecord.SeverityNumber())
-			lr.SetSeverityText(baseRecord.SeverityText())
+	// Add attributes for log count and timestamps
+	lr.SetObservedTimestamp(pcommon.NewTimestampFromTime(lc.firstObservedTimestamp))
+	lr.SetTimestamp(pcommon.NewTimestampFromTime(timeNow()))
+	lr.Attributes().PutInt(l.logCountAttribute, lc.count)
 
-			// Add attributes for log count and timestamps
-			lr.SetObservedTimestamp(pcommon.NewTimestampFromTime(lc.firstObservedTimestamp))
-			lr.SetTimestamp(pcommon.NewTimestampFromTime(timeNow()))
-			lr.Attributes().PutInt(l.logCountAttribute, lc.count)
+	firstTimestampStr := lc.firstObservedTimestamp.In(l.timezone).Format(time.RFC3339)
+	lastTimestampStr := lc.lastObservedTimestamp.In(l.timezone).Format(time.RFC3339)
+	lr.Attributes().PutStr(firstObservedTSAttr, firstTimestampStr)
+	lr.Attributes().PutStr(lastObservedTSAttr, lastTimestampStr)
 
-			firstTimestampStr := lc.firstObservedTimestamp.In(l.timezone).Format(time.RFC3339)
-			lastTimestampStr := lc.lastObservedTimestamp.In(l.timezone).Format(time.RFC3339)
-			lr.Attributes().PutStr(firstObservedTSAttr, firstTimestampStr)
-			lr.Attributes().PutStr(lastObservedTSAttr, lastTimestampStr)
+	if l.uniqueField != nil {
+		uniqueValues := lr.Attributes().PutEmptySlice(l.uniqueValues.Attribute)
+		uniqueValues.EnsureCapacity(len(lc.uniqueValues))
+		for _, value := range lc.uniqueValues {
+			uniqueValues.AppendEmpty().SetStr(value)
 		}
 	}
-
-	return logs
 }
 
-// Add adds the logRecord to the resource aggregator that is identified by the resource attributes
-func (l *logAggregator) Add(resourceAttrs pcommon.Map, logRecord plog.LogRecord) {
-	key := pdatautil.MapHash(resourceAttrs)
-	resourceCounter, ok := l.resources[key]
+// Add adds the logRecord to the resource aggregator that is identified by the resource attributes.
+// It returns false if the logRecord was not aggregated because the max unique entries was reached.
+func (l *logAggregator) Add(resourceAttrs pcommon.Map, logRecord plog.LogRecord) bool {
+	resourceKey := pdatautil.MapHash(resourceAttrs)
+	logKey := l.getLogKey(logRecord)
+
+	resourceCounter, ok := l.resources[resourceKey]
+	if ok {
+		if lc, ok := resourceCounter.logCounters[logKey]; ok {
+			lc.Increment()
+			l.addUniqueValue(lc, logRecord)
+			l.lru.MoveToFront(lc.element)
+			return true
+		}
+	}
+
+	// Make room for a new unique log if the max has been reached
+	if l.maxEntries > 0 && l.lru.Len() >= l.maxEntries {
+		if l.overflowPolicy == overflowPolicyPassthrough {
+			return false
+		}
+
+		l.evictOldest()
+
+		// Eviction may have removed the resource
+		resourceCounter, ok = l.resources[resourceKey]
+	}
+
 	if !ok {
-		resourceCounter = newResourceAggregator(resourceAttrs)
-		l.resources[key] = resourceCounter
+		// Copy the attributes as the resource may be passed to the next consumer
+		attrs := pcommon.NewMap()
+		resourceAttrs.CopyTo(attrs)
+		resourceCounter = newResourceAggregator(attrs)
+		resourceCounter.key = resourceKey
+		l.resources[resourceKey] = resourceCounter
+	}
+
+	lc := resourceCounter.Add(logKey, logRecord)
+	lc.element = l.lru.PushFront(lc)
+	l.addUniqueValue(lc, logRecord)
+	return true
+}
+
+// addUniqueValue records the unique value field of the logRecord on the counter if configured
+func (l *logAggregator) addUniqueValue(lc *logCounter, logRecord plog.LogRecord) {
+	if l.uniqueField == nil {
+		return
+	}
+
+	if value, ok := l.uniqueField.getValue(logRecord); ok {
+		lc.AddUniqueValue(value.AsString(), l.uniqueValues.Limit)
+	}
+}
+
+// evictOldest removes the least recently updated log counter and adds its aggregated log to the evicted logs
+func (l *logAggregator) evictOldest() {
+	element := l.lru.Back()
+	if element == nil {
+		return
+	}
+
+	lc := l.lru.Remove(element).(*logCounter)
+	resource := lc.resource
+	delete(resource.logCounters, lc.key)
+	if len(resource.logCounters) == 0 {
+		delete(l.resources, resource.key)
 	}
 
-	resourceCounter.Add(logRecord)
+	resourceLogs := l.evicted.ResourceLogs().AppendEmpty()
+	resource.attributes.CopyTo(resourceLogs.Resource().Attributes())
+	l.exportLogCounter(resourceLogs.ScopeLogs().AppendEmpty(), lc)
+}
+
+// TakeEvicted returns the aggregated logs evicted since it was last called.
+func (l *logAggregator) TakeEvicted() plog.Logs {
+	evicted := l.evicted
+	l.evicted = plog.NewLogs()
+	return evicted
+}
+
+// getLogKey returns the key of the log record using the include fields if any are configured
+func (l *logAggregator) getLogKey(logRecord plog.LogRecord) [8]byte {
+	if len(l.includeFields) == 0 {
+		return getLogKey(logRecord)
+	}
+
+	return getIncludeKey(logRecord, l.includeFields)
 }
 
 // Reset resets the counter.
 func (l *logAggregator) Reset() {
 	l.resources = make(map[[16]byte]*resourceAggregator)
+	l.lru.Init()
 }
 
 // resourceAggregator dimensions the counter by resource.
 type resourceAggregator struct {
+	key         [16]byte
 	attributes  pcommon.Map
 	logCounters map[[8]byte]*logCounter
 }
@@ -121,16 +234,18 @@ func newResourceAggregator(attributes pcommon.Map) *resourceAggregator {
 	}
 }
 
-// Add increments the counter that the logRecord matches.
-func (r *resourceAggregator) Add(logRecord plog.LogRecord) {
-	key := getLogKey(logRecord)
+// Add increments the counter that matches the key and returns it.
+func (r *resourceAggregator) Add(key [8]byte, logRecord plog.LogRecord) *logCounter {
 	lc, ok := r.logCounters[key]
 	if !ok {
 		lc = newLogCounter(logRecord)
 		lc.firstObservedTimestamp = timeNow().UTC()
+		lc.key = key
+		lc.resource = r
 		r.logCounters[key] = lc
 	}
 	lc.Increment()
+	return lc
 }
 
 // logCounter is a counter for a log record.
@@ -139,6 +254,12 @@ type logCounter struct {
 	firstObservedTimestamp time.Time
 	lastObservedTimestamp  time.Time
 	count                  int64
+	uniqueValues           []string
+
+	// key, resource, and element locate the counter for eviction
+	key      [8]byte
+	resource *resourceAggregator
+	element  *list.Element
 }
 
 // newLogCounter creates a new AttributeCounter.
@@ -155,6 +276,21 @@ func (a *logCounter) Increment() {
 	a.count++
 }
 
+// AddUniqueValue records the value if it hasn't been seen and the limit hasn't been reached.
+func (a *logCounter) AddUniqueValue(value string, limit int) {
+	if len(a.uniqueValues) >= limit {
+		return
+	}
+
+	for _, v := range a.uniqueValues {
+		if v == value {
+			return
+		}
+	}
+
+	a.uniqueValues = append(a.uniqueValues, value)
+}
+
 // getLogKey creates a unique md5 hash for the log record to use as a map key
 /* #nosec G104 -- According to Hash interface write can never return an error */
 func getLogKey(logRecord plog.LogRecord) [8]byte {
@@ -173,3 +309,26 @@ func getLogKey(logRecord plog.LogRecord) [8]byte {
 	copy(key[:], hash)
 	return key
 }
+
+// getIncludeKey creates a unique hash for the log record using only the values of the include fields
+/* #nosec G104 -- According to Hash interface write can never return an error */
+func getIncludeKey(logRecord plog.LogRecord, includeFields []*field) [8]byte {
+	hasher := fnv.New64()
+	for _, f := range includeFields {
+		value, ok := f.getValue(logRecord)
+		if !ok {
+			// Distinguish a missing field from one with an empty value
+			hasher.Write([]byte{0})
+			continue
+		}
+
+		hasher.Write([]byte{1})
+		valueHash := pdatautil.ValueHash(value)
+		hasher.Write(valueHash[:])
+	}
+	hash := hasher.Sum(nil)
+
+	var key [8]byte
+	copy(key[:], hash)
+	return key
+}
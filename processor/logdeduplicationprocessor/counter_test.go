@@ -15,6 +15,8 @@
 package logdeduplicationprocessor
 
 import (
+	"fmt"
+	"runtime"
 	"testing"
 	"time"
 
@@ -26,7 +28,7 @@ import (
 
 func Test_newLogAggregator(t *testing.T) {
 	cfg := createDefaultConfig().(*Config)
-	aggregator := newLogAggregator(cfg.LogCountAttribute, time.UTC)
+	aggregator := newLogAggregator(cfg, time.UTC)
 	require.Equal(t, cfg.LogCountAttribute, aggregator.logCountAttribute)
 	require.Equal(t, time.UTC, aggregator.timezone)
 	require.NotNil(t, aggregator.resources)
@@ -45,7 +47,7 @@ func Test_logAggregatorAdd(t *testing.T) {
 	}
 
 	// Setup aggregator
-	aggregator := newLogAggregator("log_count", time.UTC)
+	aggregator := newLogAggregator(&Config{LogCountAttribute: "log_count"}, time.UTC)
 	logRecord := plog.NewLogRecord()
 	resourceAttrs := pcommon.NewMap()
 	resourceAttrs.PutStr("one", "two")
@@ -83,7 +85,7 @@ func Test_logAggregatorAdd(t *testing.T) {
 }
 
 func Test_logAggregatorReset(t *testing.T) {
-	aggregator := newLogAggregator("log_count", time.UTC)
+	aggregator := newLogAggregator(&Config{LogCountAttribute: "log_count"}, time.UTC)
 	for i := 0; i < 2; i++ {
 		resourceAttrs := pcommon.NewMap()
 		resourceAttrs.PutInt("i", int64(i))
@@ -116,7 +118,7 @@ func Test_logAggregatorExport(t *testing.T) {
 
 	// Setup aggregator
 
-	aggregator := newLogAggregator(defaultLogCountAttribute, location)
+	aggregator := newLogAggregator(&Config{LogCountAttribute: defaultLogCountAttribute}, location)
 	resourceAttrs := pcommon.NewMap()
 	resourceAttrs.PutStr("one", "two")
 	expectedHash := pdatautil.MapHash(resourceAttrs)
@@ -226,6 +228,168 @@ func Test_getLogKey(t *testing.T) {
 	}
 }
 
+func Test_getIncludeKey(t *testing.T) {
+	includeFields := newFields([]string{"body.message", "attributes.host"})
+
+	newRecord := func(message, host, requestID string) plog.LogRecord {
+		logRecord := plog.NewLogRecord()
+		logRecord.Body().SetEmptyMap().PutStr("message", message)
+		logRecord.Body().Map().PutStr("request_id", requestID)
+		if host != "" {
+			logRecord.Attributes().PutStr("host", host)
+		}
+		return logRecord
+	}
+
+	key := getIncludeKey(newRecord("failed", "host1", "1"), includeFields)
+	require.Equal(t, key, getIncludeKey(newRecord("failed", "host1", "2"), includeFields))
+	require.NotEqual(t, key, getIncludeKey(newRecord("failed", "host2", "1"), includeFields))
+	require.NotEqual(t, key, getIncludeKey(newRecord("succeeded", "host1", "1"), includeFields))
+	require.NotEqual(t, getIncludeKey(newRecord("failed", "", "1"), includeFields), getIncludeKey(newRecord("failed", "host1", "1"), includeFields))
+}
+
+func Test_logAggregatorUniqueValues(t *testing.T) {
+	uniqueValues := UniqueValues{
+		Field:     "attributes.user",
+		Attribute: defaultUniqueValuesAttribute,
+		Limit:     2,
+	}
+	aggregator := newLogAggregator(&Config{LogCountAttribute: defaultLogCountAttribute, IncludeFields: []string{"body"}, UniqueValues: uniqueValues}, time.UTC)
+	resourceAttrs := pcommon.NewMap()
+
+	for _, user := range []string{"alice", "bob", "alice", "carol"} {
+		logRecord := plog.NewLogRecord()
+		logRecord.Body().SetStr("login failed")
+		logRecord.Attributes().PutStr("user", user)
+		aggregator.Add(resourceAttrs, logRecord)
+	}
+
+	exportedLogs := aggregator.Export()
+	require.Equal(t, 1, exportedLogs.LogRecordCount())
+
+	attrs := exportedLogs.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0).Attributes()
+	count, ok := attrs.Get(defaultLogCountAttribute)
+	require.True(t, ok)
+	require.Equal(t, int64(4), count.Int())
+
+	actualValues, ok := attrs.Get(defaultUniqueValuesAttribute)
+	require.True(t, ok)
+	require.Equal(t, []any{"alice", "bob"}, actualValues.Slice().AsRaw())
+}
+
+func Test_logAggregatorEvict(t *testing.T) {
+	cfg := &Config{
+		LogCountAttribute: defaultLogCountAttribute,
+		MaxUniqueEntries:  2,
+		OverflowPolicy:    overflowPolicyEvict,
+	}
+	aggregator := newLogAggregator(cfg, time.UTC)
+
+	resourceAttrs := pcommon.NewMap()
+	resourceAttrs.PutStr("one", "two")
+
+	require.True(t, aggregator.Add(resourceAttrs, generateTestLogRecord(t, "first")))
+	require.True(t, aggregator.Add(resourceAttrs, generateTestLogRecord(t, "second")))
+
+	// Updating the first log makes the second the least recently updated
+	require.True(t, aggregator.Add(resourceAttrs, generateTestLogRecord(t, "first")))
+	require.Equal(t, 0, aggregator.TakeEvicted().LogRecordCount())
+
+	require.True(t, aggregator.Add(resourceAttrs, generateTestLogRecord(t, "third")))
+	require.Equal(t, 2, aggregator.lru.Len())
+
+	evicted := aggregator.TakeEvicted()
+	require.Equal(t, 1, evicted.LogRecordCount())
+	require.Equal(t, map[string]any{"one": "two"}, evicted.ResourceLogs().At(0).Resource().Attributes().AsRaw())
+	require.Equal(t, "second", evicted.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0).Body().Str())
+	require.Equal(t, 0, aggregator.TakeEvicted().LogRecordCount())
+
+	exported := aggregator.Export()
+	require.Equal(t, 2, exported.LogRecordCount())
+
+	aggregator.Reset()
+	require.Equal(t, 0, aggregator.lru.Len())
+}
+
+func Test_logAggregatorEvictResource(t *testing.T) {
+	cfg := &Config{
+		LogCountAttribute: defaultLogCountAttribute,
+		MaxUniqueEntries:  1,
+	}
+	aggregator := newLogAggregator(cfg, time.UTC)
+
+	resourceAttrs := pcommon.NewMap()
+	resourceAttrs.PutStr("one", "two")
+
+	// Evicting the only log of the resource removes the resource before the new log re-adds it
+	require.True(t, aggregator.Add(resourceAttrs, generateTestLogRecord(t, "first")))
+	require.True(t, aggregator.Add(resourceAttrs, generateTestLogRecord(t, "second")))
+	require.Len(t, aggregator.resources, 1)
+	require.Equal(t, 1, aggregator.TakeEvicted().LogRecordCount())
+
+	exported := aggregator.Export()
+	require.Equal(t, 1, exported.LogRecordCount())
+	require.Equal(t, "second", exported.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0).Body().Str())
+}
+
+func Test_logAggregatorPassthrough(t *testing.T) {
+	cfg := &Config{
+		LogCountAttribute: defaultLogCountAttribute,
+		MaxUniqueEntries:  1,
+		OverflowPolicy:    overflowPolicyPassthrough,
+	}
+	aggregator := newLogAggregator(cfg, time.UTC)
+	resourceAttrs := pcommon.NewMap()
+
+	require.True(t, aggregator.Add(resourceAttrs, generateTestLogRecord(t, "first")))
+	require.False(t, aggregator.Add(resourceAttrs, generateTestLogRecord(t, "second")))
+
+	// Logs matching an existing aggregate are still aggregated
+	require.True(t, aggregator.Add(resourceAttrs, generateTestLogRecord(t, "first")))
+	require.Equal(t, 0, aggregator.TakeEvicted().LogRecordCount())
+
+	exported := aggregator.Export()
+	require.Equal(t, 1, exported.LogRecordCount())
+}
+
+// BenchmarkLogAggregatorCardinality adds logs that are all unique, such as logs containing request IDs.
+// With max_unique_entries set, the retained heap stays flat as the number of unique logs grows.
+func BenchmarkLogAggregatorCardinality(b *testing.B) {
+	for _, maxEntries := range []int{0, 1000} {
+		b.Run(fmt.Sprintf("max_unique_entries=%d", maxEntries), func(b *testing.B) {
+			cfg := &Config{
+				LogCountAttribute: defaultLogCountAttribute,
+				MaxUniqueEntries:  maxEntries,
+				OverflowPolicy:    overflowPolicyEvict,
+			}
+			aggregator := newLogAggregator(cfg, time.UTC)
+			resourceAttrs := pcommon.NewMap()
+			resourceAttrs.PutStr("host.name", "host1")
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				logRecord := plog.NewLogRecord()
+				logRecord.Body().SetStr(fmt.Sprintf("request %d failed", i))
+				aggregator.Add(resourceAttrs, logRecord)
+
+				// Evicted logs are sent on after each batch by the processor
+				if i%100 == 0 {
+					aggregator.TakeEvicted()
+				}
+			}
+			b.StopTimer()
+
+			aggregator.TakeEvicted()
+			runtime.GC()
+			var memStats runtime.MemStats
+			runtime.ReadMemStats(&memStats)
+			b.ReportMetric(float64(memStats.HeapInuse), "heap-bytes")
+			b.ReportMetric(float64(aggregator.lru.Len()), "entries")
+		})
+	}
+}
+
 func generateTestLogRecord(t *testing.T, body string) plog.LogRecord {
 	t.Helper()
 	logRecord := plog.NewLogRecord()
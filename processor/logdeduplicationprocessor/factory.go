@@ -47,5 +47,5 @@ func createLogsProcessor(_ context.Context, params processor.CreateSettings, cfg
 		return nil, fmt.Errorf("invalid config type: %+v", cfg)
 	}
 
-	return newProcessor(processorCfg, consumer, params.Logger)
+	return newProcessor(processorCfg, consumer, params)
 }
@@ -42,17 +42,21 @@ type field struct {
 
 // newFieldRemover creates a new field remover based on the passed in field keys
 func newFieldRemover(fieldKeys []string) *fieldRemover {
-	fe := &fieldRemover{
-		fields: make([]*field, 0, len(fieldKeys)),
+	return &fieldRemover{
+		fields: newFields(fieldKeys),
 	}
+}
 
+// newFields creates fields from the passed in field keys
+func newFields(fieldKeys []string) []*field {
+	fields := make([]*field, 0, len(fieldKeys))
 	for _, f := range fieldKeys {
-		fe.fields = append(fe.fields, &field{
+		fields = append(fields, &field{
 			keyParts: splitField(f),
 		})
 	}
 
-	return fe
+	return fields
 }
 
 // RemoveFields removes any body or attribute fields that match in the log record
@@ -84,6 +88,54 @@ func (f *field) removeField(logRecord plog.LogRecord) {
 	}
 }
 
+// getValue returns the value of the field in the log record if it exists
+func (f *field) getValue(logRecord plog.LogRecord) (pcommon.Value, bool) {
+	firstPart, remainingParts := f.keyParts[0], f.keyParts[1:]
+
+	switch firstPart {
+	case bodyField:
+		if len(remainingParts) == 0 {
+			return logRecord.Body(), true
+		}
+
+		// If body is a map then recurse through to find the field
+		if logRecord.Body().Type() == pcommon.ValueTypeMap {
+			return getValueFromMap(logRecord.Body().Map(), remainingParts)
+		}
+	case attributeField:
+		// Return all attributes as a map value
+		if len(remainingParts) == 0 {
+			value := pcommon.NewValueMap()
+			logRecord.Attributes().CopyTo(value.Map())
+			return value, true
+		}
+
+		return getValueFromMap(logRecord.Attributes(), remainingParts)
+	}
+
+	return pcommon.NewValueEmpty(), false
+}
+
+// getValueFromMap recurses through the map and returns the field's value if it's found.
+func getValueFromMap(valueMap pcommon.Map, keyParts []string) (pcommon.Value, bool) {
+	nextKeyPart, remainingParts := keyParts[0], keyParts[1:]
+
+	value, ok := valueMap.Get(nextKeyPart)
+	if !ok {
+		return pcommon.NewValueEmpty(), false
+	}
+
+	if len(remainingParts) == 0 {
+		return value, true
+	}
+
+	if value.Type() == pcommon.ValueTypeMap {
+		return getValueFromMap(value.Map(), remainingParts)
+	}
+
+	return pcommon.NewValueEmpty(), false
+}
+
 // removeFieldFromMap recurses through the map and removes the field if it's found.
 func removeFieldFromMap(valueMap pcommon.Map, keyParts []string) {
 	nextKeyPart, remainingParts := keyParts[0], keyParts[1:]
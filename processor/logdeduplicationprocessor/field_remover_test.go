@@ -108,3 +108,59 @@ func TestRemoveFields(t *testing.T) {
 	require.Equal(t, expectedAttrHash, actualAttrHash)
 	require.Equal(t, expectedBodyHash, actualBodyHash)
 }
+
+func TestGetValue(t *testing.T) {
+	logRecord := plog.NewLogRecord()
+	logRecord.Body().SetEmptyMap().PutEmptyMap("nested").PutStr("key", "value")
+	logRecord.Attributes().PutStr("host.name", "host1")
+
+	testCases := []struct {
+		desc     string
+		field    string
+		expected any
+		found    bool
+	}{
+		{
+			desc:     "Entire body",
+			field:    bodyField,
+			expected: map[string]any{"nested": map[string]any{"key": "value"}},
+			found:    true,
+		},
+		{
+			desc:     "Nested body field",
+			field:    "body.nested.key",
+			expected: "value",
+			found:    true,
+		},
+		{
+			desc:  "Missing body field",
+			field: "body.nested.missing",
+		},
+		{
+			desc:  "Body field below a non map value",
+			field: "body.nested.key.deeper",
+		},
+		{
+			desc:     "Entire attributes",
+			field:    attributeField,
+			expected: map[string]any{"host.name": "host1"},
+			found:    true,
+		},
+		{
+			desc:     "Escaped attribute field",
+			field:    "attributes.host\\.name",
+			expected: "host1",
+			found:    true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			value, ok := newFields([]string{tc.field})[0].getValue(logRecord)
+			require.Equal(t, tc.found, ok)
+			if tc.found {
+				require.Equal(t, tc.expected, value.AsRaw())
+			}
+		})
+	}
+}
@@ -20,17 +20,33 @@ import (
 	"sync"
 	"time"
 
+	"github.com/observiq/bindplane-agent/expr"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottllog"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/processor"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
 	"go.uber.org/zap"
 )
 
+const (
+	// scopeName is the instrumentation scope of the processor's metrics
+	scopeName = "github.com/observiq/bindplane-agent/processor/logdeduplicationprocessor"
+
+	// evictionsMetricName is the name of the metric counting logs that could not be aggregated within max_unique_entries
+	evictionsMetricName = "processor/logdedup/evictions"
+)
+
 // logDedupProcessor is a logDedupProcessor that counts duplicate instances of logs.
 type logDedupProcessor struct {
 	emitInterval time.Duration
 	aggregator   *logAggregator
 	remover      *fieldRemover
+	match        *expr.OTTLCondition[ottllog.TransformContext]
+	evictions    metric.Int64Counter
+	evictionAttr metric.MeasurementOption
 	consumer     consumer.Logs
 	logger       *zap.Logger
 	cancel       context.CancelFunc
@@ -38,19 +54,47 @@ type logDedupProcessor struct {
 	mux          sync.Mutex
 }
 
-func newProcessor(cfg *Config, consumer consumer.Logs, logger *zap.Logger) (*logDedupProcessor, error) {
+func newProcessor(cfg *Config, consumer consumer.Logs, set processor.CreateSettings) (*logDedupProcessor, error) {
 	// This should not happen due to config validation but we check anyways.
 	timezone, err := time.LoadLocation(cfg.Timezone)
 	if err != nil {
 		return nil, fmt.Errorf("invalid timezone: %w", err)
 	}
 
+	var match *expr.OTTLCondition[ottllog.TransformContext]
+	if cfg.OTTLMatch != "" {
+		match, err = expr.NewOTTLLogRecordCondition(cfg.OTTLMatch, set.TelemetrySettings)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ottl_match: %w", err)
+		}
+	}
+
+	evictions, err := set.MeterProvider.Meter(scopeName).Int64Counter(
+		evictionsMetricName,
+		metric.WithDescription("Number of logs evicted or passed through unaggregated because max_unique_entries was reached"),
+		metric.WithUnit("{logs}"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("create evictions metric: %w", err)
+	}
+
+	policy := cfg.OverflowPolicy
+	if policy == "" {
+		policy = overflowPolicyEvict
+	}
+
 	return &logDedupProcessor{
 		emitInterval: cfg.Interval,
-		aggregator:   newLogAggregator(cfg.LogCountAttribute, timezone),
+		aggregator:   newLogAggregator(cfg, timezone),
 		remover:      newFieldRemover(cfg.ExcludeFields),
-		consumer:     consumer,
-		logger:       logger,
+		match:        match,
+		evictions:    evictions,
+		evictionAttr: metric.WithAttributes(
+			attribute.String("processor", set.ID.String()),
+			attribute.String("policy", policy),
+		),
+		consumer: consumer,
+		logger:   set.Logger,
 	}, nil
 }
 
@@ -70,7 +114,7 @@ func (p *logDedupProcessor) Capabilities() consumer.Capabilities {
 	return consumer.Capabilities{MutatesData: true}
 }
 
-// Shutdown stops the processor.
+// Shutdown stops the processor and flushes any remaining aggregated logs.
 func (p *logDedupProcessor) Shutdown(ctx context.Context) error {
 
 	p.cancel()
@@ -85,33 +129,86 @@ func (p *logDedupProcessor) Shutdown(ctx context.Context) error {
 	case <-ctx.Done():
 		return ctx.Err()
 	case <-doneChan:
+		p.exportLogs(ctx)
 		return nil
 	}
 }
 
 // ConsumeLogs processes the logs.
-func (p *logDedupProcessor) ConsumeLogs(_ context.Context, pl plog.Logs) error {
+// Logs that don't match the ottl_match condition, or that overflow max_unique_entries, are passed to the next consumer without being deduplicated.
+func (p *logDedupProcessor) ConsumeLogs(ctx context.Context, pl plog.Logs) error {
+	var overflowed int64
+
 	p.mux.Lock()
-	defer p.mux.Unlock()
 
 	for i := 0; i < pl.ResourceLogs().Len(); i++ {
 		resourceLogs := pl.ResourceLogs().At(i)
 		resourceAttrs := resourceLogs.Resource().Attributes()
 		for j := 0; j < resourceLogs.ScopeLogs().Len(); j++ {
 			scope := resourceLogs.ScopeLogs().At(j)
-			logs := scope.LogRecords()
-			for k := 0; k < logs.Len(); k++ {
-				logRecord := logs.At(k)
-				// Remove excluded fields if any
-				p.remover.RemoveFields(logRecord)
-
-				// Add the log to the aggregator
-				p.aggregator.Add(resourceAttrs, logRecord)
-			}
+			scope.LogRecords().RemoveIf(func(logRecord plog.LogRecord) bool {
+				if !p.matches(ctx, logRecord, scope, resourceLogs) {
+					return false
+				}
+
+				// Remove excluded fields from a copy, so the log is passed through unchanged if it isn't aggregated
+				dedupRecord := logRecord
+				if len(p.remover.fields) > 0 {
+					dedupRecord = plog.NewLogRecord()
+					logRecord.CopyTo(dedupRecord)
+					p.remover.RemoveFields(dedupRecord)
+				}
+
+				// Add the log to the aggregator. It is passed through if the aggregator is full.
+				if !p.aggregator.Add(resourceAttrs, dedupRecord) {
+					overflowed++
+					return false
+				}
+				return true
+			})
 		}
 	}
 
-	return nil
+	// Aggregates evicted to make room for new unique logs are sent along with the logs passed through
+	evicted := p.aggregator.TakeEvicted()
+
+	p.mux.Unlock()
+
+	overflowed += int64(evicted.LogRecordCount())
+	if overflowed > 0 {
+		p.evictions.Add(ctx, overflowed, p.evictionAttr)
+	}
+	evicted.ResourceLogs().MoveAndAppendTo(pl.ResourceLogs())
+
+	// Remove any resources and scopes that are now empty
+	pl.ResourceLogs().RemoveIf(func(resourceLogs plog.ResourceLogs) bool {
+		resourceLogs.ScopeLogs().RemoveIf(func(scope plog.ScopeLogs) bool {
+			return scope.LogRecords().Len() == 0
+		})
+		return resourceLogs.ScopeLogs().Len() == 0
+	})
+
+	if pl.ResourceLogs().Len() == 0 {
+		return nil
+	}
+
+	return p.consumer.ConsumeLogs(ctx, pl)
+}
+
+// matches returns true if the log record should be deduplicated.
+func (p *logDedupProcessor) matches(ctx context.Context, logRecord plog.LogRecord, scope plog.ScopeLogs, resourceLogs plog.ResourceLogs) bool {
+	if p.match == nil {
+		return true
+	}
+
+	logCtx := ottllog.NewTransformContext(logRecord, scope.Scope(), resourceLogs.Resource())
+	match, err := p.match.Match(ctx, logCtx)
+	if err != nil {
+		p.logger.Error("Error while matching OTTL log", zap.Error(err))
+		return false
+	}
+
+	return match
 }
 
 // handleExportInterval sends metrics at the configured interval.
@@ -126,18 +223,23 @@ func (p *logDedupProcessor) handleExportInterval(ctx context.Context) {
 		case <-ctx.Done():
 			return
 		case <-ticker.C:
-			p.mux.Lock()
-
-			logs := p.aggregator.Export()
-			// Only send logs if we have some
-			if logs.LogRecordCount() > 0 {
-				err := p.consumer.ConsumeLogs(ctx, logs)
-				if err != nil {
-					p.logger.Error("failed to consume logs", zap.Error(err))
-				}
-			}
-			p.aggregator.Reset()
-			p.mux.Unlock()
+			p.exportLogs(ctx)
+		}
+	}
+}
+
+// exportLogs sends the aggregated logs to the next consumer and resets the aggregator.
+func (p *logDedupProcessor) exportLogs(ctx context.Context) {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+
+	logs := p.aggregator.Export()
+	// Only send logs if we have some
+	if logs.LogRecordCount() > 0 {
+		err := p.consumer.ConsumeLogs(ctx, logs)
+		if err != nil {
+			p.logger.Error("failed to consume logs", zap.Error(err))
 		}
 	}
+	p.aggregator.Reset()
 }
@@ -27,7 +27,9 @@ import (
 	"go.opentelemetry.io/collector/consumer/consumertest"
 	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/plog"
-	"go.uber.org/zap"
+	"go.opentelemetry.io/collector/processor/processortest"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
 )
 
 func Test_newProcessor(t *testing.T) {
@@ -47,6 +49,17 @@ func Test_newProcessor(t *testing.T) {
 			expected:    nil,
 			expectedErr: errors.New("invalid timezone"),
 		},
+		{
+			desc: "OTTL match error",
+			cfg: &Config{
+				LogCountAttribute: defaultLogCountAttribute,
+				Interval:          defaultInterval,
+				Timezone:          defaultTimezone,
+				OTTLMatch:         "not a condition",
+			},
+			expected:    nil,
+			expectedErr: errors.New("invalid ottl_match"),
+		},
 		{
 			desc: "valid config",
 			cfg: &Config{
@@ -64,14 +77,14 @@ func Test_newProcessor(t *testing.T) {
 	for _, tc := range testCases {
 		t.Run(tc.desc, func(t *testing.T) {
 			logsSink := &consumertest.LogsSink{}
-			logger := zap.NewNop()
+			settings := processortest.NewNopCreateSettings()
 
 			if tc.expected != nil {
 				tc.expected.consumer = logsSink
-				tc.expected.logger = logger
+				tc.expected.logger = settings.Logger
 			}
 
-			actual, err := newProcessor(tc.cfg, logsSink, logger)
+			actual, err := newProcessor(tc.cfg, logsSink, settings)
 			if tc.expectedErr != nil {
 				require.ErrorContains(t, err, tc.expectedErr.Error())
 				require.Nil(t, actual)
@@ -92,7 +105,6 @@ func TestProcessorShutdownCtxError(t *testing.T) {
 	cancel()
 
 	logsSink := &consumertest.LogsSink{}
-	logger := zap.NewNop()
 	cfg := &Config{
 		LogCountAttribute: defaultLogCountAttribute,
 		Interval:          1 * time.Second,
@@ -100,7 +112,7 @@ func TestProcessorShutdownCtxError(t *testing.T) {
 	}
 
 	// Create a processor
-	p, err := newProcessor(cfg, logsSink, logger)
+	p, err := newProcessor(cfg, logsSink, processortest.NewNopCreateSettings())
 	require.NoError(t, err)
 
 	// We don't call p.Start as it can create a non-deterministic situation in Shutdown where we may not exit due to ctx error
@@ -125,7 +137,6 @@ func TestProcessorCapabilities(t *testing.T) {
 
 func TestProcessorConsume(t *testing.T) {
 	logsSink := &consumertest.LogsSink{}
-	logger := zap.NewNop()
 	cfg := &Config{
 		LogCountAttribute: defaultLogCountAttribute,
 		Interval:          1 * time.Second,
@@ -136,7 +147,7 @@ func TestProcessorConsume(t *testing.T) {
 	}
 
 	// Create a processor
-	p, err := newProcessor(cfg, logsSink, logger)
+	p, err := newProcessor(cfg, logsSink, processortest.NewNopCreateSettings())
 	require.NoError(t, err)
 
 	err = p.Start(context.Background(), componenttest.NewNopHost())
@@ -188,3 +199,154 @@ func TestProcessorConsume(t *testing.T) {
 	err = p.Shutdown(context.Background())
 	require.NoError(t, err)
 }
+
+func TestProcessorShutdownFlush(t *testing.T) {
+	logsSink := &consumertest.LogsSink{}
+	cfg := &Config{
+		LogCountAttribute: defaultLogCountAttribute,
+		Interval:          time.Hour,
+		Timezone:          defaultTimezone,
+	}
+
+	p, err := newProcessor(cfg, logsSink, processortest.NewNopCreateSettings())
+	require.NoError(t, err)
+
+	err = p.Start(context.Background(), componenttest.NewNopHost())
+	require.NoError(t, err)
+
+	logs := plog.NewLogs()
+	sl := logs.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty()
+	generateTestLogRecord(t, "Body of the log").CopyTo(sl.LogRecords().AppendEmpty())
+
+	err = p.ConsumeLogs(context.Background(), logs)
+	require.NoError(t, err)
+	require.Equal(t, 0, logsSink.LogRecordCount())
+
+	// Aggregated logs are flushed on shutdown instead of waiting for the interval
+	err = p.Shutdown(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 1, logsSink.LogRecordCount())
+}
+
+func TestProcessorConsumeOTTLMatch(t *testing.T) {
+	logsSink := &consumertest.LogsSink{}
+	cfg := &Config{
+		LogCountAttribute: defaultLogCountAttribute,
+		Interval:          time.Hour,
+		Timezone:          defaultTimezone,
+		OTTLMatch:         `severity_number >= SEVERITY_NUMBER_ERROR`,
+	}
+
+	p, err := newProcessor(cfg, logsSink, processortest.NewNopCreateSettings())
+	require.NoError(t, err)
+
+	err = p.Start(context.Background(), componenttest.NewNopHost())
+	require.NoError(t, err)
+
+	logs := plog.NewLogs()
+	rl := logs.ResourceLogs().AppendEmpty()
+	rl.Resource().Attributes().PutStr("host", "host1")
+	records := rl.ScopeLogs().AppendEmpty().LogRecords()
+	for i := 0; i < 3; i++ {
+		errorRecord := records.AppendEmpty()
+		errorRecord.Body().SetStr("connection refused")
+		errorRecord.SetSeverityNumber(plog.SeverityNumberError)
+	}
+	infoRecord := records.AppendEmpty()
+	infoRecord.Body().SetStr("request complete")
+	infoRecord.SetSeverityNumber(plog.SeverityNumberInfo)
+
+	// Logs that don't match are passed through immediately
+	err = p.ConsumeLogs(context.Background(), logs)
+	require.NoError(t, err)
+	require.Len(t, logsSink.AllLogs(), 1)
+	passedThrough := logsSink.AllLogs()[0]
+	require.Equal(t, 1, passedThrough.LogRecordCount())
+	require.Equal(t, "request complete", passedThrough.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0).Body().Str())
+
+	// Logs that match are deduplicated
+	err = p.Shutdown(context.Background())
+	require.NoError(t, err)
+	require.Len(t, logsSink.AllLogs(), 2)
+	deduplicated := logsSink.AllLogs()[1].ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0)
+	require.Equal(t, "connection refused", deduplicated.Body().Str())
+	count, ok := deduplicated.Attributes().Get(defaultLogCountAttribute)
+	require.True(t, ok)
+	require.Equal(t, int64(3), count.Int())
+}
+
+func TestProcessorConsumeOverflow(t *testing.T) {
+	testCases := []struct {
+		desc           string
+		overflowPolicy string
+		expectedBodies []string
+	}{
+		{
+			desc:           "Evict emits the oldest aggregate",
+			overflowPolicy: overflowPolicyEvict,
+			expectedBodies: []string{"first"},
+		},
+		{
+			desc:           "Passthrough passes the new log through",
+			overflowPolicy: overflowPolicyPassthrough,
+			expectedBodies: []string{"second"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			logsSink := &consumertest.LogsSink{}
+			reader := sdkmetric.NewManualReader()
+			settings := processortest.NewNopCreateSettings()
+			settings.MeterProvider = sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+			cfg := &Config{
+				LogCountAttribute: defaultLogCountAttribute,
+				Interval:          time.Hour,
+				Timezone:          defaultTimezone,
+				MaxUniqueEntries:  1,
+				OverflowPolicy:    tc.overflowPolicy,
+				ExcludeFields:     []string{"attributes.str"},
+			}
+
+			p, err := newProcessor(cfg, logsSink, settings)
+			require.NoError(t, err)
+
+			logs := plog.NewLogs()
+			records := logs.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords()
+			generateTestLogRecord(t, "first").CopyTo(records.AppendEmpty())
+			generateTestLogRecord(t, "second").CopyTo(records.AppendEmpty())
+
+			err = p.ConsumeLogs(context.Background(), logs)
+			require.NoError(t, err)
+
+			require.Len(t, logsSink.AllLogs(), 1)
+			consumed := logsSink.AllLogs()[0]
+			bodies := []string{}
+			for i := 0; i < consumed.ResourceLogs().Len(); i++ {
+				consumedRecords := consumed.ResourceLogs().At(i).ScopeLogs().At(0).LogRecords()
+				for j := 0; j < consumedRecords.Len(); j++ {
+					bodies = append(bodies, consumedRecords.At(j).Body().Str())
+
+					// Excluded fields are only removed from aggregated logs
+					_, ok := consumedRecords.At(j).Attributes().Get("str")
+					require.Equal(t, tc.overflowPolicy == overflowPolicyPassthrough, ok)
+				}
+			}
+			require.Equal(t, tc.expectedBodies, bodies)
+
+			var rm metricdata.ResourceMetrics
+			require.NoError(t, reader.Collect(context.Background(), &rm))
+			require.Len(t, rm.ScopeMetrics, 1)
+			require.Len(t, rm.ScopeMetrics[0].Metrics, 1)
+			require.Equal(t, evictionsMetricName, rm.ScopeMetrics[0].Metrics[0].Name)
+
+			sum := rm.ScopeMetrics[0].Metrics[0].Data.(metricdata.Sum[int64])
+			require.Len(t, sum.DataPoints, 1)
+			require.Equal(t, int64(1), sum.DataPoints[0].Value)
+			policy, ok := sum.DataPoints[0].Attributes.Value("policy")
+			require.True(t, ok)
+			require.Equal(t, tc.overflowPolicy, policy.AsString())
+		})
+	}
+}
@@ -0,0 +1,75 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package maskprocessor
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"unicode"
+)
+
+// Actions applied to values matching a rule
+const (
+	// actionRedact replaces the value with the name of the rule.
+	actionRedact = "redact"
+
+	// actionHash replaces the value with a keyed HMAC of the value.
+	actionHash = "hash"
+
+	// actionPartial masks the value except for its last digits.
+	actionPartial = "partial"
+
+	// actionTokenize replaces the value with a random token that is consistent for the same value.
+	actionTokenize = "tokenize"
+)
+
+// partialDigits is the number of trailing digits kept by the partial action.
+const partialDigits = 4
+
+// partialMaskChar is the character that replaces masked characters with the partial action.
+const partialMaskChar = '*'
+
+// redactValue returns the redacted form of a value matching the rule.
+func redactValue(rule string) string {
+	return fmt.Sprintf("[masked_%s]", rule)
+}
+
+// hashValue returns the hex encoded HMAC-SHA256 of the value.
+func hashValue(secret []byte, value string) string {
+	mac := hmac.New(sha256.New, secret)
+	// Write to a hash never returns an error
+	_, _ = mac.Write([]byte(value))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// partialValue masks every letter and digit of the value except the last digits.
+// Other characters, such as separators, are kept so the value's format is preserved.
+func partialValue(value string) string {
+	runes := []rune(value)
+	kept := 0
+	for i := len(runes) - 1; i >= 0; i-- {
+		r := runes[i]
+		switch {
+		case unicode.IsDigit(r) && kept < partialDigits:
+			kept++
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			runes[i] = partialMaskChar
+		}
+	}
+
+	return string(runes)
+}
@@ -0,0 +1,59 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package maskprocessor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPartialValue(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		value    string
+		expected string
+	}{
+		{
+			desc:     "Credit card",
+			value:    "4111-1111-1111-1234",
+			expected: "****-****-****-1234",
+		},
+		{
+			desc:     "Phone with letters",
+			value:    "+1 (555) 123-4567 ext",
+			expected: "+* (***) ***-4567 ***",
+		},
+		{
+			desc:     "Fewer digits than kept",
+			value:    "a12",
+			expected: "*12",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			require.Equal(t, tc.expected, partialValue(tc.value))
+		})
+	}
+}
+
+func TestHashValue(t *testing.T) {
+	hash := hashValue([]byte("secret"), "value")
+	require.Len(t, hash, 64)
+	require.Equal(t, hash, hashValue([]byte("secret"), "value"))
+	require.NotEqual(t, hash, hashValue([]byte("other"), "value"))
+	require.NotEqual(t, hash, hashValue([]byte("secret"), "other"))
+}
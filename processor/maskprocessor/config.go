@@ -15,20 +15,90 @@
 // Package maskprocessor provides a processor that masks data.
 package maskprocessor
 
+import (
+	"errors"
+	"fmt"
+
+	"go.opentelemetry.io/collector/component"
+)
+
+var errMissingHashSecret = errors.New("hash_secret must be set when a rule uses the hash or tokenize action")
+
 // Config is the configuration for the processor.
 type Config struct {
 	// Rules are the rules used to mask values.
 	Rules map[string]string `mapstructure:"rules"`
 
+	// NamedRules are built-in rules to apply in addition to the default or custom rules.
+	NamedRules []string `mapstructure:"named_rules"`
+
 	// Exclude is a list of fields to exclude when masking.
 	Exclude []string `mapstructure:"exclude"`
+
+	// Include is a list of fields to mask. If empty, all fields are masked.
+	Include []string `mapstructure:"include"`
+
+	// Actions are the actions applied to values matching each rule, keyed by rule name.
+	// Rules without an action are redacted.
+	Actions map[string]string `mapstructure:"actions"`
+
+	// HashSecret is the secret key used by the hash action, and to key the tokens of the tokenize action.
+	HashSecret string `mapstructure:"hash_secret"`
+
+	// StorageID is the storage extension used to persist tokens created by the tokenize action.
+	StorageID *component.ID `mapstructure:"storage"`
 }
 
 // Validate validates the processor configuration.
 func (cfg Config) Validate() error {
+	ruleNames := make(map[string]struct{}, len(defaultRules))
+	for name := range defaultRules {
+		ruleNames[name] = struct{}{}
+	}
+
 	if len(cfg.Rules) > 0 {
-		_, err := compileRules(cfg.Rules)
-		return err
+		if _, err := compileRules(cfg.Rules); err != nil {
+			return err
+		}
+
+		ruleNames = make(map[string]struct{}, len(cfg.Rules))
+		for name := range cfg.Rules {
+			ruleNames[name] = struct{}{}
+		}
+	}
+
+	for _, name := range cfg.NamedRules {
+		if _, ok := namedRules[name]; !ok {
+			return fmt.Errorf("unknown named rule '%s': must be one of ipv6 or iban", name)
+		}
+
+		if _, ok := cfg.Rules[name]; ok {
+			return fmt.Errorf("named rule '%s' conflicts with a rule of the same name", name)
+		}
+
+		ruleNames[name] = struct{}{}
+	}
+
+	for _, field := range cfg.Include {
+		if !isValidField(field) {
+			return fmt.Errorf("include field '%s' must start with %s, %s, or %s", field, resourceField, attributesField, bodyField)
+		}
+	}
+
+	for name, action := range cfg.Actions {
+		if _, ok := ruleNames[name]; !ok {
+			return fmt.Errorf("action set for unknown rule '%s'", name)
+		}
+
+		switch action {
+		case actionRedact, actionPartial:
+		case actionHash, actionTokenize:
+			if cfg.HashSecret == "" {
+				return errMissingHashSecret
+			}
+		default:
+			return fmt.Errorf("rule '%s' has invalid action '%s': must be one of %s, %s, %s, or %s", name, action, actionRedact, actionHash, actionPartial, actionTokenize)
+		}
 	}
 
 	return nil
@@ -41,6 +41,120 @@ func TestConfigValidate(t *testing.T) {
 			cfg:         Config{},
 			expectedErr: nil,
 		},
+		{
+			desc: "Valid named rules",
+			cfg: Config{
+				NamedRules: []string{"ipv6", "iban"},
+				Actions: map[string]string{
+					"iban": actionPartial,
+				},
+			},
+			expectedErr: nil,
+		},
+		{
+			desc: "Unknown named rule",
+			cfg: Config{
+				NamedRules: []string{"mac"},
+			},
+			expectedErr: errors.New("unknown named rule 'mac': must be one of ipv6 or iban"),
+		},
+		{
+			desc: "Named rule conflicts with rule",
+			cfg: Config{
+				Rules: map[string]string{
+					"ipv6": "::",
+				},
+				NamedRules: []string{"ipv6"},
+			},
+			expectedErr: errors.New("named rule 'ipv6' conflicts with a rule of the same name"),
+		},
+		{
+			desc: "Action for disabled named rule",
+			cfg: Config{
+				Actions: map[string]string{
+					"iban": actionRedact,
+				},
+			},
+			expectedErr: errors.New("action set for unknown rule 'iban'"),
+		},
+		{
+			desc: "Valid include",
+			cfg: Config{
+				Include: []string{"resource", `attributes.client\.ip`, "body.user.ip"},
+			},
+			expectedErr: nil,
+		},
+		{
+			desc: "Invalid include",
+			cfg: Config{
+				Include: []string{"user.ip"},
+			},
+			expectedErr: errors.New("include field 'user.ip' must start with resource, attributes, or body"),
+		},
+		{
+			desc: "Valid actions",
+			cfg: Config{
+				Actions: map[string]string{
+					"email":       actionHash,
+					"credit_card": actionPartial,
+					"ssn":         actionTokenize,
+					"phone":       actionRedact,
+				},
+				HashSecret: "secret",
+			},
+			expectedErr: nil,
+		},
+		{
+			desc: "Action for custom rule",
+			cfg: Config{
+				Rules: map[string]string{
+					"custom": `\d+`,
+				},
+				Actions: map[string]string{
+					"custom": actionPartial,
+				},
+			},
+			expectedErr: nil,
+		},
+		{
+			desc: "Action for unknown rule",
+			cfg: Config{
+				Rules: map[string]string{
+					"custom": `\d+`,
+				},
+				Actions: map[string]string{
+					"email": actionPartial,
+				},
+			},
+			expectedErr: errors.New("action set for unknown rule 'email'"),
+		},
+		{
+			desc: "Invalid action",
+			cfg: Config{
+				Actions: map[string]string{
+					"email": "encrypt",
+				},
+			},
+			expectedErr: errors.New("rule 'email' has invalid action 'encrypt': must be one of redact, hash, partial, or tokenize"),
+		},
+		{
+			desc: "Hash without secret",
+			cfg: Config{
+				Actions: map[string]string{
+					"email": actionHash,
+				},
+			},
+			expectedErr: errMissingHashSecret,
+		},
+		{
+			desc: "Tokenize without secret",
+			cfg: Config{
+				Actions: map[string]string{
+					"ssn": actionTokenize,
+				},
+			},
+			expectedErr: errMissingHashSecret,
+		},
 	}
 
 	for _, tc := range testCases {
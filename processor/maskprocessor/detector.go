@@ -0,0 +1,115 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package maskprocessor
+
+import (
+	"net"
+	"strings"
+)
+
+// defaultValidators validate matches of the default rules to reduce false positives.
+var defaultValidators = map[string]func(string) bool{
+	"credit_card": isValidLuhn,
+	"iban":        isValidIBAN,
+	"ipv6":        isValidIPv6,
+	"phone":       isValidPhone,
+}
+
+// phoneMaxLetters is the number of letters that a phone number may contain in place of similar looking digits.
+const phoneMaxLetters = 2
+
+// isValidLuhn returns true if the digits of the value pass the Luhn checksum used by card numbers.
+func isValidLuhn(value string) bool {
+	sum := 0
+	digits := 0
+	for i := len(value) - 1; i >= 0; i-- {
+		c := value[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+
+		d := int(c - '0')
+		if digits%2 == 1 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+
+		sum += d
+		digits++
+	}
+
+	return digits >= 13 && digits <= 19 && sum%10 == 0
+}
+
+// isValidIBAN returns true if the value is an IBAN with a valid mod-97 checksum.
+func isValidIBAN(value string) bool {
+	iban := strings.ReplaceAll(value, " ", "")
+	if len(iban) < 15 || len(iban) > 34 {
+		return false
+	}
+
+	// Move the country code and check digits to the end, converting letters to the numbers 10 through 35
+	remainder := 0
+	for _, r := range iban[4:] + iban[:4] {
+		switch {
+		case r >= '0' && r <= '9':
+			remainder = (remainder*10 + int(r-'0')) % 97
+		case r >= 'A' && r <= 'Z':
+			remainder = (remainder*100 + int(r-'A'+10)) % 97
+		default:
+			return false
+		}
+	}
+
+	return remainder == 1
+}
+
+// isValidIPv6 returns true if the value is an IPv6 address.
+func isValidIPv6(value string) bool {
+	ip := net.ParseIP(value)
+	return ip != nil && strings.Contains(value, ":")
+}
+
+// isValidPhone returns true if the value looks like a phone number rather than a word or a series of numbers.
+// A phone number has at most a couple of letters in place of digits, consistent separators, and more than one distinct digit.
+func isValidPhone(value string) bool {
+	letters := 0
+	distinct := make(map[rune]struct{})
+	var separators []rune
+	for _, r := range value {
+		switch {
+		case r >= '0' && r <= '9':
+			distinct[r] = struct{}{}
+		case strings.ContainsRune("OlZSB", r):
+			letters++
+		case r == '-' || r == '.' || r == ' ':
+			separators = append(separators, r)
+		}
+	}
+
+	if letters > phoneMaxLetters || len(distinct) < 2 {
+		return false
+	}
+
+	// The last two separators split the exchange and subscriber numbers, unless the area code is in parentheses
+	if len(separators) >= 2 && !strings.Contains(value, ")") {
+		last := separators[len(separators)-2:]
+		return last[0] == last[1]
+	}
+
+	return true
+}
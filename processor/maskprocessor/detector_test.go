@@ -0,0 +1,55 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package maskprocessor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsValidLuhn(t *testing.T) {
+	require.True(t, isValidLuhn("4111-1111-1111-1111"))
+	require.True(t, isValidLuhn("378282246310005"))
+	require.False(t, isValidLuhn("4111-1111-1111-1112"))
+	require.False(t, isValidLuhn("0000"))
+}
+
+func TestIsValidIBAN(t *testing.T) {
+	require.True(t, isValidIBAN("GB82WEST12345698765432"))
+	require.True(t, isValidIBAN("DE89 3704 0044 0532 0130 00"))
+	require.False(t, isValidIBAN("GB82WEST12345698765433"))
+	require.False(t, isValidIBAN("GB82WEST"))
+}
+
+func TestIsValidIPv6(t *testing.T) {
+	require.True(t, isValidIPv6("2001:db8::1"))
+	require.True(t, isValidIPv6("fe80::1ff:fe23:4567:890a"))
+	require.True(t, isValidIPv6("::ffff:192.168.1.1"))
+	require.False(t, isValidIPv6("12:30:45"))
+	require.False(t, isValidIPv6("192.168.1.1"))
+}
+
+func TestIsValidPhone(t *testing.T) {
+	require.True(t, isValidPhone("555-867-5309"))
+	require.True(t, isValidPhone("+1 555-867-5309"))
+	require.True(t, isValidPhone("(555) 867-5309"))
+	require.True(t, isValidPhone("555.867.53O9"))
+	require.False(t, isValidPhone("BOSS-SOB-SOBS"))
+	require.False(t, isValidPhone("5SS-SOB-5309"))
+	require.False(t, isValidPhone("000-000-0000"))
+	require.False(t, isValidPhone("10000 200.3000"))
+	require.False(t, isValidPhone("2024.100-1000"))
+}
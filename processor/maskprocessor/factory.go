@@ -62,7 +62,11 @@ func createTracesProcessor(
 		return nil, errInvalidConfigType
 	}
 
-	processor := newProcessor(set.Logger, maskCfg)
+	processor, err := newProcessor(set, maskCfg)
+	if err != nil {
+		return nil, err
+	}
+
 	return processorhelper.NewTracesProcessor(
 		ctx,
 		set,
@@ -70,7 +74,8 @@ func createTracesProcessor(
 		nextConsumer,
 		processor.processTraces,
 		processorhelper.WithCapabilities(consumerCapabilities),
-		processorhelper.WithStart(processor.start))
+		processorhelper.WithStart(processor.start),
+		processorhelper.WithShutdown(processor.shutdown))
 }
 
 // createLogsProcessor creates a mask processor for logs.
@@ -85,7 +90,11 @@ func createLogsProcessor(
 		return nil, errInvalidConfigType
 	}
 
-	processor := newProcessor(set.Logger, maskCfg)
+	processor, err := newProcessor(set, maskCfg)
+	if err != nil {
+		return nil, err
+	}
+
 	return processorhelper.NewLogsProcessor(
 		ctx,
 		set,
@@ -93,7 +102,8 @@ func createLogsProcessor(
 		nextConsumer,
 		processor.processLogs,
 		processorhelper.WithCapabilities(consumerCapabilities),
-		processorhelper.WithStart(processor.start))
+		processorhelper.WithStart(processor.start),
+		processorhelper.WithShutdown(processor.shutdown))
 }
 
 // createMetricsProcessor creates a mask processor for metrics.
@@ -108,7 +118,11 @@ func createMetricsProcessor(
 		return nil, errInvalidConfigType
 	}
 
-	processor := newProcessor(set.Logger, maskCfg)
+	processor, err := newProcessor(set, maskCfg)
+	if err != nil {
+		return nil, err
+	}
+
 	return processorhelper.NewMetricsProcessor(
 		ctx,
 		set,
@@ -116,5 +130,6 @@ func createMetricsProcessor(
 		nextConsumer,
 		processor.processMetrics,
 		processorhelper.WithCapabilities(consumerCapabilities),
-		processorhelper.WithStart(processor.start))
+		processorhelper.WithStart(processor.start),
+		processorhelper.WithShutdown(processor.shutdown))
 }
@@ -0,0 +1,52 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package maskprocessor
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// fieldDelimiter is the delimiter used to split a field key into its parts.
+	fieldDelimiter = "."
+
+	// fieldEscapeKeyReplacement is the string used to temporarily replace escaped delimters while splitting a field key.
+	fieldEscapeKeyReplacement = "{TEMP_REPLACE}"
+)
+
+// splitField splits a field key into its parts.
+// It replaces escaped delimiters with the full delimiter after splitting.
+func splitField(fieldKey string) []string {
+	escapedKey := strings.ReplaceAll(fieldKey, fmt.Sprintf("\\%s", fieldDelimiter), fieldEscapeKeyReplacement)
+	keyParts := strings.Split(escapedKey, fieldDelimiter)
+
+	// Replace the temporarily escaped delimiters with the actual delimiter.
+	for i := range keyParts {
+		keyParts[i] = strings.ReplaceAll(keyParts[i], fieldEscapeKeyReplacement, fieldDelimiter)
+	}
+
+	return keyParts
+}
+
+// isValidField returns true if the field starts with a field the processor masks.
+func isValidField(field string) bool {
+	switch splitField(field)[0] {
+	case resourceField, attributesField, bodyField:
+		return true
+	default:
+		return false
+	}
+}
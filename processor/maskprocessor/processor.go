@@ -18,12 +18,17 @@ import (
 	"context"
 	"fmt"
 	"regexp"
+	"sort"
+	"strings"
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/plog"
 	"go.opentelemetry.io/collector/pdata/pmetric"
 	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/collector/processor"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
 	"go.uber.org/zap"
 )
 
@@ -31,6 +36,12 @@ const (
 	resourceField   = "resource"
 	attributesField = "attributes"
 	bodyField       = "body"
+
+	// scopeName is the instrumentation scope of the processor's metrics
+	scopeName = "github.com/observiq/bindplane-agent/processor/maskprocessor"
+
+	// matchesMetricName is the name of the metric counting values masked by each rule
+	matchesMetricName = "processor/mask/matches"
 )
 
 var defaultRules = map[string]*regexp.Regexp{
@@ -41,47 +52,94 @@ var defaultRules = map[string]*regexp.Regexp{
 	"ipv4":        regexp.MustCompile(`\b(?:[0-9]{1,3}\.){3}[0-9]{1,3}\b`),
 }
 
+// namedRules are built-in rules that are only applied when enabled by name.
+var namedRules = map[string]*regexp.Regexp{
+	"ipv6": regexp.MustCompile(`(?i)(?:[0-9a-f]{0,4}:){2,7}(?:(?:[0-9]{1,3}\.){3}[0-9]{1,3}|[0-9a-f]{1,4})?`),
+	"iban": regexp.MustCompile(`\b[A-Z]{2}[0-9]{2}(?: ?[A-Z0-9]){11,30}\b`),
+}
+
+// maskRule is a rule used to mask matching values.
+type maskRule struct {
+	name     string
+	regex    *regexp.Regexp
+	action   string
+	validate func(string) bool
+	attrs    metric.MeasurementOption
+}
+
 // maskProcessor is the processor used to mask data.
 type maskProcessor struct {
-	logger           *zap.Logger
-	cfg              *Config
-	rules            map[string]*regexp.Regexp
-	maskResourceFunc func(k string, v pcommon.Value) bool
-	maskAttrsFunc    func(k string, v pcommon.Value) bool
+	logger    *zap.Logger
+	cfg       *Config
+	id        component.ID
+	rules     []maskRule
+	include   [][]string
+	tokenizer *tokenizer
+	matches   metric.Int64Counter
 }
 
 // newProcessor creates a new mask processor.
-func newProcessor(logger *zap.Logger, cfg *Config) *maskProcessor {
-	return &maskProcessor{
-		logger: logger,
-		cfg:    cfg,
+func newProcessor(set processor.CreateSettings, cfg *Config) (*maskProcessor, error) {
+	matches, err := set.MeterProvider.Meter(scopeName).Int64Counter(
+		matchesMetricName,
+		metric.WithDescription("Number of values masked by each rule"),
+		metric.WithUnit("{matches}"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("create matches metric: %w", err)
 	}
+
+	include := make([][]string, 0, len(cfg.Include))
+	for _, field := range cfg.Include {
+		include = append(include, splitField(field))
+	}
+
+	return &maskProcessor{
+		logger:  set.Logger,
+		cfg:     cfg,
+		id:      set.ID,
+		include: include,
+		matches: matches,
+	}, nil
 }
 
 // start is used to start the processor.
-func (p *maskProcessor) start(context.Context, component.Host) error {
+func (p *maskProcessor) start(ctx context.Context, host component.Host) error {
 	rules, err := p.createRules()
 	if err != nil {
 		return err
 	}
 
+	tokenizer, err := acquireTokenizer(ctx, host, p.cfg, p.id)
+	if err != nil {
+		return err
+	}
+
+	p.tokenizer = tokenizer
 	p.rules = rules
-	p.maskResourceFunc = p.createMaskFunc(resourceField)
-	p.maskAttrsFunc = p.createMaskFunc(attributesField)
 	return nil
 }
 
+// shutdown is used to shutdown the processor.
+func (p *maskProcessor) shutdown(ctx context.Context) error {
+	if p.tokenizer == nil {
+		return nil
+	}
+
+	return releaseTokenizer(ctx, p.id)
+}
+
 // processLogs masks incoming logs.
-func (p *maskProcessor) processLogs(_ context.Context, ld plog.Logs) (plog.Logs, error) {
+func (p *maskProcessor) processLogs(ctx context.Context, ld plog.Logs) (plog.Logs, error) {
 	for i := 0; i < ld.ResourceLogs().Len(); i++ {
 		resource := ld.ResourceLogs().At(i)
-		resource.Resource().Attributes().Range(p.maskResourceFunc)
+		p.maskField(ctx, []string{resourceField}, resource.Resource().Attributes())
 		for j := 0; j < resource.ScopeLogs().Len(); j++ {
 			scope := resource.ScopeLogs().At(j)
 			for k := 0; k < scope.LogRecords().Len(); k++ {
 				logs := scope.LogRecords().At(k)
-				logs.Attributes().Range(p.maskAttrsFunc)
-				p.maskValue(bodyField, logs.Body())
+				p.maskField(ctx, []string{attributesField}, logs.Attributes())
+				p.maskValue(ctx, []string{bodyField}, logs.Body())
 			}
 		}
 	}
@@ -90,15 +148,15 @@ func (p *maskProcessor) processLogs(_ context.Context, ld plog.Logs) (plog.Logs,
 }
 
 // processTraces masks incoming traces.
-func (p *maskProcessor) processTraces(_ context.Context, td ptrace.Traces) (ptrace.Traces, error) {
+func (p *maskProcessor) processTraces(ctx context.Context, td ptrace.Traces) (ptrace.Traces, error) {
 	for i := 0; i < td.ResourceSpans().Len(); i++ {
 		resource := td.ResourceSpans().At(i)
-		resource.Resource().Attributes().Range(p.maskResourceFunc)
+		p.maskField(ctx, []string{resourceField}, resource.Resource().Attributes())
 		for j := 0; j < resource.ScopeSpans().Len(); j++ {
 			scope := resource.ScopeSpans().At(j)
 			for k := 0; k < scope.Spans().Len(); k++ {
 				spans := scope.Spans().At(k)
-				spans.Attributes().Range(p.maskAttrsFunc)
+				p.maskField(ctx, []string{attributesField}, spans.Attributes())
 			}
 		}
 	}
@@ -107,25 +165,25 @@ func (p *maskProcessor) processTraces(_ context.Context, td ptrace.Traces) (ptra
 }
 
 // processMetrics masks incoming metrics.
-func (p *maskProcessor) processMetrics(_ context.Context, md pmetric.Metrics) (pmetric.Metrics, error) {
+func (p *maskProcessor) processMetrics(ctx context.Context, md pmetric.Metrics) (pmetric.Metrics, error) {
 	for i := 0; i < md.ResourceMetrics().Len(); i++ {
 		resource := md.ResourceMetrics().At(i)
-		resource.Resource().Attributes().Range(p.maskResourceFunc)
+		p.maskField(ctx, []string{resourceField}, resource.Resource().Attributes())
 		for j := 0; j < resource.ScopeMetrics().Len(); j++ {
 			scope := resource.ScopeMetrics().At(j)
 			for k := 0; k < scope.Metrics().Len(); k++ {
 				metrics := scope.Metrics().At(k)
 				switch metrics.Type() {
 				case pmetric.MetricTypeSum:
-					p.processSum(metrics.Sum())
+					p.processSum(ctx, metrics.Sum())
 				case pmetric.MetricTypeGauge:
-					p.processGauge(metrics.Gauge())
+					p.processGauge(ctx, metrics.Gauge())
 				case pmetric.MetricTypeSummary:
-					p.processSummary(metrics.Summary())
+					p.processSummary(ctx, metrics.Summary())
 				case pmetric.MetricTypeHistogram:
-					p.processHistogram(metrics.Histogram())
+					p.processHistogram(ctx, metrics.Histogram())
 				case pmetric.MetricTypeExponentialHistogram:
-					p.processExponentialHistogram(metrics.ExponentialHistogram())
+					p.processExponentialHistogram(ctx, metrics.ExponentialHistogram())
 				}
 			}
 		}
@@ -135,75 +193,164 @@ func (p *maskProcessor) processMetrics(_ context.Context, md pmetric.Metrics) (p
 }
 
 // processSum masks a sum metric.
-func (p *maskProcessor) processSum(sum pmetric.Sum) {
+func (p *maskProcessor) processSum(ctx context.Context, sum pmetric.Sum) {
 	for i := 0; i < sum.DataPoints().Len(); i++ {
-		sum.DataPoints().At(i).Attributes().Range(p.maskAttrsFunc)
+		p.maskField(ctx, []string{attributesField}, sum.DataPoints().At(i).Attributes())
 	}
 }
 
 // processGauge masks a gauge metric.
-func (p *maskProcessor) processGauge(gauge pmetric.Gauge) {
+func (p *maskProcessor) processGauge(ctx context.Context, gauge pmetric.Gauge) {
 	for i := 0; i < gauge.DataPoints().Len(); i++ {
-		gauge.DataPoints().At(i).Attributes().Range(p.maskAttrsFunc)
+		p.maskField(ctx, []string{attributesField}, gauge.DataPoints().At(i).Attributes())
 	}
 }
 
 // processSummary masks a summary metric.
-func (p *maskProcessor) processSummary(summary pmetric.Summary) {
+func (p *maskProcessor) processSummary(ctx context.Context, summary pmetric.Summary) {
 	for i := 0; i < summary.DataPoints().Len(); i++ {
-		summary.DataPoints().At(i).Attributes().Range(p.maskAttrsFunc)
+		p.maskField(ctx, []string{attributesField}, summary.DataPoints().At(i).Attributes())
 	}
 }
 
 // processHistogram masks a histogram metric.
-func (p *maskProcessor) processHistogram(histogram pmetric.Histogram) {
+func (p *maskProcessor) processHistogram(ctx context.Context, histogram pmetric.Histogram) {
 	for i := 0; i < histogram.DataPoints().Len(); i++ {
-		histogram.DataPoints().At(i).Attributes().Range(p.maskAttrsFunc)
+		p.maskField(ctx, []string{attributesField}, histogram.DataPoints().At(i).Attributes())
 	}
 }
 
 // processExponentialHistogram masks a histogram metric.
-func (p *maskProcessor) processExponentialHistogram(histogram pmetric.ExponentialHistogram) {
+func (p *maskProcessor) processExponentialHistogram(ctx context.Context, histogram pmetric.ExponentialHistogram) {
 	for i := 0; i < histogram.DataPoints().Len(); i++ {
-		histogram.DataPoints().At(i).Attributes().Range(p.maskAttrsFunc)
+		p.maskField(ctx, []string{attributesField}, histogram.DataPoints().At(i).Attributes())
 	}
 }
 
+// maskField masks the values of a pcommon.Map found at the path.
+func (p *maskProcessor) maskField(ctx context.Context, path []string, valueMap pcommon.Map) {
+	if p.isExcluded(path) {
+		return
+	}
+
+	if _, descend := p.isIncluded(path); !descend {
+		return
+	}
+
+	valueMap.Range(func(k string, v pcommon.Value) bool {
+		// Copy the path so sibling keys do not share a backing array
+		childPath := append(path[:len(path):len(path)], k)
+		p.maskValue(ctx, childPath, v)
+		return true
+	})
+}
+
 // maskValue masks a pcommon.Value.
-func (p *maskProcessor) maskValue(field string, value pcommon.Value) {
-	for _, excludeField := range p.cfg.Exclude {
-		if field == excludeField {
-			return
-		}
+func (p *maskProcessor) maskValue(ctx context.Context, path []string, value pcommon.Value) {
+	if p.isExcluded(path) {
+		return
+	}
+
+	included, descend := p.isIncluded(path)
+	if !descend {
+		return
 	}
 
 	switch value.Type() {
 	case pcommon.ValueTypeMap:
-		maskFunc := p.createMaskFunc(field)
-		value.Map().Range(maskFunc)
+		p.maskField(ctx, path, value.Map())
 	case pcommon.ValueTypeStr:
-		p.maskString(value)
+		if included {
+			p.maskString(ctx, value)
+		}
 	case pcommon.ValueTypeSlice:
+		if !included {
+			return
+		}
+
 		// Search for strings in a slice and apply mask
 		for i := 0; i < value.Slice().Len(); i++ {
 			sliceVal := value.Slice().At(i)
 			if sliceVal.Type() == pcommon.ValueTypeStr {
-				p.maskString(sliceVal)
+				p.maskString(ctx, sliceVal)
 			}
 		}
 	}
 }
 
+// isExcluded returns true if the field at the path is excluded from masking.
+func (p *maskProcessor) isExcluded(path []string) bool {
+	if len(p.cfg.Exclude) == 0 {
+		return false
+	}
+
+	field := strings.Join(path, fieldDelimiter)
+	for _, excludeField := range p.cfg.Exclude {
+		if field == excludeField {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isIncluded returns whether the field at the path is included in masking,
+// and whether its children may be included and should be traversed.
+func (p *maskProcessor) isIncluded(path []string) (included bool, descend bool) {
+	if len(p.include) == 0 {
+		return true, true
+	}
+
+	for _, includePath := range p.include {
+		if hasPrefix(path, includePath) {
+			return true, true
+		}
+
+		if hasPrefix(includePath, path) {
+			descend = true
+		}
+	}
+
+	return false, descend
+}
+
+// hasPrefix returns true if the path starts with the prefix.
+func hasPrefix(path, prefix []string) bool {
+	if len(prefix) > len(path) {
+		return false
+	}
+
+	for i := range prefix {
+		if path[i] != prefix[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
 // maskString masks a pcommon string.
-func (p *maskProcessor) maskString(value pcommon.Value) {
+func (p *maskProcessor) maskString(ctx context.Context, value pcommon.Value) {
 	strValue := value.Str()
 
-	for mask, rule := range p.rules {
-		if !rule.MatchString(strValue) {
+	for _, rule := range p.rules {
+		if !rule.regex.MatchString(strValue) {
 			continue
 		}
 
-		strValue = rule.ReplaceAllString(strValue, mask)
+		var matches int64
+		strValue = rule.regex.ReplaceAllStringFunc(strValue, func(match string) string {
+			if rule.validate != nil && !rule.validate(match) {
+				return match
+			}
+
+			matches++
+			return p.applyAction(ctx, rule, match)
+		})
+
+		if matches > 0 {
+			p.matches.Add(ctx, matches, rule.attrs)
+		}
 	}
 
 	if strValue != value.Str() {
@@ -211,28 +358,79 @@ func (p *maskProcessor) maskString(value pcommon.Value) {
 	}
 }
 
-// createMaskFunc creates a func for ranging through a pcommon.Map and masking its values.
-func (p *maskProcessor) createMaskFunc(field string) func(k string, v pcommon.Value) bool {
-	return func(k string, v pcommon.Value) bool {
-		childField := fmt.Sprintf("%s.%s", field, k)
-		p.maskValue(childField, v)
-		return true
+// applyAction returns the replacement of a value matching the rule.
+func (p *maskProcessor) applyAction(ctx context.Context, rule maskRule, match string) string {
+	switch rule.action {
+	case actionHash:
+		return hashValue([]byte(p.cfg.HashSecret), match)
+	case actionPartial:
+		return partialValue(match)
+	case actionTokenize:
+		token, err := p.tokenizer.token(ctx, match)
+		if err != nil {
+			p.logger.Error("Failed to tokenize value, redacting instead", zap.String("rule", rule.name), zap.Error(err))
+			return redactValue(rule.name)
+		}
+		return token
+	default:
+		return redactValue(rule.name)
 	}
 }
 
-// createRules creates a map of rules for the processor.
-func (p *maskProcessor) createRules() (map[string]*regexp.Regexp, error) {
-	rules := defaultRules
+// createRules creates the rules for the processor, sorted by name so they are applied in a consistent order.
+func (p *maskProcessor) createRules() ([]maskRule, error) {
+	exprs := make(map[string]*regexp.Regexp, len(defaultRules)+len(p.cfg.NamedRules))
+	validators := make(map[string]func(string) bool, len(p.cfg.NamedRules))
 	if len(p.cfg.Rules) > 0 {
-		var err error
-		rules, err = compileRules(p.cfg.Rules)
+		compiled, err := compileRules(p.cfg.Rules)
 		if err != nil {
 			return nil, err
 		}
+
+		for name, regex := range compiled {
+			exprs[name] = regex
+		}
+	} else {
+		for name, regex := range defaultRules {
+			exprs[name] = regex
+			validators[name] = defaultValidators[name]
+		}
+	}
+
+	for _, name := range p.cfg.NamedRules {
+		regex, ok := namedRules[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown named rule '%s'", name)
+		}
+
+		exprs[name] = regex
+		validators[name] = defaultValidators[name]
 	}
 
-	formattedRules := formatRuleMaskName(rules)
-	return formattedRules, nil
+	rules := make([]maskRule, 0, len(exprs))
+	for name, regex := range exprs {
+		action := p.cfg.Actions[name]
+		if action == "" {
+			action = actionRedact
+		}
+
+		rules = append(rules, maskRule{
+			name:     name,
+			regex:    regex,
+			action:   action,
+			validate: validators[name],
+			attrs: metric.WithAttributes(
+				attribute.String("processor", p.id.String()),
+				attribute.String("rule", name),
+			),
+		})
+	}
+
+	sort.Slice(rules, func(i, j int) bool {
+		return rules[i].name < rules[j].name
+	})
+
+	return rules, nil
 }
 
 // compileRules compiles rules from the provided map of expressions.
@@ -248,13 +446,3 @@ func compileRules(exprs map[string]string) (map[string]*regexp.Regexp, error) {
 	}
 	return rules, nil
 }
-
-func formatRuleMaskName(rules map[string]*regexp.Regexp) map[string]*regexp.Regexp {
-	formattedRules := make(map[string]*regexp.Regexp, len(rules))
-	for ruleName, rule := range rules {
-		maskedName := fmt.Sprintf("[masked_%s]", ruleName)
-		formattedRules[maskedName] = rule
-	}
-
-	return formattedRules
-}
@@ -22,10 +22,13 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/pdata/plog"
 	"go.opentelemetry.io/collector/pdata/pmetric"
 	"go.opentelemetry.io/collector/pdata/ptrace"
-	"go.uber.org/zap"
+	"go.opentelemetry.io/collector/processor/processortest"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
 )
 
 var testMap = map[string]interface{}{
@@ -65,9 +68,11 @@ func TestProcessTraces(t *testing.T) {
 		Rules:   map[string]string{"field": "sensitive"},
 		Exclude: []string{"resource.exclude", "attributes.exclude"},
 	}
-	processor := newProcessor(zap.NewNop(), cfg)
-	err := processor.start(context.Background(), nil)
+	processor, err := newProcessor(processortest.NewNopCreateSettings(), cfg)
 	require.NoError(t, err)
+	err = processor.start(context.Background(), nil)
+	require.NoError(t, err)
+	defer func() { require.NoError(t, processor.shutdown(context.Background())) }()
 
 	result, err := processor.processTraces(context.Background(), traces)
 	require.NoError(t, err)
@@ -98,9 +103,11 @@ func TestProcessMetrics(t *testing.T) {
 		Rules:   map[string]string{"field": "sensitive"},
 		Exclude: []string{"resource.exclude", "attributes.exclude"},
 	}
-	processor := newProcessor(zap.NewNop(), cfg)
-	err := processor.start(context.Background(), nil)
+	processor, err := newProcessor(processortest.NewNopCreateSettings(), cfg)
+	require.NoError(t, err)
+	err = processor.start(context.Background(), nil)
 	require.NoError(t, err)
+	defer func() { require.NoError(t, processor.shutdown(context.Background())) }()
 
 	result, err := processor.processMetrics(context.Background(), metrics)
 	require.NoError(t, err)
@@ -137,9 +144,11 @@ func TestProcessLogs(t *testing.T) {
 		Rules:   map[string]string{"field": "sensitive"},
 		Exclude: []string{"resource.exclude", "attributes.exclude", "body.exclude"},
 	}
-	processor := newProcessor(zap.NewNop(), cfg)
-	err := processor.start(context.Background(), nil)
+	processor, err := newProcessor(processortest.NewNopCreateSettings(), cfg)
+	require.NoError(t, err)
+	err = processor.start(context.Background(), nil)
 	require.NoError(t, err)
+	defer func() { require.NoError(t, processor.shutdown(context.Background())) }()
 
 	result, err := processor.processLogs(context.Background(), logs)
 	require.NoError(t, err)
@@ -158,18 +167,154 @@ func TestFailedStart(t *testing.T) {
 	cfg := &Config{
 		Rules: map[string]string{"invalid": `\k`},
 	}
-	processor := newProcessor(zap.NewNop(), cfg)
+	processor, err := newProcessor(processortest.NewNopCreateSettings(), cfg)
+	require.NoError(t, err)
 
-	err := processor.start(context.Background(), nil)
+	err = processor.start(context.Background(), nil)
 	require.Error(t, err)
 	require.Contains(t, err.Error(), "does not compile as valid regex")
 }
 
+func TestProcessLogsWithActions(t *testing.T) {
+	logs := plog.NewLogs()
+	record := logs.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords().AppendEmpty()
+	record.Body().SetStr("card 4111-1111-1111-1234 user 123-45-6789")
+	record.Attributes().PutStr("token", "123-45-6789")
+	record.Attributes().PutStr("hash", "jdoe@example.com")
+
+	cfg := &Config{
+		Rules: map[string]string{
+			"credit_card": `\b\d{4}-\d{4}-\d{4}-\d{4}\b`,
+			"ssn":         `\b\d{3}-\d{2}-\d{4}\b`,
+			"email":       `\b\S+@\S+\b`,
+		},
+		Actions: map[string]string{
+			"credit_card": actionPartial,
+			"ssn":         actionTokenize,
+			"email":       actionHash,
+		},
+		HashSecret: "secret",
+	}
+	processor, err := newProcessor(processortest.NewNopCreateSettings(), cfg)
+	require.NoError(t, err)
+	err = processor.start(context.Background(), componenttest.NewNopHost())
+	require.NoError(t, err)
+
+	result, err := processor.processLogs(context.Background(), logs)
+	require.NoError(t, err)
+
+	record = result.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0)
+	token, ok := record.Attributes().Get("token")
+	require.True(t, ok)
+	require.Regexp(t, `^tok_[0-9a-f]{16}$`, token.Str())
+	require.Equal(t, "card ****-****-****-1234 user "+token.Str(), record.Body().Str())
+
+	hash, ok := record.Attributes().Get("hash")
+	require.True(t, ok)
+	require.Equal(t, hashValue([]byte("secret"), "jdoe@example.com"), hash.Str())
+
+	require.NoError(t, processor.shutdown(context.Background()))
+}
+
+func TestProcessLogsDefaultDetectors(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	settings := processortest.NewNopCreateSettings()
+	settings.MeterProvider = sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	logs := plog.NewLogs()
+	record := logs.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords().AppendEmpty()
+	record.Body().SetStr("card 4111-1111-1111-1111 order 4111-1111-1111-1112 iban GB82WEST12345698765432 host 2001:db8::1 at 12:30:45")
+
+	processor, err := newProcessor(settings, &Config{NamedRules: []string{"iban", "ipv6"}})
+	require.NoError(t, err)
+	err = processor.start(context.Background(), componenttest.NewNopHost())
+	require.NoError(t, err)
+	defer func() { require.NoError(t, processor.shutdown(context.Background())) }()
+
+	result, err := processor.processLogs(context.Background(), logs)
+	require.NoError(t, err)
+
+	record = result.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0)
+	require.Equal(t, "card [masked_credit_card] order 4111-1111-1111-1112 iban [masked_iban] host [masked_ipv6] at 12:30:45", record.Body().Str())
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	require.Len(t, rm.ScopeMetrics, 1)
+	require.Len(t, rm.ScopeMetrics[0].Metrics, 1)
+	require.Equal(t, matchesMetricName, rm.ScopeMetrics[0].Metrics[0].Name)
+
+	matches := make(map[string]int64)
+	sum := rm.ScopeMetrics[0].Metrics[0].Data.(metricdata.Sum[int64])
+	for _, dp := range sum.DataPoints {
+		rule, ok := dp.Attributes.Value("rule")
+		require.True(t, ok)
+		matches[rule.AsString()] = dp.Value
+	}
+	require.Equal(t, map[string]int64{"credit_card": 1, "iban": 1, "ipv6": 1}, matches)
+}
+
+func TestProcessLogsPhoneFalsePositives(t *testing.T) {
+	logs := plog.NewLogs()
+	record := logs.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords().AppendEmpty()
+	record.Body().SetStr("call 555-867-5309 from BOSS-SOB-SOBS id 000-000-0000 sizes 10000 200.3000")
+
+	processor, err := newProcessor(processortest.NewNopCreateSettings(), &Config{})
+	require.NoError(t, err)
+	err = processor.start(context.Background(), componenttest.NewNopHost())
+	require.NoError(t, err)
+	defer func() { require.NoError(t, processor.shutdown(context.Background())) }()
+
+	result, err := processor.processLogs(context.Background(), logs)
+	require.NoError(t, err)
+
+	record = result.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0)
+	require.Equal(t, "call [masked_phone] from BOSS-SOB-SOBS id 000-000-0000 sizes 10000 200.3000", record.Body().Str())
+}
+
+func TestProcessLogsInclude(t *testing.T) {
+	logs := plog.NewLogs()
+	resourceLogs := logs.ResourceLogs().AppendEmpty()
+	resourceLogs.Resource().Attributes().PutStr("host.ip", "10.0.0.1")
+	record := resourceLogs.ScopeLogs().AppendEmpty().LogRecords().AppendEmpty()
+	record.Attributes().PutStr("client.ip", "10.0.0.2")
+	record.Attributes().PutStr("server.ip", "10.0.0.3")
+	body := record.Body().SetEmptyMap()
+	body.PutEmptyMap("user").PutStr("ip", "10.0.0.4")
+	body.PutStr("message", "from 10.0.0.5")
+
+	cfg := &Config{
+		Include: []string{`attributes.client\.ip`, "body.user"},
+	}
+	processor, err := newProcessor(processortest.NewNopCreateSettings(), cfg)
+	require.NoError(t, err)
+	err = processor.start(context.Background(), componenttest.NewNopHost())
+	require.NoError(t, err)
+	defer func() { require.NoError(t, processor.shutdown(context.Background())) }()
+
+	result, err := processor.processLogs(context.Background(), logs)
+	require.NoError(t, err)
+
+	resourceLogs = result.ResourceLogs().At(0)
+	require.Equal(t, map[string]any{"host.ip": "10.0.0.1"}, resourceLogs.Resource().Attributes().AsRaw())
+
+	record = resourceLogs.ScopeLogs().At(0).LogRecords().At(0)
+	require.Equal(t, map[string]any{
+		"client.ip": "[masked_ipv4]",
+		"server.ip": "10.0.0.3",
+	}, record.Attributes().AsRaw())
+	require.Equal(t, map[string]any{
+		"user":    map[string]any{"ip": "[masked_ipv4]"},
+		"message": "from 10.0.0.5",
+	}, record.Body().Map().AsRaw())
+}
+
 func TestCreateRules(t *testing.T) {
 	testCases := []struct {
 		desc          string
 		exprs         map[string]string
-		expectedRules map[string]*regexp.Regexp
+		namedRules    []string
+		actions       map[string]string
+		expectedRules []maskRule
 		expectedErr   error
 	}{
 		{
@@ -177,8 +322,22 @@ func TestCreateRules(t *testing.T) {
 			exprs: map[string]string{
 				"test": "test",
 			},
-			expectedRules: map[string]*regexp.Regexp{
-				"[masked_test]": regexp.MustCompile("test"),
+			expectedRules: []maskRule{
+				{name: "test", regex: regexp.MustCompile("test"), action: actionRedact},
+			},
+		},
+		{
+			desc: "Rules with actions",
+			exprs: map[string]string{
+				"b": "b",
+				"a": "a",
+			},
+			actions: map[string]string{
+				"b": actionPartial,
+			},
+			expectedRules: []maskRule{
+				{name: "a", regex: regexp.MustCompile("a"), action: actionRedact},
+				{name: "b", regex: regexp.MustCompile("b"), action: actionPartial},
 			},
 		},
 		{
@@ -189,9 +348,32 @@ func TestCreateRules(t *testing.T) {
 			expectedErr: errors.New("rule 'invalid' does not compile"),
 		},
 		{
-			desc:          "No rules",
-			exprs:         map[string]string{},
-			expectedRules: formatRuleMaskName(defaultRules),
+			desc:  "No rules",
+			exprs: map[string]string{},
+			expectedRules: []maskRule{
+				{name: "credit_card", regex: defaultRules["credit_card"], action: actionRedact},
+				{name: "email", regex: defaultRules["email"], action: actionRedact},
+				{name: "ipv4", regex: defaultRules["ipv4"], action: actionRedact},
+				{name: "phone", regex: defaultRules["phone"], action: actionRedact},
+				{name: "ssn", regex: defaultRules["ssn"], action: actionRedact},
+			},
+		},
+		{
+			desc:       "Named rules",
+			exprs:      map[string]string{"test": "test"},
+			namedRules: []string{"ipv6"},
+			actions: map[string]string{
+				"ipv6": actionPartial,
+			},
+			expectedRules: []maskRule{
+				{name: "ipv6", regex: namedRules["ipv6"], action: actionPartial},
+				{name: "test", regex: regexp.MustCompile("test"), action: actionRedact},
+			},
+		},
+		{
+			desc:        "Unknown named rule",
+			namedRules:  []string{"mac"},
+			expectedErr: errors.New("unknown named rule 'mac'"),
 		},
 	}
 
@@ -199,7 +381,9 @@ func TestCreateRules(t *testing.T) {
 		t.Run(tc.desc, func(t *testing.T) {
 			p := &maskProcessor{
 				cfg: &Config{
-					Rules: tc.exprs,
+					Rules:      tc.exprs,
+					NamedRules: tc.namedRules,
+					Actions:    tc.actions,
 				},
 			}
 			rules, err := p.createRules()
@@ -207,7 +391,12 @@ func TestCreateRules(t *testing.T) {
 			switch tc.expectedErr {
 			case nil:
 				require.NoError(t, err)
-				require.Equal(t, tc.expectedRules, rules)
+				require.Len(t, rules, len(tc.expectedRules))
+				for i, expected := range tc.expectedRules {
+					require.Equal(t, expected.name, rules[i].name)
+					require.Equal(t, expected.regex, rules[i].regex)
+					require.Equal(t, expected.action, rules[i].action)
+				}
 			default:
 				require.Error(t, err)
 				require.Contains(t, err.Error(), tc.expectedErr.Error())
@@ -0,0 +1,210 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package maskprocessor
+
+import (
+	"container/list"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sync"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/extension/experimental/storage"
+)
+
+const (
+	// tokenPrefix is prepended to tokens created by the tokenize action.
+	tokenPrefix = "tok_"
+
+	// tokenKeyPrefix is prepended to the storage keys of tokens.
+	tokenKeyPrefix = "token_"
+
+	// tokenBytes is the number of random bytes in a token.
+	tokenBytes = 8
+
+	// tokenCacheSize is the number of tokens kept in memory when tokens are persisted.
+	// The least recently used tokens are removed first and read back from storage when seen again.
+	tokenCacheSize = 10000
+)
+
+var (
+	// tokenizers are the tokenizers of running processors, keyed by processor ID.
+	// A processor's pipelines share a tokenizer, so a value has the same token in every signal.
+	tokenizers    = make(map[component.ID]*sharedTokenizer)
+	tokenizersMux sync.Mutex
+)
+
+// sharedTokenizer is a tokenizer shared by the pipelines of a processor.
+type sharedTokenizer struct {
+	tokenizer *tokenizer
+	refs      int
+}
+
+// acquireTokenizer returns the tokenizer of the processor.
+// The tokenizer and its storage client are created if no other pipeline of the processor is running.
+func acquireTokenizer(ctx context.Context, host component.Host, cfg *Config, id component.ID) (*tokenizer, error) {
+	tokenizersMux.Lock()
+	defer tokenizersMux.Unlock()
+
+	if shared, ok := tokenizers[id]; ok {
+		shared.refs++
+		return shared.tokenizer, nil
+	}
+
+	client, err := getStorageClient(ctx, host, cfg.StorageID, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get storage client: %w", err)
+	}
+
+	// Without storage, memory is the only record of a token, so tokens are never evicted.
+	cacheSize := 0
+	if cfg.StorageID != nil {
+		cacheSize = tokenCacheSize
+	}
+
+	t := newTokenizer([]byte(cfg.HashSecret), cacheSize)
+	t.client = client
+	tokenizers[id] = &sharedTokenizer{tokenizer: t, refs: 1}
+	return t, nil
+}
+
+// releaseTokenizer releases the tokenizer of the processor.
+// Its storage client is closed once none of the processor's pipelines are running.
+func releaseTokenizer(ctx context.Context, id component.ID) error {
+	tokenizersMux.Lock()
+	defer tokenizersMux.Unlock()
+
+	shared, ok := tokenizers[id]
+	if !ok {
+		return nil
+	}
+
+	shared.refs--
+	if shared.refs > 0 {
+		return nil
+	}
+
+	delete(tokenizers, id)
+	if err := shared.tokenizer.client.Close(ctx); err != nil {
+		return fmt.Errorf("failed to close storage client: %w", err)
+	}
+
+	return nil
+}
+
+// getStorageClient returns the storage client used to persist tokens.
+func getStorageClient(ctx context.Context, host component.Host, storageID *component.ID, componentID component.ID) (storage.Client, error) {
+	if storageID == nil {
+		return storage.NewNopClient(), nil
+	}
+
+	extension, ok := host.GetExtensions()[*storageID]
+	if !ok {
+		return nil, fmt.Errorf("storage extension '%s' not found", storageID)
+	}
+
+	storageExtension, ok := extension.(storage.Extension)
+	if !ok {
+		return nil, fmt.Errorf("non-storage extension '%s' found", storageID)
+	}
+
+	return storageExtension.GetClient(ctx, component.KindProcessor, componentID, "")
+}
+
+// tokenizer creates random tokens that are consistent for the same value.
+type tokenizer struct {
+	secret    []byte
+	client    storage.Client
+	mux       sync.Mutex
+	cacheSize int
+	cache     map[string]*list.Element
+	lru       *list.List
+}
+
+// cachedToken is a token kept in memory, along with its key.
+type cachedToken struct {
+	key   string
+	token string
+}
+
+// newTokenizer creates a new tokenizer, keeping up to cacheSize tokens in memory.
+// A cacheSize of 0 keeps every token in memory.
+// Tokens are only kept in memory until a storage client is set.
+func newTokenizer(secret []byte, cacheSize int) *tokenizer {
+	return &tokenizer{
+		secret:    secret,
+		client:    storage.NewNopClient(),
+		cacheSize: cacheSize,
+		cache:     make(map[string]*list.Element),
+		lru:       list.New(),
+	}
+}
+
+// token returns the token of the value, creating one if the value has not been seen.
+func (t *tokenizer) token(ctx context.Context, value string) (string, error) {
+	key := tokenKeyPrefix + hashValue(t.secret, value)
+
+	t.mux.Lock()
+	defer t.mux.Unlock()
+
+	if element, ok := t.cache[key]; ok {
+		t.lru.MoveToFront(element)
+		return element.Value.(*cachedToken).token, nil
+	}
+
+	data, err := t.client.Get(ctx, key)
+	if err != nil {
+		return "", fmt.Errorf("get token: %w", err)
+	}
+
+	if data != nil {
+		t.cacheToken(key, string(data))
+		return string(data), nil
+	}
+
+	token, err := newToken()
+	if err != nil {
+		return "", err
+	}
+
+	if err := t.client.Set(ctx, key, []byte(token)); err != nil {
+		return "", fmt.Errorf("set token: %w", err)
+	}
+
+	t.cacheToken(key, token)
+	return token, nil
+}
+
+// cacheToken keeps the token in memory, removing the least recently used token if the cache is full.
+func (t *tokenizer) cacheToken(key, token string) {
+	if t.cacheSize > 0 && t.lru.Len() >= t.cacheSize {
+		oldest := t.lru.Remove(t.lru.Back()).(*cachedToken)
+		delete(t.cache, oldest.key)
+	}
+
+	t.cache[key] = t.lru.PushFront(&cachedToken{key: key, token: token})
+}
+
+// newToken creates a new random token.
+func newToken() (string, error) {
+	b := make([]byte, tokenBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("create token: %w", err)
+	}
+
+	return tokenPrefix + hex.EncodeToString(b), nil
+}
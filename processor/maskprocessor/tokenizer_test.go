@@ -0,0 +1,156 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package maskprocessor
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/extension/experimental/storage"
+)
+
+// memoryClient is an in memory storage client.
+type memoryClient struct {
+	storage.Client
+	data map[string][]byte
+}
+
+func (c *memoryClient) Get(_ context.Context, key string) ([]byte, error) {
+	return c.data[key], nil
+}
+
+func (c *memoryClient) Set(_ context.Context, key string, value []byte) error {
+	c.data[key] = value
+	return nil
+}
+
+func TestTokenizerConsistent(t *testing.T) {
+	tokenizer := newTokenizer([]byte("secret"), tokenCacheSize)
+
+	token, err := tokenizer.token(context.Background(), "value")
+	require.NoError(t, err)
+	require.Regexp(t, `^tok_[0-9a-f]{16}$`, token)
+
+	again, err := tokenizer.token(context.Background(), "value")
+	require.NoError(t, err)
+	require.Equal(t, token, again)
+
+	other, err := tokenizer.token(context.Background(), "other")
+	require.NoError(t, err)
+	require.NotEqual(t, token, other)
+}
+
+func TestTokenizerPersisted(t *testing.T) {
+	client := &memoryClient{data: make(map[string][]byte)}
+
+	tokenizer := newTokenizer([]byte("secret"), tokenCacheSize)
+	tokenizer.client = client
+	token, err := tokenizer.token(context.Background(), "value")
+	require.NoError(t, err)
+
+	require.Len(t, client.data, 1)
+	for key := range client.data {
+		require.NotContains(t, key, "value")
+	}
+
+	restarted := newTokenizer([]byte("secret"), tokenCacheSize)
+	restarted.client = client
+	again, err := restarted.token(context.Background(), "value")
+	require.NoError(t, err)
+	require.Equal(t, token, again)
+}
+
+func TestTokenizerEvictsLeastRecentlyUsed(t *testing.T) {
+	client := &memoryClient{data: make(map[string][]byte)}
+
+	tokenizer := newTokenizer([]byte("secret"), 2)
+	tokenizer.client = client
+	first, err := tokenizer.token(context.Background(), "first")
+	require.NoError(t, err)
+	_, err = tokenizer.token(context.Background(), "second")
+	require.NoError(t, err)
+	_, err = tokenizer.token(context.Background(), "first")
+	require.NoError(t, err)
+	_, err = tokenizer.token(context.Background(), "third")
+	require.NoError(t, err)
+
+	require.Equal(t, 2, tokenizer.lru.Len())
+	require.Len(t, tokenizer.cache, 2)
+	require.NotContains(t, tokenizer.cache, tokenKeyPrefix+hashValue([]byte("secret"), "second"))
+
+	again, err := tokenizer.token(context.Background(), "first")
+	require.NoError(t, err)
+	require.Equal(t, first, again)
+}
+
+func TestTokenizerEvictedValueSeenAgain(t *testing.T) {
+	client := &memoryClient{data: make(map[string][]byte)}
+
+	tokenizer := newTokenizer([]byte("secret"), 1)
+	tokenizer.client = client
+	first, err := tokenizer.token(context.Background(), "first")
+	require.NoError(t, err)
+	_, err = tokenizer.token(context.Background(), "second")
+	require.NoError(t, err)
+	require.NotContains(t, tokenizer.cache, tokenKeyPrefix+hashValue([]byte("secret"), "first"))
+
+	again, err := tokenizer.token(context.Background(), "first")
+	require.NoError(t, err)
+	require.Equal(t, first, again)
+}
+
+func TestAcquireTokenizerWithoutStorageKeepsAllTokens(t *testing.T) {
+	id := component.NewIDWithName(typeStr, "nostorage")
+	cfg := &Config{HashSecret: "secret"}
+
+	tokenizer, err := acquireTokenizer(context.Background(), componenttest.NewNopHost(), cfg, id)
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, releaseTokenizer(context.Background(), id))
+	}()
+	require.Equal(t, 0, tokenizer.cacheSize)
+
+	first, err := tokenizer.token(context.Background(), "first")
+	require.NoError(t, err)
+	for i := 0; i < 100; i++ {
+		_, err = tokenizer.token(context.Background(), fmt.Sprintf("value-%d", i))
+		require.NoError(t, err)
+	}
+
+	again, err := tokenizer.token(context.Background(), "first")
+	require.NoError(t, err)
+	require.Equal(t, first, again)
+}
+
+func TestAcquireTokenizerShared(t *testing.T) {
+	id := component.NewIDWithName(typeStr, "shared")
+	cfg := &Config{HashSecret: "secret"}
+	host := componenttest.NewNopHost()
+
+	logs, err := acquireTokenizer(context.Background(), host, cfg, id)
+	require.NoError(t, err)
+	traces, err := acquireTokenizer(context.Background(), host, cfg, id)
+	require.NoError(t, err)
+	require.Same(t, logs, traces)
+
+	require.NoError(t, releaseTokenizer(context.Background(), id))
+	require.Contains(t, tokenizers, id)
+	require.NoError(t, releaseTokenizer(context.Background(), id))
+	require.NotContains(t, tokenizers, id)
+}
@@ -18,7 +18,10 @@ package metricextractprocessor
 import (
 	"errors"
 	"fmt"
+	"sort"
+	"time"
 
+	"github.com/observiq/bindplane-agent/counter"
 	"github.com/observiq/bindplane-agent/expr"
 	"go.opentelemetry.io/collector/component"
 	"go.uber.org/zap"
@@ -51,8 +54,17 @@ const (
 
 	// counterIntType is the counter int metric type.
 	counterIntType = "counter_int"
+
+	// histogramType is the histogram metric type.
+	histogramType = "histogram"
+
+	// defaultInterval is the default interval histograms are aggregated over.
+	defaultInterval = time.Minute
 )
 
+// defaultBuckets are the default explicit bucket boundaries of histograms.
+var defaultBuckets = []float64{0, 5, 10, 25, 50, 75, 100, 250, 500, 750, 1000, 2500, 5000, 7500, 10000}
+
 var (
 	// errExprExtractMissing is the error message for a missing expr extract expression.
 	errExprExtractMissing = errors.New("extract expression is required")
@@ -62,6 +74,24 @@ var (
 
 	// errMetricTypeInvalid is the error message for an invalid metric type.
 	errMetricTypeInvalid = errors.New("invalid metric type")
+
+	// errMetricNameMissing is the error message for a missing metric name in metrics.
+	errMetricNameMissing = errors.New("metric_name is required")
+
+	// errBucketsInvalid is the error message for histogram buckets that are not sorted.
+	errBucketsInvalid = errors.New("buckets must be in increasing order")
+
+	// errHistogramExpr is the error message for a histogram metric type used with expr fields.
+	errHistogramExpr = errors.New("histogram metric type is only supported with ottl fields")
+
+	// errIntervalInvalid is the error message for an invalid histogram interval.
+	errIntervalInvalid = errors.New("interval must be positive")
+
+	// errMetricsWithExtract is the error message for top level extraction fields used with metrics.
+	errMetricsWithExtract = errors.New("ottl_extract and ottl_attributes cannot be used with metrics")
+
+	// errExemplarsExpr is the error message for exemplars used with expr fields.
+	errExemplarsExpr = errors.New("exemplars are only supported with ottl fields")
 )
 
 // Config is the config of the processor.
@@ -76,21 +106,68 @@ type Config struct {
 	MetricType     string            `mapstructure:"metric_type"`
 	Attributes     map[string]string `mapstructure:"attributes"`
 	OTTLAttributes map[string]string `mapstructure:"ottl_attributes"`
+	Buckets        []float64         `mapstructure:"buckets"`
+	Interval       time.Duration     `mapstructure:"interval"`
+
+	// Metrics is a list of metrics to extract from each log, used instead of the single metric fields.
+	// Logs must match the top level ottl_match before any of these metrics are extracted.
+	Metrics []MetricConfig `mapstructure:"metrics"`
+
+	// Exemplars configures the exemplars of the logs attached to the extracted datapoints.
+	// Gauge and counter datapoints are extracted from a single log, so have a single exemplar.
+	Exemplars counter.ExemplarConfig `mapstructure:"exemplars"`
+}
+
+// MetricConfig is the config of one metric extracted from logs.
+type MetricConfig struct {
+	OTTLMatch      *string           `mapstructure:"ottl_match"`
+	OTTLExtract    string            `mapstructure:"ottl_extract"`
+	MetricName     string            `mapstructure:"metric_name"`
+	MetricUnit     string            `mapstructure:"metric_unit"`
+	MetricType     string            `mapstructure:"metric_type"`
+	OTTLAttributes map[string]string `mapstructure:"ottl_attributes"`
+	Buckets        []float64         `mapstructure:"buckets"`
 }
 
 // Validate validates the config.
 func (c Config) Validate() error {
 	usesExprFields := c.Extract != "" || c.Match != nil || c.Attributes != nil
-	usesOTTLFields := c.OTTLExtract != "" || c.OTTLMatch != nil || c.OTTLAttributes != nil
+	usesOTTLFields := c.OTTLExtract != "" || c.OTTLMatch != nil || c.OTTLAttributes != nil || len(c.Metrics) != 0
 
 	if usesExprFields && usesOTTLFields {
 		return errors.New("cannot use ottl fields (ottl_match, ottl_extract, ottl_attributes) and expr fields (match, extract, attributes)")
 	}
 
-	switch c.MetricType {
-	case gaugeDoubleType, gaugeIntType, counterDoubleType, counterIntType: // OK
-	default:
-		return errMetricTypeInvalid
+	if len(c.Metrics) != 0 && (c.OTTLExtract != "" || c.OTTLAttributes != nil) {
+		return errMetricsWithExtract
+	}
+
+	for _, mc := range c.metricConfigs() {
+		switch mc.MetricType {
+		case gaugeDoubleType, gaugeIntType, counterDoubleType, counterIntType: // OK
+		case histogramType:
+			if !c.isOTTL() {
+				return errHistogramExpr
+			}
+
+			if !sort.Float64sAreSorted(mc.Buckets) || hasDuplicates(mc.Buckets) {
+				return fmt.Errorf("%s: %w", mc.MetricName, errBucketsInvalid)
+			}
+
+			if c.Interval <= 0 {
+				return errIntervalInvalid
+			}
+		default:
+			return errMetricTypeInvalid
+		}
+	}
+
+	if err := c.Exemplars.Validate(); err != nil {
+		return err
+	}
+
+	if c.Exemplars.Enabled && !c.isOTTL() {
+		return errExemplarsExpr
 	}
 
 	if c.isOTTL() {
@@ -123,10 +200,6 @@ func (c Config) validateExpr() error {
 }
 
 func (c Config) validateOTTL() error {
-	if c.OTTLExtract == "" {
-		return errOTTLExtractMissing
-	}
-
 	_, err := expr.NewOTTLLogRecordCondition(c.ottlMatchExpression(), component.TelemetrySettings{
 		Logger: zap.NewNop(),
 	})
@@ -134,14 +207,44 @@ func (c Config) validateOTTL() error {
 		return fmt.Errorf("invalid ottl_match: %w", err)
 	}
 
-	_, err = expr.NewOTTLLogRecordExpression(c.OTTLExtract, component.TelemetrySettings{
+	for _, mc := range c.metricConfigs() {
+		if err := mc.validateOTTL(); err != nil {
+			if len(c.Metrics) != 0 {
+				return fmt.Errorf("metric %s: %w", mc.MetricName, err)
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (mc MetricConfig) validateOTTL() error {
+	if mc.OTTLExtract == "" {
+		return errOTTLExtractMissing
+	}
+
+	if mc.MetricName == "" {
+		return errMetricNameMissing
+	}
+
+	if mc.OTTLMatch != nil {
+		_, err := expr.NewOTTLLogRecordCondition(*mc.OTTLMatch, component.TelemetrySettings{
+			Logger: zap.NewNop(),
+		})
+		if err != nil {
+			return fmt.Errorf("invalid ottl_match: %w", err)
+		}
+	}
+
+	_, err := expr.NewOTTLLogRecordExpression(mc.OTTLExtract, component.TelemetrySettings{
 		Logger: zap.NewNop(),
 	})
 	if err != nil {
 		return fmt.Errorf("invalid ottl_extract: %w", err)
 	}
 
-	_, err = expr.MakeOTTLAttributeMap(c.OTTLAttributes, component.TelemetrySettings{
+	_, err = expr.MakeOTTLAttributeMap(mc.OTTLAttributes, component.TelemetrySettings{
 		Logger: zap.NewNop(),
 	}, expr.NewOTTLLogRecordExpression)
 	if err != nil {
@@ -151,6 +254,65 @@ func (c Config) validateOTTL() error {
 	return nil
 }
 
+// metricConfigs returns the configs of all metrics to extract, with defaults applied.
+// If metrics is not set, the single metric is configured by the top level fields.
+func (c Config) metricConfigs() []MetricConfig {
+	if len(c.Metrics) == 0 {
+		return []MetricConfig{
+			{
+				OTTLExtract:    c.OTTLExtract,
+				MetricName:     c.MetricName,
+				MetricUnit:     c.MetricUnit,
+				MetricType:     c.MetricType,
+				OTTLAttributes: c.OTTLAttributes,
+				Buckets:        bucketsOrDefault(c.Buckets),
+			},
+		}
+	}
+
+	configs := make([]MetricConfig, 0, len(c.Metrics))
+	for _, mc := range c.Metrics {
+		if mc.MetricUnit == "" {
+			mc.MetricUnit = defaultMetricUnit
+		}
+
+		if mc.MetricType == "" {
+			mc.MetricType = defaultMetricType
+		}
+
+		mc.Buckets = bucketsOrDefault(mc.Buckets)
+		configs = append(configs, mc)
+	}
+
+	return configs
+}
+
+// hasHistograms returns true if any of the extracted metrics are histograms.
+func (c Config) hasHistograms() bool {
+	for _, mc := range c.metricConfigs() {
+		if mc.MetricType == histogramType {
+			return true
+		}
+	}
+	return false
+}
+
+func bucketsOrDefault(buckets []float64) []float64 {
+	if buckets == nil {
+		return defaultBuckets
+	}
+	return buckets
+}
+
+func hasDuplicates(buckets []float64) bool {
+	for i := 1; i < len(buckets); i++ {
+		if buckets[i] == buckets[i-1] {
+			return true
+		}
+	}
+	return false
+}
+
 func (c Config) exprMatchExpression() string {
 	if c.Match != nil {
 		return *c.Match
@@ -177,5 +339,9 @@ func createDefaultConfig() component.Config {
 		MetricName: defaultMetricName,
 		MetricUnit: defaultMetricUnit,
 		MetricType: defaultMetricType,
+		Interval:   defaultInterval,
+		Exemplars: counter.ExemplarConfig{
+			MaxPerDatapoint: counter.DefaultMaxExemplars,
+		},
 	}
 }
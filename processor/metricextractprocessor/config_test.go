@@ -16,7 +16,9 @@ package metricextractprocessor
 
 import (
 	"testing"
+	"time"
 
+	"github.com/observiq/bindplane-agent/counter"
 	"github.com/stretchr/testify/require"
 )
 
@@ -166,6 +168,172 @@ func TestConfigValidate(t *testing.T) {
 			},
 			expectedErr: "cannot use ottl fields (ottl_match, ottl_extract, ottl_attributes) and expr fields (match, extract, attributes)",
 		},
+		{
+			name: "valid histogram",
+			config: &Config{
+				OTTLExtract: `body["duration"]`,
+				MetricName:  "metric",
+				MetricType:  histogramType,
+				Buckets:     []float64{1, 10, 100},
+				Interval:    time.Minute,
+			},
+		},
+		{
+			name: "histogram buckets out of order",
+			config: &Config{
+				OTTLExtract: `body["duration"]`,
+				MetricName:  "metric",
+				MetricType:  histogramType,
+				Buckets:     []float64{10, 1},
+				Interval:    time.Minute,
+			},
+			expectedErr: "metric: buckets must be in increasing order",
+		},
+		{
+			name: "histogram duplicate buckets",
+			config: &Config{
+				OTTLExtract: `body["duration"]`,
+				MetricName:  "metric",
+				MetricType:  histogramType,
+				Buckets:     []float64{1, 1},
+				Interval:    time.Minute,
+			},
+			expectedErr: "metric: buckets must be in increasing order",
+		},
+		{
+			name: "histogram without interval",
+			config: &Config{
+				OTTLExtract: `body["duration"]`,
+				MetricName:  "metric",
+				MetricType:  histogramType,
+			},
+			expectedErr: "interval must be positive",
+		},
+		{
+			name: "histogram with expr",
+			config: &Config{
+				Extract:    "duration",
+				MetricName: "metric",
+				MetricType: histogramType,
+				Interval:   time.Minute,
+			},
+			expectedErr: "histogram metric type is only supported with ottl fields",
+		},
+		{
+			name: "exemplars",
+			config: &Config{
+				OTTLExtract: `body["duration"]`,
+				MetricName:  "metric",
+				MetricType:  gaugeDoubleType,
+				Exemplars:   counter.ExemplarConfig{Enabled: true, MaxPerDatapoint: 1},
+			},
+		},
+		{
+			name: "exemplars without max_per_datapoint",
+			config: &Config{
+				OTTLExtract: `body["duration"]`,
+				MetricName:  "metric",
+				MetricType:  gaugeDoubleType,
+				Exemplars:   counter.ExemplarConfig{Enabled: true},
+			},
+			expectedErr: "exemplars.max_per_datapoint must be positive",
+		},
+		{
+			name: "exemplars with expr",
+			config: &Config{
+				Extract:    "duration",
+				MetricName: "metric",
+				MetricType: gaugeDoubleType,
+				Exemplars:  counter.ExemplarConfig{Enabled: true, MaxPerDatapoint: 1},
+			},
+			expectedErr: "exemplars are only supported with ottl fields",
+		},
+		{
+			name: "valid metrics",
+			config: &Config{
+				OTTLMatch: strp(`attributes["type"] == "access"`),
+				Interval:  time.Minute,
+				Metrics: []MetricConfig{
+					{
+						OTTLExtract: `body["bytes"]`,
+						MetricName:  "http.bytes",
+						MetricType:  counterIntType,
+					},
+					{
+						OTTLMatch:   strp(`body["duration"] != nil`),
+						OTTLExtract: `body["duration"]`,
+						MetricName:  "http.duration",
+						MetricType:  histogramType,
+						OTTLAttributes: map[string]string{
+							"status": `body["status"]`,
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "metrics with top level extract",
+			config: &Config{
+				OTTLExtract: `body["bytes"]`,
+				Metrics: []MetricConfig{
+					{
+						OTTLExtract: `body["bytes"]`,
+						MetricName:  "http.bytes",
+					},
+				},
+			},
+			expectedErr: "ottl_extract and ottl_attributes cannot be used with metrics",
+		},
+		{
+			name: "metrics with expr",
+			config: &Config{
+				Match: strp("true"),
+				Metrics: []MetricConfig{
+					{
+						OTTLExtract: `body["bytes"]`,
+						MetricName:  "http.bytes",
+					},
+				},
+			},
+			expectedErr: "cannot use ottl fields",
+		},
+		{
+			name: "metrics missing name",
+			config: &Config{
+				Metrics: []MetricConfig{
+					{
+						OTTLExtract: `body["bytes"]`,
+					},
+				},
+			},
+			expectedErr: "metric_name is required",
+		},
+		{
+			name: "metrics invalid match",
+			config: &Config{
+				Metrics: []MetricConfig{
+					{
+						OTTLMatch:   strp("++"),
+						OTTLExtract: `body["bytes"]`,
+						MetricName:  "http.bytes",
+					},
+				},
+			},
+			expectedErr: "metric http.bytes: invalid ottl_match",
+		},
+		{
+			name: "metrics invalid metric type",
+			config: &Config{
+				Metrics: []MetricConfig{
+					{
+						OTTLExtract: `body["bytes"]`,
+						MetricName:  "http.bytes",
+						MetricType:  "summary",
+					},
+				},
+			},
+			expectedErr: "invalid metric type",
+		},
 	}
 
 	for _, tc := range testCases {
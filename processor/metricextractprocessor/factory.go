@@ -80,15 +80,43 @@ func createOTTLProcessor(params processor.CreateSettings, cfg *Config, consumer
 		return nil, fmt.Errorf("invalid ottl_match: %w", err)
 	}
 
-	value, err := expr.NewOTTLLogRecordExpression(cfg.OTTLExtract, params.TelemetrySettings)
+	metricConfigs := cfg.metricConfigs()
+	extractors := make([]*ottlMetricExtractor, 0, len(metricConfigs))
+	for _, mc := range metricConfigs {
+		extractor, err := createOTTLMetricExtractor(params, mc)
+		if err != nil && len(cfg.Metrics) != 0 {
+			return nil, fmt.Errorf("metric %s: %w", mc.MetricName, err)
+		}
+		if err != nil {
+			return nil, err
+		}
+		extractors = append(extractors, extractor)
+	}
+
+	return newOTTLExtractProcessor(cfg, consumer, match, extractors, params.Logger), nil
+}
+
+func createOTTLMetricExtractor(params processor.CreateSettings, mc MetricConfig) (*ottlMetricExtractor, error) {
+	extractor := &ottlMetricExtractor{config: mc}
+	if mc.OTTLMatch != nil {
+		match, err := expr.NewOTTLLogRecordCondition(*mc.OTTLMatch, params.TelemetrySettings)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ottl_match: %w", err)
+		}
+		extractor.ottlMatch = match
+	}
+
+	value, err := expr.NewOTTLLogRecordExpression(mc.OTTLExtract, params.TelemetrySettings)
 	if err != nil {
 		return nil, fmt.Errorf("invalid ottl_extract: %w", err)
 	}
+	extractor.ottlValue = value
 
-	attrs, err := expr.MakeOTTLAttributeMap(cfg.OTTLAttributes, params.TelemetrySettings, expr.NewOTTLLogRecordExpression)
+	attrs, err := expr.MakeOTTLAttributeMap(mc.OTTLAttributes, params.TelemetrySettings, expr.NewOTTLLogRecordExpression)
 	if err != nil {
 		return nil, fmt.Errorf("invalid ottl_attributes: %w", err)
 	}
+	extractor.ottlAttrs = attrs
 
-	return newOTTLExtractProcessor(cfg, consumer, match, value, attrs, params.Logger), nil
+	return extractor, nil
 }
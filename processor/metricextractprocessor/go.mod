@@ -3,6 +3,7 @@ module github.com/observiq/bindplane-agent/processor/metricextractprocessor
 go 1.20
 
 require (
+	github.com/observiq/bindplane-agent/counter v1.41.0
 	github.com/observiq/bindplane-agent/expr v1.41.0
 	github.com/observiq/bindplane-agent/receiver/routereceiver v1.41.0
 	github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl v0.91.0
@@ -62,3 +63,5 @@ require (
 replace github.com/observiq/bindplane-agent/receiver/routereceiver => ../../receiver/routereceiver
 
 replace github.com/observiq/bindplane-agent/expr => ../../expr
+
+replace github.com/observiq/bindplane-agent/counter => ../../counter
@@ -0,0 +1,178 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metricextractprocessor
+
+import (
+	"encoding/json"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/observiq/bindplane-agent/counter"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+)
+
+// histogramAggregator aggregates extracted values into histograms until they are flushed.
+type histogramAggregator struct {
+	mux       sync.Mutex
+	start     pcommon.Timestamp
+	resources map[string]*histogramResource
+	// maxExemplars is the maximum number of exemplars sampled per datapoint
+	maxExemplars int
+}
+
+// histogramResource holds the histograms of a resource.
+type histogramResource struct {
+	attributes pcommon.Map
+	// metric name -> histogram metric
+	metrics map[string]*histogramMetric
+}
+
+// histogramMetric holds the datapoints of a histogram metric.
+type histogramMetric struct {
+	unit   string
+	bounds []float64
+	// attributes key -> datapoint
+	datapoints map[string]pmetric.HistogramDataPoint
+	// attributes key -> exemplars sampled for the datapoint
+	exemplars map[string]*counter.ExemplarReservoir
+}
+
+func newHistogramAggregator(maxExemplars int) *histogramAggregator {
+	return &histogramAggregator{
+		start:        pcommon.NewTimestampFromTime(time.Now()),
+		resources:    make(map[string]*histogramResource),
+		maxExemplars: maxExemplars,
+	}
+}
+
+// record adds the value to the histogram of the metric with the resource and attributes.
+// If exemplar is not nil, it is offered to the exemplars sampled for the datapoint.
+func (h *histogramAggregator) record(resource pcommon.Map, mc *MetricConfig, attrs map[string]any, value float64, exemplar *counter.Exemplar) error {
+	h.mux.Lock()
+	defer h.mux.Unlock()
+
+	resourceKey := dimensionKey(resource.AsRaw())
+	hr, ok := h.resources[resourceKey]
+	if !ok {
+		hr = &histogramResource{
+			attributes: pcommon.NewMap(),
+			metrics:    make(map[string]*histogramMetric),
+		}
+		resource.CopyTo(hr.attributes)
+		h.resources[resourceKey] = hr
+	}
+
+	hm, ok := hr.metrics[mc.MetricName]
+	if !ok {
+		hm = &histogramMetric{
+			unit:       mc.MetricUnit,
+			bounds:     mc.Buckets,
+			datapoints: make(map[string]pmetric.HistogramDataPoint),
+			exemplars:  make(map[string]*counter.ExemplarReservoir),
+		}
+		hr.metrics[mc.MetricName] = hm
+	}
+
+	attrsKey := dimensionKey(attrs)
+	dp, ok := hm.datapoints[attrsKey]
+	if !ok {
+		dp = pmetric.NewHistogramDataPoint()
+		if err := dp.Attributes().FromRaw(attrs); err != nil {
+			return err
+		}
+		dp.ExplicitBounds().FromRaw(hm.bounds)
+		dp.BucketCounts().FromRaw(make([]uint64, len(hm.bounds)+1))
+		dp.SetMin(math.Inf(1))
+		dp.SetMax(math.Inf(-1))
+		hm.datapoints[attrsKey] = dp
+		hm.exemplars[attrsKey] = counter.NewExemplarReservoir(h.maxExemplars)
+	}
+
+	// Buckets include their upper bound
+	bucket := sort.SearchFloat64s(hm.bounds, value)
+	dp.BucketCounts().SetAt(bucket, dp.BucketCounts().At(bucket)+1)
+	dp.SetCount(dp.Count() + 1)
+	dp.SetSum(dp.Sum() + value)
+	dp.SetMin(math.Min(dp.Min(), value))
+	dp.SetMax(math.Max(dp.Max(), value))
+
+	if exemplar != nil {
+		hm.exemplars[attrsKey].Offer(*exemplar)
+	}
+
+	return nil
+}
+
+// len returns the number of datapoints aggregated since the previous flush.
+func (h *histogramAggregator) len() int {
+	h.mux.Lock()
+	defer h.mux.Unlock()
+
+	n := 0
+	for _, hr := range h.resources {
+		for _, hm := range hr.metrics {
+			n += len(hm.datapoints)
+		}
+	}
+	return n
+}
+
+// flush returns the aggregated histograms as delta histograms since the previous flush, and resets the aggregator.
+func (h *histogramAggregator) flush() pmetric.Metrics {
+	h.mux.Lock()
+	defer h.mux.Unlock()
+
+	now := pcommon.NewTimestampFromTime(time.Now())
+	metrics := pmetric.NewMetrics()
+	for _, hr := range h.resources {
+		resourceMetrics := metrics.ResourceMetrics().AppendEmpty()
+		hr.attributes.CopyTo(resourceMetrics.Resource().Attributes())
+
+		scopeMetrics := resourceMetrics.ScopeMetrics().AppendEmpty()
+		scopeMetrics.Scope().SetName(typeStr)
+
+		for name, hm := range hr.metrics {
+			metric := scopeMetrics.Metrics().AppendEmpty()
+			metric.SetName(name)
+			metric.SetUnit(hm.unit)
+			histogram := metric.SetEmptyHistogram()
+			histogram.SetAggregationTemporality(pmetric.AggregationTemporalityDelta)
+
+			for attrsKey, dp := range hm.datapoints {
+				dp.SetStartTimestamp(h.start)
+				dp.SetTimestamp(now)
+				for _, exemplar := range hm.exemplars[attrsKey].Exemplars() {
+					// Exemplar attributes were copied from a pcommon.Map, so they can always be set
+					_ = exemplar.CopyTo(dp.Exemplars().AppendEmpty(), false)
+				}
+				dp.MoveTo(histogram.DataPoints().AppendEmpty())
+			}
+		}
+	}
+
+	h.resources = make(map[string]*histogramResource)
+	h.start = now
+
+	return metrics
+}
+
+// dimensionKey returns a unique key for the dimension.
+func dimensionKey(dimension map[string]any) string {
+	dimensionJSON, _ := json.Marshal(dimension)
+	return string(dimensionJSON)
+}
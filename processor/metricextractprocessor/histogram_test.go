@@ -0,0 +1,228 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metricextractprocessor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/observiq/bindplane-agent/counter"
+	"github.com/observiq/bindplane-agent/receiver/routereceiver"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/processor/processortest"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+)
+
+func TestHistogramAggregator(t *testing.T) {
+	h := newHistogramAggregator(0)
+	mc := &MetricConfig{
+		MetricName: "request.duration",
+		MetricUnit: "ms",
+		MetricType: histogramType,
+		Buckets:    []float64{10, 100},
+	}
+
+	resource := pcommon.NewMap()
+	resource.PutStr("host.name", "test")
+
+	for _, value := range []float64{1, 10, 11, 100, 500} {
+		require.NoError(t, h.record(resource, mc, map[string]any{"status": "200"}, value, nil))
+	}
+	require.NoError(t, h.record(resource, mc, map[string]any{"status": "500"}, 50, nil))
+
+	metrics := h.flush()
+	require.Equal(t, 1, metrics.ResourceMetrics().Len())
+	require.Equal(t, map[string]any{"host.name": "test"}, metrics.ResourceMetrics().At(0).Resource().Attributes().AsRaw())
+
+	metric := metrics.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0)
+	require.Equal(t, "request.duration", metric.Name())
+	require.Equal(t, "ms", metric.Unit())
+	require.Equal(t, pmetric.AggregationTemporalityDelta, metric.Histogram().AggregationTemporality())
+
+	dps := metric.Histogram().DataPoints()
+	require.Equal(t, 2, dps.Len())
+	for i := 0; i < dps.Len(); i++ {
+		dp := dps.At(i)
+		require.Equal(t, []float64{10, 100}, dp.ExplicitBounds().AsRaw())
+		require.Equal(t, 0, dp.Exemplars().Len())
+		require.NotZero(t, dp.StartTimestamp())
+		require.GreaterOrEqual(t, dp.Timestamp(), dp.StartTimestamp())
+
+		status, _ := dp.Attributes().Get("status")
+		switch status.Str() {
+		case "200":
+			require.Equal(t, uint64(5), dp.Count())
+			require.Equal(t, 622.0, dp.Sum())
+			require.Equal(t, 1.0, dp.Min())
+			require.Equal(t, 500.0, dp.Max())
+			require.Equal(t, []uint64{2, 2, 1}, dp.BucketCounts().AsRaw())
+		case "500":
+			require.Equal(t, uint64(1), dp.Count())
+			require.Equal(t, []uint64{0, 1, 0}, dp.BucketCounts().AsRaw())
+		default:
+			t.Fatalf("unexpected status %q", status.Str())
+		}
+	}
+
+	// The aggregator is reset after each flush
+	require.Equal(t, 0, h.flush().ResourceMetrics().Len())
+}
+
+func TestHistogramAggregatorExemplars(t *testing.T) {
+	h := newHistogramAggregator(2)
+	mc := &MetricConfig{
+		MetricName: "request.duration",
+		MetricType: histogramType,
+		Buckets:    []float64{10, 100},
+	}
+
+	resource := pcommon.NewMap()
+	for i := 0; i < 10; i++ {
+		exemplar := counter.Exemplar{SpanID: [8]byte{byte(i)}, Value: float64(i)}
+		require.NoError(t, h.record(resource, mc, map[string]any{}, float64(i), &exemplar))
+	}
+
+	dp := h.flush().ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0).Histogram().DataPoints().At(0)
+	require.Equal(t, uint64(10), dp.Count())
+	require.Equal(t, 2, dp.Exemplars().Len())
+	for i := 0; i < dp.Exemplars().Len(); i++ {
+		// Each exemplar has the value of the log it was sampled from
+		exemplar := dp.Exemplars().At(i)
+		require.Equal(t, float64(exemplar.SpanID()[0]), exemplar.DoubleValue())
+	}
+}
+
+func TestProcessorExtractMultipleMetrics(t *testing.T) {
+	routeReceiverName := "TestProcessorExtractMultipleMetrics"
+
+	routeMetrics := &consumertest.MetricsSink{}
+	createSettings := receivertest.NewNopCreateSettings()
+	createSettings.ID = component.NewIDWithName(component.DataTypeMetrics, routeReceiverName)
+
+	_, err := routereceiver.NewFactory().CreateMetricsReceiver(context.Background(), createSettings, routereceiver.Config{}, routeMetrics)
+	require.NoError(t, err)
+
+	cfg := &Config{
+		Route:     routeReceiverName,
+		OTTLMatch: strp(`attributes["type"] == "access"`),
+		Interval:  time.Hour,
+		Metrics: []MetricConfig{
+			{
+				OTTLExtract: `body["bytes"]`,
+				MetricName:  "http.response.size",
+				MetricUnit:  "By",
+				MetricType:  counterIntType,
+			},
+			{
+				OTTLMatch:   strp(`body["duration"] != nil`),
+				OTTLExtract: `body["duration"]`,
+				MetricName:  "http.duration",
+				MetricUnit:  "ms",
+				MetricType:  histogramType,
+				Buckets:     []float64{100, 1000},
+				OTTLAttributes: map[string]string{
+					"status": `body["status"]`,
+				},
+			},
+		},
+	}
+	require.NoError(t, cfg.Validate())
+
+	p, err := NewFactory().CreateLogsProcessor(context.Background(), processortest.NewNopCreateSettings(), cfg, consumertest.NewNop())
+	require.NoError(t, err)
+	require.NoError(t, p.Start(context.Background(), componenttest.NewNopHost()))
+
+	logs := plog.NewLogs()
+	logRecords := logs.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords()
+	for _, body := range []map[string]any{
+		{"bytes": 100, "duration": 50, "status": "200"},
+		{"bytes": 200, "duration": 5000, "status": "200"},
+		{"bytes": 300},
+	} {
+		lr := logRecords.AppendEmpty()
+		lr.Attributes().PutStr("type", "access")
+		require.NoError(t, lr.Body().SetEmptyMap().FromRaw(body))
+	}
+	ignored := logRecords.AppendEmpty()
+	require.NoError(t, ignored.Body().SetEmptyMap().FromRaw(map[string]any{"bytes": 400, "duration": 1}))
+
+	require.NoError(t, p.ConsumeLogs(context.Background(), logs))
+
+	// Number metrics are sent as records are consumed
+	require.Equal(t, 1, len(routeMetrics.AllMetrics()))
+	counter := routeMetrics.AllMetrics()[0].ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+	require.Equal(t, 1, counter.Len())
+	require.Equal(t, "http.response.size", counter.At(0).Name())
+	require.Equal(t, 3, counter.At(0).Sum().DataPoints().Len())
+
+	// Histograms are sent on the interval
+	p.(*ottlExtractProcessor).sendHistograms(context.Background())
+	require.Equal(t, 2, len(routeMetrics.AllMetrics()))
+	histogram := routeMetrics.AllMetrics()[1].ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+	require.Equal(t, 1, histogram.Len())
+	require.Equal(t, "http.duration", histogram.At(0).Name())
+
+	dps := histogram.At(0).Histogram().DataPoints()
+	require.Equal(t, 1, dps.Len())
+	require.Equal(t, map[string]any{"status": "200"}, dps.At(0).Attributes().AsRaw())
+	require.Equal(t, uint64(2), dps.At(0).Count())
+	require.Equal(t, []uint64{1, 0, 1}, dps.At(0).BucketCounts().AsRaw())
+}
+
+func TestProcessorShutdownDiscardsHistograms(t *testing.T) {
+	cfg := &Config{
+		Route:     "TestProcessorShutdownDiscardsHistograms",
+		OTTLMatch: strp("true"),
+		Interval:  time.Hour,
+		Metrics: []MetricConfig{
+			{
+				OTTLExtract: `body["duration"]`,
+				MetricName:  "http.duration",
+				MetricUnit:  "ms",
+				MetricType:  histogramType,
+				Buckets:     []float64{100, 1000},
+				OTTLAttributes: map[string]string{
+					"status": `body["status"]`,
+				},
+			},
+		},
+	}
+	require.NoError(t, cfg.Validate())
+
+	p, err := NewFactory().CreateLogsProcessor(context.Background(), processortest.NewNopCreateSettings(), cfg, consumertest.NewNop())
+	require.NoError(t, err)
+	require.NoError(t, p.Start(context.Background(), componenttest.NewNopHost()))
+
+	logs := plog.NewLogs()
+	logRecords := logs.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords()
+	for _, body := range []map[string]any{
+		{"duration": 50, "status": "200"},
+		{"duration": 60, "status": "200"},
+		{"duration": 5000, "status": "500"},
+	} {
+		require.NoError(t, logRecords.AppendEmpty().Body().SetEmptyMap().FromRaw(body))
+	}
+	require.NoError(t, p.ConsumeLogs(context.Background(), logs))
+
+	require.NoError(t, p.Shutdown(context.Background()))
+	require.Equal(t, 2, p.(*ottlExtractProcessor).DiscardedOnShutdown())
+}
@@ -17,9 +17,12 @@ package metricextractprocessor
 import (
 	"context"
 	"fmt"
+	"math"
 	"strconv"
+	"sync"
 	"time"
 
+	"github.com/observiq/bindplane-agent/counter"
 	"github.com/observiq/bindplane-agent/expr"
 	"github.com/observiq/bindplane-agent/receiver/routereceiver"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottllog"
@@ -33,34 +36,54 @@ import (
 
 // ottlExtractProcessor is a processor that extracts metrics from logs using OTTL expressions
 type ottlExtractProcessor struct {
-	config    *Config
+	config     *Config
+	ottlMatch  *expr.OTTLCondition[ottllog.TransformContext]
+	extractors []*ottlMetricExtractor
+	histograms *histogramAggregator
+	consumer   consumer.Logs
+	logger     *zap.Logger
+	cancel     context.CancelFunc
+	wg         sync.WaitGroup
+}
+
+// ottlMetricExtractor extracts a single metric from logs.
+type ottlMetricExtractor struct {
+	config    MetricConfig
 	ottlMatch *expr.OTTLCondition[ottllog.TransformContext]
 	ottlValue *expr.OTTLExpression[ottllog.TransformContext]
 	ottlAttrs *expr.OTTLAttributeMap[ottllog.TransformContext]
-	consumer  consumer.Logs
-	logger    *zap.Logger
 }
 
-// newProcessor returns a new processor for .
+// newOTTLExtractProcessor returns a new processor for OTTL expressions.
+// Logs must match the match condition before any metric is extracted.
 func newOTTLExtractProcessor(
 	config *Config,
 	consumer consumer.Logs,
 	match *expr.OTTLCondition[ottllog.TransformContext],
-	value *expr.OTTLExpression[ottllog.TransformContext],
-	attrs *expr.OTTLAttributeMap[ottllog.TransformContext],
+	extractors []*ottlMetricExtractor,
 	logger *zap.Logger) *ottlExtractProcessor {
 	return &ottlExtractProcessor{
-		config:    config,
-		ottlMatch: match,
-		ottlValue: value,
-		ottlAttrs: attrs,
-		consumer:  consumer,
-		logger:    logger,
+		config:     config,
+		ottlMatch:  match,
+		extractors: extractors,
+		histograms: newHistogramAggregator(config.Exemplars.Limit()),
+		consumer:   consumer,
+		logger:     logger,
 	}
 }
 
 // Start starts the processor.
 func (e *ottlExtractProcessor) Start(_ context.Context, _ component.Host) error {
+	if !e.config.hasHistograms() {
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	e.cancel = cancel
+
+	e.wg.Add(1)
+	go e.handleHistogramInterval(ctx)
+
 	return nil
 }
 
@@ -71,9 +94,21 @@ func (e *ottlExtractProcessor) Capabilities() consumer.Capabilities {
 
 // Shutdown stops the processor.
 func (e *ottlExtractProcessor) Shutdown(_ context.Context) error {
+	if e.cancel == nil {
+		return nil
+	}
+
+	e.cancel()
+	e.wg.Wait()
 	return nil
 }
 
+// DiscardedOnShutdown returns the number of histogram datapoints aggregated since the last interval, which are discarded on shutdown.
+// They can't be flushed, since the route receiver they are sent to stops before processors.
+func (e *ottlExtractProcessor) DiscardedOnShutdown() int {
+	return e.histograms.len()
+}
+
 // ConsumeLogs processes the logs.
 func (e *ottlExtractProcessor) ConsumeLogs(ctx context.Context, pl plog.Logs) error {
 	metrics := e.extractMetrics(ctx, pl)
@@ -84,6 +119,31 @@ func (e *ottlExtractProcessor) ConsumeLogs(ctx context.Context, pl plog.Logs) er
 	return e.consumer.ConsumeLogs(ctx, pl)
 }
 
+// handleHistogramInterval sends the aggregated histograms at the configured interval.
+func (e *ottlExtractProcessor) handleHistogramInterval(ctx context.Context) {
+	defer e.wg.Done()
+
+	ticker := time.NewTicker(e.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			e.sendHistograms(ctx)
+		}
+	}
+}
+
+// sendHistograms flushes the aggregated histograms to the configured route.
+func (e *ottlExtractProcessor) sendHistograms(ctx context.Context) {
+	metrics := e.histograms.flush()
+	if metrics.ResourceMetrics().Len() != 0 {
+		e.sendMetrics(ctx, metrics)
+	}
+}
+
 func (e *ottlExtractProcessor) extractMetrics(ctx context.Context, pl plog.Logs) pmetric.Metrics {
 	metrics := pmetric.NewMetrics()
 
@@ -99,17 +159,8 @@ func (e *ottlExtractProcessor) extractMetrics(ctx context.Context, pl plog.Logs)
 		scopeMetrics := resourceMetrics.ScopeMetrics().AppendEmpty()
 		scopeMetrics.Scope().SetName(typeStr)
 
-		metric := scopeMetrics.Metrics().AppendEmpty()
-		metric.SetName(e.config.MetricName)
-		metric.SetUnit(e.config.MetricUnit)
-
-		var dpSlice pmetric.NumberDataPointSlice = pmetric.NewNumberDataPointSlice()
-		switch e.config.MetricType {
-		case gaugeDoubleType, gaugeIntType:
-			dpSlice = metric.SetEmptyGauge().DataPoints()
-		case counterDoubleType, counterIntType:
-			dpSlice = metric.SetEmptySum().DataPoints()
-		}
+		// Each metric is created the first time a datapoint is extracted for it
+		dpSlices := make([]pmetric.NumberDataPointSlice, len(e.extractors))
 
 		for j := 0; j < scopeLogs.Len(); j++ {
 			scopeLog := scopeLogs.At(j)
@@ -128,11 +179,28 @@ func (e *ottlExtractProcessor) extractMetrics(ctx context.Context, pl plog.Logs)
 					continue
 				}
 
-				e.addDatapointOTTL(ctx, lr, logCtx, dpSlice)
+				for l, extractor := range e.extractors {
+					if extractor.config.MetricType == histogramType {
+						e.recordHistogramOTTL(ctx, extractor, resource, lr, logCtx)
+						continue
+					}
+
+					dp, ok := e.extractDatapointOTTL(ctx, extractor, lr, logCtx)
+					if !ok {
+						continue
+					}
+
+					if dpSlices[l] == (pmetric.NumberDataPointSlice{}) {
+						dpSlices[l] = newNumberMetric(scopeMetrics.Metrics(), extractor.config)
+					}
+
+					// Successfully constructed dp, we can add it to the slice
+					dp.MoveTo(dpSlices[l].AppendEmpty())
+				}
 			}
 		}
 
-		if dpSlice.Len() != 0 {
+		if scopeMetrics.Metrics().Len() != 0 {
 			// Add the resource metric to the slice if we had any datapoints.
 			resourceMetrics.MoveTo(metrics.ResourceMetrics().AppendEmpty())
 		}
@@ -141,33 +209,73 @@ func (e *ottlExtractProcessor) extractMetrics(ctx context.Context, pl plog.Logs)
 	return metrics
 }
 
-func (e *ottlExtractProcessor) addDatapointOTTL(ctx context.Context, lr plog.LogRecord, logCtx ottllog.TransformContext, dpSlice pmetric.NumberDataPointSlice) {
-	val, err := e.ottlValue.Execute(ctx, logCtx)
+// newNumberMetric adds a gauge or sum metric, returning its datapoints.
+func newNumberMetric(metrics pmetric.MetricSlice, mc MetricConfig) pmetric.NumberDataPointSlice {
+	metric := metrics.AppendEmpty()
+	metric.SetName(mc.MetricName)
+	metric.SetUnit(mc.MetricUnit)
+
+	switch mc.MetricType {
+	case counterDoubleType, counterIntType:
+		return metric.SetEmptySum().DataPoints()
+	default:
+		return metric.SetEmptyGauge().DataPoints()
+	}
+}
+
+// matchOTTL returns true if the log matches the extractor's own match condition, if it has one.
+func (e *ottlExtractProcessor) matchOTTL(ctx context.Context, extractor *ottlMetricExtractor, logCtx ottllog.TransformContext) bool {
+	if extractor.ottlMatch == nil {
+		return true
+	}
+
+	matches, err := extractor.ottlMatch.Match(ctx, logCtx)
 	if err != nil {
-		e.logger.Error("Failed when extracting value.", zap.Error(err))
-		return
+		e.logger.Error("Failed when executing ottl match statement.", zap.Error(err), zap.String("metric", extractor.config.MetricName))
+		return false
 	}
 
+	return matches
+}
+
+// extractValueOTTL extracts the value of the extractor's metric from the log.
+// It returns nil if the log doesn't match or has no value.
+func (e *ottlExtractProcessor) extractValueOTTL(ctx context.Context, extractor *ottlMetricExtractor, logCtx ottllog.TransformContext) any {
+	if !e.matchOTTL(ctx, extractor, logCtx) {
+		return nil
+	}
+
+	val, err := extractor.ottlValue.Execute(ctx, logCtx)
+	if err != nil {
+		e.logger.Error("Failed when extracting value.", zap.Error(err), zap.String("metric", extractor.config.MetricName))
+		return nil
+	}
+
+	return val
+}
+
+func (e *ottlExtractProcessor) extractDatapointOTTL(ctx context.Context, extractor *ottlMetricExtractor, lr plog.LogRecord, logCtx ottllog.TransformContext) (pmetric.NumberDataPoint, bool) {
+	val := e.extractValueOTTL(ctx, extractor, logCtx)
 	if val == nil {
-		return
+		return pmetric.NumberDataPoint{}, false
 	}
 
-	attrs := e.ottlAttrs.ExtractAttributes(ctx, logCtx)
+	attrs := extractor.ottlAttrs.ExtractAttributes(ctx, logCtx)
 
 	dp := pmetric.NewNumberDataPoint()
-	err = dp.Attributes().FromRaw(attrs)
+	err := dp.Attributes().FromRaw(attrs)
 	if err != nil {
 		e.logger.Error("Failed when setting attributes.", zap.Error(err))
-		return
+		return pmetric.NumberDataPoint{}, false
 	}
 
 	dp.SetTimestamp(extractTimestampFromLogRecord(lr))
-	switch e.config.MetricType {
+	switch extractor.config.MetricType {
 	case gaugeDoubleType, counterDoubleType:
 		floatVal, err := convertAnyToFloat(val)
 		if err != nil {
 			e.logger.Error("Failed when parsing float.", zap.Error(err))
-			return
+			return pmetric.NumberDataPoint{}, false
 		}
 
 		dp.SetDoubleValue(floatVal)
@@ -175,14 +283,59 @@ func (e *ottlExtractProcessor) addDatapointOTTL(ctx context.Context, lr plog.Log
 		intVal, err := convertAnyToInt(val)
 		if err != nil {
 			e.logger.Error("Failed when parsing integer.", zap.Error(err))
-			return
+			return pmetric.NumberDataPoint{}, false
 		}
 
 		dp.SetIntValue(intVal)
 	}
 
-	// Successfully constructed dp, we can add it to the slice
-	dp.MoveTo(dpSlice.AppendEmpty())
+	if e.config.Exemplars.Enabled {
+		isInt := dp.ValueType() == pmetric.NumberDataPointValueTypeInt
+		value := dp.DoubleValue()
+		if isInt {
+			value = float64(dp.IntValue())
+		}
+
+		if err := e.exemplar(lr, value).CopyTo(dp.Exemplars().AppendEmpty(), isInt); err != nil {
+			e.logger.Error("Failed to set exemplar attributes.", zap.Error(err))
+		}
+	}
+
+	return dp, true
+}
+
+// exemplar returns an exemplar of the log with the value, with the configured log attributes.
+func (e *ottlExtractProcessor) exemplar(lr plog.LogRecord, value float64) counter.Exemplar {
+	return e.config.Exemplars.NewExemplar(extractTimestampFromLogRecord(lr), lr.TraceID(), lr.SpanID(), lr.Attributes(), value)
+}
+
+// recordHistogramOTTL records the value extracted from the log in the extractor's histogram.
+func (e *ottlExtractProcessor) recordHistogramOTTL(ctx context.Context, extractor *ottlMetricExtractor, resource pcommon.Resource, lr plog.LogRecord, logCtx ottllog.TransformContext) {
+	val := e.extractValueOTTL(ctx, extractor, logCtx)
+	if val == nil {
+		return
+	}
+
+	floatVal, err := convertAnyToFloat(val)
+	if err != nil {
+		e.logger.Error("Failed when parsing float.", zap.Error(err))
+		return
+	}
+
+	if math.IsNaN(floatVal) {
+		return
+	}
+
+	var exemplar *counter.Exemplar
+	if e.config.Exemplars.Enabled {
+		sampled := e.exemplar(lr, floatVal)
+		exemplar = &sampled
+	}
+
+	attrs := extractor.ottlAttrs.ExtractAttributes(ctx, logCtx)
+	if err := e.histograms.record(resource.Attributes(), &extractor.config, attrs, floatVal, exemplar); err != nil {
+		e.logger.Error("Failed when setting attributes.", zap.Error(err))
+	}
 }
 
 // sendMetrics sends metrics to the configured route.
@@ -19,6 +19,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/observiq/bindplane-agent/counter"
 	"github.com/observiq/bindplane-agent/receiver/routereceiver"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/pdatatest/plogtest"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/pdatatest/pmetrictest"
@@ -640,6 +641,51 @@ func TestProcessorExtractMetrics(t *testing.T) {
 	}
 }
 
+func TestProcessorExtractMetricsExemplars(t *testing.T) {
+	cfg := &Config{
+		Route:       "undefined",
+		OTTLExtract: `body["bytes"]`,
+		MetricName:  "http.response.size",
+		MetricUnit:  "By",
+		MetricType:  counterIntType,
+		Interval:    time.Minute,
+		Exemplars: counter.ExemplarConfig{
+			Enabled:         true,
+			MaxPerDatapoint: 1,
+			Attributes:      []string{"http.route"},
+		},
+	}
+	require.NoError(t, cfg.Validate())
+
+	p, err := NewFactory().CreateLogsProcessor(context.Background(), processortest.NewNopCreateSettings(), cfg, consumertest.NewNop())
+	require.NoError(t, err)
+
+	traceID := pcommon.TraceID([16]byte{1, 2, 3})
+	spanID := pcommon.SpanID([8]byte{4, 5, 6})
+	timestamp := pcommon.NewTimestampFromTime(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	logs := plog.NewLogs()
+	lr := logs.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords().AppendEmpty()
+	lr.SetTraceID(traceID)
+	lr.SetSpanID(spanID)
+	lr.SetTimestamp(timestamp)
+	lr.Attributes().PutStr("http.route", "/users")
+	lr.Attributes().PutStr("user.id", "1234")
+	lr.Body().SetEmptyMap().PutInt("bytes", 512)
+
+	metrics := p.(*ottlExtractProcessor).extractMetrics(context.Background(), logs)
+	dp := metrics.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0).Sum().DataPoints().At(0)
+	require.Equal(t, int64(512), dp.IntValue())
+	require.Equal(t, 1, dp.Exemplars().Len())
+
+	exemplar := dp.Exemplars().At(0)
+	require.Equal(t, traceID, exemplar.TraceID())
+	require.Equal(t, spanID, exemplar.SpanID())
+	require.Equal(t, timestamp, exemplar.Timestamp())
+	require.Equal(t, int64(512), exemplar.IntValue())
+	require.Equal(t, map[string]any{"http.route": "/users"}, exemplar.FilteredAttributes().AsRaw())
+}
+
 func TestConvertAnyToInt(t *testing.T) {
 	testCases := []struct {
 		name     string
@@ -32,6 +32,12 @@ type Config struct {
 	Include string `mapstructure:"include"`
 	// List of stats to calculate for each metric
 	Stats []stats.StatType `mapstructure:"stats"`
+	// Summary emits the statistics as a single summary metric, instead of one metric per statistic.
+	Summary bool `mapstructure:"summary"`
+	// GroupBy is a list of datapoint attributes to keep. Series that only differ by other attributes are combined.
+	GroupBy []string `mapstructure:"group_by"`
+	// DropAttributes is a list of datapoint attributes to remove. Series that only differ by these attributes are combined.
+	DropAttributes []string `mapstructure:"drop_attributes"`
 }
 
 // Validate validates the processor configuration
@@ -44,6 +50,10 @@ func (cfg Config) Validate() error {
 		return errors.New("interval must be positive")
 	}
 
+	if len(cfg.GroupBy) != 0 && len(cfg.DropAttributes) != 0 {
+		return errors.New("only one of `group_by` or `drop_attributes` may be specified")
+	}
+
 	// don't check stats if using defaults
 	if cfg.Stats == nil {
 		return nil
@@ -62,6 +72,10 @@ func (cfg Config) Validate() error {
 			return fmt.Errorf("each statistic type can only be specified once (%s specified more than once)", a)
 		}
 		seenTypes[a] = struct{}{}
+
+		if cfg.Summary && !summaryStat(a) {
+			return fmt.Errorf("statistic type %s cannot be emitted as a summary", a)
+		}
 	}
 
 	return nil
@@ -69,6 +83,16 @@ func (cfg Config) Validate() error {
 
 // StatTypes gets the default stats to calculate if none were specified, otherwise the configured stat types
 func (cfg Config) StatTypes() []stats.StatType {
+	if cfg.Stats == nil && cfg.Summary {
+		return []stats.StatType{
+			stats.MinType,
+			stats.MaxType,
+			stats.P50Type,
+			stats.P90Type,
+			stats.P99Type,
+		}
+	}
+
 	if cfg.Stats == nil {
 		// fallback to default
 		return []stats.StatType{
@@ -80,3 +104,14 @@ func (cfg Config) StatTypes() []stats.StatType {
 
 	return cfg.Stats
 }
+
+// summaryStat returns true if the statistic can be represented in a summary datapoint.
+func summaryStat(a stats.StatType) bool {
+	switch a {
+	case stats.MinType, stats.MaxType, stats.SumType, stats.CountType:
+		return true
+	}
+
+	_, ok := a.Quantile()
+	return ok
+}
@@ -83,9 +83,78 @@ func TestConfig_Validate(t *testing.T) {
 					stats.MaxType,
 					stats.LastType,
 					stats.FirstType,
+					stats.SumType,
+					stats.CountType,
+					stats.StddevType,
+					stats.RateType,
+					stats.P50Type,
+					stats.StatType("p99.9"),
 				},
 			},
 		},
+		{
+			name: "Config with summary",
+			input: Config{
+				Interval: 5 * time.Second,
+				Include:  "^.*$",
+				Stats: []stats.StatType{
+					stats.MinType,
+					stats.MaxType,
+					stats.P90Type,
+				},
+				Summary: true,
+			},
+		},
+		{
+			name: "Config with summary and default stat types",
+			input: Config{
+				Interval: 5 * time.Second,
+				Include:  "^.*$",
+				Summary:  true,
+			},
+		},
+		{
+			name: "Config with summary and unsupported stat type",
+			input: Config{
+				Interval: 5 * time.Second,
+				Include:  "^.*$",
+				Stats: []stats.StatType{
+					stats.P50Type,
+					stats.AvgType,
+				},
+				Summary: true,
+			},
+			expectedErr: "statistic type avg cannot be emitted as a summary",
+		},
+		{
+			name: "Config with group by",
+			input: Config{
+				Interval: 5 * time.Second,
+				Include:  "^.*$",
+				GroupBy:  []string{"state"},
+			},
+		},
+		{
+			name: "Config with group by and drop attributes",
+			input: Config{
+				Interval:       5 * time.Second,
+				Include:        "^.*$",
+				GroupBy:        []string{"state"},
+				DropAttributes: []string{"cpu"},
+			},
+			expectedErr: "only one of `group_by` or `drop_attributes` may be specified",
+		},
+		{
+			name: "Config with invalid percentile",
+			input: Config{
+				Interval: 5 * time.Second,
+				Include:  "^.*$",
+				Stats: []stats.StatType{
+					stats.StatType("p100"),
+				},
+			},
+			expectedErr: "invalid statistic type for `type`: p100",
+		},
 		{
 			name: "Config with no stat types",
 			input: Config{
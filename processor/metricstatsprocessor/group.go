@@ -0,0 +1,145 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metricstatsprocessor
+
+import (
+	"github.com/observiq/bindplane-agent/processor/metricstatsprocessor/internal/stats"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+)
+
+// attributeGrouper reduces datapoint attributes to the attributes that series are grouped by.
+type attributeGrouper struct {
+	// groupBy is the set of attributes to keep, if specified
+	groupBy map[string]struct{}
+	// drop is the set of attributes to remove, if specified
+	drop map[string]struct{}
+}
+
+func newAttributeGrouper(cfg *Config) *attributeGrouper {
+	return &attributeGrouper{
+		groupBy: stringSet(cfg.GroupBy),
+		drop:    stringSet(cfg.DropAttributes),
+	}
+}
+
+// enabled returns true if attributes are reduced by the grouper.
+func (g *attributeGrouper) enabled() bool {
+	return g.groupBy != nil || g.drop != nil
+}
+
+// group returns the attributes that the series is grouped by.
+// If grouping is not enabled, the attributes are returned as is.
+func (g *attributeGrouper) group(attrs pcommon.Map) pcommon.Map {
+	if !g.enabled() {
+		return attrs
+	}
+
+	grouped := pcommon.NewMap()
+	attrs.CopyTo(grouped)
+	grouped.RemoveIf(func(k string, _ pcommon.Value) bool {
+		if g.groupBy != nil {
+			_, keep := g.groupBy[k]
+			return !keep
+		}
+
+		_, drop := g.drop[k]
+		return drop
+	})
+
+	return grouped
+}
+
+// datapointGroup is a set of series that are combined into a single series.
+type datapointGroup struct {
+	attributes pcommon.Map
+	datapoints []*datapointMetadata
+}
+
+// groupDatapoints groups the datapoints of the metric by their grouped attributes.
+func (sp *metricstatsProcessor) groupDatapoints(ma *metricMetadata) []*datapointGroup {
+	groups := make([]*datapointGroup, 0, len(ma.datapoints))
+	groupsByKey := make(map[uint64]*datapointGroup, len(ma.datapoints))
+	for _, dpa := range ma.datapoints {
+		attrs := sp.grouper.group(dpa.attributes)
+		key := mapKey(attrs)
+		group, ok := groupsByKey[key]
+		if !ok {
+			group = &datapointGroup{attributes: attrs}
+			groupsByKey[key] = group
+			groups = append(groups, group)
+		}
+
+		group.datapoints = append(group.datapoints, dpa)
+	}
+
+	return groups
+}
+
+// setGroupValue sets the value of the statistic for the group on dp.
+// Statistics of series that were combined into one group are summed.
+// It returns false if none of the series in the group have the statistic.
+func setGroupValue(dp pmetric.NumberDataPoint, group *datapointGroup, statType stats.StatType) bool {
+	found := false
+	for _, dpa := range group.datapoints {
+		stat, ok := dpa.statistics[statType]
+		if !ok {
+			// this statistics must have failed to be created, so we can't emit it
+			continue
+		}
+
+		if !found {
+			stat.SetDatapointValue(dp)
+			found = true
+			continue
+		}
+
+		val := pmetric.NewNumberDataPoint()
+		stat.SetDatapointValue(val)
+		addNumberValue(dp, val)
+	}
+
+	return found
+}
+
+// addNumberValue adds the value of src to dst. The result is an int only if both values are ints.
+func addNumberValue(dst, src pmetric.NumberDataPoint) {
+	if dst.ValueType() == pmetric.NumberDataPointValueTypeInt && src.ValueType() == pmetric.NumberDataPointValueTypeInt {
+		dst.SetIntValue(dst.IntValue() + src.IntValue())
+		return
+	}
+
+	dst.SetDoubleValue(numberValue(dst) + numberValue(src))
+}
+
+// numberValue returns the value of the datapoint as a double.
+func numberValue(dp pmetric.NumberDataPoint) float64 {
+	if dp.ValueType() == pmetric.NumberDataPointValueTypeInt {
+		return float64(dp.IntValue())
+	}
+	return dp.DoubleValue()
+}
+
+func stringSet(values []string) map[string]struct{} {
+	if len(values) == 0 {
+		return nil
+	}
+
+	set := make(map[string]struct{}, len(values))
+	for _, v := range values {
+		set[v] = struct{}{}
+	}
+	return set
+}
@@ -0,0 +1,174 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metricstatsprocessor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/observiq/bindplane-agent/processor/metricstatsprocessor/internal/stats"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestAttributeGrouper(t *testing.T) {
+	attrs := pcommon.NewMap()
+	attrs.PutStr("cpu", "cpu0")
+	attrs.PutStr("state", "idle")
+	attrs.PutStr("host", "a")
+
+	t.Run("disabled", func(t *testing.T) {
+		g := newAttributeGrouper(&Config{})
+		require.False(t, g.enabled())
+		require.Equal(t, attrs.AsRaw(), g.group(attrs).AsRaw())
+	})
+
+	t.Run("group by", func(t *testing.T) {
+		g := newAttributeGrouper(&Config{GroupBy: []string{"state", "missing"}})
+		require.Equal(t, map[string]any{"state": "idle"}, g.group(attrs).AsRaw())
+	})
+
+	t.Run("drop attributes", func(t *testing.T) {
+		g := newAttributeGrouper(&Config{DropAttributes: []string{"cpu"}})
+		require.Equal(t, map[string]any{"state": "idle", "host": "a"}, g.group(attrs).AsRaw())
+		// The original attributes are not modified
+		require.Equal(t, 3, attrs.Len())
+	})
+}
+
+func TestMetricstatsProcessor_GroupGauge(t *testing.T) {
+	consumer := &consumertest.MetricsSink{}
+	p, err := newStatsProcessor(zaptest.NewLogger(t), &Config{
+		Interval:       time.Hour,
+		Include:        `^test\..*$`,
+		Stats:          []stats.StatType{stats.AvgType, stats.MaxType},
+		DropAttributes: []string{"cpu"},
+	}, consumer)
+	require.NoError(t, err)
+
+	md := pmetric.NewMetrics()
+	m := md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+	m.SetName("test.cpu.utilization")
+	dps := m.SetEmptyGauge().DataPoints()
+	addGroupedDatapoint(dps, "cpu0", "user", 0.2)
+	addGroupedDatapoint(dps, "cpu1", "user", 0.4)
+	addGroupedDatapoint(dps, "cpu0", "user", 0.6)
+	addGroupedDatapoint(dps, "cpu0", "system", 0.1)
+
+	require.NoError(t, p.ConsumeMetrics(context.Background(), md))
+	p.flush(context.Background())
+
+	require.Len(t, consumer.AllMetrics(), 1)
+	values := groupedValues(t, consumer.AllMetrics()[0])
+	// The average is calculated across all cores
+	require.InDelta(t, 0.4, values["test.cpu.utilization.avg"]["user"], 1e-9)
+	require.InDelta(t, 0.1, values["test.cpu.utilization.avg"]["system"], 1e-9)
+	require.Equal(t, 0.6, values["test.cpu.utilization.max"]["user"])
+	require.Equal(t, 0.1, values["test.cpu.utilization.max"]["system"])
+}
+
+func TestMetricstatsProcessor_GroupSum(t *testing.T) {
+	consumer := &consumertest.MetricsSink{}
+	p, err := newStatsProcessor(zaptest.NewLogger(t), &Config{
+		Interval: time.Hour,
+		Include:  `^test\..*$`,
+		Stats:    []stats.StatType{stats.LastType},
+		GroupBy:  []string{"state"},
+	}, consumer)
+	require.NoError(t, err)
+
+	md := pmetric.NewMetrics()
+	m := md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+	m.SetName("test.cpu.time")
+	s := m.SetEmptySum()
+	s.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+	s.SetIsMonotonic(true)
+	addGroupedDatapoint(s.DataPoints(), "cpu0", "user", 10)
+	addGroupedDatapoint(s.DataPoints(), "cpu1", "user", 20)
+	addGroupedDatapoint(s.DataPoints(), "cpu0", "user", 15)
+	addGroupedDatapoint(s.DataPoints(), "cpu1", "system", 5)
+
+	require.NoError(t, p.ConsumeMetrics(context.Background(), md))
+	p.flush(context.Background())
+
+	require.Len(t, consumer.AllMetrics(), 1)
+	values := groupedValues(t, consumer.AllMetrics()[0])
+	// The last value of each core is summed
+	require.Equal(t, 35.0, values["test.cpu.time.last"]["user"])
+	require.Equal(t, 5.0, values["test.cpu.time.last"]["system"])
+}
+
+func TestMetricstatsProcessor_GroupHistogram(t *testing.T) {
+	consumer := &consumertest.MetricsSink{}
+	p, err := newStatsProcessor(zaptest.NewLogger(t), &Config{
+		Interval:       time.Hour,
+		Include:        `^test\..*$`,
+		DropAttributes: []string{"host"},
+	}, consumer)
+	require.NoError(t, err)
+
+	md := pmetric.NewMetrics()
+	m := md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+	m.SetName("test.latency")
+	h := m.SetEmptyHistogram()
+	h.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+	addHistogramDatapoint(h.DataPoints(), 1, 2, []float64{10}, []uint64{1, 2}, 5)
+	h.DataPoints().At(0).Attributes().PutStr("host", "a")
+	addHistogramDatapoint(h.DataPoints(), 1, 3, []float64{10}, []uint64{3, 0}, 1)
+	h.DataPoints().At(1).Attributes().PutStr("host", "b")
+
+	require.NoError(t, p.ConsumeMetrics(context.Background(), md))
+	p.flush(context.Background())
+
+	require.Len(t, consumer.AllMetrics(), 1)
+	dps := consumer.AllMetrics()[0].ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0).Histogram().DataPoints()
+	require.Equal(t, 1, dps.Len())
+	require.Equal(t, 0, dps.At(0).Attributes().Len())
+	require.Equal(t, uint64(6), dps.At(0).Count())
+	require.Equal(t, []uint64{4, 2}, dps.At(0).BucketCounts().AsRaw())
+}
+
+func addGroupedDatapoint(dps pmetric.NumberDataPointSlice, cpu, state string, value float64) {
+	dp := dps.AppendEmpty()
+	dp.Attributes().PutStr("cpu", cpu)
+	dp.Attributes().PutStr("state", state)
+	dp.SetTimestamp(pcommon.Timestamp(dps.Len()))
+	dp.SetDoubleValue(value)
+}
+
+// groupedValues returns the values of each metric keyed by metric name and "state" attribute
+func groupedValues(t *testing.T, md pmetric.Metrics) map[string]map[string]float64 {
+	t.Helper()
+
+	values := map[string]map[string]float64{}
+	ms := md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+	for i := 0; i < ms.Len(); i++ {
+		m := ms.At(i)
+		dps := datapointsFromMetric(m)
+		values[m.Name()] = map[string]float64{}
+		for j := 0; j < dps.Len(); j++ {
+			dp := dps.At(j)
+			require.Equal(t, 1, dp.Attributes().Len())
+			state, ok := dp.Attributes().Get("state")
+			require.True(t, ok)
+			values[m.Name()][state.Str()] = dp.DoubleValue()
+		}
+	}
+	return values
+}
@@ -0,0 +1,371 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metricstatsprocessor
+
+import (
+	"math"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+)
+
+// maxExpHistogramBuckets is the maximum number of positive or negative buckets in a merged exponential histogram.
+// Merged histograms are downscaled until their buckets fit.
+const maxExpHistogramBuckets = 160
+
+// isCompatibleMetric returns false if the metric is a histogram that can't be merged with a previous metric of the same name,
+// or the previous metric was a histogram that the metric can't be merged with.
+func isCompatibleMetric(ma *metricMetadata, m pmetric.Metric) bool {
+	if !isHistogram(ma.metricType) && !isHistogram(m.Type()) {
+		return true
+	}
+
+	return ma.metricType == m.Type() && ma.temporality == aggregationTemporality(m)
+}
+
+func isHistogram(metricType pmetric.MetricType) bool {
+	return metricType == pmetric.MetricTypeHistogram || metricType == pmetric.MetricTypeExponentialHistogram
+}
+
+// addHistogramMetric merges the histogram's datapoints into the tracked histograms.
+// Datapoints that are merged are removed from the metric; the rest pass through.
+func (sp *metricstatsProcessor) addHistogramMetric(ma *metricMetadata, m pmetric.Metric) {
+	m.Histogram().DataPoints().RemoveIf(func(dp pmetric.HistogramDataPoint) bool {
+		if dp.Flags().NoRecordedValue() {
+			return false
+		}
+
+		key := mapKey(dp.Attributes())
+		merged, ok := ma.histograms[key]
+		if !ok {
+			merged = pmetric.NewHistogramDataPoint()
+			dp.CopyTo(merged)
+			merged.Exemplars().RemoveIf(func(pmetric.Exemplar) bool { return true })
+			ma.histograms[key] = merged
+			return true
+		}
+
+		if ma.temporality == pmetric.AggregationTemporalityCumulative {
+			// The latest cumulative datapoint already includes all previous datapoints
+			if dp.Timestamp() >= merged.Timestamp() {
+				dp.CopyTo(merged)
+				merged.Exemplars().RemoveIf(func(pmetric.Exemplar) bool { return true })
+			}
+			return true
+		}
+
+		if !mergeHistogramDatapoints(merged, dp) {
+			sp.logger.Debug("Histogram bucket boundaries are incompatible with previous datapoints, passing through.")
+			return false
+		}
+		return true
+	})
+}
+
+// addExpHistogramMetric merges the exponential histogram's datapoints into the tracked exponential histograms.
+// Datapoints that are merged are removed from the metric; the rest pass through.
+func (sp *metricstatsProcessor) addExpHistogramMetric(ma *metricMetadata, m pmetric.Metric) {
+	m.ExponentialHistogram().DataPoints().RemoveIf(func(dp pmetric.ExponentialHistogramDataPoint) bool {
+		if dp.Flags().NoRecordedValue() {
+			return false
+		}
+
+		key := mapKey(dp.Attributes())
+		merged, ok := ma.expHistograms[key]
+		if !ok {
+			merged = pmetric.NewExponentialHistogramDataPoint()
+			dp.CopyTo(merged)
+			merged.Exemplars().RemoveIf(func(pmetric.Exemplar) bool { return true })
+			ma.expHistograms[key] = merged
+			return true
+		}
+
+		if ma.temporality == pmetric.AggregationTemporalityCumulative {
+			// The latest cumulative datapoint already includes all previous datapoints
+			if dp.Timestamp() >= merged.Timestamp() {
+				dp.CopyTo(merged)
+				merged.Exemplars().RemoveIf(func(pmetric.Exemplar) bool { return true })
+			}
+			return true
+		}
+
+		mergeExpHistogramDatapoints(merged, dp)
+		return true
+	})
+}
+
+// addMergedHistogramMetric adds a histogram metric containing the merged datapoints.
+// Series that are grouped together are merged; if their bucket boundaries differ, they are emitted separately.
+func (sp *metricstatsProcessor) addMergedHistogramMetric(ms pmetric.MetricSlice, ma *metricMetadata) {
+	if len(ma.histograms) == 0 {
+		return
+	}
+
+	m := ms.AppendEmpty()
+	m.SetName(ma.name)
+	m.SetDescription(ma.desc)
+	m.SetUnit(ma.unit)
+	h := m.SetEmptyHistogram()
+	h.SetAggregationTemporality(ma.temporality)
+	grouped := make(map[uint64]pmetric.HistogramDataPoint, len(ma.histograms))
+	for _, dp := range ma.histograms {
+		attrs := sp.grouper.group(dp.Attributes())
+		key := mapKey(attrs)
+		if existing, ok := grouped[key]; ok && mergeHistogramDatapoints(existing, dp) {
+			continue
+		}
+
+		out := h.DataPoints().AppendEmpty()
+		dp.CopyTo(out)
+		attrs.CopyTo(out.Attributes())
+		grouped[key] = out
+	}
+}
+
+// addMergedExpHistogramMetric adds an exponential histogram metric containing the merged datapoints.
+// Series that are grouped together are merged.
+func (sp *metricstatsProcessor) addMergedExpHistogramMetric(ms pmetric.MetricSlice, ma *metricMetadata) {
+	if len(ma.expHistograms) == 0 {
+		return
+	}
+
+	m := ms.AppendEmpty()
+	m.SetName(ma.name)
+	m.SetDescription(ma.desc)
+	m.SetUnit(ma.unit)
+	h := m.SetEmptyExponentialHistogram()
+	h.SetAggregationTemporality(ma.temporality)
+	grouped := make(map[uint64]pmetric.ExponentialHistogramDataPoint, len(ma.expHistograms))
+	for _, dp := range ma.expHistograms {
+		attrs := sp.grouper.group(dp.Attributes())
+		key := mapKey(attrs)
+		if existing, ok := grouped[key]; ok {
+			mergeExpHistogramDatapoints(existing, dp)
+			continue
+		}
+
+		out := h.DataPoints().AppendEmpty()
+		dp.CopyTo(out)
+		attrs.CopyTo(out.Attributes())
+		grouped[key] = out
+	}
+}
+
+// mergeHistogramDatapoints adds the delta histogram datapoint src into dst.
+// It returns false if the bucket boundaries of the datapoints are different, in which case dst is not modified.
+func mergeHistogramDatapoints(dst, src pmetric.HistogramDataPoint) bool {
+	if !equalBounds(dst.ExplicitBounds(), src.ExplicitBounds()) || dst.BucketCounts().Len() != src.BucketCounts().Len() {
+		return false
+	}
+
+	for i := 0; i < src.BucketCounts().Len(); i++ {
+		dst.BucketCounts().SetAt(i, dst.BucketCounts().At(i)+src.BucketCounts().At(i))
+	}
+
+	mergeTimestamps(dst, src)
+	dst.SetCount(dst.Count() + src.Count())
+
+	mergeSumMinMax(dst, src)
+
+	return true
+}
+
+// mergeExpHistogramDatapoints adds the delta exponential histogram datapoint src into dst.
+// Both datapoints are brought to the same scale before merging, which may reduce the scale of dst.
+func mergeExpHistogramDatapoints(dst, src pmetric.ExponentialHistogramDataPoint) {
+	scale := minInt32(dst.Scale(), src.Scale())
+	for scale > minExpHistogramScale &&
+		(!bucketsFit(dst.Positive(), dst.Scale()-scale, src.Positive(), src.Scale()-scale) ||
+			!bucketsFit(dst.Negative(), dst.Scale()-scale, src.Negative(), src.Scale()-scale)) {
+		scale--
+	}
+
+	downscaleBuckets(dst.Positive(), dst.Scale()-scale)
+	downscaleBuckets(dst.Negative(), dst.Scale()-scale)
+	srcPositive := pmetric.NewExponentialHistogramDataPointBuckets()
+	src.Positive().CopyTo(srcPositive)
+	downscaleBuckets(srcPositive, src.Scale()-scale)
+	srcNegative := pmetric.NewExponentialHistogramDataPointBuckets()
+	src.Negative().CopyTo(srcNegative)
+	downscaleBuckets(srcNegative, src.Scale()-scale)
+
+	dst.SetScale(scale)
+	mergeBuckets(dst.Positive(), srcPositive)
+	mergeBuckets(dst.Negative(), srcNegative)
+
+	mergeTimestamps(dst, src)
+	dst.SetCount(dst.Count() + src.Count())
+	dst.SetZeroCount(dst.ZeroCount() + src.ZeroCount())
+	dst.SetZeroThreshold(math.Max(dst.ZeroThreshold(), src.ZeroThreshold()))
+
+	mergeSumMinMax(dst, src)
+}
+
+// minExpHistogramScale is the smallest scale of an exponential histogram.
+const minExpHistogramScale = -10
+
+// bucketsFit returns true if the buckets of a and b, downscaled by aDelta and bDelta, fit within maxExpHistogramBuckets once merged.
+func bucketsFit(a pmetric.ExponentialHistogramDataPointBuckets, aDelta int32, b pmetric.ExponentialHistogramDataPointBuckets, bDelta int32) bool {
+	aLow, aHigh, aOk := bucketRange(a, aDelta)
+	bLow, bHigh, bOk := bucketRange(b, bDelta)
+	switch {
+	case aOk && bOk:
+		return int(maxInt32(aHigh, bHigh))-int(minInt32(aLow, bLow))+1 <= maxExpHistogramBuckets
+	case aOk:
+		return int(aHigh)-int(aLow)+1 <= maxExpHistogramBuckets
+	case bOk:
+		return int(bHigh)-int(bLow)+1 <= maxExpHistogramBuckets
+	}
+	return true
+}
+
+// bucketRange returns the lowest and highest bucket index of the buckets, downscaled by delta.
+// It returns false if there are no buckets.
+func bucketRange(b pmetric.ExponentialHistogramDataPointBuckets, delta int32) (int32, int32, bool) {
+	if b.BucketCounts().Len() == 0 {
+		return 0, 0, false
+	}
+
+	// Right shifting a negative index rounds towards negative infinity, which keeps bucket boundaries aligned
+	low := b.Offset() >> delta
+	high := (b.Offset() + int32(b.BucketCounts().Len()) - 1) >> delta
+	return low, high, true
+}
+
+// downscaleBuckets reduces the scale of the buckets by delta, combining each 2^delta adjacent buckets into one.
+func downscaleBuckets(b pmetric.ExponentialHistogramDataPointBuckets, delta int32) {
+	low, high, ok := bucketRange(b, delta)
+	if !ok || delta == 0 {
+		return
+	}
+
+	counts := make([]uint64, high-low+1)
+	for i := 0; i < b.BucketCounts().Len(); i++ {
+		idx := (b.Offset() + int32(i)) >> delta
+		counts[idx-low] += b.BucketCounts().At(i)
+	}
+
+	b.SetOffset(low)
+	b.BucketCounts().FromRaw(counts)
+}
+
+// mergeBuckets adds the counts of src into dst. Both must be the same scale.
+func mergeBuckets(dst, src pmetric.ExponentialHistogramDataPointBuckets) {
+	srcLow, srcHigh, ok := bucketRange(src, 0)
+	if !ok {
+		return
+	}
+
+	dstLow, dstHigh, ok := bucketRange(dst, 0)
+	if !ok {
+		src.CopyTo(dst)
+		return
+	}
+
+	low := minInt32(srcLow, dstLow)
+	counts := make([]uint64, maxInt32(srcHigh, dstHigh)-low+1)
+	for i := 0; i < dst.BucketCounts().Len(); i++ {
+		counts[dst.Offset()-low+int32(i)] += dst.BucketCounts().At(i)
+	}
+	for i := 0; i < src.BucketCounts().Len(); i++ {
+		counts[src.Offset()-low+int32(i)] += src.BucketCounts().At(i)
+	}
+
+	dst.SetOffset(low)
+	dst.BucketCounts().FromRaw(counts)
+}
+
+// equalBounds returns true if the explicit bucket boundaries are the same.
+func equalBounds(a, b pcommon.Float64Slice) bool {
+	if a.Len() != b.Len() {
+		return false
+	}
+
+	for i := 0; i < a.Len(); i++ {
+		if a.At(i) != b.At(i) {
+			return false
+		}
+	}
+	return true
+}
+
+type histogramSummary interface {
+	HasSum() bool
+	Sum() float64
+	SetSum(float64)
+	RemoveSum()
+	HasMin() bool
+	Min() float64
+	SetMin(float64)
+	RemoveMin()
+	HasMax() bool
+	Max() float64
+	SetMax(float64)
+	RemoveMax()
+}
+
+// mergeSumMinMax merges the optional sum, min and max of src into dst.
+// If either datapoint is missing a value, the merged value is unknown, so it's removed.
+func mergeSumMinMax(dst, src histogramSummary) {
+	if dst.HasSum() && src.HasSum() {
+		dst.SetSum(dst.Sum() + src.Sum())
+	} else {
+		dst.RemoveSum()
+	}
+
+	if dst.HasMin() && src.HasMin() {
+		dst.SetMin(math.Min(dst.Min(), src.Min()))
+	} else {
+		dst.RemoveMin()
+	}
+
+	if dst.HasMax() && src.HasMax() {
+		dst.SetMax(math.Max(dst.Max(), src.Max()))
+	} else {
+		dst.RemoveMax()
+	}
+}
+
+type timestamped interface {
+	StartTimestamp() pcommon.Timestamp
+	SetStartTimestamp(pcommon.Timestamp)
+	Timestamp() pcommon.Timestamp
+	SetTimestamp(pcommon.Timestamp)
+}
+
+// mergeTimestamps widens the time range of dst to include the time range of src.
+func mergeTimestamps(dst, src timestamped) {
+	if src.StartTimestamp() != 0 && (dst.StartTimestamp() == 0 || src.StartTimestamp() < dst.StartTimestamp()) {
+		dst.SetStartTimestamp(src.StartTimestamp())
+	}
+
+	if src.Timestamp() > dst.Timestamp() {
+		dst.SetTimestamp(src.Timestamp())
+	}
+}
+
+func minInt32(a, b int32) int32 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxInt32(a, b int32) int32 {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,220 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metricstatsprocessor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestMetricstatsProcessor_DeltaHistogram(t *testing.T) {
+	consumer := &consumertest.MetricsSink{}
+	p, err := newStatsProcessor(zaptest.NewLogger(t), &Config{
+		Interval: time.Hour,
+		Include:  `^test\..*$`,
+	}, consumer)
+	require.NoError(t, err)
+
+	md := pmetric.NewMetrics()
+	m := md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+	m.SetName("test.latency")
+	h := m.SetEmptyHistogram()
+	h.SetAggregationTemporality(pmetric.AggregationTemporalityDelta)
+	addHistogramDatapoint(h.DataPoints(), 1, 2, []float64{10, 100}, []uint64{1, 2, 3}, 5)
+	addHistogramDatapoint(h.DataPoints(), 2, 3, []float64{10, 100}, []uint64{4, 0, 1}, 200)
+	h.DataPoints().At(1).Exemplars().AppendEmpty().SetDoubleValue(200)
+	// Different bucket boundaries can't be merged, so this datapoint passes through
+	addHistogramDatapoint(h.DataPoints(), 3, 4, []float64{50}, []uint64{1, 1}, 50)
+
+	require.NoError(t, p.ConsumeMetrics(context.Background(), md))
+	require.Len(t, consumer.AllMetrics(), 1)
+	require.Equal(t, 1, consumer.AllMetrics()[0].DataPointCount())
+	passedThrough := consumer.AllMetrics()[0].ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0).Histogram().DataPoints().At(0)
+	require.Equal(t, []float64{50}, passedThrough.ExplicitBounds().AsRaw())
+	consumer.Reset()
+
+	p.flush(context.Background())
+	require.Len(t, consumer.AllMetrics(), 1)
+	ms := consumer.AllMetrics()[0].ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+	require.Equal(t, 1, ms.Len())
+	require.Equal(t, "test.latency", ms.At(0).Name())
+	require.Equal(t, pmetric.AggregationTemporalityDelta, ms.At(0).Histogram().AggregationTemporality())
+	require.Equal(t, 1, ms.At(0).Histogram().DataPoints().Len())
+
+	dp := ms.At(0).Histogram().DataPoints().At(0)
+	require.Equal(t, pcommon.Timestamp(1), dp.StartTimestamp())
+	require.Equal(t, pcommon.Timestamp(3), dp.Timestamp())
+	require.Equal(t, uint64(11), dp.Count())
+	require.Equal(t, 205.0, dp.Sum())
+	require.Equal(t, 5.0, dp.Min())
+	require.Equal(t, 200.0, dp.Max())
+	require.Equal(t, []float64{10, 100}, dp.ExplicitBounds().AsRaw())
+	require.Equal(t, []uint64{5, 2, 4}, dp.BucketCounts().AsRaw())
+	require.Equal(t, 0, dp.Exemplars().Len())
+}
+
+func TestMetricstatsProcessor_CumulativeHistogram(t *testing.T) {
+	consumer := &consumertest.MetricsSink{}
+	p, err := newStatsProcessor(zaptest.NewLogger(t), &Config{
+		Interval: time.Hour,
+		Include:  `^test\..*$`,
+	}, consumer)
+	require.NoError(t, err)
+
+	for _, ts := range []pcommon.Timestamp{2, 4, 3} {
+		md := pmetric.NewMetrics()
+		m := md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+		m.SetName("test.latency")
+		h := m.SetEmptyExponentialHistogram()
+		h.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+		dp := h.DataPoints().AppendEmpty()
+		dp.SetStartTimestamp(1)
+		dp.SetTimestamp(ts)
+		dp.SetCount(uint64(ts))
+		require.NoError(t, p.ConsumeMetrics(context.Background(), md))
+	}
+	require.Empty(t, consumer.AllMetrics())
+
+	p.flush(context.Background())
+	require.Len(t, consumer.AllMetrics(), 1)
+	ms := consumer.AllMetrics()[0].ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+	require.Equal(t, 1, ms.Len())
+	require.Equal(t, pmetric.AggregationTemporalityCumulative, ms.At(0).ExponentialHistogram().AggregationTemporality())
+	require.Equal(t, 1, ms.At(0).ExponentialHistogram().DataPoints().Len())
+
+	// The latest cumulative datapoint is emitted
+	dp := ms.At(0).ExponentialHistogram().DataPoints().At(0)
+	require.Equal(t, pcommon.Timestamp(4), dp.Timestamp())
+	require.Equal(t, uint64(4), dp.Count())
+}
+
+func TestMetricstatsProcessor_HistogramNameConflict(t *testing.T) {
+	consumer := &consumertest.MetricsSink{}
+	p, err := newStatsProcessor(zaptest.NewLogger(t), &Config{
+		Interval: time.Hour,
+		Include:  `^test\..*$`,
+	}, consumer)
+	require.NoError(t, err)
+
+	md := pmetric.NewMetrics()
+	ms := md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics()
+	g := ms.AppendEmpty()
+	g.SetName("test.latency")
+	g.SetEmptyGauge().DataPoints().AppendEmpty().SetDoubleValue(1)
+	h := ms.AppendEmpty()
+	h.SetName("test.latency")
+	h.SetEmptyHistogram().SetAggregationTemporality(pmetric.AggregationTemporalityDelta)
+	addHistogramDatapoint(h.Histogram().DataPoints(), 1, 2, nil, []uint64{1}, 1)
+
+	require.NoError(t, p.ConsumeMetrics(context.Background(), md))
+
+	// The histogram can't be merged with the gauge of the same name, so it passes through
+	require.Len(t, consumer.AllMetrics(), 1)
+	passedThrough := consumer.AllMetrics()[0].ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+	require.Equal(t, 1, passedThrough.Len())
+	require.Equal(t, pmetric.MetricTypeHistogram, passedThrough.At(0).Type())
+}
+
+func TestMergeExpHistogramDatapoints(t *testing.T) {
+	t.Run("rescales to the lower scale", func(t *testing.T) {
+		dst := newExpHistogramDatapoint(1, 0, []uint64{1, 2, 3, 4}, -3, []uint64{1, 1, 1, 1})
+		src := newExpHistogramDatapoint(0, 0, []uint64{5, 6}, 0, nil)
+
+		mergeExpHistogramDatapoints(dst, src)
+
+		require.Equal(t, int32(0), dst.Scale())
+		require.Equal(t, int32(0), dst.Positive().Offset())
+		require.Equal(t, []uint64{8, 13}, dst.Positive().BucketCounts().AsRaw())
+		// Negative indexes round towards negative infinity when downscaled
+		require.Equal(t, int32(-2), dst.Negative().Offset())
+		require.Equal(t, []uint64{1, 2, 1}, dst.Negative().BucketCounts().AsRaw())
+		require.Equal(t, uint64(2), dst.ZeroCount())
+		require.Equal(t, uint64(15+12), dst.Count())
+	})
+
+	t.Run("downscales to fit buckets", func(t *testing.T) {
+		dst := newExpHistogramDatapoint(0, 0, []uint64{1}, 0, nil)
+		src := newExpHistogramDatapoint(0, 200, []uint64{1}, 0, nil)
+
+		mergeExpHistogramDatapoints(dst, src)
+
+		require.Equal(t, int32(-1), dst.Scale())
+		require.Equal(t, int32(0), dst.Positive().Offset())
+		require.Equal(t, 101, dst.Positive().BucketCounts().Len())
+		require.Equal(t, uint64(1), dst.Positive().BucketCounts().At(0))
+		require.Equal(t, uint64(1), dst.Positive().BucketCounts().At(100))
+	})
+
+	t.Run("empty destination buckets", func(t *testing.T) {
+		dst := newExpHistogramDatapoint(2, 0, nil, 0, nil)
+		src := newExpHistogramDatapoint(2, -5, []uint64{3, 0, 1}, 0, nil)
+
+		mergeExpHistogramDatapoints(dst, src)
+
+		require.Equal(t, int32(2), dst.Scale())
+		require.Equal(t, int32(-5), dst.Positive().Offset())
+		require.Equal(t, []uint64{3, 0, 1}, dst.Positive().BucketCounts().AsRaw())
+	})
+}
+
+func TestMergeHistogramDatapointsMissingSum(t *testing.T) {
+	dst := pmetric.NewHistogramDataPoint()
+	dst.SetSum(1)
+	src := pmetric.NewHistogramDataPoint()
+
+	require.True(t, mergeHistogramDatapoints(dst, src))
+	require.False(t, dst.HasSum())
+	require.False(t, dst.HasMin())
+	require.False(t, dst.HasMax())
+}
+
+func addHistogramDatapoint(dps pmetric.HistogramDataPointSlice, start, ts pcommon.Timestamp, bounds []float64, counts []uint64, value float64) {
+	dp := dps.AppendEmpty()
+	dp.SetStartTimestamp(start)
+	dp.SetTimestamp(ts)
+	dp.ExplicitBounds().FromRaw(bounds)
+	dp.BucketCounts().FromRaw(counts)
+	var count uint64
+	for _, c := range counts {
+		count += c
+	}
+	dp.SetCount(count)
+	dp.SetSum(value)
+	dp.SetMin(value)
+	dp.SetMax(value)
+}
+
+func newExpHistogramDatapoint(scale, posOffset int32, posCounts []uint64, negOffset int32, negCounts []uint64) pmetric.ExponentialHistogramDataPoint {
+	dp := pmetric.NewExponentialHistogramDataPoint()
+	dp.SetScale(scale)
+	dp.Positive().SetOffset(posOffset)
+	dp.Positive().BucketCounts().FromRaw(posCounts)
+	dp.Negative().SetOffset(negOffset)
+	dp.Negative().BucketCounts().FromRaw(negCounts)
+	dp.SetZeroCount(1)
+	count := dp.ZeroCount()
+	for _, c := range append(append([]uint64{}, posCounts...), negCounts...) {
+		count += c
+	}
+	dp.SetCount(count)
+	return dp
+}
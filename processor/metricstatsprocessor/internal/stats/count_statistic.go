@@ -0,0 +1,41 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stats
+
+import (
+	"errors"
+
+	"go.opentelemetry.io/collector/pdata/pmetric"
+)
+
+type countStatistic struct {
+	count int64
+}
+
+func newCountStatistic(initialVal pmetric.NumberDataPoint) (Statistic, error) {
+	if initialVal.ValueType() == pmetric.NumberDataPointValueTypeEmpty {
+		return nil, errors.New("cannot create count statistic from empty datapoint")
+	}
+
+	return &countStatistic{count: 1}, nil
+}
+
+func (m *countStatistic) AddDatapoint(_ pmetric.NumberDataPoint) {
+	m.count++
+}
+
+func (m *countStatistic) SetDatapointValue(dp pmetric.NumberDataPoint) {
+	dp.SetIntValue(m.count)
+}
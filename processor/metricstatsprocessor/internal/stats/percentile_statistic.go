@@ -0,0 +1,57 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stats
+
+import (
+	"fmt"
+
+	"go.opentelemetry.io/collector/pdata/pmetric"
+)
+
+// percentileStatistic estimates a quantile of the datapoints using a sketch.
+// The sketch may be shared between percentile statistics, in which case only the owner adds datapoints to it.
+type percentileStatistic struct {
+	sketch   *sketch
+	quantile float64
+	owner    bool
+}
+
+func newPercentileStatistic(statType StatType, quantile float64, s *sketch, initialVal pmetric.NumberDataPoint) (Statistic, error) {
+	if initialVal.ValueType() == pmetric.NumberDataPointValueTypeEmpty {
+		return nil, fmt.Errorf("cannot create %s statistic from empty datapoint", statType)
+	}
+
+	owner := s == nil
+	if owner {
+		s = newSketch()
+		s.add(getDatapointValueDouble(initialVal))
+	}
+
+	return &percentileStatistic{
+		sketch:   s,
+		quantile: quantile,
+		owner:    owner,
+	}, nil
+}
+
+func (m *percentileStatistic) AddDatapoint(ndp pmetric.NumberDataPoint) {
+	if m.owner {
+		m.sketch.add(getDatapointValueDouble(ndp))
+	}
+}
+
+func (m *percentileStatistic) SetDatapointValue(dp pmetric.NumberDataPoint) {
+	dp.SetDoubleValue(m.sketch.quantile(m.quantile))
+}
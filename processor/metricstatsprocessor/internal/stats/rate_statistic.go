@@ -0,0 +1,74 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stats
+
+import (
+	"errors"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pmetric"
+)
+
+// rateStatistic calculates the per second rate of change between the earliest and latest datapoints.
+type rateStatistic struct {
+	firstVal       float64
+	firstTimestamp time.Time
+	lastVal        float64
+	lastTimestamp  time.Time
+}
+
+func newRateStatistic(initialVal pmetric.NumberDataPoint) (Statistic, error) {
+	if initialVal.ValueType() == pmetric.NumberDataPointValueTypeEmpty {
+		return nil, errors.New("cannot create rate statistic from empty datapoint")
+	}
+
+	f := getDatapointValueDouble(initialVal)
+	ts := initialVal.Timestamp().AsTime()
+	return &rateStatistic{
+		firstVal:       f,
+		firstTimestamp: ts,
+		lastVal:        f,
+		lastTimestamp:  ts,
+	}, nil
+}
+
+func (m *rateStatistic) AddDatapoint(ndp pmetric.NumberDataPoint) {
+	if ndp.Timestamp() == 0 {
+		// Ignore uninitialized timestamp
+		return
+	}
+
+	ndpTimestamp := ndp.Timestamp().AsTime()
+	if m.firstTimestamp.After(ndpTimestamp) {
+		m.firstVal = getDatapointValueDouble(ndp)
+		m.firstTimestamp = ndpTimestamp
+	}
+
+	if !m.lastTimestamp.After(ndpTimestamp) {
+		m.lastVal = getDatapointValueDouble(ndp)
+		m.lastTimestamp = ndpTimestamp
+	}
+}
+
+func (m *rateStatistic) SetDatapointValue(dp pmetric.NumberDataPoint) {
+	elapsed := m.lastTimestamp.Sub(m.firstTimestamp).Seconds()
+	if elapsed <= 0 {
+		// A single point in time has no rate of change
+		dp.SetDoubleValue(0)
+		return
+	}
+
+	dp.SetDoubleValue((m.lastVal - m.firstVal) / elapsed)
+}
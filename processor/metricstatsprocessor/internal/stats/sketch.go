@@ -0,0 +1,158 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stats
+
+import (
+	"math"
+	"sort"
+)
+
+const (
+	// sketchRelativeAccuracy is the maximum relative error of quantiles returned by the sketch.
+	sketchRelativeAccuracy = 0.01
+	// sketchMaxBins is the maximum number of bins kept for each sign of value.
+	sketchMaxBins = 1024
+	// sketchMinIndexable is the smallest magnitude that is binned; anything smaller is counted as zero.
+	sketchMinIndexable = 1e-9
+)
+
+// sketch is a streaming quantile sketch with bounded relative error, based on DDSketch.
+// Values are counted in logarithmically sized bins, so memory does not grow with the number of values.
+// When there are too many bins, the bins closest to zero are collapsed together.
+type sketch struct {
+	logGamma float64
+	positive map[int]uint64
+	negative map[int]uint64
+	zeros    uint64
+	count    uint64
+	min      float64
+	max      float64
+}
+
+func newSketch() *sketch {
+	gamma := (1 + sketchRelativeAccuracy) / (1 - sketchRelativeAccuracy)
+	return &sketch{
+		logGamma: math.Log(gamma),
+		positive: make(map[int]uint64),
+		negative: make(map[int]uint64),
+		min:      math.Inf(1),
+		max:      math.Inf(-1),
+	}
+}
+
+func (s *sketch) add(v float64) {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return
+	}
+
+	switch {
+	case v > sketchMinIndexable:
+		s.positive[s.index(v)]++
+		collapseBins(s.positive)
+	case v < -sketchMinIndexable:
+		s.negative[s.index(-v)]++
+		collapseBins(s.negative)
+	default:
+		s.zeros++
+	}
+
+	s.count++
+	s.min = math.Min(s.min, v)
+	s.max = math.Max(s.max, v)
+}
+
+// quantile returns the estimated value at quantile q, which must be between 0 and 1.
+func (s *sketch) quantile(q float64) float64 {
+	if s.count == 0 {
+		return 0
+	}
+
+	if q <= 0 {
+		return s.min
+	}
+
+	if q >= 1 {
+		return s.max
+	}
+
+	rank := uint64(q * float64(s.count-1))
+	var seen uint64
+
+	// Most negative values have the largest index, so they're iterated in descending order
+	for _, idx := range sortedKeys(s.negative, true) {
+		seen += s.negative[idx]
+		if seen > rank {
+			return s.clamp(-s.value(idx))
+		}
+	}
+
+	seen += s.zeros
+	if seen > rank {
+		return s.clamp(0)
+	}
+
+	for _, idx := range sortedKeys(s.positive, false) {
+		seen += s.positive[idx]
+		if seen > rank {
+			return s.clamp(s.value(idx))
+		}
+	}
+
+	return s.max
+}
+
+// index returns the bin index for the positive value v.
+func (s *sketch) index(v float64) int {
+	return int(math.Ceil(math.Log(v) / s.logGamma))
+}
+
+// value returns the representative value of the bin at idx, which is within the relative accuracy of every value in the bin.
+func (s *sketch) value(idx int) float64 {
+	return 2 * math.Exp(float64(idx)*s.logGamma) / (1 + math.Exp(s.logGamma))
+}
+
+func (s *sketch) clamp(v float64) float64 {
+	return math.Max(s.min, math.Min(s.max, v))
+}
+
+// collapseBins merges the lowest bins into each other until there are at most sketchMaxBins bins.
+func collapseBins(bins map[int]uint64) {
+	if len(bins) <= sketchMaxBins {
+		return
+	}
+
+	keys := sortedKeys(bins, false)
+	excess := len(keys) - sketchMaxBins
+	target := keys[excess]
+	for _, idx := range keys[:excess] {
+		bins[target] += bins[idx]
+		delete(bins, idx)
+	}
+}
+
+func sortedKeys(bins map[int]uint64, descending bool) []int {
+	keys := make([]int, 0, len(bins))
+	for idx := range bins {
+		keys = append(keys, idx)
+	}
+
+	if descending {
+		sort.Sort(sort.Reverse(sort.IntSlice(keys)))
+	} else {
+		sort.Ints(keys)
+	}
+
+	return keys
+}
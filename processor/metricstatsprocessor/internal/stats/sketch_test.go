@@ -0,0 +1,77 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stats
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSketchQuantiles(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	s := newSketch()
+	values := make([]float64, 10000)
+	for i := range values {
+		// Mix of negative, zero, and positive values over several orders of magnitude
+		v := math.Exp(r.Float64()*20 - 10)
+		switch i % 10 {
+		case 0:
+			v = -v
+		case 1:
+			v = 0
+		}
+		values[i] = v
+		s.add(v)
+	}
+	sort.Float64s(values)
+
+	require.Equal(t, values[0], s.quantile(0))
+	require.Equal(t, values[len(values)-1], s.quantile(1))
+
+	for _, q := range []float64{0.01, 0.05, 0.1, 0.25, 0.5, 0.75, 0.9, 0.99} {
+		expected := values[int(q*float64(len(values)-1))]
+		actual := s.quantile(q)
+		if expected == 0 {
+			require.Equal(t, expected, actual, "quantile %v", q)
+			continue
+		}
+		require.InEpsilon(t, expected, actual, sketchRelativeAccuracy+1e-9, "quantile %v", q)
+	}
+}
+
+func TestSketchEmpty(t *testing.T) {
+	s := newSketch()
+	s.add(math.NaN())
+	require.Equal(t, 0.0, s.quantile(0.5))
+}
+
+func TestSketchCollapse(t *testing.T) {
+	s := newSketch()
+	values := make([]float64, 5000)
+	for i := range values {
+		values[i] = math.Pow(1.05, float64(i%3000)) * 1e-6
+		s.add(values[i])
+	}
+	sort.Float64s(values)
+
+	require.LessOrEqual(t, len(s.positive), sketchMaxBins)
+	require.Equal(t, uint64(5000), s.count)
+	// The highest values keep their accuracy
+	require.InEpsilon(t, values[int(0.99*float64(len(values)-1))], s.quantile(0.99), sketchRelativeAccuracy)
+}
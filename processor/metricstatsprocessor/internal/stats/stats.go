@@ -17,8 +17,11 @@ package stats
 
 import (
 	"fmt"
+	"regexp"
+	"strconv"
 
 	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.uber.org/multierr"
 )
 
 // Statistic is an interface represents a running calculation of datapoints
@@ -32,25 +35,44 @@ type StatType string
 
 // Types of statistics
 const (
-	MinType   StatType = "min"
-	MaxType   StatType = "max"
-	FirstType StatType = "first"
-	LastType  StatType = "last"
-	AvgType   StatType = "avg"
+	MinType    StatType = "min"
+	MaxType    StatType = "max"
+	FirstType  StatType = "first"
+	LastType   StatType = "last"
+	AvgType    StatType = "avg"
+	SumType    StatType = "sum"
+	CountType  StatType = "count"
+	StddevType StatType = "stddev"
+	RateType   StatType = "rate"
+	P50Type    StatType = "p50"
+	P90Type    StatType = "p90"
+	P95Type    StatType = "p95"
+	P99Type    StatType = "p99"
 )
 
+// percentileRegex matches percentile statistic types, e.g. p50 or p99.9
+var percentileRegex = regexp.MustCompile(`^p([0-9]{1,2}(?:\.[0-9]+)?)$`)
+
 type statConstructor func(pmetric.NumberDataPoint) (Statistic, error)
 
 var statConstructors = map[StatType]statConstructor{
-	MinType:   newMinStatistic,
-	MaxType:   newMaxStatistic,
-	FirstType: newFirstStatistic,
-	LastType:  newLastStatistic,
-	AvgType:   newAvgStatistic,
+	MinType:    newMinStatistic,
+	MaxType:    newMaxStatistic,
+	FirstType:  newFirstStatistic,
+	LastType:   newLastStatistic,
+	AvgType:    newAvgStatistic,
+	SumType:    newSumStatistic,
+	CountType:  newCountStatistic,
+	StddevType: newStddevStatistic,
+	RateType:   newRateStatistic,
 }
 
 // New creates a new statistic of the given type, using the initial datapoint
 func (a StatType) New(initialVal pmetric.NumberDataPoint) (Statistic, error) {
+	if q, ok := a.Quantile(); ok {
+		return newPercentileStatistic(a, q, nil, initialVal)
+	}
+
 	constructor, ok := statConstructors[a]
 	if !ok {
 		return nil, fmt.Errorf("invalid statistic type: %s", a)
@@ -60,6 +82,80 @@ func (a StatType) New(initialVal pmetric.NumberDataPoint) (Statistic, error) {
 
 // Valid returns true if this Type is a valid statistic type, false otherwise
 func (a StatType) Valid() bool {
+	if _, ok := a.Quantile(); ok {
+		return true
+	}
+
 	_, ok := statConstructors[a]
 	return ok
 }
+
+// Quantile returns the quantile (between 0 and 1) of a percentile statistic type.
+// The returned bool is false if this Type is not a percentile.
+func (a StatType) Quantile() (float64, bool) {
+	match := percentileRegex.FindStringSubmatch(string(a))
+	if match == nil {
+		return 0, false
+	}
+
+	p, err := strconv.ParseFloat(match[1], 64)
+	if err != nil || p <= 0 {
+		return 0, false
+	}
+
+	return p / 100, true
+}
+
+// IsGauge returns true if this statistic should always be emitted as a gauge, regardless of the type of the source metric.
+// These statistics describe the distribution or change of the values, rather than a value itself.
+func (a StatType) IsGauge() bool {
+	switch a {
+	case CountType, StddevType, RateType:
+		return true
+	}
+
+	_, ok := a.Quantile()
+	return ok
+}
+
+// GaugeOnly returns true if this statistic is only calculated for gauge metrics.
+// The values of a cumulative sum already include all previous values, so adding them is meaningless,
+// and percentiles of separately calculated sum series can't be combined.
+func (a StatType) GaugeOnly() bool {
+	if a == SumType {
+		return true
+	}
+
+	_, ok := a.Quantile()
+	return ok
+}
+
+// NewStatistics creates a statistic for each of the given types, using the initial datapoint.
+// Percentile statistics share a single sketch.
+// The returned error here is a multierr, and may be a partial err, so the resultant map may be used even if an error is returned.
+func NewStatistics(statTypes []StatType, initialVal pmetric.NumberDataPoint) (map[StatType]Statistic, error) {
+	var errs error
+	var shared *sketch
+	statistics := make(map[StatType]Statistic, len(statTypes))
+	for _, statType := range statTypes {
+		var stat Statistic
+		var err error
+		if q, ok := statType.Quantile(); ok {
+			stat, err = newPercentileStatistic(statType, q, shared, initialVal)
+			if ps, ok := stat.(*percentileStatistic); ok {
+				shared = ps.sketch
+			}
+		} else {
+			stat, err = statType.New(initialVal)
+		}
+
+		if err != nil {
+			errs = multierr.Append(errs, fmt.Errorf("failed to create statistic: %w", err))
+			continue
+		}
+
+		statistics[statType] = stat
+	}
+
+	return statistics, errs
+}
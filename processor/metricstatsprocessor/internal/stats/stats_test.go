@@ -30,6 +30,15 @@ func TestStatTypesValid(t *testing.T) {
 		MaxType,
 		FirstType,
 		LastType,
+		SumType,
+		CountType,
+		StddevType,
+		RateType,
+		P50Type,
+		P90Type,
+		P95Type,
+		P99Type,
+		StatType("p99.9"),
 	}
 
 	for _, statType := range types {
@@ -46,6 +55,15 @@ func TestStatTypesNew(t *testing.T) {
 		MaxType,
 		FirstType,
 		LastType,
+		SumType,
+		CountType,
+		StddevType,
+		RateType,
+		P50Type,
+		P90Type,
+		P95Type,
+		P99Type,
+		StatType("p99.9"),
 	}
 
 	for _, statType := range types {
@@ -66,6 +84,80 @@ func TestStatTypeNewInvalidType(t *testing.T) {
 	require.ErrorContains(t, err, "invalid statistic type:")
 }
 
+func TestStatTypesInvalid(t *testing.T) {
+	types := []StatType{"invalid", "p0", "p100", "p", "p-1", "p1e1", "pnan"}
+
+	for _, statType := range types {
+		t.Run(string(statType), func(t *testing.T) {
+			require.False(t, statType.Valid(), "Valid statistic type %s", statType)
+		})
+	}
+}
+
+func TestStatTypeQuantile(t *testing.T) {
+	q, ok := P99Type.Quantile()
+	require.True(t, ok)
+	require.Equal(t, 0.99, q)
+
+	q, ok = StatType("p99.9").Quantile()
+	require.True(t, ok)
+	require.InDelta(t, 0.999, q, 1e-12)
+
+	_, ok = AvgType.Quantile()
+	require.False(t, ok)
+}
+
+func TestStatTypeIsGauge(t *testing.T) {
+	for _, statType := range []StatType{CountType, StddevType, RateType, P50Type} {
+		require.True(t, statType.IsGauge(), statType)
+	}
+
+	for _, statType := range []StatType{MinType, MaxType, FirstType, LastType, AvgType, SumType} {
+		require.False(t, statType.IsGauge(), statType)
+	}
+}
+
+func TestStatTypeGaugeOnly(t *testing.T) {
+	for _, statType := range []StatType{SumType, P50Type, StatType("p99.9")} {
+		require.True(t, statType.GaugeOnly(), statType)
+	}
+
+	for _, statType := range []StatType{MinType, MaxType, FirstType, LastType, AvgType, CountType, StddevType, RateType} {
+		require.False(t, statType.GaugeOnly(), statType)
+	}
+}
+
+func TestNewStatisticsSharedSketch(t *testing.T) {
+	initialVal := pmetric.NewNumberDataPoint()
+	initialVal.SetDoubleValue(1)
+	statistics, err := NewStatistics([]StatType{P50Type, P99Type, CountType, MinType}, initialVal)
+	require.NoError(t, err)
+	require.Len(t, statistics, 4)
+	require.Same(t, statistics[P50Type].(*percentileStatistic).sketch, statistics[P99Type].(*percentileStatistic).sketch)
+
+	for i := 2; i <= 100; i++ {
+		dp := pmetric.NewNumberDataPoint()
+		dp.SetDoubleValue(float64(i))
+		for _, stat := range statistics {
+			stat.AddDatapoint(dp)
+		}
+	}
+
+	// Each value must only be added to the shared sketch once
+	require.Equal(t, uint64(100), statistics[P50Type].(*percentileStatistic).sketch.count)
+
+	finalDp := pmetric.NewNumberDataPoint()
+	statistics[P99Type].SetDatapointValue(finalDp)
+	require.InEpsilon(t, 99, finalDp.DoubleValue(), sketchRelativeAccuracy)
+}
+
+func TestNewStatisticsEmptyDatapoint(t *testing.T) {
+	statistics, err := NewStatistics([]StatType{P50Type, P99Type, CountType}, pmetric.NewNumberDataPoint())
+	require.ErrorContains(t, err, "cannot create p50 statistic from empty datapoint")
+	require.ErrorContains(t, err, "cannot create p99 statistic from empty datapoint")
+	require.Empty(t, statistics)
+}
+
 func TestStatTypesNewEmptyDatapoint(t *testing.T) {
 	types := []StatType{
 		MinType,
@@ -73,6 +165,15 @@ func TestStatTypesNewEmptyDatapoint(t *testing.T) {
 		MaxType,
 		FirstType,
 		LastType,
+		SumType,
+		CountType,
+		StddevType,
+		RateType,
+		P50Type,
+		P90Type,
+		P95Type,
+		P99Type,
+		StatType("p99.9"),
 	}
 
 	for _, statType := range types {
@@ -113,6 +214,41 @@ func TestStatisticsFloat(t *testing.T) {
 			timestamps: []int64{0, 0, 0, 0},
 			finalValue: 36.75,
 		},
+		{
+			name:       "sum",
+			statType:   SumType,
+			values:     []float64{45, 0, 99, 3.5},
+			timestamps: []int64{0, 0, 0, 0},
+			finalValue: 147.5,
+		},
+		{
+			name:       "stddev",
+			statType:   StddevType,
+			values:     []float64{2, 4, 4, 4, 5, 5, 7, 9},
+			timestamps: []int64{0, 0, 0, 0, 0, 0, 0, 0},
+			finalValue: 2,
+		},
+		{
+			name:       "rate",
+			statType:   RateType,
+			values:     []float64{10, 40, 0, 25},
+			timestamps: []int64{2e9, 8e9, 1e9, 4e9},
+			finalValue: 40.0 / 7,
+		},
+		{
+			name:       "rate (single point in time)",
+			statType:   RateType,
+			values:     []float64{10, 40},
+			timestamps: []int64{1e9, 1e9},
+			finalValue: 0,
+		},
+		{
+			name:       "p50 (single value)",
+			statType:   P50Type,
+			values:     []float64{45},
+			timestamps: []int64{0},
+			finalValue: 45,
+		},
 		{
 			name:       "first (unset timestamp)",
 			statType:   FirstType,
@@ -194,6 +330,20 @@ func TestStatisticsInt(t *testing.T) {
 			timestamps: []int64{0, 0, 0, 0},
 			finalValue: 36,
 		},
+		{
+			name:       "sum",
+			statType:   SumType,
+			values:     []int64{45, 0, 99, 3},
+			timestamps: []int64{0, 0, 0, 0},
+			finalValue: 147,
+		},
+		{
+			name:       "count",
+			statType:   CountType,
+			values:     []int64{45, 0, 99, 3},
+			timestamps: []int64{0, 0, 0, 0},
+			finalValue: 4,
+		},
 		{
 			name:       "first (unset timestamp)",
 			statType:   FirstType,
@@ -0,0 +1,51 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stats
+
+import (
+	"errors"
+	"math"
+
+	"go.opentelemetry.io/collector/pdata/pmetric"
+)
+
+// stddevStatistic calculates the population standard deviation using Welford's online algorithm.
+type stddevStatistic struct {
+	count int64
+	mean  float64
+	m2    float64
+}
+
+func newStddevStatistic(initialVal pmetric.NumberDataPoint) (Statistic, error) {
+	if initialVal.ValueType() == pmetric.NumberDataPointValueTypeEmpty {
+		return nil, errors.New("cannot create stddev statistic from empty datapoint")
+	}
+
+	m := &stddevStatistic{}
+	m.AddDatapoint(initialVal)
+	return m, nil
+}
+
+func (m *stddevStatistic) AddDatapoint(ndp pmetric.NumberDataPoint) {
+	f := getDatapointValueDouble(ndp)
+	m.count++
+	delta := f - m.mean
+	m.mean += delta / float64(m.count)
+	m.m2 += delta * (f - m.mean)
+}
+
+func (m *stddevStatistic) SetDatapointValue(dp pmetric.NumberDataPoint) {
+	dp.SetDoubleValue(math.Sqrt(m.m2 / float64(m.count)))
+}
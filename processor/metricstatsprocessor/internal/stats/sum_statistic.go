@@ -0,0 +1,60 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stats
+
+import (
+	"errors"
+
+	"go.opentelemetry.io/collector/pdata/pmetric"
+)
+
+type sumStatistic struct {
+	totalInt    int64
+	totalDouble float64
+	isInt       bool
+}
+
+func newSumStatistic(initialVal pmetric.NumberDataPoint) (Statistic, error) {
+	switch initialVal.ValueType() {
+	case pmetric.NumberDataPointValueTypeInt:
+		return &sumStatistic{
+			totalInt: initialVal.IntValue(),
+			isInt:    true,
+		}, nil
+	case pmetric.NumberDataPointValueTypeDouble:
+		return &sumStatistic{
+			totalDouble: initialVal.DoubleValue(),
+			isInt:       false,
+		}, nil
+	}
+
+	return nil, errors.New("cannot create sum statistic from empty datapoint")
+}
+
+func (m *sumStatistic) AddDatapoint(ndp pmetric.NumberDataPoint) {
+	if m.isInt {
+		m.totalInt += getDatapointValueInt(ndp)
+	} else {
+		m.totalDouble += getDatapointValueDouble(ndp)
+	}
+}
+
+func (m *sumStatistic) SetDatapointValue(dp pmetric.NumberDataPoint) {
+	if m.isInt {
+		dp.SetIntValue(m.totalInt)
+	} else {
+		dp.SetDoubleValue(m.totalDouble)
+	}
+}
@@ -33,8 +33,14 @@ type metricMetadata struct {
 	metricType pmetric.MetricType
 	// Only relevant to sum metrics
 	monotonic bool
+	// Only relevant to sum and histogram metrics
+	temporality pmetric.AggregationTemporality
 	// Map of attributes hash to datapointMetadata
 	datapoints map[uint64]*datapointMetadata
+	// Map of attributes hash to the merged histogram datapoint
+	histograms map[uint64]pmetric.HistogramDataPoint
+	// Map of attributes hash to the merged exponential histogram datapoint
+	expHistograms map[uint64]pmetric.ExponentialHistogramDataPoint
 }
 
 type datapointMetadata struct {
@@ -16,7 +16,7 @@ package metricstatsprocessor
 
 import "go.opentelemetry.io/collector/pdata/pmetric"
 
-// removeEmptyMetrics removes empty gauge or sum metrics that have no datapoints remaining
+// removeEmptyMetrics removes empty gauge, sum or histogram metrics that have no datapoints remaining
 func removeEmptyMetrics(ms pmetric.MetricSlice) {
 	ms.RemoveIf(func(m pmetric.Metric) bool {
 		switch m.Type() {
@@ -24,6 +24,10 @@ func removeEmptyMetrics(ms pmetric.MetricSlice) {
 			return m.Gauge().DataPoints().Len() == 0
 		case pmetric.MetricTypeSum:
 			return m.Sum().DataPoints().Len() == 0
+		case pmetric.MetricTypeHistogram:
+			return m.Histogram().DataPoints().Len() == 0
+		case pmetric.MetricTypeExponentialHistogram:
+			return m.ExponentialHistogram().DataPoints().Len() == 0
 		}
 		return false
 	})
@@ -52,6 +56,20 @@ func isMonotonic(m pmetric.Metric) bool {
 	return false
 }
 
+// aggregationTemporality returns the aggregation temporality of sum and histogram metrics.
+func aggregationTemporality(m pmetric.Metric) pmetric.AggregationTemporality {
+	switch m.Type() {
+	case pmetric.MetricTypeSum:
+		return m.Sum().AggregationTemporality()
+	case pmetric.MetricTypeHistogram:
+		return m.Histogram().AggregationTemporality()
+	case pmetric.MetricTypeExponentialHistogram:
+		return m.ExponentialHistogram().AggregationTemporality()
+	}
+	// Aggregation temporality is only an attribute of sum and histogram types.
+	return pmetric.AggregationTemporalityUnspecified
+}
+
 // datapointsFromMetric gets the underlying datapoint slice from gauge or sum metrics.
 func datapointsFromMetric(m pmetric.Metric) pmetric.NumberDataPointSlice {
 	switch m.Type() {
@@ -28,7 +28,6 @@ import (
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/pmetric"
-	"go.uber.org/multierr"
 	"go.uber.org/zap"
 )
 
@@ -44,6 +43,9 @@ type metricstatsProcessor struct {
 	flushInterval   time.Duration
 	calcPeriodStart pcommon.Timestamp
 	statTypes       []stats.StatType
+	sumStatTypes    []stats.StatType
+	summary         bool
+	grouper         *attributeGrouper
 	// map resource hash to resourceMetadata
 	statMap      map[uint64]*resourceMetadata
 	nextConsumer consumer.Metrics
@@ -54,6 +56,13 @@ func newStatsProcessor(logger *zap.Logger, cfg *Config, consumer consumer.Metric
 	if err != nil {
 		return nil, fmt.Errorf("failed to compile include regex: %w", err)
 	}
+
+	statTypes := cfg.StatTypes()
+	if cfg.Summary {
+		// A summary always carries the count and sum of the datapoints
+		statTypes = appendMissingStatTypes(statTypes, stats.CountType, stats.SumType)
+	}
+
 	return &metricstatsProcessor{
 		logger:          logger,
 		mux:             sync.Mutex{},
@@ -64,7 +73,10 @@ func newStatsProcessor(logger *zap.Logger, cfg *Config, consumer consumer.Metric
 		flushInterval:   cfg.Interval,
 		calcPeriodStart: pcommon.NewTimestampFromTime(time.Now()),
 		statMap:         make(map[uint64]*resourceMetadata),
-		statTypes:       cfg.StatTypes(),
+		statTypes:       statTypes,
+		sumStatTypes:    sumStatTypes(statTypes),
+		summary:         cfg.Summary,
+		grouper:         newAttributeGrouper(cfg),
 		nextConsumer:    consumer,
 	}, nil
 }
@@ -112,7 +124,24 @@ func (sp *metricstatsProcessor) addMetricsToCalculations(md pmetric.Metrics) {
 					continue
 				}
 
+				// Sums pass through if none of the statistics can be calculated for them
+				if m.Type() == pmetric.MetricTypeSum && len(sp.sumStatTypes) == 0 {
+					continue
+				}
+
 				ma := sp.metricMetadata(m, resKey, resAttrs)
+				if !isCompatibleMetric(ma, m) {
+					continue
+				}
+
+				switch m.Type() {
+				case pmetric.MetricTypeHistogram:
+					sp.addHistogramMetric(ma, m)
+					continue
+				case pmetric.MetricTypeExponentialHistogram:
+					sp.addExpHistogramMetric(ma, m)
+					continue
+				}
 
 				dps := datapointsFromMetric(m)
 				// We remove datapoints that we add to our statistics here, so we use RemoveIf to iterate the datapoints
@@ -154,12 +183,15 @@ func (sp *metricstatsProcessor) metricMetadata(m pmetric.Metric, resKey uint64,
 	if !ok {
 		// Track the metadata for this metric if we haven't already.
 		ma = &metricMetadata{
-			name:       m.Name(),
-			desc:       m.Description(),
-			unit:       m.Unit(),
-			metricType: m.Type(),
-			monotonic:  isMonotonic(m),
-			datapoints: make(map[uint64]*datapointMetadata),
+			name:          m.Name(),
+			desc:          m.Description(),
+			unit:          m.Unit(),
+			metricType:    m.Type(),
+			monotonic:     isMonotonic(m),
+			temporality:   aggregationTemporality(m),
+			datapoints:    make(map[uint64]*datapointMetadata),
+			histograms:    make(map[uint64]pmetric.HistogramDataPoint),
+			expHistograms: make(map[uint64]pmetric.ExponentialHistogramDataPoint),
 		}
 		rma.metrics[m.Name()] = ma
 	}
@@ -170,18 +202,25 @@ func (sp *metricstatsProcessor) metricMetadata(m pmetric.Metric, resKey uint64,
 // addDatapointToStats either adds the datapoint to all existing statistics (if one exists for the NumberDataPoint's attributes),
 // or creates a new set of statistics for the datapoint.
 func (sp *metricstatsProcessor) addDatapointToStats(ma *metricMetadata, dp pmetric.NumberDataPoint) {
-	attributeKey := mapKey(dp.Attributes())
+	attrs := dp.Attributes()
+	if ma.metricType == pmetric.MetricTypeGauge {
+		// Gauge series that are grouped together are calculated as one statistic.
+		// Sum series are calculated separately, and summed when flushed.
+		attrs = sp.grouper.group(attrs)
+	}
+
+	attributeKey := mapKey(attrs)
 	dpa, ok := ma.datapoints[attributeKey]
 	if !ok {
 		// Create the statistics for this datapoint if we haven't already for this set of attributes.
-		statistics, err := sp.createStatistics(dp)
+		statistics, err := sp.createStatistics(ma.metricType, dp)
 		if err != nil {
 			sp.logger.Error("Failed to create some statistics.", zap.Error(err), zap.String("metric", ma.name))
 			// We continue here even if some statistics failed to be created
 		}
 
 		dpa = &datapointMetadata{
-			attributes: dp.Attributes(),
+			attributes: attrs,
 			statistics: statistics,
 		}
 		ma.datapoints[attributeKey] = dpa
@@ -198,20 +237,16 @@ func (sp *metricstatsProcessor) addDatapointToStats(ma *metricMetadata, dp pmetr
 
 // createStatistics creates all statistics for this datapoint based on the configuration of this processor
 // The returned error here is a multierr, and may be a partial err, so the resultant map may be used even if an error is returned.
-func (sp *metricstatsProcessor) createStatistics(initialVal pmetric.NumberDataPoint) (map[stats.StatType]stats.Statistic, error) {
-	var errs error
-	statistics := make(map[stats.StatType]stats.Statistic, len(sp.statTypes))
-	for _, statType := range sp.statTypes {
-		stat, err := statType.New(initialVal)
-		if err != nil {
-			errs = multierr.Append(errs, fmt.Errorf("failed to create statistic: %w", err))
-			continue
-		}
+func (sp *metricstatsProcessor) createStatistics(metricType pmetric.MetricType, initialVal pmetric.NumberDataPoint) (map[stats.StatType]stats.Statistic, error) {
+	return stats.NewStatistics(sp.metricStatTypes(metricType), initialVal)
+}
 
-		statistics[statType] = stat
+// metricStatTypes returns the statistics calculated for metrics of the type.
+func (sp *metricstatsProcessor) metricStatTypes(metricType pmetric.MetricType) []stats.StatType {
+	if metricType == pmetric.MetricTypeSum {
+		return sp.sumStatTypes
 	}
-
-	return statistics, errs
+	return sp.statTypes
 }
 
 // flushLoop is a goroutine that flushes all statistics every sp.flushInterval.
@@ -224,7 +259,7 @@ func (sp *metricstatsProcessor) flushLoop() {
 	for {
 		select {
 		case <-t.C:
-			sp.flush()
+			sp.flush(context.Background())
 		case <-sp.doneChan:
 			return
 		}
@@ -232,7 +267,7 @@ func (sp *metricstatsProcessor) flushLoop() {
 }
 
 // flush flushes all statistics to the next component in the collector pipeline.
-func (sp *metricstatsProcessor) flush() {
+func (sp *metricstatsProcessor) flush(ctx context.Context) {
 	sp.mux.Lock()
 	defer sp.mux.Unlock()
 
@@ -244,15 +279,24 @@ func (sp *metricstatsProcessor) flush() {
 		ra.resource.CopyTo(rm.Resource().Attributes())
 		sm := rm.ScopeMetrics().AppendEmpty()
 
-		for _, statType := range sp.statTypes {
-			for _, ma := range ra.metrics {
-				sp.addCalculatedMetric(now, sm.Metrics(), ma, statType)
+		for _, ma := range ra.metrics {
+			switch {
+			case ma.metricType == pmetric.MetricTypeHistogram:
+				sp.addMergedHistogramMetric(sm.Metrics(), ma)
+			case ma.metricType == pmetric.MetricTypeExponentialHistogram:
+				sp.addMergedExpHistogramMetric(sm.Metrics(), ma)
+			case sp.summary:
+				sp.addSummaryMetric(now, sm.Metrics(), ma)
+			default:
+				for _, statType := range sp.metricStatTypes(ma.metricType) {
+					sp.addCalculatedMetric(now, sm.Metrics(), ma, statType)
+				}
 			}
 		}
 	}
 
 	if metrics.DataPointCount() != 0 {
-		if err := sp.nextConsumer.ConsumeMetrics(context.Background(), metrics); err != nil {
+		if err := sp.nextConsumer.ConsumeMetrics(ctx, metrics); err != nil {
 			sp.logger.Error("Failed to consume metrics.", zap.Error(err))
 		}
 	}
@@ -272,33 +316,79 @@ func (sp *metricstatsProcessor) addCalculatedMetric(now pcommon.Timestamp, ms pm
 	m.SetUnit(ma.unit)
 
 	var dps pmetric.NumberDataPointSlice
-	switch ma.metricType {
-	case pmetric.MetricTypeGauge:
+	switch {
+	case ma.metricType == pmetric.MetricTypeGauge, statType.IsGauge():
 		g := m.SetEmptyGauge()
 		dps = g.DataPoints()
-	case pmetric.MetricTypeSum:
+	case ma.metricType == pmetric.MetricTypeSum:
 		s := m.SetEmptySum()
 		s.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
 		s.SetIsMonotonic(ma.monotonic)
 		dps = s.DataPoints()
 	}
 
-	for _, dpa := range ma.datapoints {
-		stat, ok := dpa.statistics[statType]
-		if !ok {
+	for _, group := range sp.groupDatapoints(ma) {
+		val := pmetric.NewNumberDataPoint()
+		if !setGroupValue(val, group, statType) {
 			// this statistics must have failed to be created, so we can't emit this as a metric
 			continue
 		}
 
 		// Construct datapoints
 		dp := dps.AppendEmpty()
-		stat.SetDatapointValue(dp)
-		dpa.attributes.CopyTo(dp.Attributes())
+		val.MoveTo(dp)
+		group.attributes.CopyTo(dp.Attributes())
 		dp.SetStartTimestamp(sp.calcPeriodStart)
 		dp.SetTimestamp(now)
 	}
 }
 
+// addSummaryMetric adds a single summary metric containing all statistics of the metric.
+// Min, max and percentile statistics are emitted as quantile values.
+func (sp *metricstatsProcessor) addSummaryMetric(now pcommon.Timestamp, ms pmetric.MetricSlice, ma *metricMetadata) {
+	m := ms.AppendEmpty()
+
+	m.SetName(fmt.Sprintf("%s.summary", ma.name))
+	m.SetDescription(ma.desc)
+	m.SetUnit(ma.unit)
+	dps := m.SetEmptySummary().DataPoints()
+
+	for _, group := range sp.groupDatapoints(ma) {
+		dp := dps.AppendEmpty()
+		group.attributes.CopyTo(dp.Attributes())
+		dp.SetStartTimestamp(sp.calcPeriodStart)
+		dp.SetTimestamp(now)
+
+		for _, statType := range sp.metricStatTypes(ma.metricType) {
+			valDp := pmetric.NewNumberDataPoint()
+			if !setGroupValue(valDp, group, statType) {
+				// this statistics must have failed to be created, so we can't emit it
+				continue
+			}
+
+			val := numberValue(valDp)
+			switch statType {
+			case stats.CountType:
+				dp.SetCount(uint64(val))
+			case stats.SumType:
+				dp.SetSum(val)
+			case stats.MinType:
+				addQuantileValue(dp, 0, val)
+			case stats.MaxType:
+				addQuantileValue(dp, 1, val)
+			default:
+				if q, ok := statType.Quantile(); ok {
+					addQuantileValue(dp, q, val)
+				}
+			}
+		}
+
+		dp.QuantileValues().Sort(func(a, b pmetric.SummaryDataPointValueAtQuantile) bool {
+			return a.Quantile() < b.Quantile()
+		})
+	}
+}
+
 func (sp *metricstatsProcessor) Capabilities() consumer.Capabilities {
 	// Data is mutate, since we remove Metric payloads if they are add to a statistic
 	return consumer.Capabilities{MutatesData: true}
@@ -321,6 +411,9 @@ func (sp *metricstatsProcessor) Shutdown(ctx context.Context) error {
 	case <-waitDoneChan: // OK
 	}
 
+	// Flush statistics calculated since the last interval so they aren't lost
+	sp.flush(ctx)
+
 	return nil
 }
 
@@ -330,12 +423,51 @@ func canAddMetricToStats(m pmetric.Metric) bool {
 		return true
 	case pmetric.MetricTypeSum:
 		return m.Sum().AggregationTemporality() == pmetric.AggregationTemporalityCumulative
+	case pmetric.MetricTypeHistogram, pmetric.MetricTypeExponentialHistogram:
+		return aggregationTemporality(m) != pmetric.AggregationTemporalityUnspecified
 	}
 
-	// Currently only gauges and cumulative sums are supported.
+	// Currently only gauges, cumulative sums and histograms are supported.
 	return false
 }
 
+func addQuantileValue(dp pmetric.SummaryDataPoint, quantile, value float64) {
+	qv := dp.QuantileValues().AppendEmpty()
+	qv.SetQuantile(quantile)
+	qv.SetValue(value)
+}
+
+// sumStatTypes returns the statistics that are calculated for sum metrics.
+func sumStatTypes(statTypes []stats.StatType) []stats.StatType {
+	sumTypes := make([]stats.StatType, 0, len(statTypes))
+	for _, statType := range statTypes {
+		if !statType.GaugeOnly() {
+			sumTypes = append(sumTypes, statType)
+		}
+	}
+	return sumTypes
+}
+
+// appendMissingStatTypes appends each of the given stat types that are not already in statTypes.
+func appendMissingStatTypes(statTypes []stats.StatType, types ...stats.StatType) []stats.StatType {
+	result := append([]stats.StatType{}, statTypes...)
+	for _, t := range types {
+		found := false
+		for _, st := range result {
+			if st == t {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			result = append(result, t)
+		}
+	}
+
+	return result
+}
+
 // mapKey returns a unique key for the provided map.
 func mapKey(dimension pcommon.Map) uint64 {
 	b := pdatautil.MapHash(dimension)
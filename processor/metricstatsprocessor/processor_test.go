@@ -57,10 +57,8 @@ func TestMetricstatsProcessor(t *testing.T) {
 			noCalculation: true,
 		},
 		{
-			name:          "histogram",
-			filePath:      "histogram.json",
-			expectOutput:  true,
-			noCalculation: true,
+			name:     "histogram",
+			filePath: "histogram.json",
 		},
 		{
 			name:          "metric name doesn't match regex",
@@ -146,7 +144,7 @@ func TestMetricstatsProcessor(t *testing.T) {
 				require.Empty(t, consumer.AllMetrics(), "Metrics were output, but we didn't expect any to be.")
 			}
 
-			p.flush()
+			p.flush(context.Background())
 
 			if tc.noCalculation {
 				require.Empty(t, consumer.AllMetrics(), "Calculated metrics were output, but we didn't expect any to be.")
@@ -194,7 +192,7 @@ func TestMetricstatsProcessorMultipleMetrics(t *testing.T) {
 
 	require.Empty(t, consumer.AllMetrics())
 
-	p.flush()
+	p.flush(context.Background())
 
 	require.Len(t, consumer.AllMetrics(), 1)
 	calculatedMetric := consumer.AllMetrics()[0]
@@ -290,6 +288,210 @@ func TestMetricstatsProcessor_Flush(t *testing.T) {
 	require.NoError(t, p.Shutdown(context.Background()))
 }
 
+func TestMetricstatsProcessor_ShutdownFlush(t *testing.T) {
+	consumer := &consumertest.MetricsSink{}
+	p, err := newStatsProcessor(zaptest.NewLogger(t), &Config{
+		Interval: time.Hour,
+		Include:  `^test\..*$`,
+		Stats: []stats.StatType{
+			stats.MinType,
+		},
+	}, consumer)
+	require.NoError(t, err)
+
+	require.NoError(t, p.Start(context.Background(), componenttest.NewNopHost()))
+
+	inputMetrics := readMetrics(t, filepath.Join("testdata", "input", "gauge.json"))
+	require.NoError(t, p.ConsumeMetrics(context.Background(), inputMetrics))
+	require.Empty(t, consumer.AllMetrics())
+
+	// Statistics are flushed on shutdown instead of waiting for the interval
+	require.NoError(t, p.Shutdown(context.Background()))
+	require.Len(t, consumer.AllMetrics(), 1)
+}
+
+func TestMetricstatsProcessor_GaugeStats(t *testing.T) {
+	consumer := &consumertest.MetricsSink{}
+	p, err := newStatsProcessor(zaptest.NewLogger(t), &Config{
+		Interval: time.Hour,
+		Include:  `^test\..*$`,
+		Stats: []stats.StatType{
+			stats.SumType,
+			stats.RateType,
+		},
+	}, consumer)
+	require.NoError(t, err)
+
+	require.NoError(t, p.ConsumeMetrics(context.Background(), newSumMetrics(10, 40)))
+	p.flush(context.Background())
+
+	require.Len(t, consumer.AllMetrics(), 1)
+	ms := consumer.AllMetrics()[0].ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+	// The sum of a cumulative sum's values is meaningless, so only the rate is emitted
+	require.Equal(t, 1, ms.Len())
+	m := ms.At(0)
+	require.Equal(t, "test.requests.rate", m.Name())
+	// The rate of a sum isn't a sum itself, so it's emitted as a gauge
+	require.Equal(t, pmetric.MetricTypeGauge, m.Type())
+	require.Equal(t, 30.0, m.Gauge().DataPoints().At(0).DoubleValue())
+}
+
+func TestMetricstatsProcessor_GaugeOnlyStats(t *testing.T) {
+	consumer := &consumertest.MetricsSink{}
+	p, err := newStatsProcessor(zaptest.NewLogger(t), &Config{
+		Interval: time.Hour,
+		Include:  `^test\..*$`,
+		Stats: []stats.StatType{
+			stats.SumType,
+			stats.P50Type,
+		},
+	}, consumer)
+	require.NoError(t, err)
+
+	// Sums pass through, since none of the statistics apply to them
+	require.NoError(t, p.ConsumeMetrics(context.Background(), newSumMetrics(10, 40)))
+	require.Len(t, consumer.AllMetrics(), 1)
+	require.Equal(t, "test.requests", consumer.AllMetrics()[0].ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0).Name())
+	consumer.Reset()
+
+	require.NoError(t, p.ConsumeMetrics(context.Background(), newGaugeMetrics(10, 40)))
+	require.Empty(t, consumer.AllMetrics())
+	p.flush(context.Background())
+
+	require.Len(t, consumer.AllMetrics(), 1)
+	ms := consumer.AllMetrics()[0].ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+	require.Equal(t, 2, ms.Len())
+	for i := 0; i < ms.Len(); i++ {
+		m := ms.At(i)
+		require.Equal(t, pmetric.MetricTypeGauge, m.Type())
+		switch m.Name() {
+		case "test.usage.sum":
+			require.Equal(t, int64(50), m.Gauge().DataPoints().At(0).IntValue())
+		case "test.usage.p50":
+			require.InEpsilon(t, 10.0, m.Gauge().DataPoints().At(0).DoubleValue(), 0.01)
+		default:
+			t.Fatalf("unexpected metric %s", m.Name())
+		}
+	}
+}
+
+func TestMetricstatsProcessor_Summary(t *testing.T) {
+	now := time.UnixMilli(processorStartUnixMilli)
+	calcPeriodStart := pcommon.NewTimestampFromTime(now.Add(-1 * time.Minute))
+	consumer := &consumertest.MetricsSink{}
+	p, err := newStatsProcessor(zaptest.NewLogger(t), &Config{
+		Interval: time.Hour,
+		Include:  `^test\..*$`,
+		Stats: []stats.StatType{
+			stats.P50Type,
+			stats.MaxType,
+			stats.MinType,
+		},
+		Summary: true,
+	}, consumer)
+	require.NoError(t, err)
+
+	p.calcPeriodStart = calcPeriodStart
+	p.now = func() time.Time {
+		return now
+	}
+
+	require.NoError(t, p.ConsumeMetrics(context.Background(), newGaugeMetrics(10, 40, 20)))
+	require.Empty(t, consumer.AllMetrics())
+	p.flush(context.Background())
+
+	require.Len(t, consumer.AllMetrics(), 1)
+	ms := consumer.AllMetrics()[0].ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+	require.Equal(t, 1, ms.Len())
+
+	m := ms.At(0)
+	require.Equal(t, "test.usage.summary", m.Name())
+	require.Equal(t, "Memory usage", m.Description())
+	require.Equal(t, pmetric.MetricTypeSummary, m.Type())
+	require.Equal(t, 1, m.Summary().DataPoints().Len())
+
+	dp := m.Summary().DataPoints().At(0)
+	require.Equal(t, uint64(3), dp.Count())
+	require.Equal(t, 70.0, dp.Sum())
+	require.Equal(t, calcPeriodStart, dp.StartTimestamp())
+	require.Equal(t, pcommon.NewTimestampFromTime(now), dp.Timestamp())
+
+	quantiles := dp.QuantileValues()
+	require.Equal(t, 3, quantiles.Len())
+	require.Equal(t, 0.0, quantiles.At(0).Quantile())
+	require.Equal(t, 10.0, quantiles.At(0).Value())
+	require.Equal(t, 0.5, quantiles.At(1).Quantile())
+	require.InEpsilon(t, 20.0, quantiles.At(1).Value(), 0.01)
+	require.Equal(t, 1.0, quantiles.At(2).Quantile())
+	require.Equal(t, 40.0, quantiles.At(2).Value())
+}
+
+func TestMetricstatsProcessor_SummaryOfSum(t *testing.T) {
+	consumer := &consumertest.MetricsSink{}
+	p, err := newStatsProcessor(zaptest.NewLogger(t), &Config{
+		Interval: time.Hour,
+		Include:  `^test\..*$`,
+		Stats: []stats.StatType{
+			stats.P50Type,
+			stats.MaxType,
+			stats.MinType,
+		},
+		Summary: true,
+	}, consumer)
+	require.NoError(t, err)
+
+	require.NoError(t, p.ConsumeMetrics(context.Background(), newSumMetrics(10, 40, 20)))
+	p.flush(context.Background())
+
+	require.Len(t, consumer.AllMetrics(), 1)
+	m := consumer.AllMetrics()[0].ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0)
+	require.Equal(t, "test.requests.summary", m.Name())
+
+	// The sum and percentiles don't apply to sums, so only the count, min and max are set
+	dp := m.Summary().DataPoints().At(0)
+	require.Equal(t, uint64(3), dp.Count())
+	require.Equal(t, 0.0, dp.Sum())
+
+	quantiles := dp.QuantileValues()
+	require.Equal(t, 2, quantiles.Len())
+	require.Equal(t, 0.0, quantiles.At(0).Quantile())
+	require.Equal(t, 10.0, quantiles.At(0).Value())
+	require.Equal(t, 1.0, quantiles.At(1).Quantile())
+	require.Equal(t, 40.0, quantiles.At(1).Value())
+}
+
+// newGaugeMetrics creates a gauge with a datapoint for each value, one second apart.
+func newGaugeMetrics(values ...int64) pmetric.Metrics {
+	md := pmetric.NewMetrics()
+	m := md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+	m.SetName("test.usage")
+	m.SetDescription("Memory usage")
+	g := m.SetEmptyGauge()
+	for i, v := range values {
+		dp := g.DataPoints().AppendEmpty()
+		dp.SetTimestamp(pcommon.NewTimestampFromTime(time.Unix(int64(i+1), 0)))
+		dp.SetIntValue(v)
+	}
+	return md
+}
+
+// newSumMetrics creates a cumulative sum with a datapoint for each value, one second apart.
+func newSumMetrics(values ...int64) pmetric.Metrics {
+	md := pmetric.NewMetrics()
+	m := md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+	m.SetName("test.requests")
+	m.SetDescription("Number of requests")
+	s := m.SetEmptySum()
+	s.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+	s.SetIsMonotonic(true)
+	for i, v := range values {
+		dp := s.DataPoints().AppendEmpty()
+		dp.SetTimestamp(pcommon.NewTimestampFromTime(time.Unix(int64(i+1), 0)))
+		dp.SetIntValue(v)
+	}
+	return md
+}
+
 func readMetrics(t *testing.T, path string) pmetric.Metrics {
 	t.Helper()
 
@@ -0,0 +1,127 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package quotaprocessor provides a processor that enforces data-volume quotas per source.
+package quotaprocessor
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+)
+
+const (
+	// unitBytes measures quota usage in bytes of telemetry.
+	unitBytes = "bytes"
+	// unitRecords measures quota usage in log records, data points, or spans.
+	unitRecords = "records"
+
+	// policyDrop drops telemetry that is over quota.
+	policyDrop = "drop"
+	// policySample keeps a sample of telemetry that is over quota.
+	policySample = "sample"
+	// policyRoute sends telemetry that is over quota to a route receiver.
+	policyRoute = "route"
+)
+
+var (
+	errInvalidLimit       = errors.New("limit must be greater than 0")
+	errInvalidPeriod      = errors.New("period must be greater than 0")
+	errInvalidSampleRatio = errors.New("sample_ratio must be between 0.0 and 1.0")
+	errMissingRoute       = errors.New("route must be set when policy is route")
+)
+
+// Config is the config of the processor.
+type Config struct {
+	// Unit is what quota usage is measured in. Either bytes or records.
+	Unit string `mapstructure:"unit"`
+
+	// Limit is the default quota of each key per period.
+	Limit int64 `mapstructure:"limit"`
+
+	// Period is the length of each quota window. Windows are aligned to UTC.
+	Period time.Duration `mapstructure:"period"`
+
+	// KeyAttributes are the resource attributes used to build quota keys. If empty, all resource attributes are used.
+	KeyAttributes []string `mapstructure:"key_attributes"`
+
+	// Overrides set a different quota for keys whose resource attributes match.
+	Overrides []Override `mapstructure:"overrides"`
+
+	// Policy is applied to telemetry that is over quota. One of drop, sample, or route.
+	Policy string `mapstructure:"policy"`
+
+	// SampleRatio is the ratio of telemetry kept when over quota with the sample policy.
+	SampleRatio float64 `mapstructure:"sample_ratio"`
+
+	// Route is the route receiver that telemetry is sent to when over quota with the route policy.
+	Route string `mapstructure:"route"`
+
+	// StorageID is the storage extension used to persist quota usage across restarts.
+	StorageID *component.ID `mapstructure:"storage"`
+}
+
+// Override is a quota applied to keys with matching resource attributes.
+type Override struct {
+	// Match is the set of resource attributes a key must have for the override to apply.
+	Match map[string]string `mapstructure:"match"`
+
+	// Limit is the quota of matching keys per period.
+	Limit int64 `mapstructure:"limit"`
+}
+
+// Validate validates the processor configuration.
+func (cfg Config) Validate() error {
+	switch cfg.Unit {
+	case unitBytes, unitRecords:
+	default:
+		return fmt.Errorf("invalid unit '%s': must be one of %s or %s", cfg.Unit, unitBytes, unitRecords)
+	}
+
+	if cfg.Limit <= 0 {
+		return errInvalidLimit
+	}
+
+	if cfg.Period <= 0 {
+		return errInvalidPeriod
+	}
+
+	for i, override := range cfg.Overrides {
+		if len(override.Match) == 0 {
+			return fmt.Errorf("override %d: match must not be empty", i)
+		}
+
+		if override.Limit <= 0 {
+			return fmt.Errorf("override %d: %w", i, errInvalidLimit)
+		}
+	}
+
+	switch cfg.Policy {
+	case policyDrop:
+	case policySample:
+		if cfg.SampleRatio < 0.0 || cfg.SampleRatio > 1.0 {
+			return errInvalidSampleRatio
+		}
+	case policyRoute:
+		if cfg.Route == "" {
+			return errMissingRoute
+		}
+	default:
+		return fmt.Errorf("invalid policy '%s': must be one of %s, %s, or %s", cfg.Policy, policyDrop, policySample, policyRoute)
+	}
+
+	return nil
+}
@@ -0,0 +1,144 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package quotaprocessor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+)
+
+func TestConfigValidate(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		cfg         Config
+		expectedErr string
+	}{
+		{
+			desc: "Valid config",
+			cfg: Config{
+				Unit:   unitBytes,
+				Limit:  100,
+				Period: time.Hour,
+				Policy: policyDrop,
+			},
+		},
+		{
+			desc: "Invalid unit",
+			cfg: Config{
+				Unit:   "gigabytes",
+				Limit:  100,
+				Period: time.Hour,
+				Policy: policyDrop,
+			},
+			expectedErr: "invalid unit 'gigabytes': must be one of bytes or records",
+		},
+		{
+			desc: "Invalid limit",
+			cfg: Config{
+				Unit:   unitRecords,
+				Period: time.Hour,
+				Policy: policyDrop,
+			},
+			expectedErr: errInvalidLimit.Error(),
+		},
+		{
+			desc: "Invalid period",
+			cfg: Config{
+				Unit:   unitRecords,
+				Limit:  100,
+				Policy: policyDrop,
+			},
+			expectedErr: errInvalidPeriod.Error(),
+		},
+		{
+			desc: "Override without match",
+			cfg: Config{
+				Unit:      unitBytes,
+				Limit:     100,
+				Period:    time.Hour,
+				Policy:    policyDrop,
+				Overrides: []Override{{Limit: 10}},
+			},
+			expectedErr: "override 0: match must not be empty",
+		},
+		{
+			desc: "Override with invalid limit",
+			cfg: Config{
+				Unit:      unitBytes,
+				Limit:     100,
+				Period:    time.Hour,
+				Policy:    policyDrop,
+				Overrides: []Override{{Match: map[string]string{"host.group": "x"}}},
+			},
+			expectedErr: "override 0: limit must be greater than 0",
+		},
+		{
+			desc: "Invalid sample ratio",
+			cfg: Config{
+				Unit:        unitBytes,
+				Limit:       100,
+				Period:      time.Hour,
+				Policy:      policySample,
+				SampleRatio: 1.5,
+			},
+			expectedErr: errInvalidSampleRatio.Error(),
+		},
+		{
+			desc: "Route policy without route",
+			cfg: Config{
+				Unit:   unitBytes,
+				Limit:  100,
+				Period: time.Hour,
+				Policy: policyRoute,
+			},
+			expectedErr: errMissingRoute.Error(),
+		},
+		{
+			desc: "Invalid policy",
+			cfg: Config{
+				Unit:   unitBytes,
+				Limit:  100,
+				Period: time.Hour,
+				Policy: "block",
+			},
+			expectedErr: "invalid policy 'block': must be one of drop, sample, or route",
+		},
+		{
+			desc: "Valid route config with storage",
+			cfg: Config{
+				Unit:      unitBytes,
+				Limit:     100,
+				Period:    time.Hour,
+				Policy:    policyRoute,
+				Route:     "cheap",
+				StorageID: &component.ID{},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			err := tc.cfg.Validate()
+			if tc.expectedErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.EqualError(t, err, tc.expectedErr)
+		})
+	}
+}
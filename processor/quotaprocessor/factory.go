@@ -0,0 +1,103 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package quotaprocessor
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/processor"
+	"go.opentelemetry.io/collector/processor/processorhelper"
+)
+
+const (
+	typeStr = "quota"
+
+	stability = component.StabilityLevelAlpha
+)
+
+var (
+	consumerCapabilities = consumer.Capabilities{MutatesData: true}
+)
+
+// NewFactory creates a new ProcessorFactory with default configuration
+func NewFactory() processor.Factory {
+	return processor.NewFactory(
+		typeStr,
+		createDefaultConfig,
+		processor.WithTraces(createTracesProcessor, stability),
+		processor.WithLogs(createLogsProcessor, stability),
+		processor.WithMetrics(createMetricsProcessor, stability),
+	)
+}
+
+func createDefaultConfig() component.Config {
+	return &Config{
+		Unit:        unitBytes,
+		Period:      24 * time.Hour,
+		Policy:      policyDrop,
+		SampleRatio: 0.1,
+	}
+}
+
+func createTracesProcessor(
+	ctx context.Context,
+	set processor.CreateSettings,
+	cfg component.Config,
+	nextConsumer consumer.Traces,
+) (processor.Traces, error) {
+	oCfg := cfg.(*Config)
+	qp := newQuotaProcessor(oCfg, set.ID, component.DataTypeTraces, set.Logger)
+
+	return processorhelper.NewTracesProcessor(ctx, set, cfg, nextConsumer, qp.processTraces,
+		processorhelper.WithCapabilities(consumerCapabilities),
+		processorhelper.WithStart(qp.start),
+		processorhelper.WithShutdown(qp.shutdown),
+	)
+}
+
+func createLogsProcessor(
+	ctx context.Context,
+	set processor.CreateSettings,
+	cfg component.Config,
+	nextConsumer consumer.Logs,
+) (processor.Logs, error) {
+	oCfg := cfg.(*Config)
+	qp := newQuotaProcessor(oCfg, set.ID, component.DataTypeLogs, set.Logger)
+
+	return processorhelper.NewLogsProcessor(ctx, set, cfg, nextConsumer, qp.processLogs,
+		processorhelper.WithCapabilities(consumerCapabilities),
+		processorhelper.WithStart(qp.start),
+		processorhelper.WithShutdown(qp.shutdown),
+	)
+}
+
+func createMetricsProcessor(
+	ctx context.Context,
+	set processor.CreateSettings,
+	cfg component.Config,
+	nextConsumer consumer.Metrics,
+) (processor.Metrics, error) {
+	oCfg := cfg.(*Config)
+	qp := newQuotaProcessor(oCfg, set.ID, component.DataTypeMetrics, set.Logger)
+
+	return processorhelper.NewMetricsProcessor(ctx, set, cfg, nextConsumer, qp.processMetrics,
+		processorhelper.WithCapabilities(consumerCapabilities),
+		processorhelper.WithStart(qp.start),
+		processorhelper.WithShutdown(qp.shutdown),
+	)
+}
@@ -0,0 +1,397 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package quotaprocessor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/observiq/bindplane-agent/receiver/routereceiver"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/extension/experimental/storage"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.uber.org/zap"
+)
+
+// storageKey is the key that quota usage is persisted under.
+const storageKey = "quota_usage"
+
+// persistInterval is how often quota usage is persisted to storage.
+var persistInterval = 10 * time.Second
+
+// quotaProcessor is a processor that enforces data-volume quotas.
+type quotaProcessor struct {
+	config   *Config
+	tracker  *quotaTracker
+	id       component.ID
+	dataType component.DataType
+	logger   *zap.Logger
+	client   storage.Client
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+
+	logsSizer    *plog.ProtoMarshaler
+	metricsSizer *pmetric.ProtoMarshaler
+	tracesSizer  *ptrace.ProtoMarshaler
+}
+
+// newQuotaProcessor returns a new processor.
+func newQuotaProcessor(config *Config, id component.ID, dataType component.DataType, logger *zap.Logger) *quotaProcessor {
+	return &quotaProcessor{
+		config:       config,
+		tracker:      newQuotaTracker(config),
+		id:           id,
+		dataType:     dataType,
+		logger:       logger,
+		logsSizer:    &plog.ProtoMarshaler{},
+		metricsSizer: &pmetric.ProtoMarshaler{},
+		tracesSizer:  &ptrace.ProtoMarshaler{},
+	}
+}
+
+// start loads persisted usage and starts persisting usage on an interval.
+func (p *quotaProcessor) start(ctx context.Context, host component.Host) error {
+	client, err := getStorageClient(ctx, host, p.config.StorageID, p.id, p.dataType)
+	if err != nil {
+		return fmt.Errorf("getStorageClient: %w", err)
+	}
+	p.client = client
+
+	if err := p.loadUsage(ctx); err != nil {
+		p.logger.Warn("Failed to load quota usage, starting from zero", zap.Error(err))
+	}
+
+	persistCtx, cancel := context.WithCancel(context.Background())
+	p.cancel = cancel
+
+	p.wg.Add(1)
+	go p.persistLoop(persistCtx)
+
+	return nil
+}
+
+// shutdown stops persisting usage on an interval and persists the final usage.
+func (p *quotaProcessor) shutdown(ctx context.Context) error {
+	if p.cancel != nil {
+		p.cancel()
+	}
+	p.wg.Wait()
+
+	if p.client == nil {
+		return nil
+	}
+
+	return errors.Join(p.saveUsage(ctx), p.client.Close(ctx))
+}
+
+// persistLoop persists usage until the context is canceled.
+func (p *quotaProcessor) persistLoop(ctx context.Context) {
+	defer p.wg.Done()
+
+	ticker := time.NewTicker(persistInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := p.saveUsage(ctx); err != nil {
+				p.logger.Error("Failed to persist quota usage", zap.Error(err))
+			}
+		}
+	}
+}
+
+// loadUsage loads usage from storage.
+func (p *quotaProcessor) loadUsage(ctx context.Context) error {
+	data, err := p.client.Get(ctx, storageKey)
+	if err != nil {
+		return fmt.Errorf("get usage: %w", err)
+	}
+
+	if data == nil {
+		return nil
+	}
+
+	var state quotaState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return fmt.Errorf("unmarshal usage: %w", err)
+	}
+
+	p.tracker.load(state)
+	return nil
+}
+
+// saveUsage saves usage to storage if it has changed.
+func (p *quotaProcessor) saveUsage(ctx context.Context) error {
+	state, dirty := p.tracker.snapshot()
+	if !dirty {
+		return nil
+	}
+
+	data, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("marshal usage: %w", err)
+	}
+
+	if err := p.client.Set(ctx, storageKey, data); err != nil {
+		return fmt.Errorf("set usage: %w", err)
+	}
+
+	return nil
+}
+
+// record adds to the usage of a key and alerts when the key crosses its limit.
+func (p *quotaProcessor) record(key string, limit, amount int64) {
+	usage, crossed := p.tracker.add(key, amount, limit)
+	if !crossed {
+		return
+	}
+
+	p.logger.Warn("Quota exceeded",
+		zap.String("key", key),
+		zap.Int64("usage", usage),
+		zap.Int64("limit", limit),
+		zap.String("unit", p.config.Unit),
+		zap.String("policy", p.config.Policy),
+		zap.Time("period_start", p.tracker.periodStart()),
+	)
+}
+
+// keep returns true if telemetry over quota should be kept by the sample policy.
+func (p *quotaProcessor) keep() bool {
+	//#nosec G404 -- randomly generated number is not used for security purposes. It's ok if it's weak
+	return rand.Float64() < p.config.SampleRatio
+}
+
+// processLogs enforces quotas on logs.
+func (p *quotaProcessor) processLogs(ctx context.Context, ld plog.Logs) (plog.Logs, error) {
+	routed := plog.NewLogs()
+
+	ld.ResourceLogs().RemoveIf(func(rl plog.ResourceLogs) bool {
+		key, limit := p.tracker.key(rl.Resource().Attributes())
+		if !p.tracker.exceeded(key, limit) {
+			p.record(key, limit, p.logsAmount(rl))
+			return false
+		}
+
+		switch p.config.Policy {
+		case policySample:
+			for i := 0; i < rl.ScopeLogs().Len(); i++ {
+				rl.ScopeLogs().At(i).LogRecords().RemoveIf(func(_ plog.LogRecord) bool {
+					return !p.keep()
+				})
+			}
+			return logRecordCount(rl) == 0
+		case policyRoute:
+			rl.MoveTo(routed.ResourceLogs().AppendEmpty())
+		}
+		return true
+	})
+
+	if routed.ResourceLogs().Len() > 0 {
+		if err := routereceiver.RouteLogs(ctx, p.config.Route, routed); err != nil {
+			p.logger.Error("Failed to route logs over quota", zap.String("route", p.config.Route), zap.Error(err))
+		}
+	}
+
+	return ld, nil
+}
+
+// processMetrics enforces quotas on metrics.
+func (p *quotaProcessor) processMetrics(ctx context.Context, md pmetric.Metrics) (pmetric.Metrics, error) {
+	routed := pmetric.NewMetrics()
+
+	md.ResourceMetrics().RemoveIf(func(rm pmetric.ResourceMetrics) bool {
+		key, limit := p.tracker.key(rm.Resource().Attributes())
+		if !p.tracker.exceeded(key, limit) {
+			p.record(key, limit, p.metricsAmount(rm))
+			return false
+		}
+
+		switch p.config.Policy {
+		case policySample:
+			for i := 0; i < rm.ScopeMetrics().Len(); i++ {
+				rm.ScopeMetrics().At(i).Metrics().RemoveIf(func(_ pmetric.Metric) bool {
+					return !p.keep()
+				})
+			}
+			return dataPointCount(rm) == 0
+		case policyRoute:
+			rm.MoveTo(routed.ResourceMetrics().AppendEmpty())
+		}
+		return true
+	})
+
+	if routed.ResourceMetrics().Len() > 0 {
+		if err := routereceiver.RouteMetrics(ctx, p.config.Route, routed); err != nil {
+			p.logger.Error("Failed to route metrics over quota", zap.String("route", p.config.Route), zap.Error(err))
+		}
+	}
+
+	return md, nil
+}
+
+// processTraces enforces quotas on traces.
+func (p *quotaProcessor) processTraces(ctx context.Context, td ptrace.Traces) (ptrace.Traces, error) {
+	routed := ptrace.NewTraces()
+
+	td.ResourceSpans().RemoveIf(func(rs ptrace.ResourceSpans) bool {
+		key, limit := p.tracker.key(rs.Resource().Attributes())
+		if !p.tracker.exceeded(key, limit) {
+			p.record(key, limit, p.tracesAmount(rs))
+			return false
+		}
+
+		switch p.config.Policy {
+		case policySample:
+			for i := 0; i < rs.ScopeSpans().Len(); i++ {
+				rs.ScopeSpans().At(i).Spans().RemoveIf(func(_ ptrace.Span) bool {
+					return !p.keep()
+				})
+			}
+			return spanCount(rs) == 0
+		case policyRoute:
+			rs.MoveTo(routed.ResourceSpans().AppendEmpty())
+		}
+		return true
+	})
+
+	if routed.ResourceSpans().Len() > 0 {
+		if err := routereceiver.RouteTraces(ctx, p.config.Route, routed); err != nil {
+			p.logger.Error("Failed to route traces over quota", zap.String("route", p.config.Route), zap.Error(err))
+		}
+	}
+
+	return td, nil
+}
+
+// logsAmount returns the quota usage of resource logs.
+func (p *quotaProcessor) logsAmount(rl plog.ResourceLogs) int64 {
+	if p.config.Unit == unitRecords {
+		return int64(logRecordCount(rl))
+	}
+
+	// Temporarily move the resource into its own payload to size it without copying
+	tmp := plog.NewLogs()
+	rl.MoveTo(tmp.ResourceLogs().AppendEmpty())
+	size := p.logsSizer.LogsSize(tmp)
+	tmp.ResourceLogs().At(0).MoveTo(rl)
+
+	return int64(size)
+}
+
+// metricsAmount returns the quota usage of resource metrics.
+func (p *quotaProcessor) metricsAmount(rm pmetric.ResourceMetrics) int64 {
+	if p.config.Unit == unitRecords {
+		return int64(dataPointCount(rm))
+	}
+
+	tmp := pmetric.NewMetrics()
+	rm.MoveTo(tmp.ResourceMetrics().AppendEmpty())
+	size := p.metricsSizer.MetricsSize(tmp)
+	tmp.ResourceMetrics().At(0).MoveTo(rm)
+
+	return int64(size)
+}
+
+// tracesAmount returns the quota usage of resource spans.
+func (p *quotaProcessor) tracesAmount(rs ptrace.ResourceSpans) int64 {
+	if p.config.Unit == unitRecords {
+		return int64(spanCount(rs))
+	}
+
+	tmp := ptrace.NewTraces()
+	rs.MoveTo(tmp.ResourceSpans().AppendEmpty())
+	size := p.tracesSizer.TracesSize(tmp)
+	tmp.ResourceSpans().At(0).MoveTo(rs)
+
+	return int64(size)
+}
+
+// logRecordCount returns the number of log records in resource logs.
+func logRecordCount(rl plog.ResourceLogs) int {
+	count := 0
+	for i := 0; i < rl.ScopeLogs().Len(); i++ {
+		count += rl.ScopeLogs().At(i).LogRecords().Len()
+	}
+	return count
+}
+
+// dataPointCount returns the number of data points in resource metrics.
+func dataPointCount(rm pmetric.ResourceMetrics) int {
+	count := 0
+	for i := 0; i < rm.ScopeMetrics().Len(); i++ {
+		metrics := rm.ScopeMetrics().At(i).Metrics()
+		for j := 0; j < metrics.Len(); j++ {
+			count += metricDataPointCount(metrics.At(j))
+		}
+	}
+	return count
+}
+
+// metricDataPointCount returns the number of data points in a metric.
+func metricDataPointCount(metric pmetric.Metric) int {
+	switch metric.Type() {
+	case pmetric.MetricTypeGauge:
+		return metric.Gauge().DataPoints().Len()
+	case pmetric.MetricTypeSum:
+		return metric.Sum().DataPoints().Len()
+	case pmetric.MetricTypeHistogram:
+		return metric.Histogram().DataPoints().Len()
+	case pmetric.MetricTypeExponentialHistogram:
+		return metric.ExponentialHistogram().DataPoints().Len()
+	case pmetric.MetricTypeSummary:
+		return metric.Summary().DataPoints().Len()
+	default:
+		return 0
+	}
+}
+
+// spanCount returns the number of spans in resource spans.
+func spanCount(rs ptrace.ResourceSpans) int {
+	count := 0
+	for i := 0; i < rs.ScopeSpans().Len(); i++ {
+		count += rs.ScopeSpans().At(i).Spans().Len()
+	}
+	return count
+}
+
+// getStorageClient gets the storage client for this processor, taking into account the component ID and data type.
+func getStorageClient(ctx context.Context, host component.Host, storageID *component.ID, componentID component.ID, componentType component.DataType) (storage.Client, error) {
+	if storageID == nil {
+		return storage.NewNopClient(), nil
+	}
+
+	extension, ok := host.GetExtensions()[*storageID]
+	if !ok {
+		return nil, fmt.Errorf("storage extension '%s' not found", storageID)
+	}
+
+	storageExtension, ok := extension.(storage.Extension)
+	if !ok {
+		return nil, fmt.Errorf("non-storage extension '%s' found", storageID)
+	}
+
+	return storageExtension.GetClient(ctx, component.KindProcessor, componentID, string(componentType))
+}
@@ -0,0 +1,233 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package quotaprocessor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/observiq/bindplane-agent/receiver/routereceiver"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/extension/experimental/storage"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/collector/receiver"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// memoryClient is an in memory storage client.
+type memoryClient struct {
+	storage.Client
+	data map[string][]byte
+}
+
+func (c *memoryClient) Get(_ context.Context, key string) ([]byte, error) {
+	return c.data[key], nil
+}
+
+func (c *memoryClient) Set(_ context.Context, key string, value []byte) error {
+	c.data[key] = value
+	return nil
+}
+
+func (c *memoryClient) Close(_ context.Context) error {
+	return nil
+}
+
+func testConfig(policy string) *Config {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Unit = unitRecords
+	cfg.Limit = 2
+	cfg.KeyAttributes = []string{"host.name"}
+	cfg.Policy = policy
+	return cfg
+}
+
+func newTestLogs(hosts ...string) plog.Logs {
+	ld := plog.NewLogs()
+	for _, host := range hosts {
+		rl := ld.ResourceLogs().AppendEmpty()
+		rl.Resource().Attributes().PutStr("host.name", host)
+		records := rl.ScopeLogs().AppendEmpty().LogRecords()
+		records.AppendEmpty().Body().SetStr("first")
+		records.AppendEmpty().Body().SetStr("second")
+	}
+	return ld
+}
+
+func resourceHosts(ld plog.Logs) []string {
+	hosts := []string{}
+	for i := 0; i < ld.ResourceLogs().Len(); i++ {
+		host, _ := ld.ResourceLogs().At(i).Resource().Attributes().Get("host.name")
+		hosts = append(hosts, host.Str())
+	}
+	return hosts
+}
+
+func TestProcessLogsDrop(t *testing.T) {
+	core, logs := observer.New(zapcore.WarnLevel)
+	p := newQuotaProcessor(testConfig(policyDrop), component.NewID(typeStr), component.DataTypeLogs, zap.New(core))
+
+	ld, err := p.processLogs(context.Background(), newTestLogs("host1"))
+	require.NoError(t, err)
+	require.Equal(t, []string{"host1"}, resourceHosts(ld))
+
+	alerts := logs.FilterMessage("Quota exceeded").All()
+	require.Len(t, alerts, 1)
+	require.Equal(t, `{"host.name":"host1"}`, alerts[0].ContextMap()["key"])
+	require.Equal(t, int64(2), alerts[0].ContextMap()["usage"])
+
+	ld, err = p.processLogs(context.Background(), newTestLogs("host1", "host2"))
+	require.NoError(t, err)
+	require.Equal(t, []string{"host2"}, resourceHosts(ld))
+	require.Equal(t, 2, logs.FilterMessage("Quota exceeded").Len())
+}
+
+func TestProcessLogsBytes(t *testing.T) {
+	cfg := testConfig(policyDrop)
+	cfg.Unit = unitBytes
+	cfg.Limit = 1000
+	p := newQuotaProcessor(cfg, component.NewID(typeStr), component.DataTypeLogs, zap.NewNop())
+
+	input := newTestLogs("host1")
+	expected := plog.NewLogs()
+	input.CopyTo(expected)
+
+	ld, err := p.processLogs(context.Background(), input)
+	require.NoError(t, err)
+	require.Equal(t, expected, ld)
+
+	state, _ := p.tracker.snapshot()
+	size := (&plog.ProtoMarshaler{}).LogsSize(expected)
+	require.Equal(t, int64(size), state.Usage[`{"host.name":"host1"}`])
+}
+
+func TestProcessMetricsSample(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		sampleRatio float64
+		expected    int
+	}{
+		{
+			desc:        "Keep none",
+			sampleRatio: 0.0,
+			expected:    0,
+		},
+		{
+			desc:        "Keep all",
+			sampleRatio: 1.0,
+			expected:    1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			cfg := testConfig(policySample)
+			cfg.SampleRatio = tc.sampleRatio
+			p := newQuotaProcessor(cfg, component.NewID(typeStr), component.DataTypeMetrics, zap.NewNop())
+
+			newMetrics := func() pmetric.Metrics {
+				md := pmetric.NewMetrics()
+				rm := md.ResourceMetrics().AppendEmpty()
+				rm.Resource().Attributes().PutStr("host.name", "host1")
+				dps := rm.ScopeMetrics().AppendEmpty().Metrics().AppendEmpty().SetEmptyGauge().DataPoints()
+				dps.AppendEmpty().SetIntValue(1)
+				dps.AppendEmpty().SetIntValue(2)
+				return md
+			}
+
+			md, err := p.processMetrics(context.Background(), newMetrics())
+			require.NoError(t, err)
+			require.Equal(t, 2, md.DataPointCount())
+
+			md, err = p.processMetrics(context.Background(), newMetrics())
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, md.ResourceMetrics().Len())
+		})
+	}
+}
+
+func TestProcessTracesRoute(t *testing.T) {
+	sink := &consumertest.TracesSink{}
+	receiverFactory := routereceiver.NewFactory()
+	receiverSettings := receiver.CreateSettings{ID: component.NewIDWithName("route", "cheap")}
+	r, err := receiverFactory.CreateTracesReceiver(context.Background(), receiverSettings, receiverFactory.CreateDefaultConfig(), sink)
+	require.NoError(t, err)
+	require.NoError(t, r.Start(context.Background(), nil))
+	defer r.Shutdown(context.Background())
+
+	cfg := testConfig(policyRoute)
+	cfg.Route = "cheap"
+	p := newQuotaProcessor(cfg, component.NewID(typeStr), component.DataTypeTraces, zap.NewNop())
+
+	newTraces := func() ptrace.Traces {
+		td := ptrace.NewTraces()
+		rs := td.ResourceSpans().AppendEmpty()
+		rs.Resource().Attributes().PutStr("host.name", "host1")
+		spans := rs.ScopeSpans().AppendEmpty().Spans()
+		spans.AppendEmpty().SetName("first")
+		spans.AppendEmpty().SetName("second")
+		return td
+	}
+
+	td, err := p.processTraces(context.Background(), newTraces())
+	require.NoError(t, err)
+	require.Equal(t, 2, td.SpanCount())
+	require.Equal(t, 0, sink.SpanCount())
+
+	td, err = p.processTraces(context.Background(), newTraces())
+	require.NoError(t, err)
+	require.Equal(t, 0, td.ResourceSpans().Len())
+	require.Equal(t, 2, sink.SpanCount())
+	require.Equal(t, newTraces(), sink.AllTraces()[0])
+}
+
+func TestPersistUsage(t *testing.T) {
+	client := &memoryClient{data: map[string][]byte{}}
+	cfg := testConfig(policyDrop)
+
+	p := newQuotaProcessor(cfg, component.NewID(typeStr), component.DataTypeLogs, zap.NewNop())
+	p.client = client
+	_, err := p.processLogs(context.Background(), newTestLogs("host1"))
+	require.NoError(t, err)
+	require.NoError(t, p.saveUsage(context.Background()))
+	require.Contains(t, client.data, storageKey)
+
+	restarted := newQuotaProcessor(cfg, component.NewID(typeStr), component.DataTypeLogs, zap.NewNop())
+	restarted.client = client
+	require.NoError(t, restarted.loadUsage(context.Background()))
+
+	ld, err := restarted.processLogs(context.Background(), newTestLogs("host1"))
+	require.NoError(t, err)
+	require.Equal(t, 0, ld.ResourceLogs().Len())
+}
+
+func TestStartShutdown(t *testing.T) {
+	persistInterval = time.Millisecond
+	defer func() { persistInterval = 10 * time.Second }()
+
+	p := newQuotaProcessor(testConfig(policyDrop), component.NewID(typeStr), component.DataTypeLogs, zap.NewNop())
+	require.NoError(t, p.start(context.Background(), nil))
+	_, err := p.processLogs(context.Background(), newTestLogs("host1"))
+	require.NoError(t, err)
+	time.Sleep(10 * time.Millisecond)
+	require.NoError(t, p.shutdown(context.Background()))
+}
@@ -0,0 +1,171 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package quotaprocessor
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+)
+
+// quotaState is the usage of each quota key within a period.
+type quotaState struct {
+	PeriodStart time.Time        `json:"period_start"`
+	Usage       map[string]int64 `json:"usage"`
+}
+
+// quotaTracker tracks the usage of quota keys within the current period.
+type quotaTracker struct {
+	keyAttributes []string
+	limit         int64
+	overrides     []Override
+	period        time.Duration
+	now           func() time.Time
+
+	mux   sync.Mutex
+	state quotaState
+	dirty bool
+}
+
+// newQuotaTracker returns a new quota tracker.
+func newQuotaTracker(cfg *Config) *quotaTracker {
+	return &quotaTracker{
+		keyAttributes: cfg.KeyAttributes,
+		limit:         cfg.Limit,
+		overrides:     cfg.Overrides,
+		period:        cfg.Period,
+		now:           time.Now,
+		state:         quotaState{Usage: map[string]int64{}},
+	}
+}
+
+// key returns the quota key and limit of a resource.
+func (q *quotaTracker) key(attrs pcommon.Map) (string, int64) {
+	dimension := attrs.AsRaw()
+	if len(q.keyAttributes) > 0 {
+		dimension = make(map[string]any, len(q.keyAttributes))
+		for _, k := range q.keyAttributes {
+			if v, ok := attrs.Get(k); ok {
+				dimension[k] = v.AsRaw()
+			}
+		}
+	}
+
+	keyJSON, _ := json.Marshal(dimension)
+	return string(keyJSON), q.limitOf(attrs)
+}
+
+// limitOf returns the limit of the first override matching the attributes, or the default limit.
+func (q *quotaTracker) limitOf(attrs pcommon.Map) int64 {
+	for _, override := range q.overrides {
+		if matches(attrs, override.Match) {
+			return override.Limit
+		}
+	}
+	return q.limit
+}
+
+// matches returns true if the attributes contain every key and value in match.
+func matches(attrs pcommon.Map, match map[string]string) bool {
+	for k, want := range match {
+		v, ok := attrs.Get(k)
+		if !ok || v.AsString() != want {
+			return false
+		}
+	}
+	return true
+}
+
+// exceeded returns true if the key has used its entire limit in the current period.
+func (q *quotaTracker) exceeded(key string, limit int64) bool {
+	q.mux.Lock()
+	defer q.mux.Unlock()
+
+	q.roll()
+	return q.state.Usage[key] >= limit
+}
+
+// add adds to the usage of the key. It returns the new usage and whether this addition crossed the limit.
+func (q *quotaTracker) add(key string, amount, limit int64) (int64, bool) {
+	q.mux.Lock()
+	defer q.mux.Unlock()
+
+	q.roll()
+	previous := q.state.Usage[key]
+	usage := previous + amount
+	q.state.Usage[key] = usage
+	q.dirty = true
+
+	return usage, previous < limit && usage >= limit
+}
+
+// roll resets usage when the current period has ended. The caller must hold the lock.
+func (q *quotaTracker) roll() {
+	start := q.now().UTC().Truncate(q.period)
+	if start.Equal(q.state.PeriodStart) {
+		return
+	}
+
+	q.state = quotaState{
+		PeriodStart: start,
+		Usage:       map[string]int64{},
+	}
+	q.dirty = true
+}
+
+// periodStart returns the start of the current period.
+func (q *quotaTracker) periodStart() time.Time {
+	q.mux.Lock()
+	defer q.mux.Unlock()
+
+	q.roll()
+	return q.state.PeriodStart
+}
+
+// load restores usage from a previous run. Usage from a previous period is discarded.
+func (q *quotaTracker) load(state quotaState) {
+	q.mux.Lock()
+	defer q.mux.Unlock()
+
+	q.roll()
+	if !state.PeriodStart.Equal(q.state.PeriodStart) {
+		return
+	}
+
+	for key, usage := range state.Usage {
+		q.state.Usage[key] += usage
+	}
+}
+
+// snapshot returns a copy of the current state and whether it has changed since the last snapshot.
+func (q *quotaTracker) snapshot() (quotaState, bool) {
+	q.mux.Lock()
+	defer q.mux.Unlock()
+
+	q.roll()
+	state := quotaState{
+		PeriodStart: q.state.PeriodStart,
+		Usage:       make(map[string]int64, len(q.state.Usage)),
+	}
+	for key, usage := range q.state.Usage {
+		state.Usage[key] = usage
+	}
+
+	dirty := q.dirty
+	q.dirty = false
+	return state, dirty
+}
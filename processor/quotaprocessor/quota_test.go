@@ -0,0 +1,113 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package quotaprocessor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+)
+
+func newTestTracker(cfg *Config, now *time.Time) *quotaTracker {
+	tracker := newQuotaTracker(cfg)
+	tracker.now = func() time.Time { return *now }
+	return tracker
+}
+
+func TestQuotaTrackerKey(t *testing.T) {
+	attrs := pcommon.NewMap()
+	attrs.PutStr("host.name", "host1")
+	attrs.PutStr("host.group", "x")
+
+	cfg := &Config{Limit: 100, Period: time.Hour}
+	tracker := newQuotaTracker(cfg)
+	key, limit := tracker.key(attrs)
+	require.Equal(t, `{"host.group":"x","host.name":"host1"}`, key)
+	require.Equal(t, int64(100), limit)
+
+	cfg.KeyAttributes = []string{"host.group", "missing"}
+	cfg.Overrides = []Override{
+		{Match: map[string]string{"host.group": "y"}, Limit: 10},
+		{Match: map[string]string{"host.group": "x"}, Limit: 50},
+		{Match: map[string]string{"host.name": "host1"}, Limit: 20},
+	}
+	tracker = newQuotaTracker(cfg)
+	key, limit = tracker.key(attrs)
+	require.Equal(t, `{"host.group":"x"}`, key)
+	require.Equal(t, int64(50), limit)
+}
+
+func TestQuotaTrackerAdd(t *testing.T) {
+	now := time.Date(2023, 6, 1, 10, 30, 0, 0, time.UTC)
+	tracker := newTestTracker(&Config{Limit: 100, Period: 24 * time.Hour}, &now)
+
+	require.False(t, tracker.exceeded("key", 100))
+
+	usage, crossed := tracker.add("key", 60, 100)
+	require.Equal(t, int64(60), usage)
+	require.False(t, crossed)
+
+	usage, crossed = tracker.add("key", 60, 100)
+	require.Equal(t, int64(120), usage)
+	require.True(t, crossed)
+	require.True(t, tracker.exceeded("key", 100))
+	require.False(t, tracker.exceeded("other", 100))
+
+	_, crossed = tracker.add("key", 10, 100)
+	require.False(t, crossed)
+
+	// Usage resets at the start of the next period
+	now = now.Add(14 * time.Hour)
+	require.False(t, tracker.exceeded("key", 100))
+	require.Equal(t, time.Date(2023, 6, 2, 0, 0, 0, 0, time.UTC), tracker.periodStart())
+}
+
+func TestQuotaTrackerLoad(t *testing.T) {
+	now := time.Date(2023, 6, 1, 10, 30, 0, 0, time.UTC)
+	tracker := newTestTracker(&Config{Limit: 100, Period: 24 * time.Hour}, &now)
+
+	tracker.load(quotaState{
+		PeriodStart: time.Date(2023, 5, 31, 0, 0, 0, 0, time.UTC),
+		Usage:       map[string]int64{"key": 100},
+	})
+	require.False(t, tracker.exceeded("key", 100))
+
+	tracker.load(quotaState{
+		PeriodStart: time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC),
+		Usage:       map[string]int64{"key": 100},
+	})
+	require.True(t, tracker.exceeded("key", 100))
+}
+
+func TestQuotaTrackerSnapshot(t *testing.T) {
+	now := time.Date(2023, 6, 1, 10, 30, 0, 0, time.UTC)
+	tracker := newTestTracker(&Config{Limit: 100, Period: 24 * time.Hour}, &now)
+
+	_, dirty := tracker.snapshot()
+	require.True(t, dirty)
+
+	_, dirty = tracker.snapshot()
+	require.False(t, dirty)
+
+	tracker.add("key", 5, 100)
+	state, dirty := tracker.snapshot()
+	require.True(t, dirty)
+	require.Equal(t, quotaState{
+		PeriodStart: time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC),
+		Usage:       map[string]int64{"key": 5},
+	}, state)
+}
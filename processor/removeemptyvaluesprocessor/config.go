@@ -29,6 +29,9 @@ const (
 
 var allFields = []string{attributesField, resourceField, bodyField}
 
+// wildcardSegment is a path segment of a MapKey's key that matches any key.
+const wildcardSegment = "*"
+
 // MapKey represents a key into a particular map (denoted by field)
 type MapKey struct {
 	field string
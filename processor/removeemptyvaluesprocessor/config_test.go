@@ -63,6 +63,10 @@ func TestLoadConfig(t *testing.T) {
 						field: "attributes",
 						key:   "attribute.key",
 					},
+					{
+						field: "body",
+						key:   "*.id",
+					},
 				},
 			},
 		},
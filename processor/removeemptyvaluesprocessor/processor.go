@@ -99,7 +99,7 @@ func (evp *emptyValueProcessor) processTraces(_ context.Context, td ptrace.Trace
 
 			for k := 0; k < spans.Len(); k++ {
 				span := spans.At(k)
-				cleanMap(span.Attributes(), evp.c, evp.excludeAttributeKeySet)
+				cleanSpanAttrs(span, evp.c, evp.excludeAttributeKeySet)
 			}
 		}
 	}
@@ -168,40 +168,69 @@ func (evp *emptyValueProcessor) processMetrics(_ context.Context, md pmetric.Met
 // cleanMap removes empty values from the map, as defined by the config.
 func cleanMap(m pcommon.Map, c Config, excludeKeys map[string]struct{}) {
 	m.RemoveIf(func(s string, v pcommon.Value) bool {
-		if _, ok := excludeKeys[s]; ok {
+		if isExcludedKey(s, excludeKeys) {
 			return false
 		}
 
-		switch v.Type() {
-		case pcommon.ValueTypeEmpty:
-			return c.RemoveNulls
-		case pcommon.ValueTypeMap:
-			subMap := v.Map()
-			cleanMap(subMap, c, trimMapKeyPrefix(s, excludeKeys))
-			return c.RemoveEmptyMaps && subMap.Len() == 0
-		case pcommon.ValueTypeSlice:
-			return c.RemoveEmptyLists && v.Slice().Len() == 0
-		case pcommon.ValueTypeStr:
-			return shouldFilterString(v.Str(), c.EmptyStringValues)
-		}
+		return cleanValue(v, c, trimMapKeyPrefix(s, excludeKeys))
+	})
+}
 
-		return false
+// cleanSlice removes empty values from the slice, as defined by the config.
+// Maps nested in the slice are cleaned using the exclude keys of the slice itself.
+func cleanSlice(s pcommon.Slice, c Config, excludeKeys map[string]struct{}) {
+	s.RemoveIf(func(v pcommon.Value) bool {
+		return cleanValue(v, c, excludeKeys)
 	})
 }
 
+// cleanValue removes empty values nested in the value, and returns true if the value itself is empty.
+func cleanValue(v pcommon.Value, c Config, excludeKeys map[string]struct{}) bool {
+	switch v.Type() {
+	case pcommon.ValueTypeEmpty:
+		return c.RemoveNulls
+	case pcommon.ValueTypeMap:
+		subMap := v.Map()
+		cleanMap(subMap, c, excludeKeys)
+		return c.RemoveEmptyMaps && subMap.Len() == 0
+	case pcommon.ValueTypeSlice:
+		subSlice := v.Slice()
+		cleanSlice(subSlice, c, excludeKeys)
+		return c.RemoveEmptyLists && subSlice.Len() == 0
+	case pcommon.ValueTypeStr:
+		return shouldFilterString(v.Str(), c.EmptyStringValues)
+	}
+
+	return false
+}
+
+// isExcludedKey returns true if the key, or a wildcard matching any key, is in the set of excluded keys.
+func isExcludedKey(key string, keySet map[string]struct{}) bool {
+	if _, ok := keySet[key]; ok {
+		return true
+	}
+
+	_, ok := keySet[wildcardSegment]
+	return ok
+}
+
 // trimMapKeyPrefix returns the provided keys with the specified prefix removed.
+// Keys starting with a wildcard segment match any prefix.
 // Any keys that don't have the prefix are removed from the returned list.
 func trimMapKeyPrefix(prefix string, keySet map[string]struct{}) map[string]struct{} {
+	if len(keySet) == 0 {
+		return keySet
+	}
+
 	outKeys := make(map[string]struct{}, len(keySet))
 	for key := range keySet {
-		// TODO: use strings.CutPrefix when we update to go1.20
-		trimmedKey := strings.TrimPrefix(key, prefix+".")
-		if len(trimmedKey) == len(key) {
-			// the original key was left untrimmed, so this must not have the prefix
-			continue
+		if trimmedKey, ok := strings.CutPrefix(key, prefix+"."); ok {
+			outKeys[trimmedKey] = struct{}{}
 		}
 
-		outKeys[trimmedKey] = struct{}{}
+		if trimmedKey, ok := strings.CutPrefix(key, wildcardSegment+"."); ok {
+			outKeys[trimmedKey] = struct{}{}
+		}
 	}
 
 	return outKeys
@@ -219,6 +248,21 @@ func shouldFilterString(s string, emptyValues []string) bool {
 	return false
 }
 
+// cleanSpanAttrs removes any attributes that should be considered empty from the span, its events, and its links.
+func cleanSpanAttrs(span ptrace.Span, c Config, keys map[string]struct{}) {
+	cleanMap(span.Attributes(), c, keys)
+
+	events := span.Events()
+	for i := 0; i < events.Len(); i++ {
+		cleanMap(events.At(i).Attributes(), c, keys)
+	}
+
+	links := span.Links()
+	for i := 0; i < links.Len(); i++ {
+		cleanMap(links.At(i).Attributes(), c, keys)
+	}
+}
+
 // cleanMetricAttrs removes any attributes that should be considered empty from all the datapoints in the metrics.
 func cleanMetricAttrs(metric pmetric.Metric, c Config, keys map[string]struct{}) {
 	switch metric.Type() {
@@ -261,21 +305,7 @@ func cleanMetricAttrs(metric pmetric.Metric, c Config, keys map[string]struct{})
 // cleanLogBody removes empty values from the log body.
 func cleanLogBody(lr plog.LogRecord, c Config, keys map[string]struct{}) {
 	body := lr.Body()
-	switch body.Type() {
-	case pcommon.ValueTypeMap:
-		bodyMap := body.Map()
-		cleanMap(bodyMap, c, keys)
-		if c.RemoveEmptyMaps && bodyMap.Len() == 0 {
-			pcommon.NewValueEmpty().CopyTo(body)
-		}
-	case pcommon.ValueTypeSlice:
-		bodySlice := body.Slice()
-		if c.RemoveEmptyLists && bodySlice.Len() == 0 {
-			pcommon.NewValueEmpty().CopyTo(body)
-		}
-	case pcommon.ValueTypeStr:
-		if shouldFilterString(body.Str(), c.EmptyStringValues) {
-			pcommon.NewValueEmpty().CopyTo(body)
-		}
+	if body.Type() != pcommon.ValueTypeEmpty && cleanValue(body, c, keys) {
+		pcommon.NewValueEmpty().CopyTo(body)
 	}
 }
@@ -310,6 +310,41 @@ func TestProcessTraces(t *testing.T) {
 	})
 }
 
+func TestProcessTracesEventsAndLinks(t *testing.T) {
+	p := newEmptyValueProcessor(zaptest.NewLogger(t), Config{
+		RemoveNulls:      true,
+		RemoveEmptyLists: true,
+		RemoveEmptyMaps:  true,
+		EmptyStringValues: []string{
+			"-",
+		},
+		ExcludeKeys: []MapKey{
+			{
+				field: attributesField,
+				key:   "empty.key",
+			},
+		},
+	})
+
+	inputTraces := testTraces()
+	span := inputTraces.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0)
+	span.Events().AppendEmpty().Attributes().FromRaw(rawAttributes)
+	span.Links().AppendEmpty().Attributes().FromRaw(rawAttributes)
+
+	outputTraces, err := p.processTraces(context.Background(), inputTraces)
+	require.NoError(t, err)
+
+	expected := map[string]any{
+		"empty.key": nil,
+		"attr_key":  "attr_value",
+	}
+
+	span = outputTraces.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0)
+	require.Equal(t, expected, span.Attributes().AsRaw())
+	require.Equal(t, expected, span.Events().At(0).Attributes().AsRaw())
+	require.Equal(t, expected, span.Links().At(0).Attributes().AsRaw())
+}
+
 func TestProcessLogs(t *testing.T) {
 	t.Run("Removes attributes", func(t *testing.T) {
 		p := newEmptyValueProcessor(zaptest.NewLogger(t), Config{
@@ -591,6 +626,89 @@ func TestProcessLogs(t *testing.T) {
 	})
 }
 
+func TestProcessLogsNested(t *testing.T) {
+	t.Run("Removes empty values in slices", func(t *testing.T) {
+		p := newEmptyValueProcessor(zaptest.NewLogger(t), Config{
+			RemoveNulls:      true,
+			RemoveEmptyLists: true,
+			RemoveEmptyMaps:  true,
+			EmptyStringValues: []string{
+				"-",
+			},
+		})
+
+		inputLog := testLogNestedBody()
+
+		outputLogs, err := p.processLogs(context.Background(), inputLog)
+		require.NoError(t, err)
+
+		outLogRecord := outputLogs.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0)
+		require.Equal(t, map[string]any{
+			"tags": []any{"a", "b"},
+			"users": []any{
+				map[string]any{"id": "1", "name": "one"},
+				map[string]any{"name": "two"},
+			},
+		}, outLogRecord.Body().AsRaw())
+	})
+
+	t.Run("Removes empty slice body after removing its values", func(t *testing.T) {
+		p := newEmptyValueProcessor(zaptest.NewLogger(t), Config{
+			RemoveNulls:      true,
+			RemoveEmptyLists: true,
+		})
+
+		ld := plog.NewLogs()
+		logRecord := ld.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords().AppendEmpty()
+		require.NoError(t, logRecord.Body().SetEmptySlice().FromRaw([]any{nil, []any{nil}}))
+
+		outputLogs, err := p.processLogs(context.Background(), ld)
+		require.NoError(t, err)
+
+		outLogRecord := outputLogs.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0)
+		require.Equal(t, pcommon.ValueTypeEmpty, outLogRecord.Body().Type())
+	})
+
+	t.Run("Ignores keys matching wildcards", func(t *testing.T) {
+		p := newEmptyValueProcessor(zaptest.NewLogger(t), Config{
+			RemoveNulls:      true,
+			RemoveEmptyLists: true,
+			RemoveEmptyMaps:  true,
+			EmptyStringValues: []string{
+				"-",
+			},
+			ExcludeKeys: []MapKey{
+				{
+					field: bodyField,
+					key:   "users.id",
+				},
+				{
+					field: bodyField,
+					key:   "*.removable",
+				},
+			},
+		})
+
+		inputLog := testLogNestedBody()
+
+		outputLogs, err := p.processLogs(context.Background(), inputLog)
+		require.NoError(t, err)
+
+		outLogRecord := outputLogs.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0)
+		require.Equal(t, map[string]any{
+			"tags": []any{"a", "b"},
+			"users": []any{
+				map[string]any{"id": "1", "name": "one"},
+				map[string]any{"id": "-", "name": "two"},
+				map[string]any{"id": nil},
+			},
+			"nested": map[string]any{
+				"removable": "-",
+			},
+		}, outLogRecord.Body().AsRaw())
+	})
+}
+
 var rawResourceAttributes = map[string]any{
 	"empty.key":        nil,
 	"removable.string": "-",
@@ -638,6 +756,28 @@ func testLog() plog.Logs {
 	return ld
 }
 
+func testLogNestedBody() plog.Logs {
+	ld := plog.NewLogs()
+	resourceLog := ld.ResourceLogs().AppendEmpty()
+	logRecord := resourceLog.ScopeLogs().AppendEmpty().LogRecords().AppendEmpty()
+
+	logRecord.Body().SetEmptyMap().FromRaw(map[string]any{
+		"tags": []any{"a", nil, "-", "b", []any{}},
+		"users": []any{
+			map[string]any{"id": "1", "name": "one"},
+			map[string]any{"id": "-", "name": "two"},
+			map[string]any{"id": nil},
+			map[string]any{},
+		},
+		"nested": map[string]any{
+			"removable": "-",
+		},
+		"empty": []any{nil, []any{nil}, map[string]any{"key": nil}},
+	})
+
+	return ld
+}
+
 func testLogEmptySliceBody() plog.Logs {
 	ld := plog.NewLogs()
 	resourceLog := ld.ResourceLogs().AppendEmpty()
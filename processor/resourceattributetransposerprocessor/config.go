@@ -15,12 +15,86 @@
 // Package resourceattributetransposerprocessor provides a processor that transposes resource attributes to datapoint attributes
 package resourceattributetransposerprocessor
 
+import (
+	"errors"
+	"fmt"
+	"regexp"
+)
+
+const (
+	// conflictKeep keeps the existing attribute when the target attribute already exists.
+	conflictKeep = "keep"
+
+	// conflictOverwrite overwrites the existing attribute when the target attribute already exists.
+	conflictOverwrite = "overwrite"
+
+	// conflictSuffix copies to the target attribute with a suffix when the target attribute already exists.
+	conflictSuffix = "suffix"
+
+	// defaultConflictSuffix is the default suffix used by the suffix conflict policy.
+	defaultConflictSuffix = "_resource"
+)
+
+var (
+	// errFromMissing is the error for an operation without a resource attribute to copy from.
+	errFromMissing = errors.New("one of from, from_regex, or from_prefix must be specified")
+
+	// errFromMultiple is the error for an operation with more than one resource attribute matcher.
+	errFromMultiple = errors.New("only one of from, from_regex, or from_prefix may be specified")
+
+	// errOnConflictInvalid is the error for an invalid conflict policy.
+	errOnConflictInvalid = errors.New("on_conflict must be one of keep, overwrite, or suffix")
+)
+
 // CopyResourceConfig is a config struct specifying a mapping of a resource attribute to a datapoint attribute
 type CopyResourceConfig struct {
 	// From is the attribute on the resource to copy from
 	From string `mapstructure:"from"`
+	// FromRegex is a regex matching the attributes on the resource to copy from.
+	// To is expanded as a template for each matching attribute, e.g. $1 is replaced with the first submatch.
+	FromRegex string `mapstructure:"from_regex"`
+	// FromPrefix is a prefix of the attributes on the resource to copy from.
+	// The prefix of each matching attribute is replaced with To.
+	FromPrefix string `mapstructure:"from_prefix"`
 	// To is the attribute to copy to on the individual data point
 	To string `mapstructure:"to"`
+	// Move removes the attribute from the resource after it's copied
+	Move bool `mapstructure:"move"`
+	// OnConflict is the policy applied when the attribute to copy to already exists: keep, overwrite or suffix
+	OnConflict string `mapstructure:"on_conflict"`
+	// ConflictSuffix is appended to the attribute to copy to when it already exists and OnConflict is suffix
+	ConflictSuffix string `mapstructure:"conflict_suffix"`
+}
+
+// validate returns an error if the operation is invalid.
+func (c CopyResourceConfig) validate() error {
+	matchers := 0
+	for _, from := range []string{c.From, c.FromRegex, c.FromPrefix} {
+		if from != "" {
+			matchers++
+		}
+	}
+
+	switch {
+	case matchers == 0:
+		return errFromMissing
+	case matchers > 1:
+		return errFromMultiple
+	}
+
+	if c.FromRegex != "" {
+		if _, err := regexp.Compile(c.FromRegex); err != nil {
+			return fmt.Errorf("invalid from_regex: %w", err)
+		}
+	}
+
+	switch c.OnConflict {
+	case "", conflictKeep, conflictOverwrite, conflictSuffix:
+	default:
+		return errOnConflictInvalid
+	}
+
+	return nil
 }
 
 // Config is the configuration for the resourceattributetransposer
@@ -28,3 +102,13 @@ type Config struct {
 	// Operations is a list of copy operations to perform on each ResourceMetric.
 	Operations []CopyResourceConfig `mapstructure:"operations"`
 }
+
+// Validate returns an error if the config is invalid.
+func (c Config) Validate() error {
+	for i, op := range c.Operations {
+		if err := op.validate(); err != nil {
+			return fmt.Errorf("operations[%d]: %w", i, err)
+		}
+	}
+	return nil
+}
@@ -55,3 +55,51 @@ func TestConfig(t *testing.T) {
 		},
 	}, r1)
 }
+
+func TestConfigValidate(t *testing.T) {
+	testCases := []struct {
+		name        string
+		operations  []CopyResourceConfig
+		expectedErr string
+	}{
+		{
+			name: "valid operations",
+			operations: []CopyResourceConfig{
+				{From: "host.name", To: "host"},
+				{FromRegex: `^k8s\.(.*)$`, To: "kubernetes.$1", OnConflict: conflictSuffix},
+				{FromPrefix: "cloud.", Move: true, OnConflict: conflictOverwrite},
+			},
+		},
+		{
+			name:        "missing from",
+			operations:  []CopyResourceConfig{{To: "host"}},
+			expectedErr: "operations[0]: one of from, from_regex, or from_prefix must be specified",
+		},
+		{
+			name:        "multiple from",
+			operations:  []CopyResourceConfig{{From: "host.name", FromPrefix: "host."}},
+			expectedErr: "operations[0]: only one of from, from_regex, or from_prefix may be specified",
+		},
+		{
+			name:        "invalid regex",
+			operations:  []CopyResourceConfig{{From: "host.name"}, {FromRegex: "("}},
+			expectedErr: "operations[1]: invalid from_regex",
+		},
+		{
+			name:        "invalid on_conflict",
+			operations:  []CopyResourceConfig{{From: "host.name", OnConflict: "replace"}},
+			expectedErr: "operations[0]: on_conflict must be one of keep, overwrite, or suffix",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := Config{Operations: tc.operations}.Validate()
+			if tc.expectedErr != "" {
+				require.ErrorContains(t, err, tc.expectedErr)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
@@ -36,6 +36,7 @@ func NewFactory() processor.Factory {
 		createDefaultConfig,
 		processor.WithMetrics(createMetricsProcessor, stability),
 		processor.WithLogs(createLogsProcessor, stability),
+		processor.WithTraces(createTracesProcessor, stability),
 	)
 }
 
@@ -62,3 +63,12 @@ func createLogsProcessor(_ context.Context, params processor.CreateSettings, cfg
 
 	return newLogsProcessor(params.Logger, nextConsumer, processorCfg), nil
 }
+
+func createTracesProcessor(_ context.Context, params processor.CreateSettings, cfg component.Config, nextConsumer consumer.Traces) (processor.Traces, error) {
+	processorCfg, ok := cfg.(*Config)
+	if !ok {
+		return nil, fmt.Errorf("config was not of correct type for the processor: %+v", cfg)
+	}
+
+	return newTracesProcessor(params.Logger, nextConsumer, processorCfg), nil
+}
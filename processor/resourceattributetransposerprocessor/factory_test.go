@@ -58,3 +58,15 @@ func TestCreateLogsProcessorNilConfig(t *testing.T) {
 	_, err := createLogsProcessor(context.Background(), processortest.NewNopCreateSettings(), nil, consumertest.NewNop())
 	require.Error(t, err)
 }
+
+func TestCreateTracesProcessor(t *testing.T) {
+	cfg := createDefaultConfig()
+	p, err := createTracesProcessor(context.Background(), processortest.NewNopCreateSettings(), cfg, consumertest.NewNop())
+	require.NotNil(t, p)
+	require.NoError(t, err)
+}
+
+func TestCreateTracesProcessorNilConfig(t *testing.T) {
+	_, err := createTracesProcessor(context.Background(), processortest.NewNopCreateSettings(), nil, consumertest.NewNop())
+	require.Error(t, err)
+}
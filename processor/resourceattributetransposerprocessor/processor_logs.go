@@ -24,17 +24,19 @@ import (
 )
 
 type logsProcessor struct {
-	consumer consumer.Logs
-	logger   *zap.Logger
-	config   *Config
+	consumer   consumer.Logs
+	logger     *zap.Logger
+	config     *Config
+	transposer *transposer
 }
 
 // newLogsProcessor returns a new logsResourceAttributeTransposerProcessor
 func newLogsProcessor(logger *zap.Logger, consumer consumer.Logs, config *Config) *logsProcessor {
 	return &logsProcessor{
-		consumer: consumer,
-		logger:   logger,
-		config:   config,
+		consumer:   consumer,
+		logger:     logger,
+		config:     config,
+		transposer: newTransposer(logger, config),
 	}
 }
 
@@ -53,24 +55,21 @@ func (p logsProcessor) ConsumeLogs(ctx context.Context, md plog.Logs) error {
 	for i := 0; i < resLogs.Len(); i++ {
 		resLog := resLogs.At(i)
 		resourceAttrs := resLog.Resource().Attributes()
-		for _, op := range p.config.Operations {
-			resourceValue, ok := resourceAttrs.Get(op.From)
-			if !ok {
-				continue
-			}
+		copies := p.transposer.copies(resourceAttrs)
+		if len(copies) == 0 {
+			continue
+		}
 
-			scopeLogs := resLog.ScopeLogs()
-			for j := 0; j < scopeLogs.Len(); j++ {
-				scopeLog := scopeLogs.At(j)
-				logs := scopeLog.LogRecords()
-				for k := 0; k < logs.Len(); k++ {
-					log := logs.At(k)
-					if _, ok := log.Attributes().Get(op.To); !ok {
-						resourceValue.CopyTo(log.Attributes().PutEmpty(op.To))
-					}
-				}
+		scopeLogs := resLog.ScopeLogs()
+		for j := 0; j < scopeLogs.Len(); j++ {
+			scopeLog := scopeLogs.At(j)
+			logs := scopeLog.LogRecords()
+			for k := 0; k < logs.Len(); k++ {
+				apply(copies, logs.At(k).Attributes())
 			}
 		}
+
+		removeMoved(copies, resourceAttrs)
 	}
 
 	return p.consumer.ConsumeLogs(ctx, md)
@@ -19,23 +19,24 @@ import (
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/consumer"
-	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/pmetric"
 	"go.uber.org/zap"
 )
 
 type metricsProcessor struct {
-	consumer consumer.Metrics
-	logger   *zap.Logger
-	config   *Config
+	consumer   consumer.Metrics
+	logger     *zap.Logger
+	config     *Config
+	transposer *transposer
 }
 
 // newMetricsProcessor returns a new resourceToMetricsAttributesProcessor
 func newMetricsProcessor(logger *zap.Logger, consumer consumer.Metrics, config *Config) *metricsProcessor {
 	return &metricsProcessor{
-		consumer: consumer,
-		logger:   logger,
-		config:   config,
+		consumer:   consumer,
+		logger:     logger,
+		config:     config,
+		transposer: newTransposer(logger, config),
 	}
 }
 
@@ -55,22 +56,22 @@ func (p metricsProcessor) ConsumeMetrics(ctx context.Context, md pmetric.Metrics
 	for i := 0; i < resMetrics.Len(); i++ {
 		resMetric := resMetrics.At(i)
 		resourceAttrs := resMetric.Resource().Attributes()
-		for _, op := range p.config.Operations {
-			resourceValue, ok := resourceAttrs.Get(op.From)
-			if !ok {
-				continue
-			}
+		copies := p.transposer.copies(resourceAttrs)
+		if len(copies) == 0 {
+			continue
+		}
 
-			ilms := resMetric.ScopeMetrics()
-			for j := 0; j < ilms.Len(); j++ {
-				ilm := ilms.At(j)
-				metrics := ilm.Metrics()
-				for k := 0; k < metrics.Len(); k++ {
-					metric := metrics.At(k)
-					setMetricAttr(metric, op.To, resourceValue)
-				}
+		ilms := resMetric.ScopeMetrics()
+		for j := 0; j < ilms.Len(); j++ {
+			ilm := ilms.At(j)
+			metrics := ilm.Metrics()
+			for k := 0; k < metrics.Len(); k++ {
+				metric := metrics.At(k)
+				setMetricAttrs(metric, copies)
 			}
 		}
+
+		removeMoved(copies, resourceAttrs)
 	}
 	return p.consumer.ConsumeMetrics(ctx, md)
 }
@@ -80,41 +81,33 @@ func (metricsProcessor) Shutdown(_ context.Context) error {
 	return nil
 }
 
-// setMetricAttr sets the attribute (attrName) to the given value for every datapoint in the metric
-func setMetricAttr(metric pmetric.Metric, attrName string, value pcommon.Value) {
+// setMetricAttrs copies the resource attributes to every datapoint in the metric
+func setMetricAttrs(metric pmetric.Metric, copies []attributeCopy) {
 	switch metric.Type() {
 	case pmetric.MetricTypeGauge:
 		dps := metric.Gauge().DataPoints()
 		for i := 0; i < dps.Len(); i++ {
-			dp := dps.At(i)
-			if _, ok := dp.Attributes().Get(attrName); !ok {
-				value.CopyTo(dp.Attributes().PutEmpty(attrName))
-			}
+			apply(copies, dps.At(i).Attributes())
 		}
-
 	case pmetric.MetricTypeHistogram:
 		dps := metric.Histogram().DataPoints()
 		for i := 0; i < dps.Len(); i++ {
-			dp := dps.At(i)
-			if _, ok := dp.Attributes().Get(attrName); !ok {
-				value.CopyTo(dp.Attributes().PutEmpty(attrName))
-			}
+			apply(copies, dps.At(i).Attributes())
+		}
+	case pmetric.MetricTypeExponentialHistogram:
+		dps := metric.ExponentialHistogram().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			apply(copies, dps.At(i).Attributes())
 		}
 	case pmetric.MetricTypeSum:
 		dps := metric.Sum().DataPoints()
 		for i := 0; i < dps.Len(); i++ {
-			dp := dps.At(i)
-			if _, ok := dp.Attributes().Get(attrName); !ok {
-				value.CopyTo(dp.Attributes().PutEmpty(attrName))
-			}
+			apply(copies, dps.At(i).Attributes())
 		}
 	case pmetric.MetricTypeSummary:
 		dps := metric.Summary().DataPoints()
 		for i := 0; i < dps.Len(); i++ {
-			dp := dps.At(i)
-			if _, ok := dp.Attributes().Get(attrName); !ok {
-				value.CopyTo(dp.Attributes().PutEmpty(attrName))
-			}
+			apply(copies, dps.At(i).Attributes())
 		}
 	default:
 		// skip metric if None or unknown type
@@ -531,6 +531,45 @@ func (m *mockMetricsConsumer) Shutdown(ctx context.Context) error {
 	return args.Error(0)
 }
 
+func TestConsumeMetricsExponentialHistogramMove(t *testing.T) {
+	ctx := context.Background()
+	metrics := createMetrics()
+
+	attrs := metrics.ResourceMetrics().At(0).Resource().Attributes()
+	attrs.PutStr("resourceattrib1", "value1")
+	attrs.PutStr("resourceattrib2", "value2")
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.Operations = []CopyResourceConfig{
+		{
+			From: "resourceattrib1",
+			To:   "resourceattrib1out",
+			Move: true,
+		},
+	}
+
+	sink := &consumertest.MetricsSink{}
+	p := newMetricsProcessor(
+		zap.NewNop(),
+		sink,
+		cfg,
+	)
+
+	metric := getMetric(metrics)
+	metric.SetEmptyExponentialHistogram().DataPoints().AppendEmpty()
+
+	err := p.ConsumeMetrics(ctx, metrics)
+	require.NoError(t, err)
+
+	metricsOut := sink.AllMetrics()[0]
+	require.Equal(t, map[string]any{
+		"resourceattrib1out": "value1",
+	}, getMetricAttrsFromMetrics(metricsOut))
+	require.Equal(t, map[string]any{
+		"resourceattrib2": "value2",
+	}, metricsOut.ResourceMetrics().At(0).Resource().Attributes().AsRaw())
+}
+
 func getMetricSlice(m pmetric.Metrics) pmetric.MetricSlice {
 	return m.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
 }
@@ -557,6 +596,8 @@ func getMetricAttrsFromMetric(m pmetric.Metric) map[string]any {
 		return m.Sum().DataPoints().At(0).Attributes().AsRaw()
 	case pmetric.MetricTypeHistogram:
 		return m.Histogram().DataPoints().At(0).Attributes().AsRaw()
+	case pmetric.MetricTypeExponentialHistogram:
+		return m.ExponentialHistogram().DataPoints().At(0).Attributes().AsRaw()
 	case pmetric.MetricTypeSummary:
 		return m.Summary().DataPoints().At(0).Attributes().AsRaw()
 	}
@@ -0,0 +1,81 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resourceattributetransposerprocessor
+
+import (
+	"context"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.uber.org/zap"
+)
+
+type tracesProcessor struct {
+	consumer   consumer.Traces
+	logger     *zap.Logger
+	config     *Config
+	transposer *transposer
+}
+
+// newTracesProcessor returns a new tracesProcessor
+func newTracesProcessor(logger *zap.Logger, consumer consumer.Traces, config *Config) *tracesProcessor {
+	return &tracesProcessor{
+		consumer:   consumer,
+		logger:     logger,
+		config:     config,
+		transposer: newTransposer(logger, config),
+	}
+}
+
+// Start starts the processor. It's a noop.
+func (tracesProcessor) Start(_ context.Context, _ component.Host) error {
+	return nil
+}
+
+// Capabilities returns the consumer's capabilities. Indicates that this processor mutates the incoming traces.
+func (tracesProcessor) Capabilities() consumer.Capabilities {
+	return consumer.Capabilities{MutatesData: true}
+}
+
+// ConsumeTraces processes the incoming ptrace.Traces.
+func (p tracesProcessor) ConsumeTraces(ctx context.Context, td ptrace.Traces) error {
+	resSpans := td.ResourceSpans()
+	for i := 0; i < resSpans.Len(); i++ {
+		resSpan := resSpans.At(i)
+		resourceAttrs := resSpan.Resource().Attributes()
+		copies := p.transposer.copies(resourceAttrs)
+		if len(copies) == 0 {
+			continue
+		}
+
+		scopeSpans := resSpan.ScopeSpans()
+		for j := 0; j < scopeSpans.Len(); j++ {
+			spans := scopeSpans.At(j).Spans()
+			for k := 0; k < spans.Len(); k++ {
+				apply(copies, spans.At(k).Attributes())
+			}
+		}
+
+		removeMoved(copies, resourceAttrs)
+	}
+
+	return p.consumer.ConsumeTraces(ctx, td)
+}
+
+// Shutdown stops the processor. It's a noop.
+func (tracesProcessor) Shutdown(_ context.Context) error {
+	return nil
+}
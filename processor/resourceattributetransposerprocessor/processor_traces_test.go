@@ -0,0 +1,208 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resourceattributetransposerprocessor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.uber.org/zap"
+)
+
+func TestTracesProcessorStart(t *testing.T) {
+	p := newTracesProcessor(
+		zap.NewNop(),
+		consumertest.NewNop(),
+		createDefaultConfig().(*Config),
+	)
+
+	err := p.Start(context.Background(), componenttest.NewNopHost())
+	require.NoError(t, err)
+}
+
+func TestTracesProcessorShutdown(t *testing.T) {
+	p := newTracesProcessor(
+		zap.NewNop(),
+		consumertest.NewNop(),
+		createDefaultConfig().(*Config),
+	)
+
+	err := p.Shutdown(context.Background())
+	require.NoError(t, err)
+}
+
+func TestTracesProcessorCapabilities(t *testing.T) {
+	p := newTracesProcessor(
+		zap.NewNop(),
+		consumertest.NewNop(),
+		createDefaultConfig().(*Config),
+	)
+	capabilities := p.Capabilities()
+	require.True(t, capabilities.MutatesData)
+}
+
+func TestConsumeTraces(t *testing.T) {
+	testCases := []struct {
+		name               string
+		operations         []CopyResourceConfig
+		expectedAttrs      map[string]any
+		expectedResource   map[string]any
+		expectedOtherAttrs map[string]any
+	}{
+		{
+			name: "exact",
+			operations: []CopyResourceConfig{
+				{From: "host.name", To: "host"},
+				{From: "missing", To: "missing"},
+			},
+			expectedAttrs: map[string]any{
+				"host":          "server",
+				"k8s.pod.name":  "span-pod",
+				"k8s.namespace": "span-namespace",
+			},
+			expectedOtherAttrs: map[string]any{
+				"host": "server",
+			},
+		},
+		{
+			name: "regex",
+			operations: []CopyResourceConfig{
+				{FromRegex: `^k8s\.(.*)$`, To: "kubernetes.$1"},
+			},
+			expectedAttrs: map[string]any{
+				"k8s.pod.name":         "span-pod",
+				"k8s.namespace":        "span-namespace",
+				"kubernetes.pod.name":  "pod",
+				"kubernetes.namespace": "namespace",
+			},
+			expectedOtherAttrs: map[string]any{
+				"kubernetes.pod.name":  "pod",
+				"kubernetes.namespace": "namespace",
+			},
+		},
+		{
+			name: "prefix keeping names",
+			operations: []CopyResourceConfig{
+				{FromPrefix: "k8s.", OnConflict: conflictOverwrite},
+			},
+			expectedAttrs: map[string]any{
+				"k8s.pod.name":  "pod",
+				"k8s.namespace": "namespace",
+			},
+			expectedOtherAttrs: map[string]any{
+				"k8s.pod.name":  "pod",
+				"k8s.namespace": "namespace",
+			},
+		},
+		{
+			name: "prefix renaming",
+			operations: []CopyResourceConfig{
+				{FromPrefix: "k8s.", To: "resource.k8s."},
+			},
+			expectedAttrs: map[string]any{
+				"k8s.pod.name":           "span-pod",
+				"k8s.namespace":          "span-namespace",
+				"resource.k8s.pod.name":  "pod",
+				"resource.k8s.namespace": "namespace",
+			},
+			expectedOtherAttrs: map[string]any{
+				"resource.k8s.pod.name":  "pod",
+				"resource.k8s.namespace": "namespace",
+			},
+		},
+		{
+			name: "suffix on conflict",
+			operations: []CopyResourceConfig{
+				{From: "k8s.pod.name", To: "k8s.pod.name", OnConflict: conflictSuffix},
+				{From: "k8s.namespace", To: "k8s.namespace", OnConflict: conflictSuffix, ConflictSuffix: ".resource"},
+				{From: "host.name", To: "host", OnConflict: conflictSuffix},
+			},
+			expectedAttrs: map[string]any{
+				"host":                   "server",
+				"k8s.pod.name":           "span-pod",
+				"k8s.pod.name_resource":  "pod",
+				"k8s.namespace":          "span-namespace",
+				"k8s.namespace.resource": "namespace",
+			},
+			expectedOtherAttrs: map[string]any{
+				"host":          "server",
+				"k8s.pod.name":  "pod",
+				"k8s.namespace": "namespace",
+			},
+		},
+		{
+			name: "move",
+			operations: []CopyResourceConfig{
+				{FromPrefix: "k8s.", To: "kubernetes.", Move: true},
+			},
+			expectedAttrs: map[string]any{
+				"k8s.pod.name":         "span-pod",
+				"k8s.namespace":        "span-namespace",
+				"kubernetes.pod.name":  "pod",
+				"kubernetes.namespace": "namespace",
+			},
+			expectedOtherAttrs: map[string]any{
+				"kubernetes.pod.name":  "pod",
+				"kubernetes.namespace": "namespace",
+			},
+			expectedResource: map[string]any{
+				"host.name": "server",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			traces := ptrace.NewTraces()
+			resourceSpans := traces.ResourceSpans().AppendEmpty()
+			resourceSpans.Resource().Attributes().PutStr("host.name", "server")
+			resourceSpans.Resource().Attributes().PutStr("k8s.pod.name", "pod")
+			resourceSpans.Resource().Attributes().PutStr("k8s.namespace", "namespace")
+
+			spans := resourceSpans.ScopeSpans().AppendEmpty().Spans()
+			span := spans.AppendEmpty()
+			span.Attributes().PutStr("k8s.pod.name", "span-pod")
+			span.Attributes().PutStr("k8s.namespace", "span-namespace")
+			spans.AppendEmpty()
+
+			sink := &consumertest.TracesSink{}
+			p := newTracesProcessor(zap.NewNop(), sink, &Config{Operations: tc.operations})
+
+			err := p.ConsumeTraces(context.Background(), traces)
+			require.NoError(t, err)
+
+			tracesOut := sink.AllTraces()[0]
+			spansOut := tracesOut.ResourceSpans().At(0).ScopeSpans().At(0).Spans()
+			require.Equal(t, tc.expectedAttrs, spansOut.At(0).Attributes().AsRaw())
+
+			// The span without attributes has no conflicts
+			require.Equal(t, tc.expectedOtherAttrs, spansOut.At(1).Attributes().AsRaw())
+
+			expectedResource := tc.expectedResource
+			if expectedResource == nil {
+				expectedResource = map[string]any{
+					"host.name":     "server",
+					"k8s.pod.name":  "pod",
+					"k8s.namespace": "namespace",
+				}
+			}
+			require.Equal(t, expectedResource, tracesOut.ResourceSpans().At(0).Resource().Attributes().AsRaw())
+		})
+	}
+}
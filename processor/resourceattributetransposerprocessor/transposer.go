@@ -0,0 +1,156 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resourceattributetransposerprocessor
+
+import (
+	"regexp"
+	"strings"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.uber.org/zap"
+)
+
+// transposer copies resource attributes to the attributes of the telemetry of the resource.
+type transposer struct {
+	operations []operation
+}
+
+// operation is a copy operation with its matcher compiled.
+type operation struct {
+	config CopyResourceConfig
+	regex  *regexp.Regexp
+}
+
+// attributeCopy is a resource attribute to copy to the attributes of telemetry.
+type attributeCopy struct {
+	from  string
+	to    string
+	value pcommon.Value
+	op    *operation
+}
+
+// newTransposer returns a transposer for the operations of the config.
+// Operations with an invalid regex are logged and skipped, as they are rejected when the config is validated.
+func newTransposer(logger *zap.Logger, config *Config) *transposer {
+	operations := make([]operation, 0, len(config.Operations))
+	for _, opConfig := range config.Operations {
+		op := operation{config: opConfig}
+		if opConfig.FromRegex != "" {
+			regex, err := regexp.Compile(opConfig.FromRegex)
+			if err != nil {
+				logger.Error("Skipping operation with invalid from_regex", zap.String("from_regex", opConfig.FromRegex), zap.Error(err))
+				continue
+			}
+			op.regex = regex
+		}
+
+		operations = append(operations, op)
+	}
+
+	return &transposer{operations: operations}
+}
+
+// copies returns the resource attributes to copy, in the order of the operations.
+func (t *transposer) copies(resourceAttrs pcommon.Map) []attributeCopy {
+	var copies []attributeCopy
+	for i := range t.operations {
+		op := &t.operations[i]
+		if op.config.From != "" {
+			if value, ok := resourceAttrs.Get(op.config.From); ok {
+				copies = append(copies, attributeCopy{from: op.config.From, to: op.config.To, value: value, op: op})
+			}
+			continue
+		}
+
+		resourceAttrs.Range(func(key string, value pcommon.Value) bool {
+			if to, ok := op.target(key); ok {
+				copies = append(copies, attributeCopy{from: key, to: to, value: value, op: op})
+			}
+			return true
+		})
+	}
+
+	return copies
+}
+
+// target returns the attribute the resource attribute is copied to, if the resource attribute matches the operation.
+// If To is empty, the resource attribute is copied to an attribute with the same key.
+func (o *operation) target(key string) (string, bool) {
+	if o.regex != nil {
+		match := o.regex.FindStringSubmatchIndex(key)
+		if match == nil {
+			return "", false
+		}
+
+		if o.config.To == "" {
+			return key, true
+		}
+		return string(o.regex.ExpandString(nil, o.config.To, key, match)), true
+	}
+
+	suffix, ok := strings.CutPrefix(key, o.config.FromPrefix)
+	if !ok {
+		return "", false
+	}
+
+	if o.config.To == "" {
+		return key, true
+	}
+	return o.config.To + suffix, true
+}
+
+// apply copies the resource attributes to the attributes, according to the conflict policy of their operation.
+func apply(copies []attributeCopy, attrs pcommon.Map) {
+	for _, c := range copies {
+		c.copyTo(attrs)
+	}
+}
+
+// copyTo copies the resource attribute to the attributes, according to the conflict policy of its operation.
+func (c attributeCopy) copyTo(attrs pcommon.Map) {
+	key := c.to
+	if _, exists := attrs.Get(key); exists {
+		switch c.op.config.OnConflict {
+		case conflictOverwrite:
+		case conflictSuffix:
+			key += c.op.conflictSuffix()
+			if _, exists := attrs.Get(key); exists {
+				return
+			}
+		default:
+			return
+		}
+	}
+
+	c.value.CopyTo(attrs.PutEmpty(key))
+}
+
+// conflictSuffix returns the suffix used by the suffix conflict policy.
+func (o *operation) conflictSuffix() string {
+	if o.config.ConflictSuffix == "" {
+		return defaultConflictSuffix
+	}
+	return o.config.ConflictSuffix
+}
+
+// removeMoved removes the resource attributes that were copied by an operation with move enabled.
+// It must be called after the attributes have been copied to all telemetry of the resource.
+func removeMoved(copies []attributeCopy, resourceAttrs pcommon.Map) {
+	for _, c := range copies {
+		if c.op.config.Move {
+			resourceAttrs.Remove(c.from)
+		}
+	}
+}
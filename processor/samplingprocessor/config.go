@@ -17,14 +17,64 @@ package samplingprocessor
 
 import (
 	"errors"
+	"fmt"
 )
 
-var errInvalidDropRatio = errors.New("drop_ratio must be between 0.0 and 1.0")
+// Sampling modes
+const (
+	// modeRandom drops each item independently at random.
+	modeRandom = "random"
+
+	// modeHash drops items based on a hash of their key, so items with the same key are kept or dropped together.
+	modeHash = "hash"
+
+	// modeRateLimit keeps at most max_per_second items per second for each key.
+	modeRateLimit = "rate_limit"
+)
+
+var (
+	errInvalidDropRatio    = errors.New("drop_ratio must be between 0.0 and 1.0")
+	errInvalidMaxPerSecond = errors.New("max_per_second must be greater than 0 when mode is rate_limit")
+	errConditionsRateLimit = errors.New("conditions cannot be used when mode is rate_limit")
+	errAdjustedRateLimit   = errors.New("adjusted_count_attribute cannot be used when mode is rate_limit")
+	errAnnotateMetrics     = errors.New("adjusted_count_attribute must be set when annotate_metrics is enabled")
+)
 
 // Config is the configuration for the processor
 type Config struct {
 	// DropRatio is the ratio of payloads that are dropped. Values between 0.0 and 1.0 are valid.
 	DropRatio float64 `mapstructure:"drop_ratio"`
+
+	// Mode is the sampling mode. Valid values are random, hash, and rate_limit.
+	Mode string `mapstructure:"mode"`
+
+	// KeyAttribute is the attribute used as the key of an item in hash and rate_limit modes.
+	// If empty, hash mode uses the trace ID of spans and logs and the name of metrics.
+	KeyAttribute string `mapstructure:"key_attribute"`
+
+	// Conditions are OTTL conditions that choose the drop ratio of matching items.
+	// The drop ratio of the first matching condition is used, otherwise DropRatio is used.
+	Conditions []Condition `mapstructure:"conditions"`
+
+	// MaxPerSecond is the maximum number of items kept per second for each key in rate_limit mode.
+	MaxPerSecond float64 `mapstructure:"max_per_second"`
+
+	// AdjustedCountAttribute is the attribute set on kept items to the number of items each represents.
+	// If empty, kept items are not annotated.
+	AdjustedCountAttribute string `mapstructure:"adjusted_count_attribute"`
+
+	// AnnotateMetrics enables annotating kept metric data points with the adjusted count attribute.
+	// The attribute changes the identity of each data point's time series, so metrics are not annotated by default.
+	AnnotateMetrics bool `mapstructure:"annotate_metrics"`
+}
+
+// Condition is an OTTL condition and the drop ratio of items matching it.
+type Condition struct {
+	// Condition is the OTTL condition items must match.
+	Condition string `mapstructure:"condition"`
+
+	// DropRatio is the ratio of matching items that are dropped. Values between 0.0 and 1.0 are valid.
+	DropRatio float64 `mapstructure:"drop_ratio"`
 }
 
 // Validate validates the processor configuration
@@ -34,5 +84,37 @@ func (cfg Config) Validate() error {
 		return errInvalidDropRatio
 	}
 
+	switch cfg.Mode {
+	case "", modeRandom, modeHash:
+	case modeRateLimit:
+		if cfg.MaxPerSecond <= 0 {
+			return errInvalidMaxPerSecond
+		}
+
+		if len(cfg.Conditions) > 0 {
+			return errConditionsRateLimit
+		}
+
+		if cfg.AdjustedCountAttribute != "" {
+			return errAdjustedRateLimit
+		}
+	default:
+		return fmt.Errorf("invalid mode '%s': must be one of %s, %s, or %s", cfg.Mode, modeRandom, modeHash, modeRateLimit)
+	}
+
+	if cfg.AnnotateMetrics && cfg.AdjustedCountAttribute == "" {
+		return errAnnotateMetrics
+	}
+
+	for i, condition := range cfg.Conditions {
+		if condition.Condition == "" {
+			return fmt.Errorf("conditions[%d]: condition must be set", i)
+		}
+
+		if condition.DropRatio < 0.0 || condition.DropRatio > 1.0 {
+			return fmt.Errorf("conditions[%d]: %w", i, errInvalidDropRatio)
+		}
+	}
+
 	return nil
 }
@@ -15,6 +15,8 @@
 package samplingprocessor
 
 import (
+	"errors"
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -47,6 +49,86 @@ func TestConfigValidate(t *testing.T) {
 			},
 			expectedErr: nil,
 		},
+		{
+			desc: "Valid hash mode with conditions",
+			cfg: Config{
+				DropRatio:    0.5,
+				Mode:         modeHash,
+				KeyAttribute: "request.id",
+				Conditions: []Condition{
+					{Condition: `severity_text == "ERROR"`, DropRatio: 0.0},
+				},
+			},
+			expectedErr: nil,
+		},
+		{
+			desc: "Valid rate limit mode",
+			cfg: Config{
+				Mode:         modeRateLimit,
+				MaxPerSecond: 10,
+			},
+			expectedErr: nil,
+		},
+		{
+			desc: "Invalid mode",
+			cfg: Config{
+				Mode: "tail",
+			},
+			expectedErr: errors.New("invalid mode 'tail': must be one of random, hash, or rate_limit"),
+		},
+		{
+			desc: "Rate limit without max per second",
+			cfg: Config{
+				Mode: modeRateLimit,
+			},
+			expectedErr: errInvalidMaxPerSecond,
+		},
+		{
+			desc: "Rate limit with conditions",
+			cfg: Config{
+				Mode:         modeRateLimit,
+				MaxPerSecond: 10,
+				Conditions: []Condition{
+					{Condition: "true"},
+				},
+			},
+			expectedErr: errConditionsRateLimit,
+		},
+		{
+			desc: "Annotate metrics without adjusted count",
+			cfg: Config{
+				DropRatio:       0.5,
+				AnnotateMetrics: true,
+			},
+			expectedErr: errAnnotateMetrics,
+		},
+		{
+			desc: "Rate limit with adjusted count",
+			cfg: Config{
+				Mode:                   modeRateLimit,
+				MaxPerSecond:           10,
+				AdjustedCountAttribute: "sampling.adjusted_count",
+			},
+			expectedErr: errAdjustedRateLimit,
+		},
+		{
+			desc: "Empty condition",
+			cfg: Config{
+				Conditions: []Condition{
+					{DropRatio: 0.5},
+				},
+			},
+			expectedErr: errors.New("conditions[0]: condition must be set"),
+		},
+		{
+			desc: "Bad condition drop ratio",
+			cfg: Config{
+				Conditions: []Condition{
+					{Condition: "true", DropRatio: -1.0},
+				},
+			},
+			expectedErr: fmt.Errorf("conditions[0]: %w", errInvalidDropRatio),
+		},
 	}
 
 	for _, tc := range testCases {
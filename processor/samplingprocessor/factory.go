@@ -16,8 +16,10 @@ package samplingprocessor
 
 import (
 	"context"
+	"fmt"
 	"sync"
 
+	"github.com/observiq/bindplane-agent/expr"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/processor"
@@ -50,6 +52,7 @@ func NewFactory() processor.Factory {
 func createDefaultConfig() component.Config {
 	return &Config{
 		DropRatio: 0.5,
+		Mode:      modeRandom,
 	}
 }
 
@@ -62,6 +65,12 @@ func createTracesProcessor(
 	oCfg := cfg.(*Config)
 	sp := newSamplingProcessor(set.Logger, oCfg)
 
+	conditions, err := newRatioConditions(oCfg.Conditions, set.TelemetrySettings, expr.NewOTTLSpanCondition)
+	if err != nil {
+		return nil, fmt.Errorf("invalid condition: %w", err)
+	}
+	sp.spanConditions = conditions
+
 	return processorhelper.NewTracesProcessor(ctx, set, cfg, nextConsumer, sp.processTraces, processorhelper.WithCapabilities(consumerCapabilities))
 }
 
@@ -74,6 +83,12 @@ func createLogsProcessor(
 	oCfg := cfg.(*Config)
 	tmp := newSamplingProcessor(set.Logger, oCfg)
 
+	conditions, err := newRatioConditions(oCfg.Conditions, set.TelemetrySettings, expr.NewOTTLLogRecordCondition)
+	if err != nil {
+		return nil, fmt.Errorf("invalid condition: %w", err)
+	}
+	tmp.logConditions = conditions
+
 	return processorhelper.NewLogsProcessor(ctx, set, cfg, nextConsumer, tmp.processLogs, processorhelper.WithCapabilities(consumerCapabilities))
 }
 
@@ -86,5 +101,11 @@ func createMetricsProcessor(
 	oCfg := cfg.(*Config)
 	tmp := newSamplingProcessor(set.Logger, oCfg)
 
+	conditions, err := newRatioConditions(oCfg.Conditions, set.TelemetrySettings, expr.NewOTTLDatapointCondition)
+	if err != nil {
+		return nil, fmt.Errorf("invalid condition: %w", err)
+	}
+	tmp.datapointConditions = conditions
+
 	return processorhelper.NewMetricsProcessor(ctx, set, cfg, nextConsumer, tmp.processMetrics, processorhelper.WithCapabilities(consumerCapabilities))
 }
@@ -16,8 +16,16 @@ package samplingprocessor
 
 import (
 	"context"
+	"hash/fnv"
 	"math/rand"
+	"time"
 
+	"github.com/observiq/bindplane-agent/expr"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottldatapoint"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottllog"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottlspan"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/plog"
 	"go.opentelemetry.io/collector/pdata/pmetric"
 	"go.opentelemetry.io/collector/pdata/ptrace"
@@ -27,70 +35,346 @@ import (
 type samplingProcessor struct {
 	logger          *zap.Logger
 	dropCutOffRatio float64
+	mode            string
+	keyAttribute    string
+	adjustedCount   string
+	annotateMetrics bool
+	limiter         *rateLimiter
+
+	spanConditions      []ratioCondition[ottlspan.TransformContext]
+	logConditions       []ratioCondition[ottllog.TransformContext]
+	datapointConditions []ratioCondition[ottldatapoint.TransformContext]
+}
+
+// ratioCondition is a compiled OTTL condition and the drop ratio of items matching it.
+type ratioCondition[T any] struct {
+	condition *expr.OTTLCondition[T]
+	dropRatio float64
 }
 
 func newSamplingProcessor(logger *zap.Logger, cfg *Config) *samplingProcessor {
+	mode := cfg.Mode
+	if mode == "" {
+		mode = modeRandom
+	}
+
+	var limiter *rateLimiter
+	if mode == modeRateLimit {
+		limiter = newRateLimiter(cfg.MaxPerSecond)
+	}
+
 	return &samplingProcessor{
 		logger:          logger,
 		dropCutOffRatio: cfg.DropRatio,
+		mode:            mode,
+		keyAttribute:    cfg.KeyAttribute,
+		adjustedCount:   cfg.AdjustedCountAttribute,
+		annotateMetrics: cfg.AnnotateMetrics,
+		limiter:         limiter,
+	}
+}
+
+// newRatioConditions compiles the conditions using the create function.
+func newRatioConditions[T any](conditions []Condition, set component.TelemetrySettings, createFunc func(string, component.TelemetrySettings) (*expr.OTTLCondition[T], error)) ([]ratioCondition[T], error) {
+	ratioConditions := make([]ratioCondition[T], 0, len(conditions))
+	for _, c := range conditions {
+		condition, err := createFunc(c.Condition, set)
+		if err != nil {
+			return nil, err
+		}
+
+		ratioConditions = append(ratioConditions, ratioCondition[T]{
+			condition: condition,
+			dropRatio: c.DropRatio,
+		})
+	}
+
+	return ratioConditions, nil
+}
+
+// matchRatio returns the drop ratio of the first condition matching the context, if any.
+func matchRatio[T any](ctx context.Context, logger *zap.Logger, conditions []ratioCondition[T], tCtx T) (float64, bool) {
+	for _, c := range conditions {
+		match, err := c.condition.Match(ctx, tCtx)
+		if err != nil {
+			logger.Error("Error while matching condition", zap.Error(err))
+			continue
+		}
+
+		if match {
+			return c.dropRatio, true
+		}
 	}
+
+	return 0, false
+}
+
+// isFixed returns true if every item is kept or every item is dropped, regardless of its contents.
+func (sp *samplingProcessor) isFixed(numConditions int) bool {
+	return sp.mode != modeRateLimit && numConditions == 0 && (sp.dropCutOffRatio == 1.0 || sp.dropCutOffRatio == 0.0)
 }
 
-func (sp *samplingProcessor) sampleFunc() bool {
+// shouldDrop returns true if an item with the key and drop ratio should be dropped.
+func (sp *samplingProcessor) shouldDrop(key string, hasKey bool, dropRatio float64) bool {
+	switch sp.mode {
+	case modeRateLimit:
+		return !sp.limiter.allow(key, time.Now())
+	case modeHash:
+		if hasKey {
+			return hashRatio(key) < dropRatio
+		}
+	}
+
 	//#nosec G404 -- randomly generated number is not used for security purposes. It's ok if it's weak
-	return rand.Float64() <= sp.dropCutOffRatio
-}
-
-func (sp *samplingProcessor) processTraces(_ context.Context, td ptrace.Traces) (ptrace.Traces, error) {
-	switch {
-	case sp.dropCutOffRatio == 1.0: // Drop everything
-		return ptrace.NewTraces(), nil
-	case sp.dropCutOffRatio == 0.0: // Drop nothing
-		return td, nil
-	default: // Drop based on ratio
-		for i := 0; i < td.ResourceSpans().Len(); i++ {
-			for j := 0; j < td.ResourceSpans().At(i).ScopeSpans().Len(); j++ {
-				td.ResourceSpans().At(i).ScopeSpans().At(j).Spans().RemoveIf(func(_ ptrace.Span) bool {
-					return sp.sampleFunc()
-				})
-			}
-		}
-		return td, nil
-	}
-}
-
-func (sp *samplingProcessor) processLogs(_ context.Context, ld plog.Logs) (plog.Logs, error) {
-	switch {
-	case sp.dropCutOffRatio == 1.0: // Drop everything
-		return plog.NewLogs(), nil
-	case sp.dropCutOffRatio == 0.0: // Drop nothing
-		return ld, nil
-	default: // Drop based on ratio
-		for i := 0; i < ld.ResourceLogs().Len(); i++ {
-			for j := 0; j < ld.ResourceLogs().At(i).ScopeLogs().Len(); j++ {
-				ld.ResourceLogs().At(i).ScopeLogs().At(j).LogRecords().RemoveIf(func(_ plog.LogRecord) bool {
-					return sp.sampleFunc()
-				})
-			}
-		}
-		return ld, nil
-	}
-}
-
-func (sp *samplingProcessor) processMetrics(_ context.Context, md pmetric.Metrics) (pmetric.Metrics, error) {
-	switch {
-	case sp.dropCutOffRatio == 1.0: // Drop everything
-		return pmetric.NewMetrics(), nil
-	case sp.dropCutOffRatio == 0.0: // Drop nothing
-		return md, nil
-	default: // Drop based on ratio
-		for i := 0; i < md.ResourceMetrics().Len(); i++ {
-			for j := 0; j < md.ResourceMetrics().At(i).ScopeMetrics().Len(); j++ {
-				md.ResourceMetrics().At(i).ScopeMetrics().At(j).Metrics().RemoveIf(func(_ pmetric.Metric) bool {
-					return sp.sampleFunc()
-				})
-			}
-		}
-		return md, nil
+	return rand.Float64() < dropRatio
+}
+
+// annotate multiplies the adjusted count attribute of a kept item by the number of items it represents.
+func (sp *samplingProcessor) annotate(attrs pcommon.Map, dropRatio float64) {
+	if sp.adjustedCount == "" || dropRatio == 0.0 {
+		return
 	}
+
+	adjustedCount := 1 / (1 - dropRatio)
+
+	// Items sampled more than once represent the product of each adjusted count
+	if value, ok := attrs.Get(sp.adjustedCount); ok {
+		switch value.Type() {
+		case pcommon.ValueTypeDouble:
+			adjustedCount *= value.Double()
+		case pcommon.ValueTypeInt:
+			adjustedCount *= float64(value.Int())
+		}
+	}
+
+	attrs.PutDouble(sp.adjustedCount, adjustedCount)
+}
+
+// attributeKey returns the value of the key attribute from the first map containing it.
+func (sp *samplingProcessor) attributeKey(maps ...pcommon.Map) (string, bool) {
+	for _, m := range maps {
+		if value, ok := m.Get(sp.keyAttribute); ok {
+			return value.AsString(), true
+		}
+	}
+
+	return "", false
+}
+
+// hashRatio maps the key to a number between 0.0 and 1.0 that is always the same for the key.
+func hashRatio(key string) float64 {
+	h := fnv.New64a()
+	// Write to a hash never returns an error
+	_, _ = h.Write([]byte(key))
+	return float64(mix64(h.Sum64())>>11) / (1 << 53)
+}
+
+// mix64 spreads the bits of a hash so similar keys, such as sequential IDs, are evenly distributed.
+// It is the finalizer of MurmurHash3.
+func mix64(h uint64) uint64 {
+	h ^= h >> 33
+	h *= 0xff51afd7ed558ccd
+	h ^= h >> 33
+	h *= 0xc4ceb9fe1a85ec53
+	h ^= h >> 33
+	return h
+}
+
+func (sp *samplingProcessor) processTraces(ctx context.Context, td ptrace.Traces) (ptrace.Traces, error) {
+	if sp.isFixed(len(sp.spanConditions)) {
+		if sp.dropCutOffRatio == 1.0 { // Drop everything
+			return ptrace.NewTraces(), nil
+		}
+		return td, nil // Drop nothing
+	}
+
+	for i := 0; i < td.ResourceSpans().Len(); i++ {
+		resourceSpans := td.ResourceSpans().At(i)
+		resource := resourceSpans.Resource()
+		for j := 0; j < resourceSpans.ScopeSpans().Len(); j++ {
+			scopeSpans := resourceSpans.ScopeSpans().At(j)
+			scopeSpans.Spans().RemoveIf(func(span ptrace.Span) bool {
+				dropRatio, ok := matchRatio(ctx, sp.logger, sp.spanConditions, ottlspan.NewTransformContext(span, scopeSpans.Scope(), resource))
+				if !ok {
+					dropRatio = sp.dropCutOffRatio
+				}
+
+				key, hasKey := sp.spanKey(span, resource)
+				if sp.shouldDrop(key, hasKey, dropRatio) {
+					return true
+				}
+
+				sp.annotate(span.Attributes(), dropRatio)
+				return false
+			})
+		}
+	}
+
+	return td, nil
+}
+
+// spanKey returns the key of the span.
+func (sp *samplingProcessor) spanKey(span ptrace.Span, resource pcommon.Resource) (string, bool) {
+	if sp.keyAttribute != "" {
+		return sp.attributeKey(span.Attributes(), resource.Attributes())
+	}
+
+	if sp.mode == modeRateLimit {
+		return "", true
+	}
+
+	return span.TraceID().String(), !span.TraceID().IsEmpty()
+}
+
+func (sp *samplingProcessor) processLogs(ctx context.Context, ld plog.Logs) (plog.Logs, error) {
+	if sp.isFixed(len(sp.logConditions)) {
+		if sp.dropCutOffRatio == 1.0 { // Drop everything
+			return plog.NewLogs(), nil
+		}
+		return ld, nil // Drop nothing
+	}
+
+	for i := 0; i < ld.ResourceLogs().Len(); i++ {
+		resourceLogs := ld.ResourceLogs().At(i)
+		resource := resourceLogs.Resource()
+		for j := 0; j < resourceLogs.ScopeLogs().Len(); j++ {
+			scopeLogs := resourceLogs.ScopeLogs().At(j)
+			scopeLogs.LogRecords().RemoveIf(func(logRecord plog.LogRecord) bool {
+				dropRatio, ok := matchRatio(ctx, sp.logger, sp.logConditions, ottllog.NewTransformContext(logRecord, scopeLogs.Scope(), resource))
+				if !ok {
+					dropRatio = sp.dropCutOffRatio
+				}
+
+				key, hasKey := sp.logKey(logRecord, resource)
+				if sp.shouldDrop(key, hasKey, dropRatio) {
+					return true
+				}
+
+				sp.annotate(logRecord.Attributes(), dropRatio)
+				return false
+			})
+		}
+	}
+
+	return ld, nil
+}
+
+// logKey returns the key of the log record.
+func (sp *samplingProcessor) logKey(logRecord plog.LogRecord, resource pcommon.Resource) (string, bool) {
+	if sp.keyAttribute != "" {
+		return sp.attributeKey(logRecord.Attributes(), resource.Attributes())
+	}
+
+	if sp.mode == modeRateLimit {
+		return "", true
+	}
+
+	return logRecord.TraceID().String(), !logRecord.TraceID().IsEmpty()
+}
+
+func (sp *samplingProcessor) processMetrics(ctx context.Context, md pmetric.Metrics) (pmetric.Metrics, error) {
+	if sp.isFixed(len(sp.datapointConditions)) {
+		if sp.dropCutOffRatio == 1.0 { // Drop everything
+			return pmetric.NewMetrics(), nil
+		}
+		return md, nil // Drop nothing
+	}
+
+	for i := 0; i < md.ResourceMetrics().Len(); i++ {
+		resourceMetrics := md.ResourceMetrics().At(i)
+		resource := resourceMetrics.Resource()
+		for j := 0; j < resourceMetrics.ScopeMetrics().Len(); j++ {
+			scopeMetrics := resourceMetrics.ScopeMetrics().At(j)
+			scopeMetrics.Metrics().RemoveIf(func(metric pmetric.Metric) bool {
+				dropRatio, ok := sp.metricRatio(ctx, metric, scopeMetrics, resource)
+				if !ok {
+					dropRatio = sp.dropCutOffRatio
+				}
+
+				key, hasKey := sp.metricKey(metric, resource)
+				if sp.shouldDrop(key, hasKey, dropRatio) {
+					return true
+				}
+
+				if sp.annotateMetrics {
+					for _, attrs := range dataPointAttributes(metric) {
+						sp.annotate(attrs, dropRatio)
+					}
+				}
+				return false
+			})
+		}
+	}
+
+	return md, nil
+}
+
+// metricRatio returns the drop ratio of the first condition matching any data point of the metric, if any.
+func (sp *samplingProcessor) metricRatio(ctx context.Context, metric pmetric.Metric, scopeMetrics pmetric.ScopeMetrics, resource pcommon.Resource) (float64, bool) {
+	if len(sp.datapointConditions) == 0 {
+		return 0, false
+	}
+
+	metrics := scopeMetrics.Metrics()
+	for _, dp := range dataPoints(metric) {
+		tCtx := ottldatapoint.NewTransformContext(dp, metric, metrics, scopeMetrics.Scope(), resource)
+		if dropRatio, ok := matchRatio(ctx, sp.logger, sp.datapointConditions, tCtx); ok {
+			return dropRatio, true
+		}
+	}
+
+	return 0, false
+}
+
+// dataPoints returns the data points of the metric.
+func dataPoints(metric pmetric.Metric) []any {
+	var dataPoints []any
+	switch metric.Type() {
+	case pmetric.MetricTypeSum:
+		for i := 0; i < metric.Sum().DataPoints().Len(); i++ {
+			dataPoints = append(dataPoints, metric.Sum().DataPoints().At(i))
+		}
+	case pmetric.MetricTypeGauge:
+		for i := 0; i < metric.Gauge().DataPoints().Len(); i++ {
+			dataPoints = append(dataPoints, metric.Gauge().DataPoints().At(i))
+		}
+	case pmetric.MetricTypeSummary:
+		for i := 0; i < metric.Summary().DataPoints().Len(); i++ {
+			dataPoints = append(dataPoints, metric.Summary().DataPoints().At(i))
+		}
+	case pmetric.MetricTypeHistogram:
+		for i := 0; i < metric.Histogram().DataPoints().Len(); i++ {
+			dataPoints = append(dataPoints, metric.Histogram().DataPoints().At(i))
+		}
+	case pmetric.MetricTypeExponentialHistogram:
+		for i := 0; i < metric.ExponentialHistogram().DataPoints().Len(); i++ {
+			dataPoints = append(dataPoints, metric.ExponentialHistogram().DataPoints().At(i))
+		}
+	}
+
+	return dataPoints
+}
+
+// dataPointAttributes returns the attributes of each data point of the metric.
+func dataPointAttributes(metric pmetric.Metric) []pcommon.Map {
+	dps := dataPoints(metric)
+	attrs := make([]pcommon.Map, 0, len(dps))
+	for _, dp := range dps {
+		attrs = append(attrs, dp.(interface{ Attributes() pcommon.Map }).Attributes())
+	}
+
+	return attrs
+}
+
+// metricKey returns the key of the metric.
+func (sp *samplingProcessor) metricKey(metric pmetric.Metric, resource pcommon.Resource) (string, bool) {
+	if sp.keyAttribute != "" {
+		return sp.attributeKey(resource.Attributes())
+	}
+
+	if sp.mode == modeRateLimit {
+		return "", true
+	}
+
+	return metric.Name(), true
 }
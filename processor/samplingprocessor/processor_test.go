@@ -16,9 +16,13 @@ package samplingprocessor
 
 import (
 	"context"
+	"fmt"
 	"testing"
 
+	"github.com/observiq/bindplane-agent/expr"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/plog"
 	"go.opentelemetry.io/collector/pdata/pmetric"
 	"go.opentelemetry.io/collector/pdata/ptrace"
@@ -140,3 +144,225 @@ func Test_processMetrics(t *testing.T) {
 		})
 	}
 }
+
+func Test_processTracesHash(t *testing.T) {
+	td := ptrace.NewTraces()
+	spans := td.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans()
+	for i := 0; i < 100; i++ {
+		for j := 0; j < 3; j++ {
+			span := spans.AppendEmpty()
+			span.SetTraceID(pcommon.TraceID([16]byte{byte(i), 1}))
+			span.SetSpanID(pcommon.SpanID([8]byte{byte(i), byte(j)}))
+		}
+	}
+
+	cfg := &Config{
+		DropRatio: 0.5,
+		Mode:      modeHash,
+	}
+	processor := newSamplingProcessor(zap.NewNop(), cfg)
+	actual, err := processor.processTraces(context.Background(), td)
+	require.NoError(t, err)
+
+	counts := make(map[pcommon.TraceID]int)
+	actualSpans := actual.ResourceSpans().At(0).ScopeSpans().At(0).Spans()
+	for i := 0; i < actualSpans.Len(); i++ {
+		counts[actualSpans.At(i).TraceID()]++
+	}
+
+	// Each kept trace keeps all of its spans
+	require.NotEmpty(t, counts)
+	require.Less(t, len(counts), 100)
+	for _, count := range counts {
+		require.Equal(t, 3, count)
+	}
+}
+
+func Test_processLogsHashAttribute(t *testing.T) {
+	newLogs := func() plog.Logs {
+		ld := plog.NewLogs()
+		records := ld.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords()
+		for i := 0; i < 50; i++ {
+			records.AppendEmpty().Attributes().PutInt("request.id", int64(i))
+		}
+		return ld
+	}
+
+	cfg := &Config{
+		DropRatio:    0.5,
+		Mode:         modeHash,
+		KeyAttribute: "request.id",
+	}
+	processor := newSamplingProcessor(zap.NewNop(), cfg)
+
+	first, err := processor.processLogs(context.Background(), newLogs())
+	require.NoError(t, err)
+	second, err := processor.processLogs(context.Background(), newLogs())
+	require.NoError(t, err)
+
+	// The same requests are kept every time
+	require.Equal(t, first, second)
+	require.Greater(t, first.LogRecordCount(), 0)
+	require.Less(t, first.LogRecordCount(), 50)
+}
+
+func Test_processLogsConditions(t *testing.T) {
+	ld := plog.NewLogs()
+	records := ld.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords()
+	for i := 0; i < 10; i++ {
+		records.AppendEmpty().SetSeverityText("ERROR")
+		records.AppendEmpty().SetSeverityText("INFO")
+		records.AppendEmpty().SetSeverityText("DEBUG")
+	}
+
+	cfg := &Config{
+		DropRatio: 0.0,
+		Conditions: []Condition{
+			{Condition: `severity_text == "ERROR"`, DropRatio: 0.0},
+			{Condition: `severity_text == "INFO"`, DropRatio: 1.0},
+		},
+	}
+	processor := newSamplingProcessor(zap.NewNop(), cfg)
+	conditions, err := newRatioConditions(cfg.Conditions, componenttest.NewNopTelemetrySettings(), expr.NewOTTLLogRecordCondition)
+	require.NoError(t, err)
+	processor.logConditions = conditions
+
+	actual, err := processor.processLogs(context.Background(), ld)
+	require.NoError(t, err)
+
+	counts := make(map[string]int)
+	actualRecords := actual.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords()
+	for i := 0; i < actualRecords.Len(); i++ {
+		counts[actualRecords.At(i).SeverityText()]++
+	}
+	require.Equal(t, map[string]int{"ERROR": 10, "DEBUG": 10}, counts)
+}
+
+func Test_processMetricsRateLimit(t *testing.T) {
+	md := pmetric.NewMetrics()
+	for _, host := range []string{"a", "b"} {
+		resourceMetrics := md.ResourceMetrics().AppendEmpty()
+		resourceMetrics.Resource().Attributes().PutStr("host.name", host)
+		metrics := resourceMetrics.ScopeMetrics().AppendEmpty().Metrics()
+		for i := 0; i < 5; i++ {
+			metrics.AppendEmpty().SetEmptyGauge().DataPoints().AppendEmpty()
+		}
+	}
+
+	cfg := &Config{
+		Mode:         modeRateLimit,
+		KeyAttribute: "host.name",
+		MaxPerSecond: 2,
+	}
+	processor := newSamplingProcessor(zap.NewNop(), cfg)
+	actual, err := processor.processMetrics(context.Background(), md)
+	require.NoError(t, err)
+
+	require.Equal(t, 2, actual.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().Len())
+	require.Equal(t, 2, actual.ResourceMetrics().At(1).ScopeMetrics().At(0).Metrics().Len())
+}
+
+func Test_processLogsAdjustedCount(t *testing.T) {
+	ld := plog.NewLogs()
+	records := ld.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords()
+	for i := 0; i < 100; i++ {
+		records.AppendEmpty().SetSeverityText("INFO")
+	}
+	errorRecord := records.AppendEmpty()
+	errorRecord.SetSeverityText("ERROR")
+	// Previously sampled logs keep the product of both adjusted counts
+	records.At(0).Attributes().PutDouble("sampling.adjusted_count", 2)
+
+	cfg := &Config{
+		DropRatio:              0.75,
+		Mode:                   modeHash,
+		KeyAttribute:           "missing",
+		AdjustedCountAttribute: "sampling.adjusted_count",
+		Conditions: []Condition{
+			{Condition: `severity_text == "ERROR"`, DropRatio: 0.0},
+		},
+	}
+	processor := newSamplingProcessor(zap.NewNop(), cfg)
+	conditions, err := newRatioConditions(cfg.Conditions, componenttest.NewNopTelemetrySettings(), expr.NewOTTLLogRecordCondition)
+	require.NoError(t, err)
+	processor.logConditions = conditions
+
+	actual, err := processor.processLogs(context.Background(), ld)
+	require.NoError(t, err)
+
+	actualRecords := actual.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords()
+	for i := 0; i < actualRecords.Len(); i++ {
+		record := actualRecords.At(i)
+		adjustedCount, ok := record.Attributes().Get("sampling.adjusted_count")
+		switch {
+		case record.SeverityText() == "ERROR":
+			require.False(t, ok)
+		case adjustedCount.Double() == 8.0:
+			// The previously sampled log
+		default:
+			require.True(t, ok)
+			require.Equal(t, 4.0, adjustedCount.Double())
+		}
+	}
+}
+
+func Test_processMetricsAdjustedCount(t *testing.T) {
+	md := pmetric.NewMetrics()
+	metric := md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+	metric.SetName("requests")
+	metric.SetEmptySum().DataPoints().AppendEmpty()
+	metric.Sum().DataPoints().AppendEmpty()
+
+	cfg := &Config{
+		DropRatio:              0.5,
+		Mode:                   modeHash,
+		AdjustedCountAttribute: "sampling.adjusted_count",
+		AnnotateMetrics:        true,
+	}
+	processor := newSamplingProcessor(zap.NewNop(), cfg)
+	require.GreaterOrEqual(t, hashRatio("requests"), 0.5, "test metric name must be kept")
+
+	actual, err := processor.processMetrics(context.Background(), md)
+	require.NoError(t, err)
+
+	dps := actual.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0).Sum().DataPoints()
+	require.Equal(t, 2, dps.Len())
+	for i := 0; i < dps.Len(); i++ {
+		adjustedCount, ok := dps.At(i).Attributes().Get("sampling.adjusted_count")
+		require.True(t, ok)
+		require.Equal(t, 2.0, adjustedCount.Double())
+	}
+}
+
+func Test_processMetricsNotAnnotatedByDefault(t *testing.T) {
+	md := pmetric.NewMetrics()
+	metric := md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+	metric.SetName("requests")
+	metric.SetEmptySum().DataPoints().AppendEmpty()
+
+	cfg := &Config{
+		DropRatio:              0.5,
+		Mode:                   modeHash,
+		AdjustedCountAttribute: "sampling.adjusted_count",
+	}
+	processor := newSamplingProcessor(zap.NewNop(), cfg)
+
+	actual, err := processor.processMetrics(context.Background(), md)
+	require.NoError(t, err)
+
+	dps := actual.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0).Sum().DataPoints()
+	require.Equal(t, 1, dps.Len())
+	require.Equal(t, 0, dps.At(0).Attributes().Len())
+}
+
+func Test_hashRatioDistribution(t *testing.T) {
+	dropped := 0
+	for i := 0; i < 10000; i++ {
+		if hashRatio(fmt.Sprintf("host%d", i)) < 0.5 {
+			dropped++
+		}
+	}
+
+	// Sequential keys are dropped close to the configured ratio
+	require.InDelta(t, 5000, dropped, 250)
+}
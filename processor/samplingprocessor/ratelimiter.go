@@ -0,0 +1,87 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package samplingprocessor
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// sweepInterval is how often full buckets are removed from the rate limiter.
+const sweepInterval = time.Minute
+
+// rateLimiter is a token bucket rate limiter with a bucket for each key.
+type rateLimiter struct {
+	rate      float64
+	capacity  float64
+	mux       sync.Mutex
+	buckets   map[string]*tokenBucket
+	lastSweep time.Time
+}
+
+// tokenBucket holds the tokens available to a key.
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// newRateLimiter creates a rate limiter allowing rate items per second for each key.
+func newRateLimiter(rate float64) *rateLimiter {
+	return &rateLimiter{
+		rate: rate,
+		// Allow at least one item so rates below one per second can be met
+		capacity: math.Max(rate, 1),
+		buckets:  make(map[string]*tokenBucket),
+	}
+}
+
+// allow returns true if an item with the key is within the rate limit, taking a token from its bucket.
+func (r *rateLimiter) allow(key string, now time.Time) bool {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	if now.Sub(r.lastSweep) >= sweepInterval {
+		r.sweep(now)
+	}
+
+	bucket, ok := r.buckets[key]
+	if !ok {
+		bucket = &tokenBucket{tokens: r.capacity, last: now}
+		r.buckets[key] = bucket
+	}
+
+	bucket.tokens = math.Min(r.capacity, bucket.tokens+now.Sub(bucket.last).Seconds()*r.rate)
+	bucket.last = now
+
+	if bucket.tokens < 1 {
+		return false
+	}
+
+	bucket.tokens--
+	return true
+}
+
+// sweep removes buckets that have refilled, since they are the same as new buckets.
+func (r *rateLimiter) sweep(now time.Time) {
+	refill := time.Duration(r.capacity / r.rate * float64(time.Second))
+	for key, bucket := range r.buckets {
+		if now.Sub(bucket.last) >= refill {
+			delete(r.buckets, key)
+		}
+	}
+
+	r.lastSweep = now
+}
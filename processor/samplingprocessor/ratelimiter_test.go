@@ -0,0 +1,59 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package samplingprocessor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimiterAllow(t *testing.T) {
+	limiter := newRateLimiter(2)
+	now := time.Now()
+
+	require.True(t, limiter.allow("a", now))
+	require.True(t, limiter.allow("a", now))
+	require.False(t, limiter.allow("a", now))
+	require.True(t, limiter.allow("b", now))
+
+	// Half a second refills one token
+	now = now.Add(500 * time.Millisecond)
+	require.True(t, limiter.allow("a", now))
+	require.False(t, limiter.allow("a", now))
+}
+
+func TestRateLimiterSlowRate(t *testing.T) {
+	limiter := newRateLimiter(0.5)
+	now := time.Now()
+
+	require.True(t, limiter.allow("a", now))
+	require.False(t, limiter.allow("a", now.Add(time.Second)))
+	require.True(t, limiter.allow("a", now.Add(2*time.Second)))
+}
+
+func TestRateLimiterSweep(t *testing.T) {
+	limiter := newRateLimiter(1)
+	now := time.Now()
+
+	require.True(t, limiter.allow("a", now))
+	require.Len(t, limiter.buckets, 1)
+
+	now = now.Add(sweepInterval)
+	require.True(t, limiter.allow("b", now))
+	require.Len(t, limiter.buckets, 1)
+	require.Contains(t, limiter.buckets, "b")
+}
@@ -19,6 +19,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/observiq/bindplane-agent/counter"
 	"go.opentelemetry.io/collector/component"
 )
 
@@ -49,6 +50,13 @@ type Config struct {
 	OTTLMatch      *string           `mapstructure:"ottl_match"`
 	Attributes     map[string]string `mapstructure:"attributes"`
 	OTTLAttributes map[string]string `mapstructure:"ottl_attributes"`
+
+	// AdjustedCountAttribute is the attribute holding the adjusted count of sampled telemetry.
+	// If set, the adjusted counts of matching telemetry are summed instead of counting each as 1.
+	AdjustedCountAttribute string `mapstructure:"adjusted_count_attribute"`
+
+	// Exemplars configures the exemplars sampled from counted spans and attached to the count metric.
+	Exemplars counter.ExemplarConfig `mapstructure:"exemplars"`
 }
 
 // Validate validates the config, returning an error if the config is invalid
@@ -69,6 +77,14 @@ func (c Config) Validate() error {
 		return fmt.Errorf("cannot use ottl_match with attributes")
 	}
 
+	if err := c.Exemplars.Validate(); err != nil {
+		return err
+	}
+
+	if c.Exemplars.Enabled && !c.isOTTL() {
+		return fmt.Errorf("exemplars are only supported with ottl_match and ottl_attributes")
+	}
+
 	return nil
 }
 
@@ -98,5 +114,8 @@ func createDefaultConfig() component.Config {
 		MetricName: defaultMetricName,
 		MetricUnit: defaultMetricUnit,
 		Interval:   defaultInterval,
+		Exemplars: counter.ExemplarConfig{
+			MaxPerDatapoint: counter.DefaultMaxExemplars,
+		},
 	}
 }
@@ -17,6 +17,7 @@ package spancountprocessor
 import (
 	"testing"
 
+	"github.com/observiq/bindplane-agent/counter"
 	"github.com/stretchr/testify/require"
 )
 
@@ -25,6 +26,7 @@ func TestCreateDefaultProcessorConfig(t *testing.T) {
 	require.Equal(t, defaultInterval, cfg.Interval)
 	require.Equal(t, defaultMetricName, cfg.MetricName)
 	require.Equal(t, defaultMetricUnit, cfg.MetricUnit)
+	require.Equal(t, counter.DefaultMaxExemplars, cfg.Exemplars.MaxPerDatapoint)
 }
 
 func TestConfig_Validate(t *testing.T) {
@@ -70,6 +72,21 @@ func TestConfig_Validate(t *testing.T) {
 			},
 			err: "cannot use ottl_match with attributes",
 		},
+		{
+			name: "exemplars without max_per_datapoint",
+			config: &Config{
+				Exemplars: counter.ExemplarConfig{Enabled: true},
+			},
+			err: "exemplars.max_per_datapoint must be positive",
+		},
+		{
+			name: "exemplars with expr",
+			config: &Config{
+				Match:     strp("true"),
+				Exemplars: counter.ExemplarConfig{Enabled: true, MaxPerDatapoint: 1},
+			},
+			err: "exemplars are only supported with ottl_match and ottl_attributes",
+		},
 	}
 
 	for _, tc := range testCases {
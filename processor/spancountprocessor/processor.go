@@ -52,7 +52,7 @@ func newExprProcessor(config *Config, consumer consumer.Traces, match *expr.Expr
 		config:   config,
 		match:    match,
 		attrs:    attrs,
-		counter:  counter.NewTelemetryCounter(),
+		counter:  newCounter(config),
 		consumer: consumer,
 		logger:   logger,
 	}
@@ -69,12 +69,19 @@ func newOTTLProcessor(
 		config:    config,
 		OTTLmatch: match,
 		OTTLattrs: attrs,
-		counter:   counter.NewTelemetryCounter(),
+		counter:   newCounter(config),
 		consumer:  consumer,
 		logger:    logger,
 	}
 }
 
+// newCounter returns a counter, sampling exemplars if enabled.
+func newCounter(config *Config) *counter.TelemetryCounter {
+	c := counter.NewTelemetryCounter()
+	c.SetExemplarLimit(config.Exemplars.Limit())
+	return c
+}
+
 func (p *spanCountProcessor) isOTTL() bool {
 	return p.OTTLmatch != nil
 }
@@ -102,6 +109,15 @@ func (p *spanCountProcessor) Shutdown(_ context.Context) error {
 	return nil
 }
 
+// DiscardedOnShutdown returns the number of datapoints counted since the last interval, which are discarded on shutdown.
+// They can't be flushed, since the route receiver they are sent to stops before processors.
+func (p *spanCountProcessor) DiscardedOnShutdown() int {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+
+	return p.counter.Len()
+}
+
 // ConsumeMetrics processes the metrics.
 func (p *spanCountProcessor) ConsumeTraces(ctx context.Context, t ptrace.Traces) error {
 	p.mux.Lock()
@@ -137,7 +153,12 @@ func (p *spanCountProcessor) consumeTracesOTTL(ctx context.Context, t ptrace.Tra
 
 				if match {
 					attrs := p.OTTLattrs.ExtractAttributes(ctx, spanCtx)
-					p.counter.Add(resource.Attributes().AsRaw(), attrs)
+					adjustedCount := counter.AttributeAdjustedCount(span.Attributes(), p.config.AdjustedCountAttribute)
+					if p.config.Exemplars.Enabled {
+						p.counter.AddExemplar(resource.Attributes().AsRaw(), attrs, adjustedCount, p.exemplar(span, adjustedCount))
+					} else {
+						p.counter.AddAdjusted(resource.Attributes().AsRaw(), attrs, adjustedCount)
+					}
 				}
 			}
 		}
@@ -158,7 +179,7 @@ func (p *spanCountProcessor) consumeTracesExpr(t ptrace.Traces) {
 
 			if match {
 				attrs := p.attrs.Extract(span)
-				p.counter.Add(resource, attrs)
+				p.counter.AddAdjusted(resource, attrs, counter.AttributeAdjustedCount(span[expr.AttributesField], p.config.AdjustedCountAttribute))
 			}
 		}
 	}
@@ -217,14 +238,33 @@ func (p *spanCountProcessor) createMetrics() pmetric.Metrics {
 
 			gauge := metrics.Gauge().DataPoints().AppendEmpty()
 			gauge.SetTimestamp(pcommon.NewTimestampFromTime(time.Now()))
-			gauge.SetIntValue(int64(attributes.Count()))
+			if p.config.AdjustedCountAttribute != "" {
+				gauge.SetDoubleValue(attributes.AdjustedCount())
+			} else {
+				gauge.SetIntValue(int64(attributes.Count()))
+			}
 			err = gauge.Attributes().FromRaw(attributes.Values())
 			if err != nil {
 				p.logger.Error("Failed to set metric attributes", zap.Error(err))
 			}
+			p.addExemplars(gauge, attributes)
 
 		}
 	}
 
 	return metrics
 }
+
+// addExemplars attaches the exemplars of the attribute set to the datapoint.
+func (p *spanCountProcessor) addExemplars(dp pmetric.NumberDataPoint, attributes *counter.AttributeCounter) {
+	for _, e := range attributes.Exemplars() {
+		if err := e.CopyTo(dp.Exemplars().AppendEmpty(), p.config.AdjustedCountAttribute == ""); err != nil {
+			p.logger.Error("Failed to set exemplar attributes", zap.Error(err))
+		}
+	}
+}
+
+// exemplar returns an exemplar of the span, with the configured span attributes.
+func (p *spanCountProcessor) exemplar(span ptrace.Span, value float64) counter.Exemplar {
+	return p.config.Exemplars.NewExemplar(span.StartTimestamp(), span.TraceID(), span.SpanID(), span.Attributes(), value)
+}
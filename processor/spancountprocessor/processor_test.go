@@ -243,6 +243,22 @@ func TestConsumeTracesOTTL(t *testing.T) {
 	require.Equal(t, map[string]any{"dimension1": time.Duration(2 * time.Second).Nanoseconds(), "dimension2": "test2"}, countDP.Attributes().AsRaw())
 }
 
+func TestDiscardedOnShutdown(t *testing.T) {
+	processorFactory := NewFactory()
+	processorSettings := processor.CreateSettings{TelemetrySettings: component.TelemetrySettings{Logger: zap.NewNop()}}
+	p, err := processorFactory.CreateTracesProcessor(context.Background(), processorSettings, createDefaultConfig(), &consumertest.TracesSink{})
+	require.NoError(t, err)
+	require.NoError(t, p.Start(context.Background(), nil))
+
+	countProcessor := p.(*spanCountProcessor)
+	countProcessor.counter.Add(map[string]any{"resource": "test1"}, map[string]any{"attribute": "test1"})
+	countProcessor.counter.Add(map[string]any{"resource": "test1"}, map[string]any{"attribute": "test2"})
+	countProcessor.counter.Add(map[string]any{"resource": "test2"}, map[string]any{"attribute": "test1"})
+
+	require.NoError(t, p.Shutdown(context.Background()))
+	require.Equal(t, 3, countProcessor.DiscardedOnShutdown())
+}
+
 func TestConsumeTracesWithoutReceiver(t *testing.T) {
 	logger := NewTestLogger()
 	processorCfg := createDefaultConfig().(*Config)
@@ -270,3 +286,86 @@ func NewTestLogger() *TestLogger {
 	logger := zap.New(core)
 	return &TestLogger{buffer: buffer, Logger: logger}
 }
+
+func TestConsumeTracesAdjustedCount(t *testing.T) {
+	testCases := []struct {
+		desc  string
+		match *string
+	}{
+		{
+			desc: "OTTL",
+		},
+		{
+			desc:  "Expr",
+			match: func() *string { match := "true"; return &match }(),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			processorCfg := createDefaultConfig().(*Config)
+			processorCfg.Match = tc.match
+			processorCfg.AdjustedCountAttribute = "sampling.adjusted_count"
+
+			processorSettings := processor.CreateSettings{TelemetrySettings: component.TelemetrySettings{Logger: zap.NewNop()}}
+			p, err := NewFactory().CreateTracesProcessor(context.Background(), processorSettings, processorCfg, consumertest.NewNop())
+			require.NoError(t, err)
+
+			traces := ptrace.NewTraces()
+			spans := traces.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans()
+			spans.AppendEmpty().Attributes().PutDouble("sampling.adjusted_count", 10)
+			spans.AppendEmpty().Attributes().PutInt("sampling.adjusted_count", 4)
+			spans.AppendEmpty()
+
+			require.NoError(t, p.ConsumeTraces(context.Background(), traces))
+
+			// Telemetry without an adjusted count is counted as 1
+			metrics := p.(*spanCountProcessor).createMetrics()
+			dataPoints := metrics.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0).Gauge().DataPoints()
+			require.Equal(t, 1, dataPoints.Len())
+			require.Equal(t, 15.0, dataPoints.At(0).DoubleValue())
+		})
+	}
+}
+
+func TestConsumeTracesExemplars(t *testing.T) {
+	processorCfg := createDefaultConfig().(*Config)
+	processorCfg.Exemplars.Enabled = true
+	processorCfg.Exemplars.MaxPerDatapoint = 2
+	processorCfg.Exemplars.Attributes = []string{"http.route"}
+	processorCfg.AdjustedCountAttribute = "sampling.adjusted_count"
+
+	processorSettings := processor.CreateSettings{TelemetrySettings: component.TelemetrySettings{Logger: zap.NewNop()}}
+	p, err := NewFactory().CreateTracesProcessor(context.Background(), processorSettings, processorCfg, consumertest.NewNop())
+	require.NoError(t, err)
+
+	timestamp := pcommon.NewTimestampFromTime(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	traces := ptrace.NewTraces()
+	spans := traces.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans()
+	for i := byte(1); i <= 2; i++ {
+		span := spans.AppendEmpty()
+		span.SetTraceID(pcommon.TraceID([16]byte{i}))
+		span.SetSpanID(pcommon.SpanID([8]byte{i}))
+		span.SetStartTimestamp(timestamp)
+		span.Attributes().PutStr("http.route", "/users")
+		span.Attributes().PutStr("user.id", "1234")
+		span.Attributes().PutInt("sampling.adjusted_count", 10)
+	}
+
+	require.NoError(t, p.ConsumeTraces(context.Background(), traces))
+
+	metrics := p.(*spanCountProcessor).createMetrics()
+	dataPoint := metrics.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0).Gauge().DataPoints().At(0)
+	require.Equal(t, 20.0, dataPoint.DoubleValue())
+	require.Equal(t, 2, dataPoint.Exemplars().Len())
+
+	for i := 0; i < dataPoint.Exemplars().Len(); i++ {
+		exemplar := dataPoint.Exemplars().At(i)
+		require.Equal(t, pcommon.TraceID([16]byte{byte(i + 1)}), exemplar.TraceID())
+		require.Equal(t, pcommon.SpanID([8]byte{byte(i + 1)}), exemplar.SpanID())
+		require.Equal(t, timestamp, exemplar.Timestamp())
+		require.Equal(t, 10.0, exemplar.DoubleValue())
+		require.Equal(t, map[string]any{"http.route": "/users"}, exemplar.FilteredAttributes().AsRaw())
+	}
+}
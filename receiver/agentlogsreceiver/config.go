@@ -0,0 +1,84 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package agentlogsreceiver provides a receiver that emits the agent's own logs
+package agentlogsreceiver
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.opentelemetry.io/collector/component"
+	"go.uber.org/zap/zapcore"
+)
+
+// componentKinds are the kinds of component that can be excluded
+var componentKinds = []string{"receiver", "processor", "exporter", "extension", "connector"}
+
+// Config is the config of the agent logs receiver.
+type Config struct {
+	// Level is the minimum level of the logs emitted.
+	Level zapcore.Level `mapstructure:"level"`
+
+	// ExcludeComponents are components whose logs are not emitted, in the form <kind>/<id>, such as exporter/otlp.
+	// A component type, such as exporter/otlp, excludes all components of that type.
+	ExcludeComponents []string `mapstructure:"exclude_components"`
+
+	// MaxLogsPerSecond limits the number of logs emitted each second. 0 means no limit.
+	MaxLogsPerSecond int `mapstructure:"max_logs_per_second"`
+
+	// BufferSize is the number of logs buffered before logs are dropped.
+	BufferSize int `mapstructure:"buffer_size"`
+}
+
+var _ component.Config = (*Config)(nil)
+
+// createDefaultConfig returns the default config for the agent logs receiver.
+func createDefaultConfig() component.Config {
+	return &Config{
+		Level:            zapcore.InfoLevel,
+		MaxLogsPerSecond: 100,
+		BufferSize:       1000,
+	}
+}
+
+// Validate validates the config.
+func (c *Config) Validate() error {
+	for _, excluded := range c.ExcludeComponents {
+		kind, id, _ := strings.Cut(excluded, "/")
+		if id == "" || !isComponentKind(kind) {
+			return fmt.Errorf("invalid exclude_components entry %q, must be in the form <kind>/<id> where kind is one of %s", excluded, strings.Join(componentKinds, ", "))
+		}
+	}
+
+	if c.MaxLogsPerSecond < 0 {
+		return errors.New("max_logs_per_second must not be negative")
+	}
+
+	if c.BufferSize <= 0 {
+		return errors.New("buffer_size must be greater than 0")
+	}
+
+	return nil
+}
+
+func isComponentKind(kind string) bool {
+	for _, k := range componentKinds {
+		if kind == k {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,104 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agentlogsreceiver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/confmap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestUnmarshalConfig(t *testing.T) {
+	conf := confmap.NewFromStringMap(map[string]any{
+		"level":               "warn",
+		"exclude_components":  []any{"exporter/otlp"},
+		"max_logs_per_second": 10,
+	})
+
+	cfg := createDefaultConfig().(*Config)
+	require.NoError(t, conf.Unmarshal(cfg))
+	require.Equal(t, &Config{
+		Level:             zapcore.WarnLevel,
+		ExcludeComponents: []string{"exporter/otlp"},
+		MaxLogsPerSecond:  10,
+		BufferSize:        1000,
+	}, cfg)
+}
+
+func TestValidate(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		config      Config
+		expectedErr string
+	}{
+		{
+			desc: "default",
+			config: Config{
+				Level:            zapcore.InfoLevel,
+				MaxLogsPerSecond: 100,
+				BufferSize:       1000,
+			},
+		},
+		{
+			desc: "exclude components",
+			config: Config{
+				ExcludeComponents: []string{"exporter/otlp", "processor/batch/logs"},
+				BufferSize:        1,
+			},
+		},
+		{
+			desc: "invalid exclude component",
+			config: Config{
+				ExcludeComponents: []string{"otlp"},
+				BufferSize:        1,
+			},
+			expectedErr: `invalid exclude_components entry "otlp"`,
+		},
+		{
+			desc: "invalid exclude component kind",
+			config: Config{
+				ExcludeComponents: []string{"pipeline/logs"},
+				BufferSize:        1,
+			},
+			expectedErr: `invalid exclude_components entry "pipeline/logs"`,
+		},
+		{
+			desc: "negative max logs per second",
+			config: Config{
+				MaxLogsPerSecond: -1,
+				BufferSize:       1,
+			},
+			expectedErr: "max_logs_per_second must not be negative",
+		},
+		{
+			desc:        "no buffer",
+			config:      Config{},
+			expectedErr: "buffer_size must be greater than 0",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			err := tc.config.Validate()
+			if tc.expectedErr != "" {
+				require.ErrorContains(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
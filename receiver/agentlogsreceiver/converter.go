@@ -0,0 +1,148 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agentlogsreceiver
+
+import (
+	"context"
+	"sort"
+
+	"go.opentelemetry.io/collector/confmap"
+)
+
+// pipelineConverter excludes the components of each agent logs receiver's pipelines from the receiver.
+// An exporter that logs each failed export would otherwise emit its own failures back through the same pipeline.
+type pipelineConverter struct{}
+
+// NewConverter returns a converter that adds the processors, exporters, and connectors that agent logs pass through
+// to the exclude_components of each agent logs receiver.
+// It's used with the collector's config provider, alongside WrapCore.
+func NewConverter() confmap.Converter {
+	return pipelineConverter{}
+}
+
+// Convert adds the components of each agent logs receiver's pipelines to its exclude_components
+func (pipelineConverter) Convert(_ context.Context, conf *confmap.Conf) error {
+	pipelines, ok := conf.Get("service::pipelines").(map[string]any)
+	if !ok {
+		return nil
+	}
+
+	excluded := map[string]map[string]struct{}{}
+	for pipelineID, pipeline := range pipelines {
+		for _, id := range stringList(pipeline, "receivers") {
+			if componentType(id) != typeStr {
+				continue
+			}
+
+			if _, ok := excluded[id]; !ok {
+				excluded[id] = map[string]struct{}{}
+				for _, component := range stringList(conf.Get("receivers::"+id), "exclude_components") {
+					excluded[id][component] = struct{}{}
+				}
+			}
+
+			for _, component := range pipelineComponents(conf, pipelines, pipelineID) {
+				excluded[id][component] = struct{}{}
+			}
+		}
+	}
+
+	if len(excluded) == 0 {
+		return nil
+	}
+
+	receivers := make(map[string]any, len(excluded))
+	for id, components := range excluded {
+		receivers[id] = map[string]any{"exclude_components": sortedAny(components)}
+	}
+
+	return conf.Merge(confmap.NewFromStringMap(map[string]any{"receivers": receivers}))
+}
+
+// pipelineComponents returns the components logs pass through after entering the pipeline, as <kind>/<id>.
+// Logs exported to a connector continue through the pipelines the connector receives for.
+func pipelineComponents(conf *confmap.Conf, pipelines map[string]any, start string) []string {
+	var components []string
+	visited := map[string]bool{start: true}
+	queue := []string{start}
+
+	for len(queue) > 0 {
+		pipeline := pipelines[queue[0]]
+		queue = queue[1:]
+
+		for _, id := range stringList(pipeline, "processors") {
+			components = append(components, "processor/"+id)
+		}
+
+		for _, id := range stringList(pipeline, "exporters") {
+			if !conf.IsSet("connectors::" + id) {
+				components = append(components, "exporter/"+id)
+				continue
+			}
+
+			components = append(components, "connector/"+id)
+			for pipelineID, next := range pipelines {
+				if visited[pipelineID] || !contains(stringList(next, "receivers"), id) {
+					continue
+				}
+				visited[pipelineID] = true
+				queue = append(queue, pipelineID)
+			}
+		}
+	}
+
+	return components
+}
+
+// stringList returns the list of strings at the key of a config section
+func stringList(section any, key string) []string {
+	values, ok := section.(map[string]any)
+	if !ok {
+		return nil
+	}
+
+	list, _ := values[key].([]any)
+	strs := make([]string, 0, len(list))
+	for _, value := range list {
+		if str, ok := value.(string); ok {
+			strs = append(strs, str)
+		}
+	}
+	return strs
+}
+
+// sortedAny returns the keys of the set in sorted order, as a list of config values
+func sortedAny(set map[string]struct{}) []any {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	values := make([]any, 0, len(keys))
+	for _, key := range keys {
+		values = append(values, key)
+	}
+	return values
+}
+
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,78 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agentlogsreceiver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/confmap"
+)
+
+func TestConverter(t *testing.T) {
+	conf := confmap.NewFromStringMap(map[string]any{
+		"receivers": map[string]any{
+			"agentlogs": nil,
+			"agentlogs/warn": map[string]any{
+				"level":              "warn",
+				"exclude_components": []any{"receiver/filelog"},
+			},
+			"filelog": nil,
+		},
+		"connectors": map[string]any{
+			"count": nil,
+		},
+		"service": map[string]any{
+			"pipelines": map[string]any{
+				"logs": map[string]any{
+					"receivers":  []any{"agentlogs", "filelog"},
+					"processors": []any{"batch"},
+					"exporters":  []any{"otlp", "count"},
+				},
+				"metrics": map[string]any{
+					"receivers": []any{"count"},
+					"exporters": []any{"googlecloud"},
+				},
+				"logs/warn": map[string]any{
+					"receivers": []any{"agentlogs/warn"},
+					"exporters": []any{"otlphttp"},
+				},
+				"logs/other": map[string]any{
+					"receivers": []any{"filelog"},
+					"exporters": []any{"logging"},
+				},
+			},
+		},
+	})
+
+	require.NoError(t, NewConverter().Convert(context.Background(), conf))
+
+	require.Equal(t, []any{"connector/count", "exporter/googlecloud", "exporter/otlp", "processor/batch"}, conf.Get("receivers::agentlogs::exclude_components"))
+	require.Equal(t, []any{"exporter/otlphttp", "receiver/filelog"}, conf.Get("receivers::agentlogs/warn::exclude_components"))
+	require.Equal(t, "warn", conf.Get("receivers::agentlogs/warn::level"))
+	require.Nil(t, conf.Get("receivers::filelog"))
+}
+
+func TestConverterWithoutPipelines(t *testing.T) {
+	conf := confmap.NewFromStringMap(map[string]any{
+		"receivers": map[string]any{
+			"agentlogs": nil,
+		},
+	})
+
+	require.NoError(t, NewConverter().Convert(context.Background(), conf))
+	require.Nil(t, conf.Get("receivers::agentlogs"))
+}
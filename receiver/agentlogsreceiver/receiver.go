@@ -0,0 +1,337 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agentlogsreceiver
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/receiver"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	// scopeName is the instrumentation scope of the emitted logs
+	scopeName = "github.com/observiq/bindplane-agent/receiver/agentlogsreceiver"
+
+	// maxBatchSize is the most logs emitted at once
+	maxBatchSize = 100
+
+	// dropReportInterval is how often the number of dropped logs is logged
+	dropReportInterval = time.Minute
+
+	// exporterSuppression is how long exporter logs are dropped after emitting logs fails,
+	// so an exporter that logs its own failures doesn't feed an endless loop of failing logs
+	exporterSuppression = time.Minute
+)
+
+// agentLogsReceiver emits the agent's own logs, received from the tap
+type agentLogsReceiver struct {
+	logger    *zap.Logger
+	cfg       *Config
+	consumer  consumer.Logs
+	tap       *tap
+	buildInfo component.BuildInfo
+	entries   chan logEntry
+	limiter   *rateLimiter
+
+	// dropped is the number of logs dropped since the last report
+	dropped atomic.Int64
+
+	// suppressExportersUntil is the unix nano time until which exporter logs are dropped
+	suppressExportersUntil atomic.Int64
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// logEntry is an entry from the tap, with its fields encoded
+type logEntry struct {
+	entry  zapcore.Entry
+	kind   string
+	id     string
+	fields map[string]any
+}
+
+var _ receiver.Logs = (*agentLogsReceiver)(nil)
+
+// newReceiver creates a receiver that emits entries from the tap
+func newReceiver(set receiver.CreateSettings, cfg *Config, consumer consumer.Logs, t *tap) *agentLogsReceiver {
+	return &agentLogsReceiver{
+		logger:    set.Logger,
+		cfg:       cfg,
+		consumer:  consumer,
+		tap:       t,
+		buildInfo: set.BuildInfo,
+		entries:   make(chan logEntry, cfg.BufferSize),
+		limiter:   newRateLimiter(cfg.MaxLogsPerSecond),
+	}
+}
+
+// Start subscribes to the tap and starts emitting logs
+func (r *agentLogsReceiver) Start(_ context.Context, _ component.Host) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	r.cancel = cancel
+
+	r.wg.Add(1)
+	go r.emit(ctx)
+
+	r.tap.subscribe(r)
+	return nil
+}
+
+// Shutdown unsubscribes from the tap and stops emitting logs
+func (r *agentLogsReceiver) Shutdown(_ context.Context) error {
+	r.tap.unsubscribe(r)
+
+	if r.cancel != nil {
+		r.cancel()
+	}
+	r.wg.Wait()
+
+	return nil
+}
+
+// level returns the minimum level of entries the receiver emits
+func (r *agentLogsReceiver) level() zapcore.Level {
+	return r.cfg.Level
+}
+
+// offer buffers the entry to be emitted, unless it's excluded, rate limited, or the buffer is full.
+// It's called while logging, so it never blocks.
+func (r *agentLogsReceiver) offer(entry zapcore.Entry, kind, id string, fields []zapcore.Field) {
+	// The receiver's own logs are never emitted, as they're often about failing to emit logs
+	if kind == "receiver" && componentType(id) == typeStr {
+		return
+	}
+
+	if r.excluded(kind, id) {
+		return
+	}
+
+	if kind == "exporter" && time.Now().UnixNano() < r.suppressExportersUntil.Load() {
+		r.dropped.Add(1)
+		return
+	}
+
+	if !r.limiter.allow(time.Now()) {
+		r.dropped.Add(1)
+		return
+	}
+
+	// Fields are encoded now, as they may change after logging
+	enc := zapcore.NewMapObjectEncoder()
+	for _, field := range fields {
+		field.AddTo(enc)
+	}
+
+	select {
+	case r.entries <- logEntry{entry: entry, kind: kind, id: id, fields: enc.Fields}:
+	default:
+		r.dropped.Add(1)
+	}
+}
+
+// excluded returns true if logs of the component are excluded
+func (r *agentLogsReceiver) excluded(kind, id string) bool {
+	if kind == "" || id == "" {
+		return false
+	}
+
+	for _, excluded := range r.cfg.ExcludeComponents {
+		if excluded == kind+"/"+id || excluded == kind+"/"+componentType(id) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// emit emits buffered entries until the context is canceled
+func (r *agentLogsReceiver) emit(ctx context.Context) {
+	defer r.wg.Done()
+
+	ticker := time.NewTicker(dropReportInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if dropped := r.dropped.Swap(0); dropped > 0 {
+				r.logger.Warn("Dropped agent logs", zap.Int64("count", dropped))
+			}
+		case entry := <-r.entries:
+			r.emitBatch(ctx, entry)
+		}
+	}
+}
+
+// emitBatch emits the entry, along with any other buffered entries up to the max batch size
+func (r *agentLogsReceiver) emitBatch(ctx context.Context, first logEntry) {
+	logs := plog.NewLogs()
+	resourceLogs := logs.ResourceLogs().AppendEmpty()
+	resourceLogs.Resource().Attributes().PutStr("service.name", filepath.Base(r.buildInfo.Command))
+	resourceLogs.Resource().Attributes().PutStr("service.version", r.buildInfo.Version)
+	scopeLogs := resourceLogs.ScopeLogs().AppendEmpty()
+	scopeLogs.Scope().SetName(scopeName)
+	scopeLogs.Scope().SetVersion(r.buildInfo.Version)
+	records := scopeLogs.LogRecords()
+
+	fillRecord(records.AppendEmpty(), first)
+
+batch:
+	for records.Len() < maxBatchSize {
+		select {
+		case entry := <-r.entries:
+			fillRecord(records.AppendEmpty(), entry)
+		default:
+			break batch
+		}
+	}
+
+	if err := r.consumer.ConsumeLogs(ctx, logs); err != nil {
+		r.suppressExportersUntil.Store(time.Now().Add(exporterSuppression).UnixNano())
+		r.logger.Error("Failed to emit agent logs, dropping exporter logs temporarily", zap.Error(err), zap.Duration("duration", exporterSuppression))
+	}
+}
+
+// fillRecord fills the log record from the entry
+func fillRecord(record plog.LogRecord, entry logEntry) {
+	record.SetTimestamp(pcommon.NewTimestampFromTime(entry.entry.Time))
+	record.SetObservedTimestamp(pcommon.NewTimestampFromTime(time.Now()))
+	record.SetSeverityNumber(severityNumber(entry.entry.Level))
+	record.SetSeverityText(entry.entry.Level.CapitalString())
+	record.Body().SetStr(entry.entry.Message)
+
+	attributes := record.Attributes()
+	for key, value := range entry.fields {
+		_ = attributes.PutEmpty(key).FromRaw(sanitize(value))
+	}
+
+	if entry.kind != "" {
+		attributes.PutStr("component.kind", entry.kind)
+	}
+	if entry.id != "" {
+		attributes.PutStr("component.id", entry.id)
+	}
+	if entry.entry.LoggerName != "" {
+		attributes.PutStr("logger", entry.entry.LoggerName)
+	}
+	if entry.entry.Caller.Defined {
+		attributes.PutStr("caller", entry.entry.Caller.TrimmedPath())
+	}
+	if entry.entry.Stack != "" {
+		attributes.PutStr("stacktrace", entry.entry.Stack)
+	}
+}
+
+// sanitize converts values from a zap map encoder to types that can be set as attributes
+func sanitize(value any) any {
+	switch v := value.(type) {
+	case nil, string, bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64, []byte:
+		return v
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	case time.Duration:
+		return v.String()
+	case map[string]any:
+		sanitized := make(map[string]any, len(v))
+		for key, item := range v {
+			sanitized[key] = sanitize(item)
+		}
+		return sanitized
+	case []any:
+		sanitized := make([]any, len(v))
+		for i, item := range v {
+			sanitized[i] = sanitize(item)
+		}
+		return sanitized
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// severityNumber returns the OTel severity of the level
+func severityNumber(level zapcore.Level) plog.SeverityNumber {
+	switch level {
+	case zapcore.DebugLevel:
+		return plog.SeverityNumberDebug
+	case zapcore.InfoLevel:
+		return plog.SeverityNumberInfo
+	case zapcore.WarnLevel:
+		return plog.SeverityNumberWarn
+	case zapcore.ErrorLevel:
+		return plog.SeverityNumberError
+	case zapcore.DPanicLevel, zapcore.PanicLevel:
+		return plog.SeverityNumberFatal
+	case zapcore.FatalLevel:
+		return plog.SeverityNumberFatal4
+	default:
+		return plog.SeverityNumberUnspecified
+	}
+}
+
+// componentType returns the type of the component ID
+func componentType(id string) string {
+	componentType, _, _ := strings.Cut(id, "/")
+	return componentType
+}
+
+// rateLimiter limits the number of events each second
+type rateLimiter struct {
+	limit       int
+	mutex       sync.Mutex
+	windowStart time.Time
+	count       int
+}
+
+// newRateLimiter creates a rate limiter allowing limit events each second. A limit of 0 allows all events.
+func newRateLimiter(limit int) *rateLimiter {
+	return &rateLimiter{limit: limit}
+}
+
+// allow returns true if the event at the time is allowed
+func (l *rateLimiter) allow(now time.Time) bool {
+	if l.limit == 0 {
+		return true
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if now.Sub(l.windowStart) >= time.Second {
+		l.windowStart = now
+		l.count = 0
+	}
+
+	if l.count >= l.limit {
+		return false
+	}
+
+	l.count++
+	return true
+}
@@ -0,0 +1,166 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agentlogsreceiver
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// newTestLogger returns a logger writing to the tap, along with the observed logs written to the wrapped core
+func newTestLogger(t *tap) (*zap.Logger, *observer.ObservedLogs) {
+	observed, logs := observer.New(zapcore.DebugLevel)
+	return zap.New(zapcore.NewTee(observed, &tapCore{tap: t})), logs
+}
+
+func newTestReceiver(t *testing.T, cfg *Config, sink *consumertest.LogsSink, tp *tap) *agentLogsReceiver {
+	r := newReceiver(receivertest.NewNopCreateSettings(), cfg, sink, tp)
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() {
+		require.NoError(t, r.Shutdown(context.Background()))
+	})
+	return r
+}
+
+func TestReceiverEmitsLogs(t *testing.T) {
+	tp := newTap()
+	logger, observed := newTestLogger(tp)
+	sink := &consumertest.LogsSink{}
+
+	cfg := createDefaultConfig().(*Config)
+	newTestReceiver(t, cfg, sink, tp)
+
+	exporterLogger := logger.Named("exporter").With(zap.String(kindKey, "exporter"), zap.String(nameKey, "otlp/primary"))
+	exporterLogger.Warn("Export failed", zap.Error(errors.New("connection refused")), zap.Int("retries", 3), zap.Duration("backoff", time.Second))
+	logger.Debug("Below the receiver level")
+
+	require.Eventually(t, func() bool { return sink.LogRecordCount() == 1 }, 5*time.Second, 10*time.Millisecond)
+
+	// Logs are still written to the wrapped core
+	require.Equal(t, 2, observed.Len())
+
+	record := sink.AllLogs()[0].ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0)
+	require.Equal(t, "Export failed", record.Body().Str())
+	require.Equal(t, plog.SeverityNumberWarn, record.SeverityNumber())
+	require.Equal(t, "WARN", record.SeverityText())
+	require.Equal(t, map[string]any{
+		"component.kind": "exporter",
+		"component.id":   "otlp/primary",
+		"logger":         "exporter",
+		"error":          "connection refused",
+		"retries":        int64(3),
+		"backoff":        "1s",
+	}, record.Attributes().AsRaw())
+}
+
+func TestReceiverExcludesComponents(t *testing.T) {
+	tp := newTap()
+	logger, _ := newTestLogger(tp)
+	sink := &consumertest.LogsSink{}
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.ExcludeComponents = []string{"exporter/otlp", "processor/batch/logs"}
+	newTestReceiver(t, cfg, sink, tp)
+
+	logger.With(zap.String(kindKey, "exporter"), zap.String(nameKey, "otlp/primary")).Info("excluded by type")
+	logger.With(zap.String(kindKey, "processor"), zap.String(nameKey, "batch/logs")).Info("excluded by ID")
+	logger.With(zap.String(kindKey, "receiver"), zap.String(nameKey, "agentlogs")).Info("own logs are excluded")
+	logger.With(zap.String(kindKey, "processor"), zap.String(nameKey, "batch/metrics")).Info("included")
+
+	require.Eventually(t, func() bool { return sink.LogRecordCount() == 1 }, 5*time.Second, 10*time.Millisecond)
+	record := sink.AllLogs()[0].ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0)
+	require.Equal(t, "included", record.Body().Str())
+}
+
+func TestReceiverRateLimit(t *testing.T) {
+	tp := newTap()
+	logger, _ := newTestLogger(tp)
+	sink := &consumertest.LogsSink{}
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.MaxLogsPerSecond = 5
+	r := newTestReceiver(t, cfg, sink, tp)
+
+	for i := 0; i < 20; i++ {
+		logger.Info("flood")
+	}
+
+	require.Eventually(t, func() bool { return sink.LogRecordCount() == 5 }, 5*time.Second, 10*time.Millisecond)
+	require.Equal(t, int64(15), r.dropped.Load())
+}
+
+func TestReceiverSuppressesExportersAfterFailure(t *testing.T) {
+	tp := newTap()
+	logger, _ := newTestLogger(tp)
+
+	cfg := createDefaultConfig().(*Config)
+	r := newTestReceiver(t, cfg, nil, tp)
+	r.consumer = consumertest.NewErr(errors.New("export failed"))
+
+	logger.Info("first")
+	require.Eventually(t, func() bool { return r.suppressExportersUntil.Load() > 0 }, 5*time.Second, 10*time.Millisecond)
+
+	// The exporter failing to send agent logs would otherwise feed its own failures back into the pipeline
+	logger.With(zap.String(kindKey, "exporter"), zap.String(nameKey, "otlp")).Error("failed to send")
+	require.Equal(t, int64(1), r.dropped.Load())
+}
+
+func TestReceiverShutdownUnsubscribes(t *testing.T) {
+	tp := newTap()
+	require.False(t, tp.enabled(zapcore.FatalLevel))
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.Level = zapcore.WarnLevel
+	r := newReceiver(receivertest.NewNopCreateSettings(), cfg, consumertest.NewNop(), tp)
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+
+	require.True(t, tp.enabled(zapcore.WarnLevel))
+	require.False(t, tp.enabled(zapcore.InfoLevel))
+
+	require.NoError(t, r.Shutdown(context.Background()))
+	require.False(t, tp.enabled(zapcore.FatalLevel))
+}
+
+func TestRateLimiter(t *testing.T) {
+	now := time.Now()
+	l := newRateLimiter(2)
+	require.True(t, l.allow(now))
+	require.True(t, l.allow(now))
+	require.False(t, l.allow(now.Add(500*time.Millisecond)))
+	require.True(t, l.allow(now.Add(time.Second)))
+
+	unlimited := newRateLimiter(0)
+	for i := 0; i < 1000; i++ {
+		require.True(t, unlimited.allow(now))
+	}
+}
+
+func TestWrapCore(t *testing.T) {
+	observed, logs := observer.New(zapcore.InfoLevel)
+	logger := zap.New(WrapCore(observed))
+	logger.Info("written")
+	require.Equal(t, 1, logs.Len())
+}
@@ -0,0 +1,175 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agentlogsreceiver
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	// kindKey and nameKey are the fields the collector adds to the logger of each component
+	kindKey = "kind"
+	nameKey = "name"
+)
+
+// tap receives log entries from a zap core and passes them to subscribed receivers
+type tap struct {
+	mutex       sync.RWMutex
+	subscribers map[subscriber]struct{}
+
+	// minLevel is the lowest level of any subscriber, or disabledLevel if there are none.
+	// It's checked for every entry, so it's kept separate from the subscribers to avoid locking.
+	minLevel atomic.Int32
+}
+
+// subscriber receives entries from the tap
+type subscriber interface {
+	// level returns the minimum level of entries the subscriber receives
+	level() zapcore.Level
+
+	// offer offers the entry to the subscriber. It must not block.
+	offer(entry zapcore.Entry, kind, id string, fields []zapcore.Field)
+}
+
+// disabledLevel is above all levels, so no entries are enabled
+const disabledLevel = zapcore.InvalidLevel
+
+// globalTap is the tap receiving entries from the core returned by WrapCore
+var globalTap = newTap()
+
+func newTap() *tap {
+	t := &tap{subscribers: make(map[subscriber]struct{})}
+	t.minLevel.Store(int32(disabledLevel))
+	return t
+}
+
+// subscribe starts passing entries to the subscriber
+func (t *tap) subscribe(s subscriber) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.subscribers[s] = struct{}{}
+	t.updateMinLevel()
+}
+
+// unsubscribe stops passing entries to the subscriber
+func (t *tap) unsubscribe(s subscriber) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	delete(t.subscribers, s)
+	t.updateMinLevel()
+}
+
+// updateMinLevel updates the min level from the subscribers. The mutex must be held.
+func (t *tap) updateMinLevel() {
+	minLevel := disabledLevel
+	for s := range t.subscribers {
+		if s.level() < minLevel {
+			minLevel = s.level()
+		}
+	}
+	t.minLevel.Store(int32(minLevel))
+}
+
+// enabled returns true if a subscriber receives entries of the level
+func (t *tap) enabled(level zapcore.Level) bool {
+	return level >= zapcore.Level(t.minLevel.Load())
+}
+
+// publish offers the entry to all subscribers
+func (t *tap) publish(entry zapcore.Entry, kind, id string, fields []zapcore.Field) {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	for s := range t.subscribers {
+		if entry.Level >= s.level() {
+			s.offer(entry, kind, id, fields)
+		}
+	}
+}
+
+// WrapCore returns a core that writes to the core, and passes entries to running agent logs receivers.
+// It's used with zap.WrapCore when creating the collector's logger.
+func WrapCore(core zapcore.Core) zapcore.Core {
+	return zapcore.NewTee(core, &tapCore{tap: globalTap})
+}
+
+// tapCore is a core that passes entries to the tap
+type tapCore struct {
+	tap    *tap
+	fields []zapcore.Field
+	kind   string
+	id     string
+}
+
+// Enabled returns true if a receiver is running with the level enabled
+func (c *tapCore) Enabled(level zapcore.Level) bool {
+	return c.tap.enabled(level)
+}
+
+// With returns a copy of the core with the fields added, keeping track of the component it logs for
+func (c *tapCore) With(fields []zapcore.Field) zapcore.Core {
+	clone := &tapCore{
+		tap:    c.tap,
+		fields: make([]zapcore.Field, 0, len(c.fields)+len(fields)),
+		kind:   c.kind,
+		id:     c.id,
+	}
+
+	clone.fields = append(clone.fields, c.fields...)
+	for _, field := range fields {
+		if field.Type == zapcore.StringType {
+			// The component is added as attributes of its own, rather than fields
+			switch field.Key {
+			case kindKey:
+				clone.kind = field.String
+				continue
+			case nameKey:
+				clone.id = field.String
+				continue
+			}
+		}
+		clone.fields = append(clone.fields, field)
+	}
+
+	return clone
+}
+
+// Check adds the core to the checked entry if the level is enabled
+func (c *tapCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return checked.AddCore(entry, c)
+	}
+	return checked
+}
+
+// Write passes the entry to the tap
+func (c *tapCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	all := make([]zapcore.Field, 0, len(c.fields)+len(fields))
+	all = append(all, c.fields...)
+	all = append(all, fields...)
+
+	c.tap.publish(entry, c.kind, c.id, all)
+	return nil
+}
+
+// Sync does nothing, as entries are passed to the tap as they're written
+func (c *tapCore) Sync() error {
+	return nil
+}
@@ -21,6 +21,7 @@ import (
 
 	"github.com/observiq/bindplane-agent/updater/internal/logging"
 	"github.com/observiq/bindplane-agent/updater/internal/path"
+	"github.com/observiq/bindplane-agent/updater/internal/preflight"
 	"github.com/observiq/bindplane-agent/updater/internal/updater"
 	"github.com/observiq/bindplane-agent/updater/internal/version"
 	"github.com/spf13/pflag"
@@ -29,6 +30,7 @@ import (
 
 func main() {
 	var showVersion = pflag.BoolP("version", "v", false, "Prints the version of the updater and exits, if specified.")
+	var dryRun = pflag.Bool("dry-run", false, "Runs the preflight checks for the update and prints the results without modifying the installation.")
 	pflag.Parse()
 
 	if *showVersion {
@@ -46,6 +48,11 @@ func main() {
 		log.Fatalf("Failed to determine install directory: %s", err)
 	}
 
+	if *dryRun {
+		runDryRun(installDir)
+		return
+	}
+
 	logger, err := logging.NewLogger(installDir)
 	if err != nil {
 		log.Fatalf("Failed to create logger: %s\n", err)
@@ -62,3 +69,32 @@ func main() {
 
 	logger.Info("Updater finished successfully")
 }
+
+// runDryRun runs the preflight checks and prints the results to stdout.
+// The updater log file is left untouched, so that logs from previous updates are kept.
+func runDryRun(installDir string) {
+	logger, err := zap.NewDevelopment()
+	if err != nil {
+		log.Fatalf("Failed to create logger: %s\n", err)
+	}
+
+	report, err := preflight.NewChecker(logger, installDir).Check()
+
+	for _, f := range report.ReplacedFiles {
+		fmt.Println("replace:", f)
+	}
+	for _, f := range report.NewFiles {
+		fmt.Println("create:", f)
+	}
+	fmt.Println("required disk space (bytes):", report.RequiredBytes)
+	fmt.Println("available disk space (bytes):", report.AvailableBytes)
+	if report.NewVersion != "" {
+		fmt.Println("new collector:", report.NewVersion)
+	}
+
+	if err != nil {
+		logger.Fatal("Preflight checks failed", zap.Error(err))
+	}
+
+	fmt.Println("Preflight checks passed")
+}
@@ -16,7 +16,6 @@
 package install
 
 import (
-	"errors"
 	"fmt"
 	"io/fs"
 	"os"
@@ -63,11 +62,6 @@ func NewInstaller(logger *zap.Logger, installDir string, service service.Service
 // as well as installing the new service file using the installer's Service interface.
 // It then starts the service.
 func (i archiveInstaller) Install(rb rollback.Rollbacker) error {
-	// If JMX jar exists outside of install directory, make sure that gets backed up
-	if err := i.attemptSpecialJMXJarInstall(rb); err != nil {
-		return fmt.Errorf("failed to process special JMX jar: %w", err)
-	}
-
 	// install files that go to installDirPath to their correct location,
 	// excluding any config files (logging.yaml, config.yaml, manager.yaml)
 	if err := installFiles(i.logger, i.latestDir, i.installDir, i.backupDir, rb); err != nil {
@@ -95,26 +89,7 @@ func (i archiveInstaller) Install(rb rollback.Rollbacker) error {
 // installFiles moves the file tree rooted at inputPath to installDir,
 // skipping configuration files. Appends CopyFileAction-s to the Rollbacker as it copies file.
 func installFiles(logger *zap.Logger, inputPath, installDir, backupDir string, rb rollback.Rollbacker) error {
-	err := filepath.WalkDir(inputPath, func(inPath string, d fs.DirEntry, err error) error {
-		switch {
-		case err != nil:
-			// if there was an error walking the directory, we want to bail out.
-			return err
-		case d.IsDir():
-			// Skip directories, we'll create them when we get a file in the directory.
-			return nil
-		case skipConfigFiles(inPath):
-			// Found a config file that we should skip copying.
-			return nil
-		}
-
-		// We want the path relative to the directory we are walking in order to calculate where the file should be
-		// mirrored in the destination directory.
-		relPath, err := filepath.Rel(inputPath, inPath)
-		if err != nil {
-			return err
-		}
-
+	err := walkInstallFiles(inputPath, func(inPath, relPath string) error {
 		// use the relative path to get the outPath (where we should write the file), and
 		// to get the out directory (which we will create if it does not exist).
 		outPath := filepath.Join(installDir, relPath)
@@ -150,58 +125,47 @@ func installFiles(logger *zap.Logger, inputPath, installDir, backupDir string, r
 	return nil
 }
 
-func (i archiveInstaller) attemptSpecialJMXJarInstall(rb rollback.Rollbacker) error {
-	jarPath := path.SpecialJMXJarFile(i.installDir)
-	jarDirPath := path.SpecialJarDir(i.installDir)
-	latestJarPath := path.LatestJMXJarFile(i.latestDir)
-	_, err := os.Stat(jarPath)
-	switch {
-	case err == nil:
-		if err := installFile(i.logger, latestJarPath, jarDirPath, i.backupDir, rb); err != nil {
-			return fmt.Errorf("failed to install JMX jar from latest directory: %w", err)
-		}
-		// Just log this error as the worst case is that there will be two jars copied over
-		if err = os.Remove(latestJarPath); err != nil {
-			i.logger.Warn("Failed to remove JMX jar from latest directory", zap.Error(err))
-		}
-	case !errors.Is(err, os.ErrNotExist):
-		return fmt.Errorf("failed determine where currently installed JMX jar is: %w", err)
-	}
-
-	return nil
-}
-
-// installFile moves new file to output path.
-// Appends CopyFileAction-s to the Rollbacker as it copies file.
-func installFile(logger *zap.Logger, inPath, installDirPath, backupDirPath string, rb rollback.Rollbacker) error {
-	baseInPath := filepath.Base(inPath)
-
-	// use the relative path to get the outPath (where we should write the file), and
-	// to get the out directory (which we will create if it does not exist).
-	outPath := filepath.Join(installDirPath, baseInPath)
-	outDir := filepath.Dir(outPath)
-
-	if err := os.MkdirAll(outDir, 0750); err != nil {
-		return fmt.Errorf("failed to create dir: %w", err)
-	}
+// FilesToInstall returns the paths, relative to latestDir, of every file that would be
+// copied into the install directory by Install. Configuration files are not included.
+func FilesToInstall(latestDir string) ([]string, error) {
+	files := []string{}
+	err := walkInstallFiles(latestDir, func(_, relPath string) error {
+		files = append(files, relPath)
+		return nil
+	})
 
-	// We create the action record here, because we want to record whether the file exists or not before
-	// we open the file (which will end up creating the file).
-	cfa, err := action.NewCopyFileAction(logger, baseInPath, outPath, backupDirPath)
 	if err != nil {
-		return fmt.Errorf("failed to create copy file action: %w", err)
+		return nil, fmt.Errorf("failed to walk latest dir: %w", err)
 	}
 
-	// Record that we are performing copying the file.
-	// We record before we actually do the action here because the file may be partially written,
-	// and we will want to roll that back if that is the case.
-	rb.AppendAction(cfa)
+	return files, nil
+}
 
-	if err := file.CopyFileOverwrite(logger.Named("copy-file"), inPath, outPath); err != nil {
-		return fmt.Errorf("failed to copy file: %w", err)
-	}
+// walkInstallFiles walks the file tree rooted at inputPath, calling fn for each file that should be installed.
+// fn is given the full path of the file, as well as the path relative to inputPath.
+func walkInstallFiles(inputPath string, fn func(inPath, relPath string) error) error {
+	return filepath.WalkDir(inputPath, func(inPath string, d fs.DirEntry, err error) error {
+		switch {
+		case err != nil:
+			// if there was an error walking the directory, we want to bail out.
+			return err
+		case d.IsDir():
+			// Skip directories, we'll create them when we get a file in the directory.
+			return nil
+		case skipConfigFiles(inPath):
+			// Found a config file that we should skip copying.
+			return nil
+		}
 
-	return nil
+		// We want the path relative to the directory we are walking in order to calculate where the file should be
+		// mirrored in the destination directory.
+		relPath, err := filepath.Rel(inputPath, inPath)
+		if err != nil {
+			return err
+		}
+
+		return fn(inPath, relPath)
+	})
 }
 
 // skipConfigFiles returns true if the given path is a special config file.
@@ -122,7 +122,7 @@ func TestInstallArtifacts(t *testing.T) {
 	})
 
 	if runtime.GOOS != "windows" {
-		t.Run("Installs artifacts correctly when linux jmx jar", func(t *testing.T) {
+		t.Run("Leaves JMX jar outside of install directory untouched", func(t *testing.T) {
 			jarDir := t.TempDir()
 			specialJarPath := filepath.Join(jarDir, "opentelemetry-java-contrib-jmx-metrics.jar")
 			_, err := os.Create(specialJarPath)
@@ -176,12 +176,13 @@ func TestInstallArtifacts(t *testing.T) {
 			contentsEqual(t, outDirManager, "# The original manager file")
 			contentsEqual(t, outDirLogging, "# The original logging file")
 
-			require.FileExists(t, filepath.Join(jarDir, "opentelemetry-java-contrib-jmx-metrics.jar"))
+			require.FileExists(t, filepath.Join(outDir, "opentelemetry-java-contrib-jmx-metrics.jar"))
 			require.FileExists(t, filepath.Join(outDir, "test.txt"))
 			require.DirExists(t, filepath.Join(outDir, "test-folder"))
 			require.FileExists(t, filepath.Join(outDir, "test-folder", "another-test.txt"))
 
-			contentsEqual(t, filepath.Join(jarDir, "opentelemetry-java-contrib-jmx-metrics.jar"), "# The new jar file")
+			contentsEqual(t, specialJarPath, "# The original jar file")
+			contentsEqual(t, filepath.Join(outDir, "opentelemetry-java-contrib-jmx-metrics.jar"), "# The new jar file")
 			contentsEqual(t, filepath.Join(outDir, "test.txt"), "This is a test file\n")
 			contentsEqual(t, filepath.Join(outDir, "test-folder", "another-test.txt"), "This is a nested text file\n")
 
@@ -197,11 +198,11 @@ func TestInstallArtifacts(t *testing.T) {
 			copyJarAction, err := action.NewCopyFileAction(
 				installer.logger,
 				filepath.Join("opentelemetry-java-contrib-jmx-metrics.jar"),
-				filepath.Join(jarDir, "opentelemetry-java-contrib-jmx-metrics.jar"),
+				filepath.Join(installer.installDir, "opentelemetry-java-contrib-jmx-metrics.jar"),
 				installer.backupDir,
 			)
 			require.NoError(t, err)
-			copyJarAction.FileCreated = false
+			copyJarAction.FileCreated = true
 
 			copyNestedTestTxtAction, err := action.NewCopyFileAction(
 				installer.logger,
@@ -374,3 +375,26 @@ func contentsEqual(t *testing.T, path, expectedContents string) {
 	contents = bytes.ReplaceAll(contents, []byte("\r\n"), []byte("\n"))
 	require.Equal(t, []byte(expectedContents), contents)
 }
+
+func TestFilesToInstall(t *testing.T) {
+	t.Run("Lists files excluding config files", func(t *testing.T) {
+		latestDir := t.TempDir()
+		require.NoError(t, os.MkdirAll(filepath.Join(latestDir, "plugins"), 0750))
+		for _, f := range []string{"observiq-otel-collector", "config.yaml", "logging.yaml", "manager.yaml", filepath.Join("plugins", "plugin.yaml")} {
+			require.NoError(t, os.WriteFile(filepath.Join(latestDir, f), []byte("contents"), 0600))
+		}
+
+		files, err := FilesToInstall(latestDir)
+		require.NoError(t, err)
+		require.ElementsMatch(t, []string{
+			"observiq-otel-collector",
+			filepath.Join("plugins", "plugin.yaml"),
+		}, files)
+	})
+
+	t.Run("Latest dir does not exist", func(t *testing.T) {
+		files, err := FilesToInstall(filepath.Join("testdata", "does-not-exist"))
+		require.ErrorContains(t, err, "failed to walk latest dir")
+		require.Nil(t, files)
+	})
+}
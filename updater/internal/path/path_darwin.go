@@ -19,6 +19,9 @@ import "go.uber.org/zap"
 // DarwinInstallDir is the path to the install directory on Darwin.
 const DarwinInstallDir = "/opt/observiq-otel-collector"
 
+// collectorBinaryName is the file name of the collector executable on darwin.
+const collectorBinaryName = "observiq-otel-collector"
+
 // InstallDir returns the filepath to the install directory
 func InstallDir(_ *zap.Logger) (string, error) {
 	return DarwinInstallDir, nil
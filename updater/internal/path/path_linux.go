@@ -19,6 +19,9 @@ import "go.uber.org/zap"
 // LinuxInstallDir is the install directory of the collector on linux.
 const LinuxInstallDir = "/opt/observiq-otel-collector"
 
+// collectorBinaryName is the file name of the collector executable on linux.
+const collectorBinaryName = "observiq-otel-collector"
+
 // InstallDir returns the filepath to the install directory
 func InstallDir(_ *zap.Logger) (string, error) {
 	return LinuxInstallDir, nil
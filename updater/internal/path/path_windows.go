@@ -23,6 +23,9 @@ import (
 
 const defaultProductName = "observIQ Distro for OpenTelemetry Collector"
 
+// collectorBinaryName is the file name of the collector executable on windows.
+const collectorBinaryName = "observiq-otel-collector.exe"
+
 // installDirFromRegistry gets the installation dir of the given product from the Windows Registry
 func installDirFromRegistry(logger *zap.Logger, productName string) (string, error) {
 	// this key is created when installing using the MSI installer
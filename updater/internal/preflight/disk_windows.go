@@ -0,0 +1,32 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package preflight
+
+import "golang.org/x/sys/windows"
+
+// availableBytes returns the number of bytes available to the current user on the volume containing dir.
+func availableBytes(dir string) (uint64, error) {
+	dirPtr, err := windows.UTF16PtrFromString(dir)
+	if err != nil {
+		return 0, err
+	}
+
+	var freeBytes uint64
+	if err := windows.GetDiskFreeSpaceEx(dirPtr, &freeBytes, nil, nil); err != nil {
+		return 0, err
+	}
+
+	return freeBytes, nil
+}
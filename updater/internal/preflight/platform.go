@@ -0,0 +1,95 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package preflight
+
+import (
+	"debug/elf"
+	"debug/macho"
+	"debug/pe"
+	"errors"
+	"fmt"
+)
+
+// errUnknownFormat is returned when a file is not a recognized executable format.
+var errUnknownFormat = errors.New("unrecognized executable format")
+
+// binaryPlatform determines the GOOS and GOARCH that the executable at binaryPath was built for.
+// The GOOS is inferred from the executable format (ELF for linux, Mach-O for darwin, PE for windows).
+func binaryPlatform(binaryPath string) (goos, goarch string, err error) {
+	if f, err := elf.Open(binaryPath); err == nil {
+		defer f.Close()
+		goarch, err := elfArch(f.Machine)
+		return "linux", goarch, err
+	}
+
+	if f, err := macho.Open(binaryPath); err == nil {
+		defer f.Close()
+		goarch, err := machoArch(f.Cpu)
+		return "darwin", goarch, err
+	}
+
+	if f, err := pe.Open(binaryPath); err == nil {
+		defer f.Close()
+		goarch, err := peArch(f.Machine)
+		return "windows", goarch, err
+	}
+
+	return "", "", errUnknownFormat
+}
+
+func elfArch(machine elf.Machine) (string, error) {
+	switch machine {
+	case elf.EM_X86_64:
+		return "amd64", nil
+	case elf.EM_386:
+		return "386", nil
+	case elf.EM_AARCH64:
+		return "arm64", nil
+	case elf.EM_ARM:
+		return "arm", nil
+	default:
+		return "", fmt.Errorf("unsupported ELF machine: %s", machine)
+	}
+}
+
+func machoArch(cpu macho.Cpu) (string, error) {
+	switch cpu {
+	case macho.CpuAmd64:
+		return "amd64", nil
+	case macho.Cpu386:
+		return "386", nil
+	case macho.CpuArm64:
+		return "arm64", nil
+	case macho.CpuArm:
+		return "arm", nil
+	default:
+		return "", fmt.Errorf("unsupported Mach-O cpu: %s", cpu)
+	}
+}
+
+func peArch(machine uint16) (string, error) {
+	switch machine {
+	case pe.IMAGE_FILE_MACHINE_AMD64:
+		return "amd64", nil
+	case pe.IMAGE_FILE_MACHINE_I386:
+		return "386", nil
+	case pe.IMAGE_FILE_MACHINE_ARM64:
+		return "arm64", nil
+	case pe.IMAGE_FILE_MACHINE_ARMNT:
+		return "arm", nil
+	default:
+		return "", fmt.Errorf("unsupported PE machine: %#x", machine)
+	}
+}
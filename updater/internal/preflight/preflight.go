@@ -0,0 +1,257 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package preflight verifies that an update is able to succeed before the collector is stopped.
+package preflight
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/observiq/bindplane-agent/updater/internal/install"
+	"github.com/observiq/bindplane-agent/updater/internal/path"
+	"go.uber.org/zap"
+)
+
+// executeTimeout is how long the new collector binary has to report its version.
+const executeTimeout = 10 * time.Second
+
+// Report contains the results of the preflight checks.
+type Report struct {
+	// ReplacedFiles are the files, relative to the install directory, that will be overwritten by the update.
+	ReplacedFiles []string
+	// NewFiles are the files, relative to the install directory, that will be created by the update.
+	NewFiles []string
+	// RequiredBytes is the amount of disk space needed to back up the installation and copy the new files.
+	RequiredBytes uint64
+	// AvailableBytes is the amount of disk space available to the install directory.
+	AvailableBytes uint64
+	// NewVersion is the version output reported by the new collector binary.
+	NewVersion string
+}
+
+// Checker is an interface that performs checks to determine if an update can succeed.
+//
+//go:generate mockery --name Checker --filename mock_checker.go --structname MockChecker
+type Checker interface {
+	// Check runs all preflight checks, returning an error if any of them failed.
+	// The returned Report is always non-nil, but may be incomplete if an error is returned.
+	Check() (*Report, error)
+}
+
+// installChecker checks the artifacts in latestDir against the installation in installDir.
+type installChecker struct {
+	installDir string
+	latestDir  string
+	logger     *zap.Logger
+}
+
+// NewChecker returns a new Checker for the installation based at installDir.
+func NewChecker(logger *zap.Logger, installDir string) Checker {
+	return &installChecker{
+		installDir: installDir,
+		latestDir:  path.LatestDir(installDir),
+		logger:     logger.Named("preflight"),
+	}
+}
+
+// Check runs every preflight check. All checks are run, even if an earlier one fails,
+// so that every problem is reported at once.
+func (c installChecker) Check() (*Report, error) {
+	report := &Report{}
+	var errs []error
+
+	if err := c.checkFiles(report); err != nil {
+		errs = append(errs, err)
+	}
+
+	if err := checkWritable(c.installDir); err != nil {
+		errs = append(errs, fmt.Errorf("install directory is not writable: %w", err))
+	}
+
+	if err := c.checkDiskSpace(report); err != nil {
+		errs = append(errs, err)
+	}
+
+	binaryPath := path.LatestCollectorBinary(c.latestDir)
+	if err := checkArchitecture(binaryPath); err != nil {
+		errs = append(errs, err)
+	} else {
+		// Only attempt to execute the binary if it's built for this platform
+		version, err := checkExecutes(binaryPath)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		report.NewVersion = version
+	}
+
+	c.logger.Debug("Preflight checks complete",
+		zap.Strings("replacedFiles", report.ReplacedFiles),
+		zap.Strings("newFiles", report.NewFiles),
+		zap.Uint64("requiredBytes", report.RequiredBytes),
+		zap.Uint64("availableBytes", report.AvailableBytes),
+		zap.String("newVersion", report.NewVersion),
+	)
+
+	return report, errors.Join(errs...)
+}
+
+// checkFiles records which files will be replaced or created by the update.
+func (c installChecker) checkFiles(report *Report) error {
+	files, err := install.FilesToInstall(c.latestDir)
+	if err != nil {
+		return fmt.Errorf("failed to list files to install: %w", err)
+	}
+
+	for _, relPath := range files {
+		_, err := os.Stat(filepath.Join(c.installDir, relPath))
+		switch {
+		case err == nil:
+			report.ReplacedFiles = append(report.ReplacedFiles, relPath)
+		case errors.Is(err, os.ErrNotExist):
+			report.NewFiles = append(report.NewFiles, relPath)
+		default:
+			return fmt.Errorf("failed to stat installed file %s: %w", relPath, err)
+		}
+	}
+
+	return nil
+}
+
+// checkDiskSpace verifies there is enough space to back up the current installation,
+// as well as copy the new artifacts into the install directory.
+func (c installChecker) checkDiskSpace(report *Report) error {
+	backupBytes, err := c.backupSize()
+	if err != nil {
+		return fmt.Errorf("failed to determine backup size: %w", err)
+	}
+
+	installBytes, err := dirSize(c.latestDir, "")
+	if err != nil {
+		return fmt.Errorf("failed to determine size of new artifacts: %w", err)
+	}
+
+	available, err := availableBytes(c.installDir)
+	if err != nil {
+		return fmt.Errorf("failed to determine available disk space: %w", err)
+	}
+
+	report.RequiredBytes = backupBytes + installBytes
+	report.AvailableBytes = available
+
+	if report.RequiredBytes > report.AvailableBytes {
+		return fmt.Errorf("insufficient disk space: %d bytes required, %d bytes available", report.RequiredBytes, report.AvailableBytes)
+	}
+
+	return nil
+}
+
+// backupSize returns the number of bytes the rollbacker will copy when backing up the installation.
+func (c installChecker) backupSize() (uint64, error) {
+	return dirSize(c.installDir, path.TempDir(c.installDir))
+}
+
+// dirSize returns the total size of all files under dir, skipping the directory skipDir if it is specified.
+func dirSize(dir, skipDir string) (uint64, error) {
+	var absSkipDir string
+	if skipDir != "" {
+		var err error
+		absSkipDir, err = filepath.Abs(skipDir)
+		if err != nil {
+			return 0, fmt.Errorf("failed to get absolute path for skipped directory: %w", err)
+		}
+	}
+
+	var size uint64
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			fullPath, err := filepath.Abs(p)
+			if err != nil {
+				return fmt.Errorf("failed to determine absolute path of directory: %w", err)
+			}
+
+			if absSkipDir != "" && fullPath == absSkipDir {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		size += uint64(info.Size())
+		return nil
+	})
+
+	return size, err
+}
+
+// checkWritable verifies that a file can be created in dir.
+func checkWritable(dir string) error {
+	f, err := os.CreateTemp(dir, ".preflight-*")
+	if err != nil {
+		return err
+	}
+
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to close test file: %w", err)
+	}
+
+	return os.Remove(f.Name())
+}
+
+// checkArchitecture verifies that the executable at binaryPath was built for the current OS and architecture.
+func checkArchitecture(binaryPath string) error {
+	goos, goarch, err := binaryPlatform(binaryPath)
+	if err != nil {
+		return fmt.Errorf("failed to determine platform of new collector binary: %w", err)
+	}
+
+	if goos != runtime.GOOS || goarch != runtime.GOARCH {
+		return fmt.Errorf("new collector binary is built for %s/%s, but this system is %s/%s", goos, goarch, runtime.GOOS, runtime.GOARCH)
+	}
+
+	return nil
+}
+
+// checkExecutes verifies that the executable at binaryPath runs, returning the version it reports.
+func checkExecutes(binaryPath string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), executeTimeout)
+	defer cancel()
+
+	//#nosec G204 -- path is not determined via user input
+	out, err := exec.CommandContext(ctx, binaryPath, "--version").Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to execute new collector binary: %w", err)
+	}
+
+	// The first line of output is the version, e.g. "observiq-otel-collector version v1.41.0"
+	version, _, _ := strings.Cut(strings.TrimSpace(string(out)), "\n")
+	return version, nil
+}
@@ -0,0 +1,146 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package preflight
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/observiq/bindplane-agent/updater/internal/path"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestCheck(t *testing.T) {
+	t.Run("Reports files and fails on invalid binary", func(t *testing.T) {
+		installDir := t.TempDir()
+		latestDir := path.LatestDir(installDir)
+		require.NoError(t, os.MkdirAll(latestDir, 0750))
+
+		writeFile(t, filepath.Join(installDir, "VERSION.txt"), "v1.0.0")
+		writeFile(t, filepath.Join(latestDir, "VERSION.txt"), "v1.1.0")
+		writeFile(t, filepath.Join(latestDir, "config.yaml"), "# new config")
+		writeFile(t, filepath.Join(latestDir, "plugins", "new_plugin.yaml"), "# new plugin")
+		writeFile(t, path.LatestCollectorBinary(latestDir), "not an executable")
+
+		checker := NewChecker(zaptest.NewLogger(t), installDir)
+		report, err := checker.Check()
+		require.ErrorContains(t, err, "failed to determine platform of new collector binary")
+		require.NotNil(t, report)
+		require.Equal(t, []string{"VERSION.txt"}, report.ReplacedFiles)
+		require.ElementsMatch(t, []string{filepath.Base(path.LatestCollectorBinary(latestDir)), filepath.Join("plugins", "new_plugin.yaml")}, report.NewFiles)
+		require.NotZero(t, report.RequiredBytes)
+		require.Empty(t, report.NewVersion)
+	})
+
+	t.Run("Latest dir does not exist", func(t *testing.T) {
+		installDir := t.TempDir()
+
+		checker := NewChecker(zaptest.NewLogger(t), installDir)
+		report, err := checker.Check()
+		require.ErrorContains(t, err, "failed to list files to install")
+		require.ErrorContains(t, err, "failed to determine size of new artifacts")
+		require.NotNil(t, report)
+	})
+}
+
+func TestCheckDiskSpace(t *testing.T) {
+	t.Run("Enough disk space", func(t *testing.T) {
+		installDir := t.TempDir()
+		latestDir := path.LatestDir(installDir)
+
+		writeFile(t, filepath.Join(installDir, "observiq-otel-collector"), "old collector")
+		writeFile(t, filepath.Join(latestDir, "observiq-otel-collector"), "new collector!")
+
+		checker := &installChecker{
+			installDir: installDir,
+			latestDir:  latestDir,
+			logger:     zaptest.NewLogger(t),
+		}
+
+		report := &Report{}
+		require.NoError(t, checker.checkDiskSpace(report))
+		// The tmp directory should not be counted as part of the backup
+		require.Equal(t, uint64(len("old collector")+len("new collector!")), report.RequiredBytes)
+		require.GreaterOrEqual(t, report.AvailableBytes, report.RequiredBytes)
+	})
+}
+
+func TestCheckWritable(t *testing.T) {
+	t.Run("Directory is writable", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, checkWritable(dir))
+
+		entries, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		require.Empty(t, entries)
+	})
+
+	t.Run("Directory does not exist", func(t *testing.T) {
+		require.Error(t, checkWritable(filepath.Join(t.TempDir(), "does-not-exist")))
+	})
+}
+
+func TestCheckArchitecture(t *testing.T) {
+	t.Run("Executable matches platform", func(t *testing.T) {
+		// The running test binary is always built for the current platform
+		exe, err := os.Executable()
+		require.NoError(t, err)
+		require.NoError(t, checkArchitecture(exe))
+	})
+
+	t.Run("File is not an executable", func(t *testing.T) {
+		notExe := filepath.Join(t.TempDir(), "not-exe")
+		writeFile(t, notExe, "plain text")
+		require.ErrorContains(t, checkArchitecture(notExe), "failed to determine platform of new collector binary")
+	})
+}
+
+func TestCheckExecutes(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("test uses shell scripts")
+	}
+
+	t.Run("Binary reports version", func(t *testing.T) {
+		script := filepath.Join(t.TempDir(), "collector")
+		writeScript(t, script, "#!/bin/sh\necho \"observiq-otel-collector version v1.2.3\"\necho \"commit: abc\"\n")
+
+		version, err := checkExecutes(script)
+		require.NoError(t, err)
+		require.Equal(t, "observiq-otel-collector version v1.2.3", version)
+	})
+
+	t.Run("Binary exits with error", func(t *testing.T) {
+		script := filepath.Join(t.TempDir(), "collector")
+		writeScript(t, script, "#!/bin/sh\nexit 1\n")
+
+		_, err := checkExecutes(script)
+		require.ErrorContains(t, err, "failed to execute new collector binary")
+	})
+}
+
+func writeFile(t *testing.T, p, contents string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(p), 0750))
+	require.NoError(t, os.WriteFile(p, []byte(contents), 0600))
+}
+
+func writeScript(t *testing.T, p, contents string) {
+	t.Helper()
+	//#nosec G306 -- script must be executable
+	require.NoError(t, os.WriteFile(p, []byte(contents), 0700))
+}
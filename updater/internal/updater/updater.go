@@ -26,6 +26,7 @@ import (
 	"github.com/observiq/bindplane-agent/updater/internal/action"
 	"github.com/observiq/bindplane-agent/updater/internal/install"
 	"github.com/observiq/bindplane-agent/updater/internal/path"
+	"github.com/observiq/bindplane-agent/updater/internal/preflight"
 	"github.com/observiq/bindplane-agent/updater/internal/rollback"
 	"github.com/observiq/bindplane-agent/updater/internal/service"
 	"github.com/observiq/bindplane-agent/updater/internal/state"
@@ -37,6 +38,7 @@ import (
 type Updater struct {
 	installDir string
 	installer  install.Installer
+	preflight  preflight.Checker
 	svc        service.Service
 	rollbacker rollback.Rollbacker
 	monitor    state.Monitor
@@ -55,6 +57,7 @@ func NewUpdater(logger *zap.Logger, installDir string) (*Updater, error) {
 	return &Updater{
 		installDir: installDir,
 		installer:  install.NewInstaller(logger, installDir, svc),
+		preflight:  preflight.NewChecker(logger, installDir),
 		svc:        svc,
 		rollbacker: rollback.NewRollbacker(logger, installDir),
 		monitor:    monitor,
@@ -64,6 +67,19 @@ func NewUpdater(logger *zap.Logger, installDir string) (*Updater, error) {
 
 // Update performs the update of the collector binary
 func (u *Updater) Update() error {
+	// Verify the update can succeed before touching the running service.
+	// Nothing has been modified at this point, so there is nothing to roll back;
+	// the collector will report the failure once it notices the updater has exited.
+	report, err := u.preflight.Check()
+	if err != nil {
+		return fmt.Errorf("failed preflight checks: %w", err)
+	}
+
+	u.logger.Debug("Preflight checks passed",
+		zap.Strings("replacedFiles", report.ReplacedFiles),
+		zap.Strings("newFiles", report.NewFiles),
+	)
+
 	// Stop the service before backing up the install directory;
 	// We want to stop as early as possible so that we don't hit the collector's timeout
 	// while it waits to be shutdown.
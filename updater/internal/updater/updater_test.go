@@ -21,6 +21,8 @@ import (
 	"github.com/observiq/bindplane-agent/packagestate"
 	"github.com/observiq/bindplane-agent/updater/internal/action"
 	install_mocks "github.com/observiq/bindplane-agent/updater/internal/install/mocks"
+	"github.com/observiq/bindplane-agent/updater/internal/preflight"
+	preflight_mocks "github.com/observiq/bindplane-agent/updater/internal/preflight/mocks"
 	rollback_mocks "github.com/observiq/bindplane-agent/updater/internal/rollback/mocks"
 	service_mocks "github.com/observiq/bindplane-agent/updater/internal/service/mocks"
 	"github.com/observiq/bindplane-agent/updater/internal/state"
@@ -40,6 +42,7 @@ func TestNewUpdater(t *testing.T) {
 		require.NoError(t, err)
 		require.NotNil(t, updater)
 		assert.NotNil(t, updater.installer)
+		assert.NotNil(t, updater.preflight)
 		assert.NotNil(t, updater.svc)
 		assert.NotNil(t, updater.rollbacker)
 		assert.NotNil(t, updater.monitor)
@@ -61,6 +64,7 @@ func TestUpdaterUpdate(t *testing.T) {
 		installDir := t.TempDir()
 
 		installer := install_mocks.NewMockInstaller(t)
+		checker := preflight_mocks.NewMockChecker(t)
 		svc := service_mocks.NewMockService(t)
 		rollbacker := rollback_mocks.NewMockRollbacker(t)
 		monitor := state_mocks.NewMockMonitor(t)
@@ -68,12 +72,14 @@ func TestUpdaterUpdate(t *testing.T) {
 		updater := &Updater{
 			installDir: installDir,
 			installer:  installer,
+			preflight:  checker,
 			svc:        svc,
 			rollbacker: rollbacker,
 			monitor:    monitor,
 			logger:     zaptest.NewLogger(t),
 		}
 
+		checker.On("Check").Times(1).Return(&preflight.Report{}, nil)
 		svc.On("Stop").Times(1).Return(nil)
 		rollbacker.On("AppendAction", action.NewServiceStopAction(svc)).Times(1).Return()
 		rollbacker.On("Backup").Times(1).Return(nil)
@@ -84,10 +90,38 @@ func TestUpdaterUpdate(t *testing.T) {
 		require.NoError(t, err)
 	})
 
+	t.Run("Preflight checks fail", func(t *testing.T) {
+		installDir := t.TempDir()
+
+		installer := install_mocks.NewMockInstaller(t)
+		checker := preflight_mocks.NewMockChecker(t)
+		svc := service_mocks.NewMockService(t)
+		rollbacker := rollback_mocks.NewMockRollbacker(t)
+		monitor := state_mocks.NewMockMonitor(t)
+
+		updater := &Updater{
+			installDir: installDir,
+			installer:  installer,
+			preflight:  checker,
+			svc:        svc,
+			rollbacker: rollbacker,
+			monitor:    monitor,
+			logger:     zaptest.NewLogger(t),
+		}
+
+		checker.On("Check").Times(1).Return(&preflight.Report{}, errors.New("insufficient disk space"))
+
+		err := updater.Update()
+		require.ErrorContains(t, err, "failed preflight checks")
+		// The service must not have been stopped
+		svc.AssertNotCalled(t, "Stop")
+	})
+
 	t.Run("Service stop fails", func(t *testing.T) {
 		installDir := t.TempDir()
 
 		installer := install_mocks.NewMockInstaller(t)
+		checker := preflight_mocks.NewMockChecker(t)
 		svc := service_mocks.NewMockService(t)
 		rollbacker := rollback_mocks.NewMockRollbacker(t)
 		monitor := state_mocks.NewMockMonitor(t)
@@ -95,12 +129,14 @@ func TestUpdaterUpdate(t *testing.T) {
 		updater := &Updater{
 			installDir: installDir,
 			installer:  installer,
+			preflight:  checker,
 			svc:        svc,
 			rollbacker: rollbacker,
 			monitor:    monitor,
 			logger:     zaptest.NewLogger(t),
 		}
 
+		checker.On("Check").Times(1).Return(&preflight.Report{}, nil)
 		svc.On("Stop").Times(1).Return(errors.New("insufficient permissions"))
 
 		err := updater.Update()
@@ -111,6 +147,7 @@ func TestUpdaterUpdate(t *testing.T) {
 		installDir := t.TempDir()
 
 		installer := install_mocks.NewMockInstaller(t)
+		checker := preflight_mocks.NewMockChecker(t)
 		svc := service_mocks.NewMockService(t)
 		rollbacker := rollback_mocks.NewMockRollbacker(t)
 		monitor := state_mocks.NewMockMonitor(t)
@@ -118,6 +155,7 @@ func TestUpdaterUpdate(t *testing.T) {
 		updater := &Updater{
 			installDir: installDir,
 			installer:  installer,
+			preflight:  checker,
 			svc:        svc,
 			rollbacker: rollbacker,
 			monitor:    monitor,
@@ -126,6 +164,7 @@ func TestUpdaterUpdate(t *testing.T) {
 
 		err := errors.New("insufficient permissions")
 
+		checker.On("Check").Times(1).Return(&preflight.Report{}, nil)
 		svc.On("Stop").Times(1).Return(nil)
 		rollbacker.On("AppendAction", action.NewServiceStopAction(svc)).Times(1).Return()
 		rollbacker.On("Backup").Times(1).Return(err)
@@ -140,6 +179,7 @@ func TestUpdaterUpdate(t *testing.T) {
 		installDir := t.TempDir()
 
 		installer := install_mocks.NewMockInstaller(t)
+		checker := preflight_mocks.NewMockChecker(t)
 		svc := service_mocks.NewMockService(t)
 		rollbacker := rollback_mocks.NewMockRollbacker(t)
 		monitor := state_mocks.NewMockMonitor(t)
@@ -147,6 +187,7 @@ func TestUpdaterUpdate(t *testing.T) {
 		updater := &Updater{
 			installDir: installDir,
 			installer:  installer,
+			preflight:  checker,
 			svc:        svc,
 			rollbacker: rollbacker,
 			monitor:    monitor,
@@ -155,6 +196,7 @@ func TestUpdaterUpdate(t *testing.T) {
 
 		err := errors.New("insufficient permissions")
 
+		checker.On("Check").Times(1).Return(&preflight.Report{}, nil)
 		svc.On("Stop").Times(1).Return(nil)
 		rollbacker.On("AppendAction", action.NewServiceStopAction(svc)).Times(1).Return()
 		rollbacker.On("Backup").Times(1).Return(err)
@@ -169,6 +211,7 @@ func TestUpdaterUpdate(t *testing.T) {
 		installDir := t.TempDir()
 
 		installer := install_mocks.NewMockInstaller(t)
+		checker := preflight_mocks.NewMockChecker(t)
 		svc := service_mocks.NewMockService(t)
 		rollbacker := rollback_mocks.NewMockRollbacker(t)
 		monitor := state_mocks.NewMockMonitor(t)
@@ -176,6 +219,7 @@ func TestUpdaterUpdate(t *testing.T) {
 		updater := &Updater{
 			installDir: installDir,
 			installer:  installer,
+			preflight:  checker,
 			svc:        svc,
 			rollbacker: rollbacker,
 			monitor:    monitor,
@@ -184,6 +228,7 @@ func TestUpdaterUpdate(t *testing.T) {
 
 		err := errors.New("insufficient permissions")
 
+		checker.On("Check").Times(1).Return(&preflight.Report{}, nil)
 		svc.On("Stop").Times(1).Return(nil)
 		rollbacker.On("AppendAction", action.NewServiceStopAction(svc)).Times(1).Return()
 		rollbacker.On("Backup").Times(1).Return(nil)
@@ -199,6 +244,7 @@ func TestUpdaterUpdate(t *testing.T) {
 		installDir := t.TempDir()
 
 		installer := install_mocks.NewMockInstaller(t)
+		checker := preflight_mocks.NewMockChecker(t)
 		svc := service_mocks.NewMockService(t)
 		rollbacker := rollback_mocks.NewMockRollbacker(t)
 		monitor := state_mocks.NewMockMonitor(t)
@@ -206,6 +252,7 @@ func TestUpdaterUpdate(t *testing.T) {
 		updater := &Updater{
 			installDir: installDir,
 			installer:  installer,
+			preflight:  checker,
 			svc:        svc,
 			rollbacker: rollbacker,
 			monitor:    monitor,
@@ -214,6 +261,7 @@ func TestUpdaterUpdate(t *testing.T) {
 
 		err := errors.New("insufficient permissions")
 
+		checker.On("Check").Times(1).Return(&preflight.Report{}, nil)
 		svc.On("Stop").Times(1).Return(nil)
 		rollbacker.On("AppendAction", action.NewServiceStopAction(svc)).Times(1).Return()
 		rollbacker.On("Backup").Times(1).Return(nil)
@@ -229,6 +277,7 @@ func TestUpdaterUpdate(t *testing.T) {
 		installDir := t.TempDir()
 
 		installer := install_mocks.NewMockInstaller(t)
+		checker := preflight_mocks.NewMockChecker(t)
 		svc := service_mocks.NewMockService(t)
 		rollbacker := rollback_mocks.NewMockRollbacker(t)
 		monitor := state_mocks.NewMockMonitor(t)
@@ -236,6 +285,7 @@ func TestUpdaterUpdate(t *testing.T) {
 		updater := &Updater{
 			installDir: installDir,
 			installer:  installer,
+			preflight:  checker,
 			svc:        svc,
 			rollbacker: rollbacker,
 			monitor:    monitor,
@@ -244,6 +294,7 @@ func TestUpdaterUpdate(t *testing.T) {
 
 		err := errors.New("insufficient permissions")
 
+		checker.On("Check").Times(1).Return(&preflight.Report{}, nil)
 		svc.On("Stop").Times(1).Return(nil)
 		rollbacker.On("AppendAction", action.NewServiceStopAction(svc)).Times(1).Return()
 		rollbacker.On("Backup").Times(1).Return(nil)
@@ -260,6 +311,7 @@ func TestUpdaterUpdate(t *testing.T) {
 		installDir := t.TempDir()
 
 		installer := install_mocks.NewMockInstaller(t)
+		checker := preflight_mocks.NewMockChecker(t)
 		svc := service_mocks.NewMockService(t)
 		rollbacker := rollback_mocks.NewMockRollbacker(t)
 		monitor := state_mocks.NewMockMonitor(t)
@@ -267,6 +319,7 @@ func TestUpdaterUpdate(t *testing.T) {
 		updater := &Updater{
 			installDir: installDir,
 			installer:  installer,
+			preflight:  checker,
 			svc:        svc,
 			rollbacker: rollbacker,
 			monitor:    monitor,
@@ -275,6 +328,7 @@ func TestUpdaterUpdate(t *testing.T) {
 
 		err := errors.New("insufficient permissions")
 
+		checker.On("Check").Times(1).Return(&preflight.Report{}, nil)
 		svc.On("Stop").Times(1).Return(nil)
 		rollbacker.On("AppendAction", action.NewServiceStopAction(svc)).Times(1).Return()
 		rollbacker.On("Backup").Times(1).Return(nil)
@@ -291,6 +345,7 @@ func TestUpdaterUpdate(t *testing.T) {
 		installDir := t.TempDir()
 
 		installer := install_mocks.NewMockInstaller(t)
+		checker := preflight_mocks.NewMockChecker(t)
 		svc := service_mocks.NewMockService(t)
 		rollbacker := rollback_mocks.NewMockRollbacker(t)
 		monitor := state_mocks.NewMockMonitor(t)
@@ -298,12 +353,14 @@ func TestUpdaterUpdate(t *testing.T) {
 		updater := &Updater{
 			installDir: installDir,
 			installer:  installer,
+			preflight:  checker,
 			svc:        svc,
 			rollbacker: rollbacker,
 			monitor:    monitor,
 			logger:     zaptest.NewLogger(t),
 		}
 
+		checker.On("Check").Times(1).Return(&preflight.Report{}, nil)
 		svc.On("Stop").Times(1).Return(nil)
 		rollbacker.On("AppendAction", action.NewServiceStopAction(svc)).Times(1).Return()
 		rollbacker.On("Backup").Times(1).Return(nil)